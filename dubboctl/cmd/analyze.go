@@ -20,10 +20,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	"github.com/apache/dubbo-kubernetes/pkg/kube"
 	"github.com/spf13/cobra"
 	appsv1 "k8s.io/api/apps/v1"
@@ -31,6 +33,7 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 type analyzeLevel string
@@ -111,7 +114,8 @@ func runAnalyzers(ctx context.Context, client kube.CLIClient, namespace string)
 	}
 
 	msgs = append(msgs, analyzeHTTPRoutes(ctx, client, namespace, services.Items)...)
-	msgs = append(msgs, analyzeSecurityPolicies(ctx, client, namespace, pods.Items)...)
+	msgs = append(msgs, analyzeGRPCRoutes(ctx, client, namespace)...)
+	msgs = append(msgs, analyzeSecurityPolicies(ctx, client, namespace, pods.Items, services.Items)...)
 	msgs = append(msgs, analyzeCircuitBreakerPolicies(ctx, client, namespace, services.Items)...)
 	msgs = append(msgs, collectHighAvailability(ctx, client, namespace)...)
 	return msgs, nil
@@ -265,6 +269,7 @@ func analyzeHTTPRoutes(ctx context.Context, client kube.CLIClient, namespace str
 	}
 	for _, route := range routes.Items {
 		resource := fmt.Sprintf("HTTPRoute %s/%s", route.Namespace, route.Name)
+		msgs = append(msgs, analyzeProxylessRouteFilters(route)...)
 		for ruleIdx, rule := range route.Spec.Rules {
 			for _, ref := range rule.BackendRefs {
 				if ref.Kind != nil && *ref.Kind != "Service" {
@@ -303,9 +308,97 @@ func analyzeHTTPRoutes(ctx context.Context, client kube.CLIClient, namespace str
 	return msgs
 }
 
-// analyzeSecurityPolicies reports selectors matching no pods and JWT rules that
-// are validated but not enforced by any authorization policy.
-func analyzeSecurityPolicies(ctx context.Context, client kube.CLIClient, namespace string, pods []corev1.Pod) []analyzeMessage {
+// analyzeProxylessRouteFilters warns about filters and JWT claim matches on
+// mesh (Service-attached) HTTPRoutes that proxyless clients cannot apply.
+// Both are dropped from the generated RDS.
+func analyzeProxylessRouteFilters(route gatewayv1.HTTPRoute) []analyzeMessage {
+	if !isMeshRoute(route.Spec.ParentRefs) {
+		return nil
+	}
+	msgs := []analyzeMessage{}
+	resource := fmt.Sprintf("HTTPRoute %s/%s", route.Namespace, route.Name)
+	for ruleIdx, rule := range route.Spec.Rules {
+		for matchIdx, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if claim, ok := securityconfig.ClaimFromRouteHeader(string(header.Name)); ok {
+					msgs = append(msgs, proxylessClaimMatchMessage(resource, ruleIdx, matchIdx, claim))
+					break
+				}
+			}
+		}
+		for _, filter := range rule.Filters {
+			switch filter.Type {
+			case gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+				gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+				gatewayv1.HTTPRouteFilterURLRewrite,
+				gatewayv1.HTTPRouteFilterRequestMirror:
+			case gatewayv1.HTTPRouteFilterRequestRedirect:
+				if len(rule.BackendRefs) > 0 {
+					msgs = append(msgs, analyzeMessage{levelWarning, resource,
+						fmt.Sprintf("rule[%d] has a RequestRedirect filter; its backendRefs are never reached", ruleIdx)})
+				}
+			default:
+				msgs = append(msgs, analyzeMessage{levelWarning, resource,
+					fmt.Sprintf("rule[%d] filter %s has no proxyless equivalent and is ignored for mesh traffic", ruleIdx, filter.Type)})
+			}
+		}
+	}
+	return msgs
+}
+
+// analyzeGRPCRoutes reports JWT claim matches on mesh (Service-attached)
+// GRPCRoutes, which proxyless clients cannot apply.
+func analyzeGRPCRoutes(ctx context.Context, client kube.CLIClient, namespace string) []analyzeMessage {
+	routes, err := client.GatewayAPI().GatewayV1().GRPCRoutes(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return []analyzeMessage{{levelWarning, "GRPCRoute", fmt.Sprintf("failed to list GRPCRoutes: %v", err)}}
+	}
+	msgs := []analyzeMessage{}
+	for _, route := range routes.Items {
+		msgs = append(msgs, analyzeProxylessGRPCRoute(route)...)
+	}
+	return msgs
+}
+
+func analyzeProxylessGRPCRoute(route gatewayv1.GRPCRoute) []analyzeMessage {
+	if !isMeshRoute(route.Spec.ParentRefs) {
+		return nil
+	}
+	msgs := []analyzeMessage{}
+	resource := fmt.Sprintf("GRPCRoute %s/%s", route.Namespace, route.Name)
+	for ruleIdx, rule := range route.Spec.Rules {
+		for matchIdx, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if claim, ok := securityconfig.ClaimFromRouteHeader(string(header.Name)); ok {
+					msgs = append(msgs, proxylessClaimMatchMessage(resource, ruleIdx, matchIdx, claim))
+					break
+				}
+			}
+		}
+	}
+	return msgs
+}
+
+// proxylessClaimMatchMessage explains why a claim match is dropped: the
+// proxyless caller matches routes before any token has been verified.
+func proxylessClaimMatchMessage(resource string, ruleIdx, matchIdx int, claim string) analyzeMessage {
+	return analyzeMessage{levelWarning, resource,
+		fmt.Sprintf("rule[%d].matches[%d] matches JWT claim %q, which proxyless callers cannot verify; the match is ignored for mesh traffic and only honoured on Gateway parents", ruleIdx, matchIdx, claim)}
+}
+
+func isMeshRoute(parentRefs []gatewayv1.ParentReference) bool {
+	for _, parent := range parentRefs {
+		if parent.Kind != nil && *parent.Kind == "Service" && (parent.Group == nil || *parent.Group == "") {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeSecurityPolicies reports selectors matching no pods, JWT rules that
+// are validated but not enforced by any authorization policy, and Dubbo RPC
+// conditions the selected workloads cannot enforce.
+func analyzeSecurityPolicies(ctx context.Context, client kube.CLIClient, namespace string, pods []corev1.Pod, services []corev1.Service) []analyzeMessage {
 	msgs := []analyzeMessage{}
 	security := client.Dubbo().SecurityV1alpha3()
 
@@ -334,6 +427,18 @@ func analyzeSecurityPolicies(ctx context.Context, client kube.CLIClient, namespa
 						fmt.Sprintf("selector %v matches no pods; the policy has no effect", sel.GetMatchLabels())})
 				}
 			}
+			var conditions []dubboRPCCondition
+			for i, rule := range policy.Spec.GetRules() {
+				for _, when := range rule.GetWhen() {
+					if securityconfig.IsDubboRPCKey(when.GetKey()) {
+						conditions = append(conditions, dubboRPCCondition{rule: i, key: when.GetKey(), values: when.GetValues()})
+					}
+				}
+			}
+			if len(conditions) > 0 {
+				selected := selectPods(pods, policy.Namespace, policy.Spec.GetSelector().GetMatchLabels())
+				msgs = append(msgs, analyzeDubboRPCConditions(resource, policy.Spec.GetAction().String(), conditions, selected, services)...)
+			}
 		}
 	}
 
@@ -391,6 +496,145 @@ func analyzeSecurityPolicies(ctx context.Context, client kube.CLIClient, namespa
 	return msgs
 }
 
+// Service annotation and pod label naming the Dubbo interfaces a workload
+// serves; they match the ones dubbod reads.
+const (
+	dubboInterfacesAnnotation = "dubbo.apache.org/interfaces"
+	dubboInterfaceLabel       = "dubbo.apache.org/interface"
+)
+
+// dubboRPCCondition is one Dubbo RPC condition of an authorization rule.
+type dubboRPCCondition struct {
+	rule   int
+	key    string
+	values []string
+}
+
+func selectPods(pods []corev1.Pod, namespace string, matchLabels map[string]string) []corev1.Pod {
+	selector := labels.SelectorFromSet(matchLabels)
+	var out []corev1.Pod
+	for _, pod := range pods {
+		if pod.Namespace == namespace && selector.Matches(labels.Set(pod.Labels)) {
+			out = append(out, pod)
+		}
+	}
+	return out
+}
+
+// analyzeDubboRPCConditions reports the Dubbo RPC conditions that the pods
+// selected by a policy cannot enforce: pods without a Dubbo data plane enforce
+// none of them, and pods serving no Dubbo interface never receive the Triple
+// group and version metadata. It also reports interface values matching none
+// of the interfaces the pods serve.
+func analyzeDubboRPCConditions(resource, action string, conditions []dubboRPCCondition, pods []corev1.Pod, services []corev1.Service) []analyzeMessage {
+	effect := "requests are denied"
+	if action == "DENY" {
+		effect = "the rule never matches"
+	}
+	keys := map[string]bool{}
+	for _, c := range conditions {
+		keys[c.key] = true
+	}
+	var allKeys, metadataKeys []string
+	for _, key := range securityconfig.DubboRPCKeys {
+		if !keys[key] {
+			continue
+		}
+		allKeys = append(allKeys, key)
+		if key == securityconfig.DubboGroupKey || key == securityconfig.DubboVersionKey {
+			metadataKeys = append(metadataKeys, key)
+		}
+	}
+
+	msgs := []analyzeMessage{}
+	var noDataPlane, noInterfaces []string
+	type servingPod struct {
+		name       string
+		interfaces []string
+	}
+	var served []servingPod
+	for _, pod := range pods {
+		name := pod.Namespace + "/" + pod.Name
+		if !isInjectedPod(pod) {
+			noDataPlane = append(noDataPlane, name)
+			continue
+		}
+		interfaces := podDubboInterfaces(pod, services)
+		if len(interfaces) == 0 {
+			noInterfaces = append(noInterfaces, name)
+			continue
+		}
+		served = append(served, servingPod{name, interfaces})
+	}
+	if len(noDataPlane) > 0 {
+		msgs = append(msgs, analyzeMessage{levelWarning, resource,
+			fmt.Sprintf("%s cannot be enforced on pods without a Dubbo data plane: %s",
+				strings.Join(allKeys, ", "), strings.Join(noDataPlane, ", "))})
+	}
+	if len(metadataKeys) > 0 && len(noInterfaces) > 0 {
+		msgs = append(msgs, analyzeMessage{levelWarning, resource,
+			fmt.Sprintf("%s cannot be enforced on pods serving no Dubbo interface, whose callers send no tri-service-group/tri-service-version metadata, so %s: %s",
+				strings.Join(metadataKeys, ", "), effect, strings.Join(noInterfaces, ", "))})
+	}
+
+	for _, c := range conditions {
+		if c.key != securityconfig.DubboInterfaceKey || len(c.values) == 0 {
+			continue
+		}
+		for _, pod := range served {
+			if !anyDubboRPCValueMatches(c.values, pod.interfaces) {
+				msgs = append(msgs, analyzeMessage{levelInfo, resource,
+					fmt.Sprintf("rule[%d] %s %v matches none of the interfaces served by %s (%s)",
+						c.rule, c.key, c.values, pod.name, strings.Join(pod.interfaces, ", "))})
+			}
+		}
+	}
+	return msgs
+}
+
+func anyDubboRPCValueMatches(patterns, values []string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if securityconfig.DubboRPCValueMatches(pattern, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// podDubboInterfaces lists the names of the Dubbo interfaces a pod serves,
+// from its own label and the services selecting it.
+func podDubboInterfaces(pod corev1.Pod, services []corev1.Service) []string {
+	names := map[string]bool{}
+	if name := pod.Labels[dubboInterfaceLabel]; name != "" {
+		names[name] = true
+	}
+	for _, svc := range services {
+		if svc.Namespace != pod.Namespace || len(svc.Spec.Selector) == 0 ||
+			!labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		for _, key := range strings.Split(svc.Annotations[dubboInterfacesAnnotation], ",") {
+			// Service keys are "[group/]interface[:version]".
+			key = strings.TrimSpace(key)
+			if _, rest, found := strings.Cut(key, "/"); found {
+				key = rest
+			}
+			key, _, _ = strings.Cut(key, ":")
+			if key != "" {
+				names[key] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(names))
+	for name := range names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
 // analyzeCircuitBreakerPolicies reports target references to services that do not exist.
 func analyzeCircuitBreakerPolicies(ctx context.Context, client kube.CLIClient, namespace string, services []corev1.Service) []analyzeMessage {
 	msgs := []analyzeMessage{}
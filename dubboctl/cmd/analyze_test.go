@@ -19,10 +19,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/kdubbo/api/annotation"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func controlPlaneDeployment(replicas int32) appsv1.Deployment {
@@ -170,3 +172,117 @@ func TestAnalyzeHighAvailabilityFlagsSingleReplicaGateway(t *testing.T) {
 		t.Fatalf("single replica gateway not reported: %v", msgs)
 	}
 }
+
+func TestAnalyzeProxylessRouteFiltersFlagsUnsupportedFilters(t *testing.T) {
+	kind := gatewayv1.Kind("Service")
+	route := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "app"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Kind: &kind, Name: "reviews"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{Filters: []gatewayv1.HTTPRouteFilter{
+					{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier},
+					{Type: gatewayv1.HTTPRouteFilterURLRewrite},
+				}},
+				{Filters: []gatewayv1.HTTPRouteFilter{{Type: gatewayv1.HTTPRouteFilterCORS}}},
+			},
+		},
+	}
+
+	msgs := analyzeProxylessRouteFilters(route)
+	if len(msgs) != 1 || !messagesContain(msgs, "rule[1] filter CORS has no proxyless equivalent") {
+		t.Fatalf("expected a single CORS warning, got %+v", msgs)
+	}
+
+	route.Spec.ParentRefs = []gatewayv1.ParentReference{{Name: "dxgate"}}
+	if msgs := analyzeProxylessRouteFilters(route); len(msgs) != 0 {
+		t.Fatalf("gateway-attached routes must not be flagged, got %+v", msgs)
+	}
+}
+
+func TestAnalyzeProxylessRoutesFlagJWTClaimMatches(t *testing.T) {
+	kind := gatewayv1.Kind("Service")
+	meshParent := []gatewayv1.ParentReference{{Kind: &kind, Name: "reviews"}}
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "app"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: meshParent},
+			Rules: []gatewayv1.HTTPRouteRule{{Matches: []gatewayv1.HTTPRouteMatch{
+				{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-plan", Value: "gold"}}},
+				{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "X-JWT-Claim-plan", Value: "gold"}}},
+			}}},
+		},
+	}
+	if msgs := analyzeProxylessRouteFilters(httpRoute); len(msgs) != 1 || !messagesContain(msgs, `rule[0].matches[1] matches JWT claim "plan"`) {
+		t.Fatalf("expected a single claim match warning, got %+v", msgs)
+	}
+
+	grpcRoute := gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeter", Namespace: "app"},
+		Spec: gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: meshParent},
+			Rules: []gatewayv1.GRPCRouteRule{{Matches: []gatewayv1.GRPCRouteMatch{
+				{Headers: []gatewayv1.GRPCHeaderMatch{{Name: "x-jwt-claim-tenant", Value: "acme"}}},
+			}}},
+		},
+	}
+	if msgs := analyzeProxylessGRPCRoute(grpcRoute); len(msgs) != 1 || !messagesContain(msgs, `rule[0].matches[0] matches JWT claim "tenant"`) {
+		t.Fatalf("expected a single claim match warning, got %+v", msgs)
+	}
+
+	grpcRoute.Spec.ParentRefs = []gatewayv1.ParentReference{{Name: "dxgate"}}
+	if msgs := analyzeProxylessGRPCRoute(grpcRoute); len(msgs) != 0 {
+		t.Fatalf("gateway-attached routes must not be flagged, got %+v", msgs)
+	}
+}
+
+func TestAnalyzeDubboRPCConditionsReportsUnenforceableFields(t *testing.T) {
+	injected := func(name string, labels map[string]string) corev1.Pod {
+		pod := runningPod(name, "node-a", labels)
+		pod.Namespace = "app"
+		pod.Annotations = map[string]string{annotation.OrgApacheDubboInherentStatus.Name: "{}"}
+		return pod
+	}
+	plain := runningPod("legacy", "node-a", map[string]string{"app": "legacy"})
+	plain.Namespace = "app"
+	services := []corev1.Service{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "orders",
+			Namespace:   "app",
+			Annotations: map[string]string{dubboInterfacesAnnotation: "shop/org.apache.OrderService:1.0.0"},
+		},
+		Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "orders"}},
+	}}
+	pods := []corev1.Pod{
+		injected("orders", map[string]string{"app": "orders"}),
+		injected("grpc", map[string]string{"app": "grpc"}),
+		plain,
+	}
+	conditions := []dubboRPCCondition{
+		{rule: 0, key: "dubbo.interface", values: []string{"org.apache.UserService"}},
+		{rule: 0, key: "dubbo.version", values: []string{"1.0.0"}},
+	}
+
+	msgs := analyzeDubboRPCConditions("AuthorizationPolicy app/orders", "ALLOW", conditions, pods, services)
+	if len(msgs) != 3 {
+		t.Fatalf("expected three messages, got %+v", msgs)
+	}
+	if !messagesContain(msgs, "dubbo.interface, dubbo.version cannot be enforced on pods without a Dubbo data plane: app/legacy") {
+		t.Fatalf("expected a missing data plane warning, got %+v", msgs)
+	}
+	if !messagesContain(msgs, "dubbo.version cannot be enforced on pods serving no Dubbo interface") ||
+		!messagesContain(msgs, "requests are denied: app/grpc") {
+		t.Fatalf("expected a missing metadata warning, got %+v", msgs)
+	}
+	if !messagesContain(msgs, "matches none of the interfaces served by app/orders (org.apache.OrderService)") {
+		t.Fatalf("expected an unmatched interface note, got %+v", msgs)
+	}
+
+	conditions[0].values = []string{"org.apache.*"}
+	msgs = analyzeDubboRPCConditions("AuthorizationPolicy app/orders", "DENY", conditions, pods[:1], services)
+	if len(msgs) != 0 {
+		t.Fatalf("expected no messages for an enforceable policy, got %+v", msgs)
+	}
+}
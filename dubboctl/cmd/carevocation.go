@@ -0,0 +1,306 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// revocationsConfigMap and its data key match the ones dubbod's CA signs
+	// its revocation list from.
+	revocationsConfigMap = "dubbo-ca-revocations"
+	revocationsKey       = "revocations"
+)
+
+// revocationReasons are the RFC 5280 reasons dubbod accepts.
+var revocationReasons = []string{
+	"unspecified",
+	"keyCompromise",
+	"caCompromise",
+	"affiliationChanged",
+	"superseded",
+	"cessationOfOperation",
+}
+
+// caRevocation mirrors one entry of the revocations ConfigMap.
+type caRevocation struct {
+	SerialNumber string    `json:"serialNumber,omitempty"`
+	Identity     string    `json:"identity,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	RevokedAt    time.Time `json:"revokedAt"`
+}
+
+type revocationArgs struct {
+	serial   string
+	identity string
+	reason   string
+}
+
+func (a *revocationArgs) addFlags(cmd *cobra.Command, withReason bool) {
+	cmd.Flags().StringVar(&a.serial, "serial", "", "Hexadecimal serial number of the certificate, as printed by openssl")
+	cmd.Flags().StringVar(&a.identity, "identity", "", "SPIFFE identity whose certificates are revoked, e.g. spiffe://cluster.local/ns/default/sa/api")
+	if withReason {
+		cmd.Flags().StringVar(&a.reason, "reason", "", "Revocation reason, one of "+strings.Join(revocationReasons, ", "))
+	}
+}
+
+// revocation validates the flags and returns the entry they describe.
+func (a *revocationArgs) revocation(now time.Time) (caRevocation, error) {
+	r := caRevocation{Reason: a.reason, RevokedAt: now.UTC()}
+	switch {
+	case (a.serial == "") == (a.identity == ""):
+		return r, fmt.Errorf("exactly one of --serial and --identity is required")
+	case a.serial != "":
+		serial, err := normalizeSerialNumber(a.serial)
+		if err != nil {
+			return r, err
+		}
+		r.SerialNumber = serial
+	default:
+		if !strings.HasPrefix(a.identity, "spiffe://") {
+			return r, fmt.Errorf("identity %q is not a SPIFFE ID", a.identity)
+		}
+		r.Identity = a.identity
+	}
+	if r.Reason != "" && !slices.Contains(revocationReasons, r.Reason) {
+		return r, fmt.Errorf("unknown revocation reason %q, want one of %s", r.Reason, strings.Join(revocationReasons, ", "))
+	}
+	return r, nil
+}
+
+func normalizeSerialNumber(s string) (string, error) {
+	hex := strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x"), ":", "")
+	serial, ok := new(big.Int).SetString(hex, 16)
+	if !ok || serial.Sign() <= 0 {
+		return "", fmt.Errorf("invalid serial number %q", s)
+	}
+	return serial.Text(16), nil
+}
+
+func caRevokeCmd(ctx cli.Context) *cobra.Command {
+	args := &revocationArgs{}
+	command := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke a workload certificate by serial number, or every certificate of an identity",
+		Long: `dubbod signs a certificate revocation list (CRL) from the revocations and hands it to every
+workload, which then rejects peers presenting a revoked certificate. Revoking an identity also
+stops the CA from issuing new certificates for it. A CRL can only list serial numbers, so it covers
+the certificates of the identity that the running dubbod replicas signed themselves, plus those in
+inherent gRPC workload Secrets. Certificates signed by another replica or before a dubbod restart
+stay valid until they expire; revoke them by serial number.`,
+		Example: `  dubboctl ca revoke --serial 3a:9f:01:c2 --reason keyCompromise
+  dubboctl ca revoke --identity spiffe://cluster.local/ns/default/sa/api`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			revocation, err := args.revocation(time.Now())
+			if err != nil {
+				return err
+			}
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			if err := updateRevocations(cmd.Context(), client.Kube(), ctx.Namespace(), func(revocations []caRevocation) []caRevocation {
+				return append(removeRevocation(revocations, revocation), revocation)
+			}); err != nil {
+				return err
+			}
+			return writeRevoked(cmd.OutOrStdout(), revocation)
+		},
+	}
+	args.addFlags(command, true)
+	return command
+}
+
+func writeRevoked(w io.Writer, revocation caRevocation) error {
+	if _, err := fmt.Fprintf(w, "Revoked %s; dubbod distributes the updated CRL to all workloads\n", revocationTarget(revocation)); err != nil {
+		return err
+	}
+	if revocation.Identity == "" {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, `WARNING: the CRL only lists the certificates of this identity that a running dubbod signed
+since it started, or that are stored in inherent gRPC workload Secrets. Other certificates of the
+identity stay valid until they expire; revoke them with --serial.`)
+	return err
+}
+
+func caUnrevokeCmd(ctx cli.Context) *cobra.Command {
+	args := &revocationArgs{}
+	command := &cobra.Command{
+		Use:   "unrevoke",
+		Short: "Remove a revocation made with dubboctl ca revoke",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			revocation, err := args.revocation(time.Now())
+			if err != nil {
+				return err
+			}
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			found := false
+			if err := updateRevocations(cmd.Context(), client.Kube(), ctx.Namespace(), func(revocations []caRevocation) []caRevocation {
+				out := removeRevocation(revocations, revocation)
+				found = len(out) != len(revocations)
+				return out
+			}); err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("%s is not revoked", revocationTarget(revocation))
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Removed the revocation of %s\n", revocationTarget(revocation))
+			return err
+		},
+	}
+	args.addFlags(command, false)
+	return command
+}
+
+func caRevocationsCmd(ctx cli.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revocations",
+		Short: "List the certificates and identities revoked in dubbod's CA",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			revocations, err := getRevocations(cmd.Context(), client.Kube(), ctx.Namespace())
+			if err != nil {
+				return err
+			}
+			return printRevocations(cmd.OutOrStdout(), revocations)
+		},
+	}
+}
+
+func revocationTarget(r caRevocation) string {
+	if r.Identity != "" {
+		return "identity " + r.Identity
+	}
+	return "serial number " + r.SerialNumber
+}
+
+func removeRevocation(revocations []caRevocation, r caRevocation) []caRevocation {
+	out := make([]caRevocation, 0, len(revocations))
+	for _, existing := range revocations {
+		if existing.Identity == r.Identity && existing.SerialNumber == r.SerialNumber {
+			continue
+		}
+		out = append(out, existing)
+	}
+	return out
+}
+
+func getRevocations(ctx context.Context, client kubernetes.Interface, namespace string) ([]caRevocation, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, revocationsConfigMap, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s: %v", namespace, revocationsConfigMap, err)
+	}
+	return parseRevocations(cm)
+}
+
+func parseRevocations(cm *corev1.ConfigMap) ([]caRevocation, error) {
+	raw := cm.Data[revocationsKey]
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var revocations []caRevocation
+	if err := json.Unmarshal([]byte(raw), &revocations); err != nil {
+		return nil, fmt.Errorf("failed to parse %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	return revocations, nil
+}
+
+// updateRevocations applies update to the revocations ConfigMap, creating it
+// when missing.
+func updateRevocations(ctx context.Context, client kubernetes.Interface, namespace string, update func([]caRevocation) []caRevocation) error {
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(ctx, revocationsConfigMap, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get %s/%s: %v", namespace, revocationsConfigMap, err)
+	}
+	create := kerrors.IsNotFound(err)
+	if create {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: revocationsConfigMap, Namespace: namespace}}
+	}
+	revocations, err := parseRevocations(cm)
+	if err != nil {
+		return err
+	}
+	revocations = update(revocations)
+	sort.Slice(revocations, func(i, j int) bool {
+		return revocationTarget(revocations[i]) < revocationTarget(revocations[j])
+	})
+	data, err := json.MarshalIndent(revocations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	cm = cm.DeepCopy()
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[revocationsKey] = string(data)
+	if create {
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	} else {
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+func printRevocations(w io.Writer, revocations []caRevocation) error {
+	if len(revocations) == 0 {
+		_, err := fmt.Fprintln(w, "No certificates are revoked")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "REVOKED\tREASON\tREVOKED AT")
+	for _, r := range revocations {
+		reason := r.Reason
+		if reason == "" {
+			reason = "unspecified"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", revocationTarget(r), reason, r.RevokedAt.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,106 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRevocationArgs(t *testing.T) {
+	now := time.Unix(100, 0)
+	r, err := (&revocationArgs{serial: "0A:FF", reason: "keyCompromise"}).revocation(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.SerialNumber != "aff" || r.Reason != "keyCompromise" || !r.RevokedAt.Equal(now) {
+		t.Fatalf("unexpected revocation %+v", r)
+	}
+	for _, bad := range []revocationArgs{
+		{},
+		{serial: "1", identity: "spiffe://cluster.local/ns/a/sa/b"},
+		{serial: "zz"},
+		{identity: "cluster.local/ns/a/sa/b"},
+		{serial: "1", reason: "lost"},
+	} {
+		if _, err := bad.revocation(now); err == nil {
+			t.Fatalf("revocation(%+v) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestWriteRevokedWarnsAboutIdentities(t *testing.T) {
+	var out strings.Builder
+	if err := writeRevoked(&out, caRevocation{SerialNumber: "aff"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "WARNING") {
+		t.Fatalf("serial revocation printed a warning:\n%s", out.String())
+	}
+	out.Reset()
+	if err := writeRevoked(&out, caRevocation{Identity: "spiffe://cluster.local/ns/default/sa/api"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "WARNING") || !strings.Contains(out.String(), "--serial") {
+		t.Fatalf("identity revocation printed no warning:\n%s", out.String())
+	}
+}
+
+func TestUpdateRevocations(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	identity := caRevocation{Identity: "spiffe://cluster.local/ns/default/sa/api", RevokedAt: time.Unix(100, 0).UTC()}
+	serial := caRevocation{SerialNumber: "aff", Reason: "superseded", RevokedAt: time.Unix(200, 0).UTC()}
+
+	for _, r := range []caRevocation{serial, identity, serial} {
+		if err := updateRevocations(ctx, client, "dubbo-system", func(revocations []caRevocation) []caRevocation {
+			return append(removeRevocation(revocations, r), r)
+		}); err != nil {
+			t.Fatalf("updateRevocations() error = %v", err)
+		}
+	}
+	revocations, err := getRevocations(ctx, client, "dubbo-system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revocations) != 2 || revocations[0].Identity != identity.Identity || revocations[1].SerialNumber != "aff" {
+		t.Fatalf("revocations = %+v, want the identity and the serial once", revocations)
+	}
+
+	var out strings.Builder
+	if err := printRevocations(&out, revocations); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"identity spiffe://cluster.local/ns/default/sa/api", "serial number aff", "superseded", "unspecified"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if err := updateRevocations(ctx, client, "dubbo-system", func(revocations []caRevocation) []caRevocation {
+		return removeRevocation(revocations, identity)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if revocations, _ := getRevocations(ctx, client, "dubbo-system"); len(revocations) != 1 {
+		t.Fatalf("revocations after removal = %+v, want 1", revocations)
+	}
+}
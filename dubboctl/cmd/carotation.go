@@ -0,0 +1,232 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// rootRotationStatusConfigMap, its data key and the request annotation
+	// match the ones dubbod's self-signed CA publishes in its namespace.
+	rootRotationStatusConfigMap   = "dubbo-ca-root-rotation"
+	rootRotationStatusKey         = "status"
+	rootRotationRequestAnnotation = "dubbo.apache.org/root-rotation-requested"
+)
+
+// rootRotationPhases lists the rotation phases in order, with what each one
+// waits for before dubbod moves on.
+var rootRotationPhases = []struct {
+	name        string
+	description string
+}{
+	{"Idle", "no rotation in progress"},
+	{"DistributingBundle", "old and new roots trusted, old root signs; waits for every workload to get the bundle"},
+	{"ReissuingCertificates", "new root signs; waits for every workload certificate to be re-issued"},
+	{"RetiringOldRoot", "old root dropped from the bundle; waits for every workload to get the new bundle"},
+}
+
+// rootRotationStatus mirrors the JSON dubbod writes to the status ConfigMap.
+type rootRotationStatus struct {
+	Phase              string     `json:"phase"`
+	Reason             string     `json:"reason,omitempty"`
+	RotationStartedAt  *time.Time `json:"rotationStartedAt,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+	LastCompletedAt    *time.Time `json:"lastCompletedAt,omitempty"`
+	RootExpiresAt      *time.Time `json:"rootExpiresAt,omitempty"`
+	NextRootExpiresAt  *time.Time `json:"nextRootExpiresAt,omitempty"`
+	PendingWorkloads   int        `json:"pendingWorkloads"`
+	Pending            []string   `json:"pending,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	HandledRequest     string     `json:"handledRequest,omitempty"`
+}
+
+// CACmd groups commands operating on dubbod's built-in certificate authority.
+func CACmd(ctx cli.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "ca",
+		Short: "Inspect and operate dubbod's certificate authority",
+	}
+	command.AddCommand(caRotationCmd(ctx))
+	command.AddCommand(caRevokeCmd(ctx))
+	command.AddCommand(caUnrevokeCmd(ctx))
+	command.AddCommand(caRevocationsCmd(ctx))
+	return command
+}
+
+func caRotationCmd(ctx cli.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "rotation",
+		Short: "Inspect or start a staged rotation of the self-signed root certificate",
+		Long: `dubbod rotates its self-signed root in stages so that no workload ever meets a peer
+certificate it cannot verify: the new root is first distributed next to the old one,
+then every certificate is re-issued under it, and only then is the old root retired.
+Each stage waits until every workload has converged.`,
+	}
+	command.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the current root rotation phase and the workloads it waits for",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			status, request, err := getRootRotationStatus(cmd.Context(), client.Kube(), ctx.Namespace())
+			if err != nil {
+				return err
+			}
+			return printRootRotationStatus(cmd.OutOrStdout(), status, request)
+		},
+	})
+	command.AddCommand(&cobra.Command{
+		Use:   "start",
+		Short: "Ask dubbod to rotate the root certificate on its next check",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			if err := requestRootRotation(cmd.Context(), client.Kube(), ctx.Namespace(), time.Now()); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), "Root rotation requested; dubbod starts it on its next root cert check")
+			return err
+		},
+	})
+	return command
+}
+
+func getRootRotationStatus(ctx context.Context, client kubernetes.Interface, namespace string) (*rootRotationStatus, string, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, rootRotationStatusConfigMap, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return &rootRotationStatus{Phase: "Idle"}, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get %s/%s: %v", namespace, rootRotationStatusConfigMap, err)
+	}
+	status, err := parseRootRotationStatus(cm)
+	if err != nil {
+		return nil, "", err
+	}
+	return status, cm.Annotations[rootRotationRequestAnnotation], nil
+}
+
+func parseRootRotationStatus(cm *corev1.ConfigMap) (*rootRotationStatus, error) {
+	status := &rootRotationStatus{Phase: "Idle"}
+	raw := cm.Data[rootRotationStatusKey]
+	if raw == "" {
+		return status, nil
+	}
+	if err := json.Unmarshal([]byte(raw), status); err != nil {
+		return nil, fmt.Errorf("failed to parse %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	return status, nil
+}
+
+// requestRootRotation stamps the request annotation with a new value; dubbod
+// acts on every value it has not handled before.
+func requestRootRotation(ctx context.Context, client kubernetes.Interface, namespace string, now time.Time) error {
+	request := strconv.FormatInt(now.Unix(), 10)
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(ctx, rootRotationStatusConfigMap, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        rootRotationStatusConfigMap,
+				Namespace:   namespace,
+				Annotations: map[string]string{rootRotationRequestAnnotation: request},
+			},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if status, err := parseRootRotationStatus(cm); err == nil && status.Phase != "" && status.Phase != "Idle" {
+		return fmt.Errorf("a root rotation is already in phase %s", status.Phase)
+	}
+	cm = cm.DeepCopy()
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[rootRotationRequestAnnotation] = request
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+func printRootRotationStatus(w io.Writer, status *rootRotationStatus, request string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "PHASE:\t%s\n", status.Phase)
+	if status.Reason != "" && status.Phase != "Idle" {
+		fmt.Fprintf(tw, "REASON:\t%s\n", status.Reason)
+	}
+	printRootRotationTime(tw, "STARTED:", status.RotationStartedAt)
+	printRootRotationTime(tw, "LAST TRANSITION:", status.LastTransitionTime)
+	printRootRotationTime(tw, "LAST COMPLETED:", status.LastCompletedAt)
+	printRootRotationTime(tw, "ROOT EXPIRES:", status.RootExpiresAt)
+	printRootRotationTime(tw, "NEXT ROOT EXPIRES:", status.NextRootExpiresAt)
+	if request != "" && request != status.HandledRequest {
+		fmt.Fprintf(tw, "REQUESTED:\tpending pickup by dubbod\n")
+	}
+	if status.Message != "" {
+		fmt.Fprintf(tw, "MESSAGE:\t%s\n", status.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nPHASES:")
+	for _, phase := range rootRotationPhases {
+		marker := " "
+		if phase.name == status.Phase {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "  %s %-22s %s\n", marker, phase.name, phase.description)
+	}
+
+	if status.PendingWorkloads > 0 {
+		fmt.Fprintf(w, "\nPENDING (%d):\n", status.PendingWorkloads)
+		for _, pending := range status.Pending {
+			fmt.Fprintf(w, "  %s\n", pending)
+		}
+		if more := status.PendingWorkloads - len(status.Pending); more > 0 {
+			fmt.Fprintf(w, "  ... and %d more\n", more)
+		}
+	}
+	return nil
+}
+
+func printRootRotationTime(w io.Writer, label string, t *time.Time) {
+	if t != nil {
+		fmt.Fprintf(w, "%s\t%s\n", label, t.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,95 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPrintRootRotationStatus(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	status := &rootRotationStatus{
+		Phase:             "ReissuingCertificates",
+		Reason:            "RootExpiring",
+		RotationStartedAt: &started,
+		PendingWorkloads:  3,
+		Pending:           []string{"pod default/a", "configmap demo/dubbo-ca-root-cert"},
+		Message:           "waiting for 3 workloads before leaving phase ReissuingCertificates",
+	}
+	var out strings.Builder
+	if err := printRootRotationStatus(&out, status, ""); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"PHASE:",
+		"RootExpiring",
+		"2026-01-02T03:04:05Z",
+		"* ReissuingCertificates",
+		"  DistributingBundle",
+		"PENDING (3):",
+		"pod default/a",
+		"... and 1 more",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRequestRootRotation(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	now := time.Unix(100, 0)
+
+	if err := requestRootRotation(ctx, client, "dubbo-system", now); err != nil {
+		t.Fatalf("requestRootRotation() error = %v", err)
+	}
+	status, request, err := getRootRotationStatus(ctx, client, "dubbo-system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Phase != "Idle" || request != "100" {
+		t.Fatalf("status = %+v request = %q, want Idle and 100", status, request)
+	}
+
+	cm, _ := client.CoreV1().ConfigMaps("dubbo-system").Get(ctx, rootRotationStatusConfigMap, metav1.GetOptions{})
+	cm.Data = map[string]string{rootRotationStatusKey: `{"phase":"DistributingBundle","handledRequest":"100"}`}
+	if _, err := client.CoreV1().ConfigMaps("dubbo-system").Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := requestRootRotation(ctx, client, "dubbo-system", now.Add(time.Minute)); err == nil {
+		t.Fatal("requestRootRotation() during a rotation = nil, want error")
+	}
+}
+
+func TestParseRootRotationStatusRejectsMalformedStatus(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: rootRotationStatusConfigMap, Namespace: "dubbo-system"},
+		Data:       map[string]string{rootRotationStatusKey: "{"},
+	}
+	if _, err := parseRootRotationStatus(cm); err == nil {
+		t.Fatal("parseRootRotationStatus() = nil error, want error")
+	}
+}
@@ -0,0 +1,451 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	proxyConfigOutputTable = "table"
+	proxyConfigOutputJSON  = "json"
+	proxyConfigOutputYAML  = "yaml"
+)
+
+type proxyConfigArgs struct {
+	namespace string
+	output    string
+	cluster   string
+	route     string
+}
+
+// proxyConfigDump mirrors the JSON emitted by dubbod's /debug/config_dump
+// endpoint. Resources are kept as generic JSON: the table views only read a
+// handful of fields and -o json/yaml print them untouched.
+type proxyConfigDump struct {
+	ProxyID   string           `json:"proxy"`
+	Listeners []map[string]any `json:"listeners,omitempty"`
+	Routes    []map[string]any `json:"routes,omitempty"`
+	Clusters  []map[string]any `json:"clusters,omitempty"`
+	Endpoints []map[string]any `json:"endpoints,omitempty"`
+}
+
+// ProxyConfigCmd shows the xDS configuration dubbod generates for one
+// proxyless workload, based on the /debug/config_dump endpoint.
+func ProxyConfigCmd(ctx cli.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:     "proxy-config",
+		Aliases: []string{"pc"},
+		Short:   "Retrieves the xDS configuration dubbod generates for a workload",
+		Long: `Retrieves the listeners, routes, clusters and endpoints dubbod generates for a
+proxyless workload connected to it. The configuration is generated on request from
+dubbod's current state, so it shows what the workload would receive on its next push.`,
+	}
+	command.AddCommand(proxyConfigTypeCmd(ctx, "listeners", []string{"listener", "l"}, "Retrieves listener configuration for a workload"))
+	command.AddCommand(proxyConfigTypeCmd(ctx, "routes", []string{"route", "r"}, "Retrieves route configuration for a workload"))
+	command.AddCommand(proxyConfigTypeCmd(ctx, "clusters", []string{"cluster", "c"}, "Retrieves cluster configuration for a workload"))
+	command.AddCommand(proxyConfigTypeCmd(ctx, "endpoints", []string{"endpoint", "ep"}, "Retrieves endpoint configuration for a workload"))
+	return command
+}
+
+func proxyConfigTypeCmd(ctx cli.Context, resourceType string, aliases []string, short string) *cobra.Command {
+	args := &proxyConfigArgs{namespace: metav1.NamespaceDefault, output: proxyConfigOutputTable}
+	command := &cobra.Command{
+		Use:     resourceType + " <pod-name[.namespace]>",
+		Aliases: aliases,
+		Short:   short,
+		Example: fmt.Sprintf(`  # Show the %[1]s of a pod in the default namespace
+  dubboctl proxy-config %[1]s productpage-v1-7f8d9c
+
+  # Show them as YAML for a pod in another namespace
+  dubboctl proxy-config %[1]s reviews-v2-5c7b.bookinfo -o yaml`, resourceType),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, positional []string) error {
+			if err := validateProxyConfigOutput(args.output); err != nil {
+				return err
+			}
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create Kubernetes client: %v", err)
+			}
+			proxyID := proxyIDForPod(positional[0], args.namespace)
+			dump, err := fetchProxyConfigDump(cmd.Context(), client, ctx.Namespace(), proxyID, resourceType)
+			if err != nil {
+				return err
+			}
+			filterProxyConfigDump(dump, args.cluster, args.route)
+			return writeProxyConfig(cmd.OutOrStdout(), dump, resourceType, args.output)
+		},
+	}
+	flags := command.Flags()
+	flags.StringVarP(&args.namespace, "namespace", "n", args.namespace, "Namespace of the pod, unless given as <pod-name>.<namespace>")
+	flags.StringVarP(&args.output, "output", "o", args.output, "Output format: table, json or yaml")
+	switch resourceType {
+	case "clusters", "endpoints":
+		flags.StringVar(&args.cluster, "cluster", "", "Only show entries for this cluster name")
+	case "routes":
+		flags.StringVar(&args.route, "name", "", "Only show the route configuration or route with this name")
+	}
+	return command
+}
+
+// proxyIDForPod builds the xDS node ID proxyless workloads connect with,
+// which is "<pod>.<namespace>".
+func proxyIDForPod(arg, namespace string) string {
+	if strings.Contains(arg, ".") {
+		return arg
+	}
+	return arg + "." + namespace
+}
+
+func validateProxyConfigOutput(output string) error {
+	switch output {
+	case proxyConfigOutputTable, proxyConfigOutputJSON, proxyConfigOutputYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, want table, json or yaml", output)
+}
+
+// fetchProxyConfigDump asks every running dubbod for the proxy's config; only
+// the instance the proxy is connected to can generate it.
+func fetchProxyConfigDump(ctx context.Context, client kube.CLIClient, namespace, proxyID, resourceType string) (*proxyConfigDump, error) {
+	pods, err := client.Kube().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=dubbod",
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dubbod pods in namespace %q: %v", namespace, err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no running dubbod pods found in namespace %q; is the control plane installed?", namespace)
+	}
+	path := "/debug/config_dump?" + url.Values{"proxyID": {proxyID}, "type": {resourceType}}.Encode()
+	var errs []string
+	for _, pod := range pods.Items {
+		data, err := proxyGetDebugEndpoint(ctx, client, pod, path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		return parseProxyConfigDump(data)
+	}
+	return nil, fmt.Errorf("proxy %q not found on any dubbod instance: %s", proxyID, strings.Join(errs, "; "))
+}
+
+func parseProxyConfigDump(data []byte) (*proxyConfigDump, error) {
+	dump := &proxyConfigDump{}
+	if err := json.Unmarshal(data, dump); err != nil {
+		return nil, fmt.Errorf("failed to parse dubbod config dump: %v", err)
+	}
+	return dump, nil
+}
+
+// filterProxyConfigDump keeps the clusters and endpoints named cluster, and the
+// route configurations named route. A route configuration that does not match
+// by name is narrowed to its routes that do.
+func filterProxyConfigDump(dump *proxyConfigDump, cluster, route string) {
+	if cluster != "" {
+		dump.Clusters = filterByString(dump.Clusters, "name", cluster)
+		dump.Endpoints = filterByString(dump.Endpoints, "clusterName", cluster)
+	}
+	if route == "" {
+		return
+	}
+	routes := make([]map[string]any, 0, len(dump.Routes))
+	for _, rc := range dump.Routes {
+		if jsonString(rc, "name") == route {
+			routes = append(routes, rc)
+			continue
+		}
+		var vhosts []any
+		for _, vh := range jsonSlice(rc, "virtualHosts") {
+			vhost, ok := vh.(map[string]any)
+			if !ok {
+				continue
+			}
+			var kept []any
+			for _, r := range jsonSlice(vhost, "routes") {
+				if m, ok := r.(map[string]any); ok && jsonString(m, "name") == route {
+					kept = append(kept, r)
+				}
+			}
+			if len(kept) > 0 {
+				narrowed := shallowCopyMap(vhost)
+				narrowed["routes"] = kept
+				vhosts = append(vhosts, narrowed)
+			}
+		}
+		if len(vhosts) > 0 {
+			narrowed := shallowCopyMap(rc)
+			narrowed["virtualHosts"] = vhosts
+			routes = append(routes, narrowed)
+		}
+	}
+	dump.Routes = routes
+}
+
+func writeProxyConfig(out io.Writer, dump *proxyConfigDump, resourceType, output string) error {
+	var resources []map[string]any
+	switch resourceType {
+	case "listeners":
+		resources = dump.Listeners
+	case "routes":
+		resources = dump.Routes
+	case "clusters":
+		resources = dump.Clusters
+	case "endpoints":
+		resources = dump.Endpoints
+	}
+	if resources == nil {
+		resources = []map[string]any{}
+	}
+	switch output {
+	case proxyConfigOutputJSON:
+		raw, err := json.MarshalIndent(resources, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(raw))
+		return err
+	case proxyConfigOutputYAML:
+		raw, err := yaml.Marshal(resources)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(raw)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 4, ' ', 0)
+	switch resourceType {
+	case "listeners":
+		fmt.Fprintln(w, "NAME\tADDRESS\tROUTE")
+		for _, l := range resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", jsonString(l, "name"), socketAddress(jsonMap(l, "address")), listenerRouteName(l))
+		}
+	case "routes":
+		fmt.Fprintln(w, "NAME\tVIRTUAL HOST\tDOMAINS\tMATCH\tDESTINATION")
+		for _, rc := range resources {
+			for _, vh := range jsonSlice(rc, "virtualHosts") {
+				vhost, ok := vh.(map[string]any)
+				if !ok {
+					continue
+				}
+				for _, r := range jsonSlice(vhost, "routes") {
+					m, ok := r.(map[string]any)
+					if !ok {
+						continue
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", jsonString(rc, "name"), jsonString(vhost, "name"),
+						joinStrings(jsonSlice(vhost, "domains")), routeMatch(jsonMap(m, "match")), routeDestination(jsonMap(m, "route")))
+				}
+			}
+		}
+	case "clusters":
+		fmt.Fprintln(w, "SERVICE FQDN\tPORT\tSUBSET\tDIRECTION\tTYPE")
+		for _, c := range resources {
+			name := jsonString(c, "name")
+			direction, port, subset, hostname := splitClusterName(name)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", hostname, port, subset, direction, jsonString(c, "type"))
+		}
+	case "endpoints":
+		fmt.Fprintln(w, "ENDPOINT\tSTATUS\tLOCALITY\tWEIGHT\tCLUSTER")
+		for _, cla := range resources {
+			for _, le := range jsonSlice(cla, "endpoints") {
+				locality, ok := le.(map[string]any)
+				if !ok {
+					continue
+				}
+				for _, e := range jsonSlice(locality, "lbEndpoints") {
+					ep, ok := e.(map[string]any)
+					if !ok {
+						continue
+					}
+					status := jsonString(ep, "healthStatus")
+					if status == "" {
+						status = "UNKNOWN"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", socketAddress(jsonMap(jsonMap(ep, "endpoint"), "address")), status,
+						localityString(jsonMap(locality, "locality")), jsonScalar(ep, "loadBalancingWeight"), jsonString(cla, "clusterName"))
+				}
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// splitClusterName splits "direction|port|subset|hostname" cluster names;
+// anything else is shown whole in the FQDN column.
+func splitClusterName(name string) (direction, port, subset, hostname string) {
+	parts := strings.Split(name, "|")
+	if len(parts) != 4 {
+		return "-", "-", "-", name
+	}
+	subset = parts[2]
+	if subset == "" {
+		subset = "-"
+	}
+	return parts[0], parts[1], subset, parts[3]
+}
+
+func listenerRouteName(listener map[string]any) string {
+	hcm := jsonMap(jsonMap(listener, "apiListener"), "apiListener")
+	if name := jsonString(jsonMap(hcm, "rds"), "routeConfigName"); name != "" {
+		return name
+	}
+	if rc := jsonMap(hcm, "routeConfig"); rc != nil {
+		return "inline: " + jsonString(rc, "name")
+	}
+	return "-"
+}
+
+func routeMatch(match map[string]any) string {
+	var parts []string
+	for _, key := range []string{"prefix", "path", "pathSeparatedPrefix"} {
+		if v, ok := match[key].(string); ok {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	if regex := jsonString(jsonMap(match, "safeRegex"), "regex"); regex != "" {
+		parts = append(parts, "regex="+regex)
+	}
+	for _, h := range jsonSlice(match, "headers") {
+		header, ok := h.(map[string]any)
+		if !ok {
+			continue
+		}
+		value := jsonString(header, "exactMatch")
+		if value == "" {
+			value = jsonString(jsonMap(header, "stringMatch"), "exact")
+		}
+		parts = append(parts, "header:"+jsonString(header, "name")+"="+value)
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+func routeDestination(action map[string]any) string {
+	if cluster := jsonString(action, "cluster"); cluster != "" {
+		return cluster
+	}
+	var clusters []string
+	for _, c := range jsonSlice(jsonMap(action, "weightedClusters"), "clusters") {
+		if wc, ok := c.(map[string]any); ok {
+			clusters = append(clusters, fmt.Sprintf("%s(%s)", jsonString(wc, "name"), jsonScalar(wc, "weight")))
+		}
+	}
+	if len(clusters) == 0 {
+		return "-"
+	}
+	return strings.Join(clusters, ",")
+}
+
+func socketAddress(address map[string]any) string {
+	socket := jsonMap(address, "socketAddress")
+	if socket == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s:%s", jsonString(socket, "address"), jsonScalar(socket, "portValue"))
+}
+
+func localityString(locality map[string]any) string {
+	var parts []string
+	for _, key := range []string{"region", "zone", "subZone"} {
+		if v := jsonString(locality, key); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, "/")
+}
+
+func filterByString(resources []map[string]any, key, want string) []map[string]any {
+	out := make([]map[string]any, 0, len(resources))
+	for _, r := range resources {
+		if jsonString(r, key) == want {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func jsonMap(obj map[string]any, key string) map[string]any {
+	if obj == nil {
+		return nil
+	}
+	m, _ := obj[key].(map[string]any)
+	return m
+}
+
+func jsonSlice(obj map[string]any, key string) []any {
+	if obj == nil {
+		return nil
+	}
+	s, _ := obj[key].([]any)
+	return s
+}
+
+func jsonString(obj map[string]any, key string) string {
+	if obj == nil {
+		return ""
+	}
+	s, _ := obj[key].(string)
+	return s
+}
+
+// jsonScalar renders numbers and wrapped values, which protojson emits either
+// as JSON numbers or, for 64-bit and wrapper types, as strings.
+func jsonScalar(obj map[string]any, key string) string {
+	if obj == nil || obj[key] == nil {
+		return "-"
+	}
+	return fmt.Sprint(obj[key])
+}
+
+func joinStrings(values []any) string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func shallowCopyMap(in map[string]any) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
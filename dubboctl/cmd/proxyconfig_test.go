@@ -0,0 +1,140 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testProxyConfigDump = `{
+  "proxy": "reviews-v1.default",
+  "listeners": [{
+    "name": "reviews.default.svc.cluster.local:9080",
+    "apiListener": {"apiListener": {"rds": {"routeConfigName": "reviews.default.svc.cluster.local:9080"}}}
+  }],
+  "routes": [{
+    "name": "reviews.default.svc.cluster.local:9080",
+    "virtualHosts": [{
+      "name": "reviews",
+      "domains": ["reviews.default.svc.cluster.local"],
+      "routes": [
+        {"name": "v2", "match": {"prefix": "/v2"}, "route": {"cluster": "outbound|9080|v2|reviews.default.svc.cluster.local"}},
+        {"name": "default", "match": {"prefix": "/"}, "route": {"weightedClusters": {"clusters": [
+          {"name": "outbound|9080|v1|reviews.default.svc.cluster.local", "weight": 90},
+          {"name": "outbound|9080|v2|reviews.default.svc.cluster.local", "weight": 10}
+        ]}}}
+      ]
+    }]
+  }],
+  "clusters": [
+    {"name": "outbound|9080|v1|reviews.default.svc.cluster.local", "type": "EDS"},
+    {"name": "outbound|9080||ratings.default.svc.cluster.local", "type": "EDS"}
+  ],
+  "endpoints": [
+    {"clusterName": "outbound|9080|v1|reviews.default.svc.cluster.local", "endpoints": [{
+      "locality": {"region": "us-east", "zone": "a"},
+      "lbEndpoints": [{"endpoint": {"address": {"socketAddress": {"address": "10.0.0.5", "portValue": 9080}}}, "healthStatus": "HEALTHY", "loadBalancingWeight": 1}]
+    }]},
+    {"clusterName": "outbound|9080||ratings.default.svc.cluster.local", "endpoints": [{
+      "lbEndpoints": [{"endpoint": {"address": {"socketAddress": {"address": "10.0.0.9", "portValue": 9080}}}}]
+    }]}
+  ]
+}`
+
+func TestProxyIDForPod(t *testing.T) {
+	if got := proxyIDForPod("reviews-v1", "default"); got != "reviews-v1.default" {
+		t.Fatalf("proxyIDForPod() = %q, want reviews-v1.default", got)
+	}
+	if got := proxyIDForPod("reviews-v1.bookinfo", "default"); got != "reviews-v1.bookinfo" {
+		t.Fatalf("proxyIDForPod() = %q, want reviews-v1.bookinfo", got)
+	}
+}
+
+func TestFilterProxyConfigDumpByClusterAndRoute(t *testing.T) {
+	dump, err := parseProxyConfigDump([]byte(testProxyConfigDump))
+	if err != nil {
+		t.Fatalf("parseProxyConfigDump() error = %v", err)
+	}
+
+	filterProxyConfigDump(dump, "outbound|9080||ratings.default.svc.cluster.local", "v2")
+	if len(dump.Clusters) != 1 || jsonString(dump.Clusters[0], "name") != "outbound|9080||ratings.default.svc.cluster.local" {
+		t.Fatalf("clusters = %v, want only ratings", dump.Clusters)
+	}
+	if len(dump.Endpoints) != 1 || jsonString(dump.Endpoints[0], "clusterName") != "outbound|9080||ratings.default.svc.cluster.local" {
+		t.Fatalf("endpoints = %v, want only ratings", dump.Endpoints)
+	}
+	if len(dump.Routes) != 1 {
+		t.Fatalf("routes = %v, want the route configuration narrowed to v2", dump.Routes)
+	}
+	routes := jsonSlice(jsonSlice(dump.Routes[0], "virtualHosts")[0].(map[string]any), "routes")
+	if len(routes) != 1 || jsonString(routes[0].(map[string]any), "name") != "v2" {
+		t.Fatalf("routes = %v, want only v2", routes)
+	}
+}
+
+func TestWriteProxyConfigTables(t *testing.T) {
+	dump, err := parseProxyConfigDump([]byte(testProxyConfigDump))
+	if err != nil {
+		t.Fatalf("parseProxyConfigDump() error = %v", err)
+	}
+
+	cases := map[string][]string{
+		"listeners": {"reviews.default.svc.cluster.local:9080", "ROUTE"},
+		"routes":    {"prefix=/v2", "outbound|9080|v1|reviews.default.svc.cluster.local(90)"},
+		"clusters":  {"reviews.default.svc.cluster.local    9080    v1        outbound", "ratings.default.svc.cluster.local    9080    -"},
+		"endpoints": {"10.0.0.5:9080", "HEALTHY", "us-east/a", "10.0.0.9:9080    UNKNOWN"},
+	}
+	for resourceType, want := range cases {
+		var out bytes.Buffer
+		if err := writeProxyConfig(&out, dump, resourceType, proxyConfigOutputTable); err != nil {
+			t.Fatalf("writeProxyConfig(%s) error = %v", resourceType, err)
+		}
+		for _, w := range want {
+			if !strings.Contains(out.String(), w) {
+				t.Fatalf("%s output missing %q:\n%s", resourceType, w, out.String())
+			}
+		}
+	}
+}
+
+func TestWriteProxyConfigStructuredOutput(t *testing.T) {
+	dump, err := parseProxyConfigDump([]byte(testProxyConfigDump))
+	if err != nil {
+		t.Fatalf("parseProxyConfigDump() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := writeProxyConfig(&out, dump, "clusters", proxyConfigOutputYAML); err != nil {
+		t.Fatalf("writeProxyConfig(yaml) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "- name: outbound|9080|v1|reviews.default.svc.cluster.local") {
+		t.Fatalf("yaml output = %s", out.String())
+	}
+
+	out.Reset()
+	if err := writeProxyConfig(&out, &proxyConfigDump{}, "routes", proxyConfigOutputJSON); err != nil {
+		t.Fatalf("writeProxyConfig(json) error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Fatalf("json output = %q, want []", out.String())
+	}
+	if err := validateProxyConfigOutput("wide"); err == nil {
+		t.Fatal("validateProxyConfigOutput(wide) error = nil, want error")
+	}
+}
@@ -66,8 +66,12 @@ func GetRootCmd(args []string) *cobra.Command {
 
 	rootCmd.AddCommand(GetCmd(ctx))
 	rootCmd.AddCommand(ProxyStatusCmd(ctx))
+	rootCmd.AddCommand(ProxyConfigCmd(ctx))
 	rootCmd.AddCommand(AnalyzeCmd(ctx))
 	rootCmd.AddCommand(MulticlusterCmd())
+	rootCmd.AddCommand(TagCmd(ctx))
+	rootCmd.AddCommand(CACmd(ctx))
+	rootCmd.AddCommand(SecurityCmd(ctx))
 
 	rootCmd.AddCommand(GuiCmd())
 
@@ -0,0 +1,167 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mtlsReadinessPath matches the endpoint dubbod serves the report on.
+const mtlsReadinessPath = "/debug/mtlsz"
+
+// SecurityCmd groups commands inspecting mesh security settings.
+func SecurityCmd(ctx cli.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "security",
+		Short: "Inspect mesh security settings",
+	}
+	command.AddCommand(mtlsReadinessCmd(ctx))
+	return command
+}
+
+func mtlsReadinessCmd(ctx cli.Context) *cobra.Command {
+	var namespace string
+	command := &cobra.Command{
+		Use:   "mtls-readiness",
+		Short: "List the callers that still use plaintext on PERMISSIVE service ports",
+		Long: `Proxyless workloads serving a PERMISSIVE port count the connections they accept by
+transport. dubbod collects those counters; this command lists, per service port,
+the callers that connected in plaintext and the ones that used mTLS since the
+workloads started. A port is ready for STRICT once every workload behind it
+reported and none of them saw a plaintext caller.`,
+		Example: `  # Check every PERMISSIVE service port
+  dubboctl security mtls-readiness
+
+  # Check the services of one namespace
+  dubboctl security mtls-readiness -n backend`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create Kubernetes client: %v", err)
+			}
+			report, err := fetchMTLSReadiness(cmd.Context(), client, ctx.Namespace())
+			if err != nil {
+				return err
+			}
+			return writeMTLSReadiness(cmd.OutOrStdout(), filterMTLSReadiness(report, namespace))
+		},
+	}
+	command.Flags().StringVarP(&namespace, "namespace", "n", metav1.NamespaceAll, "Only report services in this namespace")
+	return command
+}
+
+// fetchMTLSReadiness reads the report from the first dubbod pod that answers;
+// every replica collects from the same workloads.
+func fetchMTLSReadiness(ctx context.Context, client kube.CLIClient, namespace string) (securityconfig.MTLSReadinessReport, error) {
+	pods, err := client.Kube().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=dubbod",
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return securityconfig.MTLSReadinessReport{}, fmt.Errorf("failed to list dubbod pods in namespace %q: %v", namespace, err)
+	}
+	if len(pods.Items) == 0 {
+		return securityconfig.MTLSReadinessReport{}, fmt.Errorf("no running dubbod pods found in namespace %q; is the control plane installed?", namespace)
+	}
+	var errs []string
+	for _, pod := range pods.Items {
+		data, err := proxyGetDebugEndpoint(ctx, client, pod, mtlsReadinessPath)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		report := securityconfig.MTLSReadinessReport{}
+		if err := json.Unmarshal(data, &report); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		return report, nil
+	}
+	return securityconfig.MTLSReadinessReport{}, fmt.Errorf("failed to fetch mTLS readiness from dubbod: %s", strings.Join(errs, "; "))
+}
+
+func filterMTLSReadiness(report securityconfig.MTLSReadinessReport, namespace string) securityconfig.MTLSReadinessReport {
+	if namespace == metav1.NamespaceAll {
+		return report
+	}
+	filtered := report
+	filtered.Services = nil
+	for _, svc := range report.Services {
+		if svc.Namespace == namespace {
+			filtered.Services = append(filtered.Services, svc)
+		}
+	}
+	return filtered
+}
+
+func writeMTLSReadiness(out io.Writer, report securityconfig.MTLSReadinessReport) error {
+	if len(report.Services) == 0 {
+		_, err := fmt.Fprintln(out, "No service port runs in PERMISSIVE mode.")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tPORT\tREADY FOR STRICT\tPODS REPORTING\tPLAINTEXT CALLERS\tMTLS CALLERS")
+	for _, svc := range report.Services {
+		pods := fmt.Sprintf("%d/%d", svc.ReportingPods, svc.ReportingPods+len(svc.UnreachablePods))
+		fmt.Fprintf(w, "%s/%s\t%d\t%s\t%s\t%d\t%d\n", svc.Namespace, svc.Name, svc.Port,
+			mtlsReadinessVerdict(svc), pods, len(svc.PlaintextCallers), len(svc.MTLSCallers))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, svc := range report.Services {
+		if len(svc.PlaintextCallers) == 0 && len(svc.UnreachablePods) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s/%s:%d\n", svc.Namespace, svc.Name, svc.Port)
+		for _, caller := range svc.PlaintextCallers {
+			source := caller.Workload
+			if source == securityconfig.UnknownSourceWorkload {
+				source = "clients outside the mesh"
+			}
+			fmt.Fprintf(out, "  plaintext from %s: %.0f connections\n", source, caller.Connections)
+		}
+		for _, pod := range svc.UnreachablePods {
+			fmt.Fprintf(out, "  no report from pod %s\n", pod)
+		}
+	}
+	return nil
+}
+
+func mtlsReadinessVerdict(svc securityconfig.ServiceMTLSReadiness) string {
+	switch {
+	case svc.ReadyForStrict():
+		return "yes"
+	case len(svc.PlaintextCallers) > 0:
+		return "no"
+	default:
+		return "unknown"
+	}
+}
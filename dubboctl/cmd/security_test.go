@@ -0,0 +1,60 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+)
+
+func TestWriteMTLSReadiness(t *testing.T) {
+	report := securityconfig.MTLSReadinessReport{Services: []securityconfig.ServiceMTLSReadiness{
+		{
+			Namespace: "backend", Name: "orders", Port: 9080, Mode: "PERMISSIVE", ReportingPods: 2,
+			PlaintextCallers: []securityconfig.MTLSReadinessCaller{{Workload: "legacy/batch", Connections: 12}, {Workload: securityconfig.UnknownSourceWorkload, Connections: 2}},
+		},
+		{
+			Namespace: "backend", Name: "users", Port: 9090, Mode: "PERMISSIVE", ReportingPods: 1,
+			MTLSCallers: []securityconfig.MTLSReadinessCaller{{Workload: "backend/web", Principal: "spiffe://cluster.local/ns/backend/sa/web", Connections: 3}},
+		},
+		{Namespace: "frontend", Name: "web", Port: 8080, Mode: "PERMISSIVE", UnreachablePods: []string{"frontend/web-0"}},
+	}}
+	var out strings.Builder
+	if err := writeMTLSReadiness(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"backend/orders   9080   no",
+		"backend/users    9090   yes",
+		"frontend/web     8080   unknown",
+		"plaintext from legacy/batch: 12 connections",
+		"plaintext from clients outside the mesh: 2 connections",
+		"no report from pod frontend/web-0",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	filtered := filterMTLSReadiness(report, "frontend")
+	if len(filtered.Services) != 1 || filtered.Services[0].Name != "web" {
+		t.Fatalf("filtered = %+v, want frontend/web only", filtered.Services)
+	}
+}
@@ -0,0 +1,321 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
+	"github.com/spf13/cobra"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultRevision          = "default"
+	revisionLabel            = "dubbo.apache.org/rev"
+	revisionTagLabel         = "dubbo.apache.org/tag"
+	injectorWebhookName      = "dubbo-inherent-injector"
+	revisionTagWebhookPrefix = "dubbo-revision-tag-"
+)
+
+type tagSetArgs struct {
+	revision  string
+	overwrite bool
+}
+
+type tagRemoveArgs struct {
+	skipConfirmation bool
+}
+
+// revisionTag is a stable name, such as "stable" or "prod", that namespaces
+// select with dubbo.apache.org/rev instead of a concrete revision. Moving the
+// tag moves every namespace using it; relabelling one namespace moves only it.
+type revisionTag struct {
+	tag        string
+	revision   string
+	namespaces []string
+}
+
+func TagCmd(ctx cli.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage stable tags pointing at control plane revisions",
+		Long: `A revision tag is a stable name for a control plane revision. Namespaces labelled
+dubbo.apache.org/rev=<tag> are injected by whichever revision the tag points at, so a
+canary upgrade moves the tag and a rollback moves it back.`,
+	}
+	command.AddCommand(tagSetCmd(ctx))
+	command.AddCommand(tagListCmd(ctx))
+	command.AddCommand(tagRemoveCmd(ctx))
+	return command
+}
+
+func tagSetCmd(ctx cli.Context) *cobra.Command {
+	args := &tagSetArgs{}
+	command := &cobra.Command{
+		Use:   "set <tag>",
+		Short: "Point a revision tag at a control plane revision",
+		Example: `  # Point the stable tag at the 1-1 revision
+  dubboctl tag set stable --revision 1-1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, positional []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			if err := setRevisionTag(cmd.Context(), client.Kube(), positional[0], *args); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Revision tag %q points to revision %q\n", positional[0], args.revision)
+			return err
+		},
+	}
+	flags := command.Flags()
+	flags.StringVarP(&args.revision, "revision", "r", "", "Control plane revision the tag points at")
+	flags.BoolVar(&args.overwrite, "overwrite", false, "Move the tag if it already points at another revision")
+	_ = command.MarkFlagRequired("revision")
+	return command
+}
+
+func tagListCmd(ctx cli.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List revision tags and the namespaces using them",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			tags, err := listRevisionTags(cmd.Context(), client.Kube())
+			if err != nil {
+				return err
+			}
+			return printRevisionTags(cmd.OutOrStdout(), tags)
+		},
+	}
+}
+
+func tagRemoveCmd(ctx cli.Context) *cobra.Command {
+	args := &tagRemoveArgs{}
+	command := &cobra.Command{
+		Use:   "remove <tag>",
+		Short: "Remove a revision tag",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, positional []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			if err := removeRevisionTag(cmd.Context(), client.Kube(), positional[0], args.skipConfirmation); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Revision tag %q removed\n", positional[0])
+			return err
+		},
+	}
+	command.Flags().BoolVarP(&args.skipConfirmation, "skip-confirmation", "y", false, "Remove the tag even if namespaces still use it; their new pods will not be injected")
+	return command
+}
+
+func setRevisionTag(ctx context.Context, client kubernetes.Interface, tag string, args tagSetArgs) error {
+	if err := validateRevisionTag(tag); err != nil {
+		return err
+	}
+	webhooks := client.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	if _, err := webhooks.Get(ctx, revisionInjectorWebhookName(tag), metav1.GetOptions{}); err == nil {
+		return fmt.Errorf("%q is an installed revision and cannot be used as a tag", tag)
+	} else if !kerrors.IsNotFound(err) {
+		return err
+	}
+	base, err := webhooks.Get(ctx, revisionInjectorWebhookName(args.revision), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return fmt.Errorf("revision %q is not installed: MutatingWebhookConfiguration %s not found", args.revision, revisionInjectorWebhookName(args.revision))
+	}
+	if err != nil {
+		return err
+	}
+	desired, err := buildRevisionTagWebhook(base, tag, args.revision)
+	if err != nil {
+		return err
+	}
+
+	current, err := webhooks.Get(ctx, desired.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = webhooks.Create(ctx, desired, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if rev := current.Labels[revisionLabel]; rev != args.revision && !args.overwrite {
+		return fmt.Errorf("tag %q already points to revision %q; pass --overwrite to move it", tag, rev)
+	}
+	desired.ResourceVersion = current.ResourceVersion
+	_, err = webhooks.Update(ctx, desired, metav1.UpdateOptions{})
+	return err
+}
+
+func listRevisionTags(ctx context.Context, client kubernetes.Interface) ([]revisionTag, error) {
+	webhooks, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{LabelSelector: revisionTagLabel})
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: revisionLabel})
+	if err != nil {
+		return nil, err
+	}
+	return revisionTags(webhooks.Items, namespaces.Items), nil
+}
+
+func removeRevisionTag(ctx context.Context, client kubernetes.Interface, tag string, skipConfirmation bool) error {
+	tags, err := listRevisionTags(ctx, client)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if t.tag != tag {
+			continue
+		}
+		if len(t.namespaces) > 0 && !skipConfirmation {
+			return fmt.Errorf("tag %q is still used by namespaces %s; relabel them or pass --skip-confirmation", tag, strings.Join(t.namespaces, ","))
+		}
+		return client.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(ctx, revisionTagWebhookName(tag), metav1.DeleteOptions{})
+	}
+	return fmt.Errorf("revision tag %q not found", tag)
+}
+
+func validateRevisionTag(tag string) error {
+	if tag == defaultRevision {
+		return fmt.Errorf("%q is reserved for the default revision and cannot be used as a tag", tag)
+	}
+	if errs := validation.IsDNS1123Label(tag); len(errs) > 0 {
+		return fmt.Errorf("invalid tag %q: %s", tag, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// buildRevisionTagWebhook copies the revision-selected webhooks of a revision's
+// injector and makes them select the tag instead. The copies keep the
+// revision's rev label, so the revision's dubbod keeps their CA bundle current.
+// The revision-less opt-ins are left out; they belong to the default revision.
+func buildRevisionTagWebhook(base *admissionregistrationv1.MutatingWebhookConfiguration, tag, revision string) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	out := &admissionregistrationv1.MutatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "MutatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionTagWebhookName(tag),
+			Labels: map[string]string{
+				"app":            "dubbod",
+				revisionLabel:    revision,
+				revisionTagLabel: tag,
+			},
+		},
+	}
+	for _, wh := range base.Webhooks {
+		if !strings.HasPrefix(wh.Name, "rev.") {
+			continue
+		}
+		copied := *wh.DeepCopy()
+		copied.NamespaceSelector = retargetRevisionSelector(copied.NamespaceSelector, revision, tag)
+		copied.ObjectSelector = retargetRevisionSelector(copied.ObjectSelector, revision, tag)
+		out.Webhooks = append(out.Webhooks, copied)
+	}
+	if len(out.Webhooks) == 0 {
+		return nil, fmt.Errorf("MutatingWebhookConfiguration %s has no revision-selected webhooks to tag", base.Name)
+	}
+	return out, nil
+}
+
+func retargetRevisionSelector(selector *metav1.LabelSelector, revision, tag string) *metav1.LabelSelector {
+	if selector == nil {
+		return nil
+	}
+	for i, expr := range selector.MatchExpressions {
+		if expr.Key != revisionLabel || expr.Operator != metav1.LabelSelectorOpIn {
+			continue
+		}
+		values := make([]string, 0, len(expr.Values))
+		for _, v := range expr.Values {
+			if v == revision {
+				v = tag
+			}
+			values = append(values, v)
+		}
+		selector.MatchExpressions[i].Values = values
+	}
+	return selector
+}
+
+func revisionTags(webhooks []admissionregistrationv1.MutatingWebhookConfiguration, namespaces []corev1.Namespace) []revisionTag {
+	out := make([]revisionTag, 0, len(webhooks))
+	for _, wh := range webhooks {
+		tag := wh.Labels[revisionTagLabel]
+		if tag == "" {
+			continue
+		}
+		t := revisionTag{tag: tag, revision: wh.Labels[revisionLabel]}
+		for _, ns := range namespaces {
+			if ns.Labels[revisionLabel] == tag {
+				t.namespaces = append(t.namespaces, ns.Name)
+			}
+		}
+		sort.Strings(t.namespaces)
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].tag < out[j].tag })
+	return out
+}
+
+func printRevisionTags(writer io.Writer, tags []revisionTag) error {
+	table := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintln(table, "TAG\tREVISION\tNAMESPACES"); err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if _, err := fmt.Fprintf(table, "%s\t%s\t%s\n", t.tag, t.revision, strings.Join(t.namespaces, ",")); err != nil {
+			return err
+		}
+	}
+	return table.Flush()
+}
+
+// revisionInjectorWebhookName mirrors the chart: only non-default revisions
+// carry a suffix.
+func revisionInjectorWebhookName(revision string) string {
+	if revision == "" || revision == defaultRevision {
+		return injectorWebhookName
+	}
+	return injectorWebhookName + "-" + revision
+}
+
+func revisionTagWebhookName(tag string) string {
+	return revisionTagWebhookPrefix + tag
+}
@@ -0,0 +1,126 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildRevisionTagWebhookRetargetsRevisionSelectors(t *testing.T) {
+	base := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "dubbo-inherent-injector-1-1"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				Name:         "rev.namespace.inherent-injector.dubbo.apache.org",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: []byte("ca")},
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: revisionLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"1-1"}},
+				}},
+			},
+			{
+				Name: "rev.object.inherent-injector.dubbo.apache.org",
+				ObjectSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: revisionLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"1-1"}},
+				}},
+			},
+			{Name: "namespace.inherent-injector.dubbo.apache.org"},
+		},
+	}
+
+	got, err := buildRevisionTagWebhook(base, "stable", "1-1")
+	if err != nil {
+		t.Fatalf("buildRevisionTagWebhook() error = %v", err)
+	}
+	if got.Name != "dubbo-revision-tag-stable" {
+		t.Fatalf("name = %q, want dubbo-revision-tag-stable", got.Name)
+	}
+	if got.Labels[revisionLabel] != "1-1" || got.Labels[revisionTagLabel] != "stable" {
+		t.Fatalf("labels = %v, want rev 1-1 and tag stable", got.Labels)
+	}
+	if len(got.Webhooks) != 2 {
+		t.Fatalf("webhooks = %d, want only the revision-selected webhooks", len(got.Webhooks))
+	}
+	if values := got.Webhooks[0].NamespaceSelector.MatchExpressions[0].Values; len(values) != 1 || values[0] != "stable" {
+		t.Fatalf("namespace selector values = %v, want [stable]", values)
+	}
+	if values := got.Webhooks[1].ObjectSelector.MatchExpressions[0].Values; len(values) != 1 || values[0] != "stable" {
+		t.Fatalf("object selector values = %v, want [stable]", values)
+	}
+	if string(got.Webhooks[0].ClientConfig.CABundle) != "ca" {
+		t.Fatalf("caBundle = %q, want copied from the revision", got.Webhooks[0].ClientConfig.CABundle)
+	}
+	if values := base.Webhooks[0].NamespaceSelector.MatchExpressions[0].Values; values[0] != "1-1" {
+		t.Fatalf("base webhook selector mutated to %v", values)
+	}
+}
+
+func TestValidateRevisionTag(t *testing.T) {
+	for _, tag := range []string{"default", "", "Stable", "prod_1"} {
+		if err := validateRevisionTag(tag); err == nil {
+			t.Fatalf("validateRevisionTag(%q) = nil, want error", tag)
+		}
+	}
+	if err := validateRevisionTag("stable"); err != nil {
+		t.Fatalf("validateRevisionTag(stable) error = %v", err)
+	}
+}
+
+func TestRevisionInjectorWebhookName(t *testing.T) {
+	if got := revisionInjectorWebhookName("default"); got != "dubbo-inherent-injector" {
+		t.Fatalf("default revision webhook = %q", got)
+	}
+	if got := revisionInjectorWebhookName("1-1"); got != "dubbo-inherent-injector-1-1" {
+		t.Fatalf("1-1 revision webhook = %q", got)
+	}
+}
+
+func TestPrintRevisionTagsListsNamespaces(t *testing.T) {
+	webhook := func(tag, rev string) admissionregistrationv1.MutatingWebhookConfiguration {
+		return admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Name:   revisionTagWebhookName(tag),
+			Labels: map[string]string{revisionTagLabel: tag, revisionLabel: rev},
+		}}
+	}
+	namespace := func(name, rev string) corev1.Namespace {
+		return corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{revisionLabel: rev}}}
+	}
+	tags := revisionTags(
+		[]admissionregistrationv1.MutatingWebhookConfiguration{webhook("stable", "1-0"), webhook("canary", "1-1")},
+		[]corev1.Namespace{namespace("shop", "stable"), namespace("cart", "stable"), namespace("pay", "1-1")},
+	)
+
+	var out bytes.Buffer
+	if err := printRevisionTags(&out, tags); err != nil {
+		t.Fatalf("printRevisionTags() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("output lines = %d, want header and two tags:\n%s", len(lines), out.String())
+	}
+	if fields := strings.Fields(lines[1]); len(fields) != 2 || fields[0] != "canary" || fields[1] != "1-1" {
+		t.Fatalf("canary row = %q, want no namespaces", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); len(fields) != 3 || fields[0] != "stable" || fields[2] != "cart,shop" {
+		t.Fatalf("stable row = %q, want cart,shop", lines[2])
+	}
+}
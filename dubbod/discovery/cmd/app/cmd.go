@@ -18,6 +18,7 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/bootstrap"
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/features"
@@ -114,8 +115,23 @@ func addFlags(c *cobra.Command) {
 	c.PersistentFlags().StringSliceVar(&serverArgs.RegistryOptions.Registries,
 		"registries",
 		[]string{string(provider.Kubernetes)},
-		fmt.Sprintf("Comma separated list of platform service registries to read from (choose one or more from {%s})",
-			provider.Kubernetes))
+		fmt.Sprintf("Comma separated list of platform service registries to read from (choose one or more from {%s, %s, %s})",
+			provider.Kubernetes, provider.Zookeeper, provider.Nacos))
+	c.PersistentFlags().StringSliceVar(&serverArgs.RegistryOptions.ZookeeperAddresses,
+		"zookeeperAddresses", nil,
+		"Comma separated host:port list of the ZooKeeper ensemble read by the Zookeeper registry")
+	c.PersistentFlags().DurationVar(&serverArgs.RegistryOptions.ZookeeperSessionTimeout,
+		"zookeeperSessionTimeout", 30*time.Second,
+		"Session timeout requested from the ZooKeeper ensemble read by the Zookeeper registry")
+	c.PersistentFlags().StringVar(&serverArgs.RegistryOptions.NacosAddress,
+		"nacosAddress", "",
+		"Base URL of the Nacos server read by the Nacos registry, e.g. http://nacos:8848")
+	c.PersistentFlags().StringVar(&serverArgs.RegistryOptions.NacosNamespace,
+		"nacosNamespace", "",
+		"Nacos namespace ID to read providers from; empty reads the public namespace")
+	c.PersistentFlags().StringVar(&serverArgs.RegistryOptions.DubboRegistryNamespace,
+		"dubboRegistryNamespace", "",
+		"Namespace for services read from a Zookeeper or Nacos registry; defaults to the dubbod namespace")
 	c.PersistentFlags().StringVar(&serverArgs.RegistryOptions.ClusterRegistriesNamespace,
 		"clusterRegistriesNamespace", serverArgs.RegistryOptions.ClusterRegistriesNamespace,
 		"Namespace for ConfigMap which stores clusters configs")
@@ -147,6 +163,14 @@ func addFlags(c *cobra.Command) {
 		"activationAddr",
 		":26030",
 		"KEDA external scaler gRPC address for on-demand activation; empty disables it")
+	c.PersistentFlags().StringVar(&serverArgs.ServerOptions.ActivationPeerAddr,
+		"activationPeerAddr",
+		"",
+		"host:port resolving to every dubbod replica's activation port, for handing activation demand off between replicas; empty disables it")
+	c.PersistentFlags().StringVar(&serverArgs.ServerOptions.ActivationDemandAddr,
+		"activationDemandAddr",
+		"",
+		"host:port resolving to every dubbod replica's secure xDS port, for proxyless workloads to report activation demand to; empty uses their discovery address")
 	c.PersistentFlags().StringVar(&serverArgs.ServerOptions.HTTPSAddr,
 		"httpsAddr",
 		":26017",
@@ -19,6 +19,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/status"
 	"github.com/apache/dubbo-kubernetes/pkg/kube"
 	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
 	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
@@ -28,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 )
 
 var logger = log.RegisterScope("activation", "Service activation policies")
@@ -42,7 +44,8 @@ const resyncInterval = 30 * time.Second
 //
 // It publishes status only. Replica counts belong to the autoscaler the policy
 // references, and writing them here would put two controllers on the same
-// field.
+// field. When that autoscaler is dubbod itself, the NativeScaler writes them,
+// on the leader only.
 type Controller struct {
 	policies kclient.Client[*clientnetworking.ServiceActivationPolicy]
 	services kclient.Client[*corev1.Service]
@@ -50,22 +53,36 @@ type Controller struct {
 
 	evaluator PolicyEvaluator
 	readiness *clusterReadiness
+	idle      *IdleTracker
+	prewarm   *Prewarmer
+	native    *NativeScaler
 }
 
 // NewController wires policy status to cluster-visible autoscaler and Gateway
-// state, so every HA replica evaluates the same facts.
-func NewController(client kube.Client) *Controller {
+// state, so every HA replica evaluates the same facts. It also hands the idle
+// and prewarm settings of every policy to idle and prewarm, and the policies
+// naming a workload to native. Any of the three may be nil.
+func NewController(client kube.Client, idle *IdleTracker, prewarm *Prewarmer, native *NativeScaler) *Controller {
 	readiness := newClusterReadiness(client)
 	c := &Controller{
 		policies:  kclient.New[*clientnetworking.ServiceActivationPolicy](client),
 		services:  kclient.New[*corev1.Service](client),
 		readiness: readiness,
+		idle:      idle,
+		prewarm:   prewarm,
+		native:    native,
 	}
 	c.evaluator = PolicyEvaluator{
 		Services:  c,
 		Scaler:    readiness,
 		Activator: readiness,
 	}
+	if prewarm != nil {
+		c.evaluator.Prewarm = prewarm
+	}
+	if native != nil {
+		c.evaluator.Native = native
+	}
 
 	c.queue = controllers.NewQueue("service activation policy",
 		controllers.WithReconciler(c.Reconcile),
@@ -124,6 +141,16 @@ func NewController(client kube.Client) *Controller {
 			}
 		},
 	)
+	// ScalerReady and ReplicasManaged follow the workload a policy scales.
+	if native != nil {
+		native.AddEventHandler(func(workload Workload) {
+			for _, policy := range c.policies.List(workload.Namespace, klabels.Everything()) {
+				if named, ok := nativeWorkloadOf(policy); ok && named == workload {
+					c.queue.AddObject(policy)
+				}
+			}
+		})
+	}
 
 	return c
 }
@@ -134,19 +161,20 @@ func (c *Controller) HasService(namespace, name string) bool {
 }
 
 func (c *Controller) Run(stop <-chan struct{}) {
-	kube.WaitForCacheSync(
-		"activation controller",
-		stop,
-		c.policies.HasSynced,
-		c.services.HasSynced,
-		c.readiness.HasSynced,
-	)
+	synced := []cache.InformerSynced{c.policies.HasSynced, c.services.HasSynced, c.readiness.HasSynced}
+	if c.native != nil {
+		synced = append(synced, c.native.HasSynced)
+	}
+	kube.WaitForCacheSync("activation controller", stop, synced...)
 
 	go c.resync(stop)
 
 	c.queue.Run(stop)
 	controllers.ShutdownAll(c.policies, c.services)
 	c.readiness.ShutdownHandlers()
+	if c.native != nil {
+		c.native.ShutdownHandlers()
+	}
 }
 
 // resync re-queues every policy on a tick, picking up scaler and gateway
@@ -170,11 +198,45 @@ func (c *Controller) Reconcile(key types.NamespacedName) error {
 	policy := c.policies.Get(key.Name, key.Namespace)
 	if policy == nil {
 		// Deleted; nothing to publish.
+		if c.idle != nil {
+			c.idle.Forget(key)
+		}
+		if c.prewarm != nil {
+			c.prewarm.Forget(key)
+		}
+		if c.native != nil {
+			c.native.Forget(key)
+		}
 		return nil
 	}
 
+	// Invalid idle or prewarm settings are reported as not accepted and leave
+	// the target to the autoscaler.
+	if c.idle != nil {
+		settings, _ := IdleSettingsOf(policy)
+		c.idle.Track(key, targetOf(policy), settings)
+	}
+	if c.prewarm != nil {
+		settings, _ := PrewarmSettingsOf(policy)
+		c.prewarm.Track(key, targetOf(policy), settings)
+	}
+
 	conditions := c.evaluator.Evaluate(policy)
-	if SameConditions(policy.Status.GetConditions(), conditions) {
+
+	// Only an accepted policy may scale its workload. One whose target Service
+	// does not exist would take the workload to zero and never see the demand
+	// that brings it back.
+	if c.native != nil {
+		workload, native := nativeWorkloadOf(policy)
+		settings, err := NativeSettingsOf(policy)
+		if native && err == nil && conditions[0].GetStatus() == conditionTrue {
+			c.native.Track(key, targetOf(policy), workload, settings)
+		} else {
+			c.native.Forget(key)
+		}
+	}
+
+	if status.SameConditions(policy.Status.GetConditions(), conditions) {
 		// Writing an unchanged status would feed the resync tick back into
 		// itself and turn a quiet cluster into a steady write load.
 		return nil
@@ -0,0 +1,146 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a standard five-field cron expression: minute, hour, day of
+// month, month and day of week. Each field takes *, values, ranges, lists and
+// steps, e.g. "*/15 8-18 * * 1-5".
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Restricting both day fields matches either, as cron does.
+	domRestricted, dowRestricted bool
+}
+
+// cronSearchYears bounds the search for the next start, so an expression that
+// never matches, such as February 30th, ends instead of looping.
+const cronSearchYears = 5
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+func parseCron(expression string) (cronSchedule, error) {
+	parts := strings.Fields(expression)
+	if len(parts) != len(cronFields) {
+		return cronSchedule{}, fmt.Errorf("cron expression %q has %d fields, want %d", expression, len(parts), len(cronFields))
+	}
+	bits := make([]uint64, len(cronFields))
+	for i, field := range cronFields {
+		var err error
+		if bits[i], err = parseCronField(parts[i], field); err != nil {
+			return cronSchedule{}, fmt.Errorf("cron expression %q: %v", expression, err)
+		}
+	}
+	// Sunday is both 0 and 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return cronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dom:           bits[2],
+		month:         bits[3],
+		dow:           bits[4],
+		domRestricted: parts[2] != "*",
+		dowRestricted: parts[4] != "*",
+	}, nil
+}
+
+func parseCronField(raw string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(raw, ",") {
+		rangePart, stepPart, stepped := strings.Cut(item, "/")
+		step := 1
+		if stepped {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("%s step %q is not a positive number", field.name, stepPart)
+			}
+		}
+
+		low, high := field.min, field.max
+		if rangePart != "*" {
+			lowPart, highPart, ranged := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = strconv.Atoi(lowPart); err != nil {
+				return 0, fmt.Errorf("%s %q is not a number", field.name, lowPart)
+			}
+			high = low
+			if ranged {
+				if high, err = strconv.Atoi(highPart); err != nil {
+					return 0, fmt.Errorf("%s %q is not a number", field.name, highPart)
+				}
+			} else if stepped {
+				high = field.max
+			}
+		}
+		if low < field.min || high > field.max || low > high {
+			return 0, fmt.Errorf("%s %q is outside %d-%d", field.name, rangePart, field.min, field.max)
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+// next returns the first start strictly after the given time, in its location.
+func (s cronSchedule) next(after time.Time) (time.Time, bool) {
+	location := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Year() + cronSearchYears
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
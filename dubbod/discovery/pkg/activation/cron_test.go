@@ -0,0 +1,78 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Monday 2026-03-02 07:45 UTC.
+	after := time.Date(2026, 3, 2, 7, 45, 0, 0, time.UTC)
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{"30 7 * * 1-5", time.Date(2026, 3, 3, 7, 30, 0, 0, time.UTC)},
+		{"*/20 8-18 * * *", time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)},
+		{"50,55 7 * * *", time.Date(2026, 3, 2, 7, 50, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		// Sunday may be written 0 or 7.
+		{"0 9 * * 7", time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matches.
+		{"0 9 15 * 3", time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := parseCron(tt.expression)
+			if err != nil {
+				t.Fatalf("parseCron() error = %v", err)
+			}
+			got, ok := schedule.next(after)
+			if !ok || !got.Equal(tt.want) {
+				t.Fatalf("next(%v) = %v, %v; want %v", after, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNextGivesUpOnImpossibleDates(t *testing.T) {
+	schedule, err := parseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("parseCron() error = %v", err)
+	}
+	if got, ok := schedule.next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Fatalf("next() = %v for February 30th, want none", got)
+	}
+}
+
+func TestParseCronRejectsMalformedExpressions(t *testing.T) {
+	for _, expression := range []string{
+		"0 8 * *",
+		"60 8 * * *",
+		"0 8-6 * * *",
+		"*/0 * * * *",
+		"0 8 * JAN *",
+		"0 8 0 * *",
+	} {
+		if _, err := parseCron(expression); err == nil {
+			t.Errorf("parseCron(%q) accepted a malformed expression", expression)
+		}
+	}
+}
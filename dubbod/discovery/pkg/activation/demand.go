@@ -59,6 +59,15 @@ type Registry struct {
 	// Per target, the live subscribers.
 	subscribers map[Target]map[int]chan int64
 	nextID      int
+	// Per target, when a reporter last saw a request for it, held or sent.
+	active map[Target]time.Time
+	// Per reporter, when it last reported and whether it counts requests,
+	// including reporters with nothing pending.
+	reporters map[string]reporterState
+	// Per reporter, when it was forgotten. A peer that has not yet noticed the
+	// reporter is gone still hands its last snapshot off, and that snapshot
+	// must not bring it back.
+	forgotten map[string]time.Time
 
 	// now is swappable so expiry can be tested without sleeping.
 	now func() time.Time
@@ -68,12 +77,23 @@ type Registry struct {
 type report struct {
 	pending  int64
 	received time.Time
+	// handedOff marks a report taken from another replica rather than from
+	// the reporter itself.
+	handedOff bool
+}
+
+type reporterState struct {
+	received       time.Time
+	countsRequests bool
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
 		targets:     map[Target]map[string]report{},
 		subscribers: map[Target]map[int]chan int64{},
+		active:      map[Target]time.Time{},
+		reporters:   map[string]reporterState{},
+		forgotten:   map[string]time.Time{},
 		now:         time.Now,
 		ttl:         reporterTTL,
 	}
@@ -93,6 +113,12 @@ func (r *Registry) Report(reporter string, target Target, pending int64) {
 		r.targets[target] = byReporter
 	}
 	byReporter[reporter] = report{pending: pending, received: r.now()}
+	if pending > 0 {
+		r.active[target] = r.now()
+	}
+	state := r.reporters[reporter]
+	state.received = r.now()
+	r.reporters[reporter] = state
 	total := r.totalLocked(target)
 	subscribers := r.snapshotSubscribersLocked(target)
 	r.mu.Unlock()
@@ -106,16 +132,149 @@ func (r *Registry) Report(reporter string, target Target, pending int64) {
 // cleared rather than left at its last value. Report cannot express that: it
 // only ever speaks about one target, and a gateway with nothing pending would
 // have no message to send.
-func (r *Registry) ReportSnapshot(reporter string, pending map[Target]int64) {
-	type notification struct {
-		subscribers []chan int64
-		total       int64
+//
+// countsRequests is whether the reporter records every request it sends with
+// RecordRequests; see CountsAllRequests.
+func (r *Registry) ReportSnapshot(reporter string, pending map[Target]int64, countsRequests bool) {
+	r.mu.Lock()
+	r.reporters[reporter] = reporterState{received: r.now(), countsRequests: countsRequests}
+	touched := map[Target]struct{}{}
+	r.replaceLocked(reporter, pending, report{received: r.now()}, touched)
+	r.unlockAndNotify(touched)
+}
+
+// ReporterSnapshot is the last snapshot one reporter published, as a replica
+// holds it.
+type ReporterSnapshot struct {
+	Reporter       string
+	Pending        map[Target]int64
+	Received       time.Time
+	CountsRequests bool
+}
+
+// Reports returns the live snapshot of every reporter, for handing off to
+// another replica. Reports taken from other replicas are included, so demand
+// a reporter could only get to one replica still spreads to all of them.
+func (r *Registry) Reports() []ReporterSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cutoff := r.now().Add(-r.ttl)
+	byReporter := map[string]*ReporterSnapshot{}
+	// Reporters with nothing pending are handed off too, so every replica
+	// knows whether all of them count requests.
+	for reporter, state := range r.reporters {
+		if state.received.Before(cutoff) {
+			continue
+		}
+		byReporter[reporter] = &ReporterSnapshot{
+			Reporter:       reporter,
+			Pending:        map[Target]int64{},
+			Received:       state.received,
+			CountsRequests: state.countsRequests,
+		}
+	}
+	for target, reports := range r.targets {
+		for reporter, entry := range reports {
+			if entry.received.Before(cutoff) {
+				continue
+			}
+			snapshot, ok := byReporter[reporter]
+			if !ok {
+				snapshot = &ReporterSnapshot{Reporter: reporter, Pending: map[Target]int64{}}
+				byReporter[reporter] = snapshot
+			}
+			snapshot.Pending[target] = entry.pending
+			if entry.received.After(snapshot.Received) {
+				snapshot.Received = entry.received
+			}
+		}
 	}
+	out := make([]ReporterSnapshot, 0, len(byReporter))
+	for _, snapshot := range byReporter {
+		out = append(out, *snapshot)
+	}
+	return out
+}
 
+// Merge takes over the reports another replica holds and returns how many
+// replaced what this replica had. A reporter's snapshot is taken only when it
+// is newer than the one held here, and keeps the time it was received, so a
+// report that has stopped being refreshed still expires a TTL after its
+// reporter last sent it no matter how many replicas pass it on.
+func (r *Registry) Merge(snapshots []ReporterSnapshot) int {
 	r.mu.Lock()
-	now := r.now()
+	cutoff := r.now().Add(-r.ttl)
+	latest := map[string]time.Time{}
+	for reporter, state := range r.reporters {
+		latest[reporter] = state.received
+	}
+	for reporter, at := range r.forgotten {
+		// Past the TTL, anything from before the forget is too old anyway.
+		if at.Before(cutoff) {
+			delete(r.forgotten, reporter)
+			continue
+		}
+		if at.After(latest[reporter]) {
+			latest[reporter] = at
+		}
+	}
+	for _, reports := range r.targets {
+		for reporter, entry := range reports {
+			if entry.received.After(latest[reporter]) {
+				latest[reporter] = entry.received
+			}
+		}
+	}
+
 	touched := map[Target]struct{}{}
+	merged := 0
+	for _, snapshot := range snapshots {
+		if snapshot.Received.Before(cutoff) || !snapshot.Received.After(latest[snapshot.Reporter]) {
+			continue
+		}
+		r.replaceLocked(snapshot.Reporter, snapshot.Pending,
+			report{received: snapshot.Received, handedOff: true}, touched)
+		r.reporters[snapshot.Reporter] = reporterState{
+			received:       snapshot.Received,
+			countsRequests: snapshot.CountsRequests,
+		}
+		latest[snapshot.Reporter] = snapshot.Received
+		merged++
+	}
+	r.unlockAndNotify(touched)
+	return merged
+}
+
+// ReporterCounts returns how many reporters this replica holds live reports
+// from, split by whether they reached it directly or through another replica.
+func (r *Registry) ReporterCounts() (direct, handedOff int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cutoff := r.now().Add(-r.ttl)
+	sources := map[string]bool{}
+	for _, reports := range r.targets {
+		for reporter, entry := range reports {
+			if entry.received.Before(cutoff) {
+				continue
+			}
+			// A reporter heard directly on any target counts as direct.
+			sources[reporter] = sources[reporter] || !entry.handedOff
+		}
+	}
+	for _, isDirect := range sources {
+		if isDirect {
+			direct++
+		} else {
+			handedOff++
+		}
+	}
+	return direct, handedOff
+}
 
+// replaceLocked makes pending the whole of what reporter holds, each count
+// stored as template with the count filled in, and records the targets whose
+// total may have changed in touched.
+func (r *Registry) replaceLocked(reporter string, pending map[Target]int64, template report, touched map[Target]struct{}) {
 	// Drop this reporter from targets it no longer mentions.
 	for target, byReporter := range r.targets {
 		if _, ok := byReporter[reporter]; !ok {
@@ -137,10 +296,23 @@ func (r *Registry) ReportSnapshot(reporter string, pending map[Target]int64) {
 			byReporter = map[string]report{}
 			r.targets[target] = byReporter
 		}
-		byReporter[reporter] = report{pending: count, received: now}
+		entry := template
+		entry.pending = count
+		byReporter[reporter] = entry
+		if count > 0 && entry.received.After(r.active[target]) {
+			r.active[target] = entry.received
+		}
 		touched[target] = struct{}{}
 	}
+}
 
+// unlockAndNotify releases the lock, then sends the new totals of touched
+// targets to their subscribers.
+func (r *Registry) unlockAndNotify(touched map[Target]struct{}) {
+	type notification struct {
+		subscribers []chan int64
+		total       int64
+	}
 	notifications := make([]notification, 0, len(touched))
 	for target := range touched {
 		notifications = append(notifications, notification{
@@ -156,30 +328,63 @@ func (r *Registry) ReportSnapshot(reporter string, pending map[Target]int64) {
 }
 
 // Forget drops a gateway's reports, for a clean shutdown that should not wait
-// out the TTL.
+// out the TTL. Snapshots the gateway sent before this are not merged back from
+// other replicas.
 func (r *Registry) Forget(reporter string) {
-	type notification struct {
-		subscribers []chan int64
-		total       int64
-	}
-
 	r.mu.Lock()
-	var notifications []notification
+	delete(r.reporters, reporter)
+	r.forgotten[reporter] = r.now()
+	touched := map[Target]struct{}{}
 	for target, byReporter := range r.targets {
 		if _, ok := byReporter[reporter]; !ok {
 			continue
 		}
 		delete(byReporter, reporter)
-		notifications = append(notifications, notification{
-			subscribers: r.snapshotSubscribersLocked(target),
-			total:       r.totalLocked(target),
-		})
+		touched[target] = struct{}{}
 	}
-	r.mu.Unlock()
+	r.unlockAndNotify(touched)
+}
 
-	for _, item := range notifications {
-		notify(item.subscribers, item.total)
+// RecordRequests notes requests a reporter saw for a target since its previous
+// snapshot. Unlike pending counts they are not summed anywhere: all that idle
+// detection needs is when the target was last in use.
+func (r *Registry) RecordRequests(target Target, requests int64) {
+	if requests <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active[target] = r.now()
+}
+
+// CountsAllRequests reports whether at least one reporter is live and every
+// live one counts the requests it sends. Only then does a target missing from
+// the counts mean nobody called it: a gateway that only reports held requests
+// says nothing about the traffic it passes straight through.
+func (r *Registry) CountsAllRequests() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cutoff := r.now().Add(-r.ttl)
+	live := false
+	for reporter, state := range r.reporters {
+		if state.received.Before(cutoff) {
+			delete(r.reporters, reporter)
+			continue
+		}
+		if !state.countsRequests {
+			return false
+		}
+		live = true
 	}
+	return live
+}
+
+// LastActive returns when a request for the target was last held or sent, or
+// the zero time if none was seen since this replica started.
+func (r *Registry) LastActive(target Target) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active[target]
 }
 
 func (r *Registry) Pending(target Target) int64 {
@@ -253,6 +458,17 @@ func (r *Registry) totalLocked(target Target) int64 {
 	return total
 }
 
+// touch re-sends the current total to a target's subscribers, for a change in
+// whether the target is active that the total alone does not show.
+func (r *Registry) touch(target Target) {
+	r.mu.Lock()
+	subscribers := r.snapshotSubscribersLocked(target)
+	total := r.totalLocked(target)
+	r.mu.Unlock()
+
+	notify(subscribers, total)
+}
+
 func (r *Registry) snapshotSubscribersLocked(target Target) []chan int64 {
 	channels := r.subscribers[target]
 	if len(channels) == 0 {
@@ -16,27 +16,56 @@
 package activation
 
 import (
+	"context"
 	"errors"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation/demandpb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// WorkloadReportInterval is how often proxyless workloads refresh their demand
+// snapshots. It is well under reporterTTL, so one lost snapshot does not
+// expire a workload's demand.
+const WorkloadReportInterval = 5 * time.Second
+
+// IdentityFunc returns the authenticated identity of the peer of a stream.
+type IdentityFunc func(ctx context.Context) (string, error)
+
 // DemandService receives the demand gateways broadcast to every control-plane
 // replica.
+//
+// Proxyless workloads report over the same protocol. They have no Activator
+// in front of a cold target when they call it directly, so they count the
+// calls waiting for endpoints and the calls they failed fast in the last
+// WorkloadReportInterval, and report the calls they sent as requests. Their
+// service authenticates every stream and scopes the claimed reporter under the
+// identity of the workload certificate, so a workload can neither clear nor
+// inflate another workload's demand.
+//
+// Gateways only report the requests they hold. Idle detection therefore waits
+// until every live reporter says it counts requests; see CountsAllRequests.
 type DemandService struct {
 	demandpb.UnimplementedActivationDemandServer
 
 	registry *Registry
+	identify IdentityFunc
+	// replica names this replica in handoff answers.
+	replica string
 }
 
 func NewDemandService(registry *Registry) *DemandService {
 	return &DemandService{registry: registry}
 }
 
+// NewWorkloadDemandService accepts reports from authenticated workloads only.
+func NewWorkloadDemandService(registry *Registry, identify IdentityFunc) *DemandService {
+	return &DemandService{registry: registry, identify: identify}
+}
+
 // Report consumes one gateway's snapshot stream until it ends.
 //
 // The stream's lifetime is the gateway's liveness. On any exit the gateway is
@@ -44,6 +73,14 @@ func NewDemandService(registry *Registry) *DemandService {
 // update does not hold a workload scaled up for a full TTL after the old pod
 // is gone.
 func (s *DemandService) Report(stream demandpb.ActivationDemand_ReportServer) error {
+	identity := ""
+	if s.identify != nil {
+		var err error
+		if identity, err = s.identify(stream.Context()); err != nil {
+			return status.Errorf(codes.Unauthenticated, "cannot identify the demand reporter: %v", err)
+		}
+	}
+
 	reporter := ""
 	var snapshots int64
 
@@ -66,6 +103,9 @@ func (s *DemandService) Report(stream demandpb.ActivationDemand_ReportServer) er
 		if name == "" {
 			return status.Error(codes.InvalidArgument, "demand snapshot is missing a reporter identity")
 		}
+		if identity != "" {
+			name = workloadReporter(identity, name)
+		}
 		// Two identities on one stream would leave the first one's demand
 		// behind with nothing refreshing it.
 		if reporter != "" && name != reporter {
@@ -74,27 +114,74 @@ func (s *DemandService) Report(stream demandpb.ActivationDemand_ReportServer) er
 		}
 		reporter = name
 
-		pending, err := targetsOf(snapshot)
+		pending, requests, err := targetsOf(snapshot)
 		if err != nil {
 			return err
 		}
-		s.registry.ReportSnapshot(reporter, pending)
+		s.registry.ReportSnapshot(reporter, pending, snapshot.GetCountsRequests())
+		for target, count := range requests {
+			s.registry.RecordRequests(target, count)
+		}
 		snapshots++
 	}
 }
 
-func targetsOf(snapshot *demandpb.DemandSnapshot) (map[Target]int64, error) {
+// Handoff answers another replica with every report held here.
+func (s *DemandService) Handoff(ctx context.Context, request *demandpb.HandoffRequest) (*demandpb.DemandHandoff, error) {
+	// Workloads reach the same service on the secure port; the demand of the
+	// whole mesh is not theirs to read.
+	if s.identify != nil {
+		return nil, status.Error(codes.PermissionDenied, "demand handoff is served to dubbod replicas only")
+	}
+	now := s.registry.now()
+	reports := s.registry.Reports()
+	response := &demandpb.DemandHandoff{
+		Replica:   s.replica,
+		Reporters: make([]*demandpb.ReporterDemand, 0, len(reports)),
+	}
+	for _, report := range reports {
+		targets := make([]*demandpb.TargetDemand, 0, len(report.Pending))
+		for target, pending := range report.Pending {
+			targets = append(targets, &demandpb.TargetDemand{
+				Namespace: target.Namespace,
+				Service:   target.Name,
+				Pending:   pending,
+			})
+		}
+		response.Reporters = append(response.Reporters, &demandpb.ReporterDemand{
+			Reporter:       report.Reporter,
+			Targets:        targets,
+			AgeMillis:      now.Sub(report.Received).Milliseconds(),
+			CountsRequests: report.CountsRequests,
+		})
+	}
+	logger.Debugf("handed %d demand reports off to replica %q", len(reports), request.GetReplica())
+	return response, nil
+}
+
+// workloadReporter keys a workload's reports by its certificate identity and
+// the claimed name, which tells apart the replicas sharing that identity.
+func workloadReporter(identity, name string) string {
+	return identity + "#" + name
+}
+
+func targetsOf(snapshot *demandpb.DemandSnapshot) (map[Target]int64, map[Target]int64, error) {
 	pending := make(map[Target]int64, len(snapshot.GetTargets()))
+	requests := map[Target]int64{}
 	for _, item := range snapshot.GetTargets() {
 		namespace := strings.TrimSpace(item.GetNamespace())
 		service := strings.TrimSpace(item.GetService())
 		if namespace == "" || service == "" {
-			return nil, status.Errorf(codes.InvalidArgument,
+			return nil, nil, status.Errorf(codes.InvalidArgument,
 				"demand target is missing a namespace or service: %q/%q", namespace, service)
 		}
 		// Summed rather than overwritten: a malformed snapshot that repeats a
 		// target must not silently discard one of the counts.
-		pending[Target{Namespace: namespace, Name: service}] += item.GetPending()
+		target := Target{Namespace: namespace, Name: service}
+		pending[target] += item.GetPending()
+		if item.GetRequests() > 0 {
+			requests[target] += item.GetRequests()
+		}
 	}
-	return pending, nil
+	return pending, requests, nil
 }
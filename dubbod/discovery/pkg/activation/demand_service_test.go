@@ -17,6 +17,7 @@ package activation
 
 import (
 	"context"
+	"errors"
 	"net"
 	"testing"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -32,13 +34,18 @@ var reviews = Target{Namespace: "app", Name: "reviews"}
 
 func startDemand(t *testing.T, registry *Registry) demandpb.ActivationDemandClient {
 	t.Helper()
+	return startDemandService(t, NewDemandService(registry))
+}
+
+func startDemandService(t *testing.T, service *DemandService) demandpb.ActivationDemandClient {
+	t.Helper()
 
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		t.Fatal(err)
 	}
 	server := grpc.NewServer()
-	demandpb.RegisterActivationDemandServer(server, NewDemandService(registry))
+	demandpb.RegisterActivationDemandServer(server, service)
 	go func() { _ = server.Serve(listener) }()
 
 	connection, err := grpc.NewClient(listener.Addr().String(),
@@ -140,6 +147,30 @@ func TestClosingTheStreamForgetsTheGateway(t *testing.T) {
 
 // Every replica gets the same broadcast, so two gateways reporting the same
 // target must add up rather than overwrite one another.
+// Requests sent on to a serving target are not pending anywhere, and are the
+// only sign it is still in use.
+func TestReportedRequestsMarkTheTargetInUse(t *testing.T) {
+	registry := NewRegistry()
+	client := startDemand(t, registry)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.Report(ctx)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	sent := demandFor(orders, 0)
+	sent.Requests = 12
+	if err := stream.Send(snapshot("gateway-a", sent)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	waitFor(t, func() bool { return !registry.LastActive(orders).IsZero() },
+		"reported requests did not mark the target in use")
+	if got := registry.Pending(orders); got != 0 {
+		t.Fatalf("pending = %d, want requests kept apart from pending", got)
+	}
+}
+
 func TestReportsFromSeveralGatewaysAccumulate(t *testing.T) {
 	registry := NewRegistry()
 	client := startDemand(t, registry)
@@ -268,3 +299,49 @@ func TestReportedDemandActivatesTheScalerOnTheSameReplica(t *testing.T) {
 	waitFor(t, func() bool { return server.Registry().Pending(orders) == 1 },
 		"demand did not reach the registry")
 }
+
+// Proxyless workloads share one claimed name per pod but not one certificate:
+// their reports must be keyed by the certificate identity, so a workload can
+// neither overwrite nor clear another's demand.
+func TestWorkloadReportsAreScopedByCertificateIdentity(t *testing.T) {
+	registry := NewRegistry()
+	client := startDemandService(t, NewWorkloadDemandService(registry, func(ctx context.Context) (string, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if ids := md.Get("test-identity"); len(ids) == 1 {
+			return ids[0], nil
+		}
+		return "", errors.New("no client certificate is presented")
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	report := func(identity string, pending int64) demandpb.ActivationDemand_ReportClient {
+		stream, err := client.Report(metadata.AppendToOutgoingContext(ctx, "test-identity", identity))
+		if err != nil {
+			t.Fatalf("Report() error = %v", err)
+		}
+		if err := stream.Send(snapshot("frontend-0", demandFor(orders, pending))); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		return stream
+	}
+	first := report("spiffe://cluster.local/ns/app/sa/frontend", 2)
+	report("spiffe://cluster.local/ns/app/sa/checkout", 3)
+	waitFor(t, func() bool { return registry.Pending(orders) == 5 && registry.Reporters(orders) == 2 },
+		"reports of two workloads claiming one name did not accumulate")
+
+	if _, err := first.CloseAndRecv(); err != nil {
+		t.Fatalf("CloseAndRecv() error = %v", err)
+	}
+	waitFor(t, func() bool { return registry.Pending(orders) == 3 },
+		"closing one workload's stream did not forget only its demand")
+
+	stream, err := client.Report(ctx)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	_ = stream.Send(snapshot("frontend-0", demandFor(orders, 1)))
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unauthenticated report error = %v, want Unauthenticated", err)
+	}
+}
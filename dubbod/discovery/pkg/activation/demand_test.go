@@ -220,7 +220,7 @@ func TestReportSnapshotReplacesTheWholeReporterView(t *testing.T) {
 	registry := NewRegistry()
 	reviews := Target{Namespace: "app", Name: "reviews"}
 
-	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 3, reviews: 2})
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 3, reviews: 2}, false)
 	if got := registry.Pending(orders); got != 3 {
 		t.Fatalf("orders pending = %d, want 3", got)
 	}
@@ -230,7 +230,7 @@ func TestReportSnapshotReplacesTheWholeReporterView(t *testing.T) {
 
 	// A target absent from the new snapshot has drained; there is no separate
 	// clear message, so its absence is what has to clear it.
-	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 1})
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 1}, false)
 	if got := registry.Pending(orders); got != 1 {
 		t.Fatalf("orders pending after replacement = %d, want 1", got)
 	}
@@ -239,7 +239,7 @@ func TestReportSnapshotReplacesTheWholeReporterView(t *testing.T) {
 	}
 
 	// An empty snapshot means the gateway drained everything.
-	registry.ReportSnapshot("gateway-a", nil)
+	registry.ReportSnapshot("gateway-a", nil, false)
 	if got := registry.Pending(orders); got != 0 {
 		t.Fatalf("orders pending after empty snapshot = %d, want 0", got)
 	}
@@ -250,13 +250,13 @@ func TestReportSnapshotReplacesTheWholeReporterView(t *testing.T) {
 func TestReportSnapshotLeavesOtherReportersAlone(t *testing.T) {
 	registry := NewRegistry()
 
-	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 2})
-	registry.ReportSnapshot("gateway-b", map[Target]int64{orders: 3})
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 2}, false)
+	registry.ReportSnapshot("gateway-b", map[Target]int64{orders: 3}, false)
 	if got := registry.Pending(orders); got != 5 {
 		t.Fatalf("pending = %d, want 5", got)
 	}
 
-	registry.ReportSnapshot("gateway-a", nil)
+	registry.ReportSnapshot("gateway-a", nil, false)
 	if got := registry.Pending(orders); got != 3 {
 		t.Fatalf("pending after gateway-a drained = %d, want 3", got)
 	}
@@ -267,15 +267,148 @@ func TestReportSnapshotNotifiesSubscribers(t *testing.T) {
 	updates, cancel := registry.Subscribe(orders)
 	defer cancel()
 
-	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 4})
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 4}, false)
 	if got := receive(t, updates); got != 4 {
 		t.Fatalf("update = %d, want 4", got)
 	}
 
 	// Dropping the target must wake the subscriber too, or a KEDA stream would
 	// never learn the workload can scale back down.
-	registry.ReportSnapshot("gateway-a", nil)
+	registry.ReportSnapshot("gateway-a", nil, false)
 	if got := receive(t, updates); got != 0 {
 		t.Fatalf("drain update = %d, want 0", got)
 	}
 }
+
+func TestRegistryRemembersWhenATargetWasLastInUse(t *testing.T) {
+	registry := NewRegistry()
+	now := time.Unix(1_700_000_000, 0)
+	registry.now = func() time.Time { return now }
+
+	if got := registry.LastActive(orders); !got.IsZero() {
+		t.Fatalf("last active = %v before any report, want zero", got)
+	}
+
+	registry.RecordRequests(orders, 4)
+	now = now.Add(time.Minute)
+	// Reporting nothing pending or sent is not activity.
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 0}, false)
+	registry.RecordRequests(orders, 0)
+	if got := registry.LastActive(orders); !got.Equal(now.Add(-time.Minute)) {
+		t.Fatalf("last active = %v, want the requests a minute ago", got)
+	}
+
+	// A held request is activity even when the reporter sent nothing on.
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 1}, false)
+	if got := registry.LastActive(orders); !got.Equal(now) {
+		t.Fatalf("last active = %v, want the held request now", got)
+	}
+}
+
+func TestMergeTakesOnlyNewerReports(t *testing.T) {
+	registry := NewRegistry()
+	now := time.Unix(1_000, 0)
+	registry.now = func() time.Time { return now }
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 2}, false)
+
+	// A peer's older view of gateway-a must not undo what it told us since.
+	merged := registry.Merge([]ReporterSnapshot{
+		{Reporter: "gateway-a", Pending: map[Target]int64{orders: 7}, Received: now.Add(-time.Second)},
+		{Reporter: "gateway-b", Pending: map[Target]int64{orders: 3, reviews: 1}, Received: now.Add(-time.Second)},
+	})
+	if merged != 1 || registry.Pending(orders) != 5 || registry.Pending(reviews) != 1 {
+		t.Fatalf("merged %d; pending orders = %d, reviews = %d; want 1, 5, 1",
+			merged, registry.Pending(orders), registry.Pending(reviews))
+	}
+
+	// A newer snapshot replaces the reporter's whole view, as its own would.
+	registry.Merge([]ReporterSnapshot{
+		{Reporter: "gateway-b", Pending: map[Target]int64{orders: 1}, Received: now},
+	})
+	if registry.Pending(orders) != 3 || registry.Pending(reviews) != 0 {
+		t.Fatalf("pending orders = %d, reviews = %d; want 3, 0", registry.Pending(orders), registry.Pending(reviews))
+	}
+	if direct, handedOff := registry.ReporterCounts(); direct != 1 || handedOff != 1 {
+		t.Fatalf("reporters = %d direct, %d handed off; want 1 and 1", direct, handedOff)
+	}
+}
+
+// Handing a report on must not refresh it: demand nobody is sending any more
+// has to expire everywhere a TTL after it was sent.
+func TestMergedReportsExpireFromWhenTheyWereSent(t *testing.T) {
+	registry := NewRegistry()
+	now := time.Unix(1_000, 0)
+	registry.now = func() time.Time { return now }
+
+	registry.Merge([]ReporterSnapshot{
+		{Reporter: "gateway-a", Pending: map[Target]int64{orders: 4}, Received: now.Add(-reporterTTL + time.Second)},
+		{Reporter: "gateway-b", Pending: map[Target]int64{orders: 1}, Received: now.Add(-reporterTTL - time.Second)},
+	})
+	if got := registry.Pending(orders); got != 4 {
+		t.Fatalf("pending = %d, want 4 without the expired report", got)
+	}
+	now = now.Add(2 * time.Second)
+	if got := registry.Pending(orders); got != 0 {
+		t.Fatalf("pending = %d, want 0 once the handed-off report aged out", got)
+	}
+}
+
+func TestReportsGroupEachReporterSnapshot(t *testing.T) {
+	registry := NewRegistry()
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 2, reviews: 0}, false)
+	registry.Report("gateway-b", orders, 1)
+
+	reports := registry.Reports()
+	if len(reports) != 2 {
+		t.Fatalf("reports = %+v, want one per reporter", reports)
+	}
+	for _, report := range reports {
+		if report.Reporter == "gateway-a" && (len(report.Pending) != 2 || report.Pending[orders] != 2) {
+			t.Fatalf("gateway-a = %+v, want both targets it reported", report)
+		}
+	}
+
+	// Taken by another replica, the reports reproduce the same totals.
+	peer := NewRegistry()
+	peer.Merge(reports)
+	if got := peer.Pending(orders); got != 3 {
+		t.Fatalf("peer pending = %d, want 3", got)
+	}
+}
+
+func TestCountsAllRequestsNeedsEveryLiveReporterToCount(t *testing.T) {
+	registry := NewRegistry()
+	now := time.Unix(1_000, 0)
+	registry.now = func() time.Time { return now }
+	if registry.CountsAllRequests() {
+		t.Fatal("counts all requests with no reporter at all")
+	}
+
+	registry.ReportSnapshot("workload-a", nil, true)
+	if !registry.CountsAllRequests() {
+		t.Fatal("does not count all requests with only a counting reporter")
+	}
+
+	// A gateway with nothing pending still passes traffic through.
+	registry.ReportSnapshot("gateway-a", nil, false)
+	if registry.CountsAllRequests() {
+		t.Fatal("counts all requests with a gateway that does not count")
+	}
+
+	// Handed off, the reporters keep what they said.
+	peer := NewRegistry()
+	peer.now = registry.now
+	peer.Merge(registry.Reports())
+	if peer.CountsAllRequests() {
+		t.Fatal("peer counts all requests although a handed-off gateway does not count")
+	}
+
+	registry.Forget("gateway-a")
+	if !registry.CountsAllRequests() {
+		t.Fatal("a forgotten gateway still holds idle detection back")
+	}
+	now = now.Add(reporterTTL + time.Second)
+	if registry.CountsAllRequests() {
+		t.Fatal("an expired reporter still counts")
+	}
+}
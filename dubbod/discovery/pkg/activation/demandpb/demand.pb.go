@@ -53,9 +53,13 @@ type DemandSnapshot struct {
 	Reporter string `protobuf:"bytes,1,opt,name=reporter,proto3" json:"reporter,omitempty"`
 	// Targets with requests waiting. A target absent from a snapshot has no
 	// pending requests at that gateway; there is no separate clear message.
-	Targets       []*TargetDemand `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Targets []*TargetDemand `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"`
+	// Set by reporters that fill requests for every target they send traffic
+	// to. Idle detection only runs while every live reporter sets it: one that
+	// does not may be calling a target the control plane would call idle.
+	CountsRequests bool `protobuf:"varint,3,opt,name=counts_requests,json=countsRequests,proto3" json:"counts_requests,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *DemandSnapshot) Reset() {
@@ -102,6 +106,13 @@ func (x *DemandSnapshot) GetTargets() []*TargetDemand {
 	return nil
 }
 
+func (x *DemandSnapshot) GetCountsRequests() bool {
+	if x != nil {
+		return x.CountsRequests
+	}
+	return false
+}
+
 type TargetDemand struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Namespace of the Service being activated.
@@ -109,7 +120,13 @@ type TargetDemand struct {
 	// Name of the Service being activated.
 	Service string `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
 	// Requests this gateway is currently holding for the target.
-	Pending       int64 `protobuf:"varint,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	Pending int64 `protobuf:"varint,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	// Requests the reporter sent or held for the target since its previous
+	// snapshot, whether or not the target had endpoints. The control plane
+	// decides a target is idle from this count, so a reporter that sets
+	// counts_requests lists every target it saw traffic for, with pending zero
+	// when nothing is waiting.
+	Requests      int64 `protobuf:"varint,4,opt,name=requests,proto3" json:"requests,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -165,6 +182,13 @@ func (x *TargetDemand) GetPending() int64 {
 	return 0
 }
 
+func (x *TargetDemand) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
 // ReportSummary is returned once the stream closes. It exists so a gateway can
 // tell an orderly shutdown from a connection that was cut.
 type ReportSummary struct {
@@ -212,22 +236,214 @@ func (x *ReportSummary) GetSnapshots() int64 {
 	return 0
 }
 
+type HandoffRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identity of the replica asking, for logs on the answering side.
+	Replica       string `protobuf:"bytes,1,opt,name=replica,proto3" json:"replica,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandoffRequest) Reset() {
+	*x = HandoffRequest{}
+	mi := &file_demand_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandoffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandoffRequest) ProtoMessage() {}
+
+func (x *HandoffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandoffRequest.ProtoReflect.Descriptor instead.
+func (*HandoffRequest) Descriptor() ([]byte, []int) {
+	return file_demand_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HandoffRequest) GetReplica() string {
+	if x != nil {
+		return x.Replica
+	}
+	return ""
+}
+
+// DemandHandoff is one replica's view of the demand reported to the mesh.
+type DemandHandoff struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identity of the answering replica. A replica that reaches itself through
+	// the shared Service name recognizes its own answer by it.
+	Replica string `protobuf:"bytes,1,opt,name=replica,proto3" json:"replica,omitempty"`
+	// The latest snapshot of every reporter the replica has heard from, directly
+	// or through another replica, within the report TTL.
+	Reporters     []*ReporterDemand `protobuf:"bytes,2,rep,name=reporters,proto3" json:"reporters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DemandHandoff) Reset() {
+	*x = DemandHandoff{}
+	mi := &file_demand_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DemandHandoff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DemandHandoff) ProtoMessage() {}
+
+func (x *DemandHandoff) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DemandHandoff.ProtoReflect.Descriptor instead.
+func (*DemandHandoff) Descriptor() ([]byte, []int) {
+	return file_demand_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DemandHandoff) GetReplica() string {
+	if x != nil {
+		return x.Replica
+	}
+	return ""
+}
+
+func (x *DemandHandoff) GetReporters() []*ReporterDemand {
+	if x != nil {
+		return x.Reporters
+	}
+	return nil
+}
+
+type ReporterDemand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Reporter identity, as the reporter or, for workloads, the replica that
+	// authenticated it named it.
+	Reporter string `protobuf:"bytes,1,opt,name=reporter,proto3" json:"reporter,omitempty"`
+	// The reporter's last snapshot, in the same form it was sent.
+	Targets []*TargetDemand `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"`
+	// Milliseconds since the snapshot was received. Ages rather than timestamps
+	// keep clock skew between replicas out of the TTL: a report handed on keeps
+	// aging from when its reporter sent it, so handoff can never keep demand
+	// alive that the reporter has stopped refreshing.
+	AgeMillis int64 `protobuf:"varint,3,opt,name=age_millis,json=ageMillis,proto3" json:"age_millis,omitempty"`
+	// Whether the reporter counts requests, as it said in its snapshot.
+	CountsRequests bool `protobuf:"varint,4,opt,name=counts_requests,json=countsRequests,proto3" json:"counts_requests,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReporterDemand) Reset() {
+	*x = ReporterDemand{}
+	mi := &file_demand_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReporterDemand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReporterDemand) ProtoMessage() {}
+
+func (x *ReporterDemand) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReporterDemand.ProtoReflect.Descriptor instead.
+func (*ReporterDemand) Descriptor() ([]byte, []int) {
+	return file_demand_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReporterDemand) GetReporter() string {
+	if x != nil {
+		return x.Reporter
+	}
+	return ""
+}
+
+func (x *ReporterDemand) GetTargets() []*TargetDemand {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *ReporterDemand) GetAgeMillis() int64 {
+	if x != nil {
+		return x.AgeMillis
+	}
+	return 0
+}
+
+func (x *ReporterDemand) GetCountsRequests() bool {
+	if x != nil {
+		return x.CountsRequests
+	}
+	return false
+}
+
 var File_demand_proto protoreflect.FileDescriptor
 
 const file_demand_proto_rawDesc = "" +
 	"\n" +
-	"\fdemand.proto\x12\x19dubbo.activation.v1alpha1\"o\n" +
+	"\fdemand.proto\x12\x19dubbo.activation.v1alpha1\"\x98\x01\n" +
 	"\x0eDemandSnapshot\x12\x1a\n" +
 	"\breporter\x18\x01 \x01(\tR\breporter\x12A\n" +
-	"\atargets\x18\x02 \x03(\v2'.dubbo.activation.v1alpha1.TargetDemandR\atargets\"`\n" +
+	"\atargets\x18\x02 \x03(\v2'.dubbo.activation.v1alpha1.TargetDemandR\atargets\x12'\n" +
+	"\x0fcounts_requests\x18\x03 \x01(\bR\x0ecountsRequests\"|\n" +
 	"\fTargetDemand\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x18\n" +
 	"\aservice\x18\x02 \x01(\tR\aservice\x12\x18\n" +
-	"\apending\x18\x03 \x01(\x03R\apending\"-\n" +
+	"\apending\x18\x03 \x01(\x03R\apending\x12\x1a\n" +
+	"\brequests\x18\x04 \x01(\x03R\brequests\"-\n" +
 	"\rReportSummary\x12\x1c\n" +
-	"\tsnapshots\x18\x01 \x01(\x03R\tsnapshots2u\n" +
+	"\tsnapshots\x18\x01 \x01(\x03R\tsnapshots\"*\n" +
+	"\x0eHandoffRequest\x12\x18\n" +
+	"\areplica\x18\x01 \x01(\tR\areplica\"r\n" +
+	"\rDemandHandoff\x12\x18\n" +
+	"\areplica\x18\x01 \x01(\tR\areplica\x12G\n" +
+	"\treporters\x18\x02 \x03(\v2).dubbo.activation.v1alpha1.ReporterDemandR\treporters\"\xb7\x01\n" +
+	"\x0eReporterDemand\x12\x1a\n" +
+	"\breporter\x18\x01 \x01(\tR\breporter\x12A\n" +
+	"\atargets\x18\x02 \x03(\v2'.dubbo.activation.v1alpha1.TargetDemandR\atargets\x12\x1d\n" +
+	"\n" +
+	"age_millis\x18\x03 \x01(\x03R\tageMillis\x12'\n" +
+	"\x0fcounts_requests\x18\x04 \x01(\bR\x0ecountsRequests2\xd7\x01\n" +
 	"\x10ActivationDemand\x12a\n" +
-	"\x06Report\x12).dubbo.activation.v1alpha1.DemandSnapshot\x1a(.dubbo.activation.v1alpha1.ReportSummary\"\x00(\x01BMZKgithub.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation/demandpbb\x06proto3"
+	"\x06Report\x12).dubbo.activation.v1alpha1.DemandSnapshot\x1a(.dubbo.activation.v1alpha1.ReportSummary\"\x00(\x01\x12`\n" +
+	"\aHandoff\x12).dubbo.activation.v1alpha1.HandoffRequest\x1a(.dubbo.activation.v1alpha1.DemandHandoff\"\x00BMZKgithub.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation/demandpbb\x06proto3"
 
 var (
 	file_demand_proto_rawDescOnce sync.Once
@@ -241,21 +457,28 @@ func file_demand_proto_rawDescGZIP() []byte {
 	return file_demand_proto_rawDescData
 }
 
-var file_demand_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_demand_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_demand_proto_goTypes = []any{
 	(*DemandSnapshot)(nil), // 0: dubbo.activation.v1alpha1.DemandSnapshot
 	(*TargetDemand)(nil),   // 1: dubbo.activation.v1alpha1.TargetDemand
 	(*ReportSummary)(nil),  // 2: dubbo.activation.v1alpha1.ReportSummary
+	(*HandoffRequest)(nil), // 3: dubbo.activation.v1alpha1.HandoffRequest
+	(*DemandHandoff)(nil),  // 4: dubbo.activation.v1alpha1.DemandHandoff
+	(*ReporterDemand)(nil), // 5: dubbo.activation.v1alpha1.ReporterDemand
 }
 var file_demand_proto_depIdxs = []int32{
 	1, // 0: dubbo.activation.v1alpha1.DemandSnapshot.targets:type_name -> dubbo.activation.v1alpha1.TargetDemand
-	0, // 1: dubbo.activation.v1alpha1.ActivationDemand.Report:input_type -> dubbo.activation.v1alpha1.DemandSnapshot
-	2, // 2: dubbo.activation.v1alpha1.ActivationDemand.Report:output_type -> dubbo.activation.v1alpha1.ReportSummary
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 1: dubbo.activation.v1alpha1.DemandHandoff.reporters:type_name -> dubbo.activation.v1alpha1.ReporterDemand
+	1, // 2: dubbo.activation.v1alpha1.ReporterDemand.targets:type_name -> dubbo.activation.v1alpha1.TargetDemand
+	0, // 3: dubbo.activation.v1alpha1.ActivationDemand.Report:input_type -> dubbo.activation.v1alpha1.DemandSnapshot
+	3, // 4: dubbo.activation.v1alpha1.ActivationDemand.Handoff:input_type -> dubbo.activation.v1alpha1.HandoffRequest
+	2, // 5: dubbo.activation.v1alpha1.ActivationDemand.Report:output_type -> dubbo.activation.v1alpha1.ReportSummary
+	4, // 6: dubbo.activation.v1alpha1.ActivationDemand.Handoff:output_type -> dubbo.activation.v1alpha1.DemandHandoff
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_demand_proto_init() }
@@ -269,7 +492,7 @@ func file_demand_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_demand_proto_rawDesc), len(file_demand_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -39,7 +39,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ActivationDemand_Report_FullMethodName = "/dubbo.activation.v1alpha1.ActivationDemand/Report"
+	ActivationDemand_Report_FullMethodName  = "/dubbo.activation.v1alpha1.ActivationDemand/Report"
+	ActivationDemand_Handoff_FullMethodName = "/dubbo.activation.v1alpha1.ActivationDemand/Handoff"
 )
 
 // ActivationDemandClient is the client API for ActivationDemand service.
@@ -61,6 +62,13 @@ type ActivationDemandClient interface {
 	// drops that gateway's demand immediately instead of waiting for it to age
 	// out, so a gateway that shuts down cleanly cannot hold a workload up.
 	Report(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[DemandSnapshot, ReportSummary], error)
+	// Handoff returns every report the replica currently holds. Replicas call
+	// each other with it, so one that has just started, or whose stream from a
+	// gateway was cut, can answer KEDA before that gateway reaches it.
+	//
+	// It is served on the plaintext activation port only; a workload has no
+	// business reading the demand of others.
+	Handoff(ctx context.Context, in *HandoffRequest, opts ...grpc.CallOption) (*DemandHandoff, error)
 }
 
 type activationDemandClient struct {
@@ -84,6 +92,16 @@ func (c *activationDemandClient) Report(ctx context.Context, opts ...grpc.CallOp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ActivationDemand_ReportClient = grpc.ClientStreamingClient[DemandSnapshot, ReportSummary]
 
+func (c *activationDemandClient) Handoff(ctx context.Context, in *HandoffRequest, opts ...grpc.CallOption) (*DemandHandoff, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DemandHandoff)
+	err := c.cc.Invoke(ctx, ActivationDemand_Handoff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ActivationDemandServer is the server API for ActivationDemand service.
 // All implementations must embed UnimplementedActivationDemandServer
 // for forward compatibility.
@@ -103,6 +121,13 @@ type ActivationDemandServer interface {
 	// drops that gateway's demand immediately instead of waiting for it to age
 	// out, so a gateway that shuts down cleanly cannot hold a workload up.
 	Report(grpc.ClientStreamingServer[DemandSnapshot, ReportSummary]) error
+	// Handoff returns every report the replica currently holds. Replicas call
+	// each other with it, so one that has just started, or whose stream from a
+	// gateway was cut, can answer KEDA before that gateway reaches it.
+	//
+	// It is served on the plaintext activation port only; a workload has no
+	// business reading the demand of others.
+	Handoff(context.Context, *HandoffRequest) (*DemandHandoff, error)
 	mustEmbedUnimplementedActivationDemandServer()
 }
 
@@ -116,6 +141,9 @@ type UnimplementedActivationDemandServer struct{}
 func (UnimplementedActivationDemandServer) Report(grpc.ClientStreamingServer[DemandSnapshot, ReportSummary]) error {
 	return status.Error(codes.Unimplemented, "method Report not implemented")
 }
+func (UnimplementedActivationDemandServer) Handoff(context.Context, *HandoffRequest) (*DemandHandoff, error) {
+	return nil, status.Error(codes.Unimplemented, "method Handoff not implemented")
+}
 func (UnimplementedActivationDemandServer) mustEmbedUnimplementedActivationDemandServer() {}
 func (UnimplementedActivationDemandServer) testEmbeddedByValue()                          {}
 
@@ -144,13 +172,36 @@ func _ActivationDemand_Report_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ActivationDemand_ReportServer = grpc.ClientStreamingServer[DemandSnapshot, ReportSummary]
 
+func _ActivationDemand_Handoff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandoffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivationDemandServer).Handoff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivationDemand_Handoff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivationDemandServer).Handoff(ctx, req.(*HandoffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ActivationDemand_ServiceDesc is the grpc.ServiceDesc for ActivationDemand service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var ActivationDemand_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "dubbo.activation.v1alpha1.ActivationDemand",
 	HandlerType: (*ActivationDemandServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handoff",
+			Handler:    _ActivationDemand_Handoff_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Report",
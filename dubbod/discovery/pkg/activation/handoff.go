@@ -0,0 +1,243 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation/demandpb"
+	"github.com/apache/dubbo-kubernetes/pkg/monitoring"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// handoffInterval is how often a replica exchanges reports with the others.
+// Well under reporterTTL, so a report a gateway could only get to one replica
+// reaches the rest long before it would expire.
+const handoffInterval = 10 * time.Second
+
+// handoffTimeout bounds one round, so a replica that hangs cannot hold up the
+// first round a starting replica waits for.
+const handoffTimeout = 5 * time.Second
+
+var (
+	resultTag = monitoring.CreateLabel("result")
+	sourceTag = monitoring.CreateLabel("source")
+
+	handoffs = monitoring.NewSum(
+		"dubbod_activation_handoffs",
+		"Demand handoffs with other dubbod replicas, labeled by result.",
+		monitoring.WithLabels("result"),
+	)
+	handoffSuccesses = handoffs.With(resultTag.Value("success"))
+	handoffErrors    = handoffs.With(resultTag.Value("error"))
+
+	handoffReporters = monitoring.NewSum(
+		"dubbod_activation_handoff_reporters",
+		"Reporter snapshots taken from another replica because they were newer than this replica's. "+
+			"Growing steadily, it means reports are not reaching every replica directly.",
+	)
+
+	demandSkew = monitoring.NewDistribution(
+		"dubbod_activation_demand_skew",
+		"Per target and peer, the difference between the pending requests this replica and the peer held when they exchanged reports.",
+		[]float64{0, 1, 2, 5, 10, 50, 100, 1000},
+	)
+
+	demandReporters = monitoring.NewGauge(
+		"dubbod_activation_demand_reporters",
+		"Reporters this replica holds live demand from, labeled by whether they reported directly or were handed off by a peer.",
+		monitoring.WithLabels("source"),
+	)
+	directReporters    = demandReporters.With(sourceTag.Value("direct"))
+	handedOffReporters = demandReporters.With(sourceTag.Value("handoff"))
+)
+
+// Handoff exchanges demand reports with the other dubbod replicas.
+//
+// Gateways report to every replica, but a replica that has just started has
+// heard from none of them, and one whose stream from a gateway was cut misses
+// that gateway until it reconnects. Either would answer KEDA with too little
+// demand. Taking the reports the other replicas hold closes both gaps: at start
+// before the replica reports ready, and every handoffInterval after.
+type Handoff struct {
+	registry *Registry
+	replica  string
+	// peers is a host:port resolving to every replica, this one included.
+	peers string
+
+	// lookup and fetch are swappable so rounds can be tested without DNS or
+	// real replicas.
+	lookup func(ctx context.Context, host string) ([]string, error)
+	fetch  func(ctx context.Context, address string, request *demandpb.HandoffRequest) (*demandpb.DemandHandoff, error)
+
+	synced atomic.Bool
+}
+
+func NewHandoff(registry *Registry, replica, peers string) *Handoff {
+	return &Handoff{
+		registry: registry,
+		replica:  replica,
+		peers:    peers,
+		lookup:   net.DefaultResolver.LookupHost,
+		fetch:    fetchHandoff,
+	}
+}
+
+// HasSynced reports whether the first round has finished, successfully or
+// not. A replica with no reachable peer has nothing to wait for.
+func (h *Handoff) HasSynced() bool {
+	return h.synced.Load()
+}
+
+// Run exchanges reports once immediately and then every handoffInterval until
+// stop is closed.
+func (h *Handoff) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(handoffInterval)
+	defer ticker.Stop()
+	for {
+		h.round()
+		h.synced.Store(true)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// round takes the reports of every peer it can reach.
+func (h *Handoff) round() {
+	ctx, cancel := context.WithTimeout(context.Background(), handoffTimeout)
+	defer cancel()
+
+	host, port, err := net.SplitHostPort(h.peers)
+	if err != nil {
+		logger.Errorf("invalid activation peer address %q: %v", h.peers, err)
+		return
+	}
+	addresses, err := h.lookup(ctx, host)
+	if err != nil {
+		logger.Warnf("unable to resolve activation peers %s: %v", host, err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	answers := make([]*demandpb.DemandHandoff, len(addresses))
+	for i, address := range addresses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			answer, err := h.fetch(ctx, net.JoinHostPort(address, port), &demandpb.HandoffRequest{Replica: h.replica})
+			if err != nil {
+				handoffErrors.Increment()
+				logger.Debugf("demand handoff from %s failed: %v", address, err)
+				return
+			}
+			handoffSuccesses.Increment()
+			answers[i] = answer
+		}()
+	}
+	wg.Wait()
+
+	for _, answer := range answers {
+		if answer == nil || answer.GetReplica() == h.replica {
+			continue
+		}
+		peer := snapshotsOf(answer, h.registry.now())
+		for _, skew := range demandSkewOf(h.registry.Reports(), peer) {
+			demandSkew.Record(float64(skew))
+		}
+		if merged := h.registry.Merge(peer); merged > 0 {
+			handoffReporters.RecordInt(int64(merged))
+			logger.Infof("took %d newer demand reports from replica %s", merged, answer.GetReplica())
+		}
+	}
+
+	direct, handedOff := h.registry.ReporterCounts()
+	directReporters.RecordInt(int64(direct))
+	handedOffReporters.RecordInt(int64(handedOff))
+}
+
+// snapshotsOf turns a peer's answer back into the snapshots it holds, dating
+// each one from its age so the two replicas' clocks never meet.
+func snapshotsOf(answer *demandpb.DemandHandoff, now time.Time) []ReporterSnapshot {
+	out := make([]ReporterSnapshot, 0, len(answer.GetReporters()))
+	for _, item := range answer.GetReporters() {
+		reporter := strings.TrimSpace(item.GetReporter())
+		if reporter == "" {
+			continue
+		}
+		pending := make(map[Target]int64, len(item.GetTargets()))
+		for _, target := range item.GetTargets() {
+			if target.GetNamespace() == "" || target.GetService() == "" {
+				continue
+			}
+			pending[Target{Namespace: target.GetNamespace(), Name: target.GetService()}] += target.GetPending()
+		}
+		out = append(out, ReporterSnapshot{
+			Reporter:       reporter,
+			Pending:        pending,
+			Received:       now.Add(-time.Duration(item.GetAgeMillis()) * time.Millisecond),
+			CountsRequests: item.GetCountsRequests(),
+		})
+	}
+	return out
+}
+
+// demandSkewOf returns, per target either side holds demand for, how far apart
+// the two sides' pending totals are.
+func demandSkewOf(local, peer []ReporterSnapshot) map[Target]int64 {
+	totals := func(snapshots []ReporterSnapshot) map[Target]int64 {
+		out := map[Target]int64{}
+		for _, snapshot := range snapshots {
+			for target, pending := range snapshot.Pending {
+				out[target] += pending
+			}
+		}
+		return out
+	}
+	ours, theirs := totals(local), totals(peer)
+	skew := map[Target]int64{}
+	for target, pending := range ours {
+		difference := pending - theirs[target]
+		if difference < 0 {
+			difference = -difference
+		}
+		skew[target] = difference
+	}
+	for target, pending := range theirs {
+		if _, ok := ours[target]; !ok {
+			skew[target] = pending
+		}
+	}
+	return skew
+}
+
+func fetchHandoff(ctx context.Context, address string, request *demandpb.HandoffRequest) (*demandpb.DemandHandoff, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return demandpb.NewActivationDemandClient(conn).Handoff(ctx, request)
+}
@@ -0,0 +1,163 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation/demandpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newHandoffTest hands off between replicas named by their addresses, each
+// answering from its own registry.
+func newHandoffTest(replica string, peers map[string]*Registry) *Handoff {
+	handoff := NewHandoff(NewRegistry(), replica, "dubbod-activation-replicas:26030")
+	peers[replica] = handoff.registry
+	handoff.lookup = func(context.Context, string) ([]string, error) {
+		addresses := make([]string, 0, len(peers))
+		for address := range peers {
+			addresses = append(addresses, address)
+		}
+		return addresses, nil
+	}
+	handoff.fetch = func(ctx context.Context, address string, request *demandpb.HandoffRequest) (*demandpb.DemandHandoff, error) {
+		host, _, _ := net.SplitHostPort(address)
+		registry := peers[host]
+		if registry == nil {
+			return nil, errors.New("connection refused")
+		}
+		service := NewDemandService(registry)
+		service.replica = host
+		return service.Handoff(ctx, request)
+	}
+	return handoff
+}
+
+func TestStartingReplicaTakesTheDemandOthersHold(t *testing.T) {
+	peers := map[string]*Registry{}
+	running := NewRegistry()
+	peers["10.0.0.1"] = running
+	running.ReportSnapshot("gateway-a", map[Target]int64{orders: 3}, false)
+	running.ReportSnapshot("gateway-b", map[Target]int64{orders: 1}, false)
+
+	starting := newHandoffTest("10.0.0.2", peers)
+	peers["10.0.0.3"] = nil // a replica that does not answer
+	if starting.HasSynced() {
+		t.Fatal("synced before the first round")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go starting.Run(stop)
+	waitFor(t, starting.HasSynced, "first handoff round did not finish")
+
+	if got := starting.registry.Pending(orders); got != 4 {
+		t.Fatalf("pending after handoff = %d, want 4", got)
+	}
+	if direct, handedOff := starting.registry.ReporterCounts(); direct != 0 || handedOff != 2 {
+		t.Fatalf("reporters = %d direct, %d handed off; want 0 and 2", direct, handedOff)
+	}
+}
+
+// A gateway that shut down reaches every replica's stream at slightly
+// different times. The replica that forgot it first must not take its demand
+// back from one that has not yet.
+func TestForgottenReporterIsNotTakenBackFromAPeer(t *testing.T) {
+	now := time.Unix(1_000, 0)
+	clock := func() time.Time { return now }
+	peers := map[string]*Registry{}
+	lagging := NewRegistry()
+	lagging.now = clock
+	peers["10.0.0.1"] = lagging
+	local := newHandoffTest("10.0.0.2", peers)
+	local.registry.now = clock
+
+	lagging.ReportSnapshot("gateway-a", map[Target]int64{orders: 3}, false)
+	local.registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 3}, false)
+	now = now.Add(time.Second)
+	local.registry.Forget("gateway-a")
+
+	local.round()
+	if got := local.registry.Pending(orders); got != 0 {
+		t.Fatalf("pending after handoff = %d, want the forgotten gateway to stay gone", got)
+	}
+
+	// Reporting to the peer after it was forgotten here, the gateway is back.
+	now = now.Add(time.Second)
+	lagging.ReportSnapshot("gateway-a", map[Target]int64{orders: 2}, false)
+	local.round()
+	if got := local.registry.Pending(orders); got != 2 {
+		t.Fatalf("pending after handoff = %d, want the newer report taken", got)
+	}
+}
+
+func TestDemandSkewComparesTotalsPerTarget(t *testing.T) {
+	local := []ReporterSnapshot{
+		{Reporter: "gateway-a", Pending: map[Target]int64{orders: 3}},
+		{Reporter: "gateway-b", Pending: map[Target]int64{orders: 1}},
+	}
+	peer := []ReporterSnapshot{
+		{Reporter: "gateway-a", Pending: map[Target]int64{orders: 3, reviews: 2}},
+	}
+	skew := demandSkewOf(local, peer)
+	if len(skew) != 2 || skew[orders] != 1 || skew[reviews] != 2 {
+		t.Fatalf("skew = %v, want orders 1 and reviews 2", skew)
+	}
+}
+
+func TestHandoffAnswersOverGRPCWithAges(t *testing.T) {
+	registry := NewRegistry()
+	now := time.Unix(1_000, 0)
+	registry.now = func() time.Time { return now }
+	registry.ReportSnapshot("gateway-a", map[Target]int64{orders: 2}, false)
+	now = now.Add(1500 * time.Millisecond)
+
+	service := NewDemandService(registry)
+	service.replica = "dubbod-0"
+	answer, err := startDemandService(t, service).Handoff(context.Background(), &demandpb.HandoffRequest{Replica: "dubbod-1"})
+	if err != nil {
+		t.Fatalf("Handoff() error = %v", err)
+	}
+	if answer.GetReplica() != "dubbod-0" || len(answer.GetReporters()) != 1 {
+		t.Fatalf("answer = %v, want gateway-a from dubbod-0", answer)
+	}
+	reporter := answer.GetReporters()[0]
+	if reporter.GetReporter() != "gateway-a" || reporter.GetAgeMillis() != 1500 || reporter.GetTargets()[0].GetPending() != 2 {
+		t.Fatalf("reporter = %v, want gateway-a with 2 pending, 1.5s old", reporter)
+	}
+
+	snapshots := snapshotsOf(answer, time.Unix(2_000, 0))
+	if want := time.Unix(2_000, 0).Add(-1500 * time.Millisecond); !snapshots[0].Received.Equal(want) {
+		t.Fatalf("received = %v, want %v on the taker's clock", snapshots[0].Received, want)
+	}
+}
+
+func TestWorkloadsCannotReadTheMeshDemand(t *testing.T) {
+	service := NewWorkloadDemandService(NewRegistry(), func(context.Context) (string, error) {
+		return "spiffe://cluster.local/ns/app/sa/orders", nil
+	})
+	_, err := startDemandService(t, service).Handoff(context.Background(), &demandpb.HandoffRequest{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Handoff() error = %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,334 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	defaultDrainPeriod = 30 * time.Second
+
+	// minIdleWindow keeps the window well above the interval reporters
+	// publish at. A shorter one would call a target idle between two reports
+	// of steady traffic.
+	minIdleWindow = time.Minute
+
+	// idleCheckInterval is how often idle windows and drain periods are
+	// checked. It bounds how late a transition can happen, not how fast new
+	// traffic is noticed: a held request keeps the scaler active on its own.
+	idleCheckInterval = time.Second
+)
+
+// IdleSettings is the idle detection of one policy. A zero Window leaves
+// deactivation to the autoscaler.
+type IdleSettings struct {
+	Window time.Duration
+	Drain  time.Duration
+}
+
+// IdleSettingsOf reads spec.idle of a policy. Once no request for the target
+// was seen for its window, its callers are rerouted to the Activator, and
+// after the drain period the scaler stops keeping it up. Without it the
+// autoscaler's cooldown alone decides.
+func IdleSettingsOf(policy *clientnetworking.ServiceActivationPolicy) (IdleSettings, error) {
+	return idleSettingsFromSpec(policy.Spec.GetIdle())
+}
+
+func idleSettingsFromSpec(idle *networking.ActivationIdle) (IdleSettings, error) {
+	if idle == nil {
+		return IdleSettings{}, nil
+	}
+	if idle.GetWindow() == nil {
+		return IdleSettings{}, fmt.Errorf("idle.window is required")
+	}
+	window := idle.GetWindow().AsDuration()
+	if window < minIdleWindow {
+		return IdleSettings{}, fmt.Errorf("idle.window must be at least %v, got %v", minIdleWindow, window)
+	}
+	settings := IdleSettings{Window: window, Drain: defaultDrainPeriod}
+	if idle.GetDrainPeriod() != nil {
+		if settings.Drain = idle.GetDrainPeriod().AsDuration(); settings.Drain <= 0 {
+			return IdleSettings{}, fmt.Errorf("idle.drainPeriod must be greater than zero, got %v", settings.Drain)
+		}
+	}
+	return settings, nil
+}
+
+// ActivationRouting reports what the data plane can do for a target. It is an
+// interface so idle detection can be tested without a push context.
+type ActivationRouting interface {
+	// Serving reports whether the target has endpoints.
+	Serving(Target) bool
+	// CanReroute reports whether EDS can send the target's callers to the
+	// Activator of its namespace, which needs an Activator with endpoints.
+	CanReroute(Target) bool
+}
+
+type idlePhase int
+
+const (
+	// idleActive targets are in use, or were within their idle window.
+	idleActive idlePhase = iota
+	// idleDraining targets have callers rerouted to the Activator, and are
+	// still reported active while those callers pick up the reroute.
+	idleDraining
+	// idleDrained targets are reported inactive, so the autoscaler can take
+	// them to zero.
+	idleDrained
+)
+
+type idleState struct {
+	phase idlePhase
+	// since is when the current phase began. Activity older than that belongs
+	// to the previous phase.
+	since time.Time
+}
+
+// IdleTracker decides when an activated Service has gone idle, from the
+// requests gateways and proxyless workloads report.
+//
+// A target is only idle if nobody sent it a request, and only reporters that
+// count requests can say so. While any live reporter does not, no target
+// starts draining and a drain in progress is called off.
+//
+// Deactivation runs in two steps so no request is dropped on the way to zero.
+// First the target's callers are rerouted to the Activator, which holds what
+// arrives from then on; only after the drain period does the scaler stop
+// reporting the target active. A request seen in either step cancels it.
+//
+// Each replica decides on its own, from the reports broadcast to all of them,
+// and a replica that restarts starts every idle window afresh.
+type IdleTracker struct {
+	registry *Registry
+	// warm reports targets held warm ahead of their traffic, if set. They are
+	// never drained.
+	warm func(Target) bool
+
+	mu       sync.Mutex
+	policies map[types.NamespacedName]trackedPolicy
+	states   map[Target]*idleState
+
+	// now is swappable so transitions can be tested without sleeping.
+	now func() time.Time
+}
+
+type trackedPolicy struct {
+	target   Target
+	settings IdleSettings
+}
+
+func NewIdleTracker(registry *Registry) *IdleTracker {
+	return &IdleTracker{
+		registry: registry,
+		policies: map[types.NamespacedName]trackedPolicy{},
+		states:   map[Target]*idleState{},
+		now:      time.Now,
+	}
+}
+
+// Track starts, updates or, for zero settings, stops idle detection for a
+// policy.
+func (t *IdleTracker) Track(policy types.NamespacedName, target Target, settings IdleSettings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if settings.Window <= 0 {
+		delete(t.policies, policy)
+		return
+	}
+	t.policies[policy] = trackedPolicy{target: target, settings: settings}
+}
+
+// Forget stops idle detection for a deleted policy.
+func (t *IdleTracker) Forget(policy types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.policies, policy)
+}
+
+// Retained reports whether the scaler has to keep a target up with nothing
+// pending. Targets without idle detection are never retained, which leaves
+// them to the autoscaler's cooldown as before.
+func (t *IdleTracker) Retained(target Target) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.states[target]
+	return ok && state.phase != idleDrained
+}
+
+// DrainingServices lists the targets whose callers have to be rerouted to the
+// Activator although they may still have endpoints.
+func (t *IdleTracker) DrainingServices() []types.NamespacedName {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []types.NamespacedName
+	for target, state := range t.states {
+		if state.phase != idleActive {
+			out = append(out, types.NamespacedName{Namespace: target.Namespace, Name: target.Name})
+		}
+	}
+	slices.SortFunc(out, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return out
+}
+
+// Run checks idle windows until stop is closed. onChange is called whenever
+// the set of draining targets changes, so EDS can be pushed.
+func (t *IdleTracker) Run(stop <-chan struct{}, routing ActivationRouting, onChange func()) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			t.check(routing, onChange)
+		}
+	}
+}
+
+// check moves every tracked target one step through its idle phases.
+func (t *IdleTracker) check(routing ActivationRouting, onChange func()) {
+	t.mu.Lock()
+	now := t.now()
+	routed := false
+	var scaled []Target
+
+	counting := t.registry.CountsAllRequests()
+	settings := t.settingsLocked()
+	for target, state := range t.states {
+		if _, ok := settings[target]; ok {
+			continue
+		}
+		// The policy is gone or no longer detects idleness; the target goes
+		// back to plain activation.
+		delete(t.states, target)
+		if state.phase != idleActive {
+			routed = true
+			scaled = append(scaled, target)
+		}
+	}
+
+	for target, current := range settings {
+		state, ok := t.states[target]
+		if !ok {
+			// A target first seen without endpoints is already at zero.
+			// Starting it active would have the scaler wake every idle
+			// Service each time dubbod restarts.
+			state = &idleState{phase: idleActive, since: now}
+			if routing.Serving(target) {
+				scaled = append(scaled, target)
+			} else {
+				state.phase = idleDrained
+				routed = true
+			}
+			t.states[target] = state
+		}
+
+		lastActive := t.registry.LastActive(target)
+		if t.warm != nil && t.warm(target) {
+			lastActive = now
+		}
+		used := lastActive.After(state.since)
+		switch state.phase {
+		case idleActive:
+			if lastActive.Before(state.since) {
+				lastActive = state.since
+			}
+			if now.Sub(lastActive) < current.settings.Window {
+				continue
+			}
+			// Traffic could be reaching the target unseen. Restart the window
+			// so it runs in full once every reporter counts.
+			if !counting {
+				state.since = now
+				continue
+			}
+			// Without an Activator to catch them, callers would be left with
+			// empty EDS once the target is gone. Stay up until one exists.
+			if !routing.CanReroute(target) {
+				continue
+			}
+			*state = idleState{phase: idleDraining, since: now}
+			routed = true
+			logger.Infof("%s/%s idle for %v; rerouting its callers to the Activator",
+				target.Namespace, target.Name, current.settings.Window)
+		case idleDraining:
+			if !counting {
+				*state = idleState{phase: idleActive, since: now}
+				routed = true
+				logger.Infof("%s/%s may be in use by a reporter that does not count requests; restoring its endpoints",
+					target.Namespace, target.Name)
+				continue
+			}
+			if used || !routing.CanReroute(target) {
+				*state = idleState{phase: idleActive, since: now}
+				routed = true
+				logger.Infof("%s/%s in use again while draining; restoring its endpoints", target.Namespace, target.Name)
+				continue
+			}
+			if now.Sub(state.since) >= current.settings.Drain {
+				*state = idleState{phase: idleDrained, since: now}
+				scaled = append(scaled, target)
+				logger.Infof("%s/%s drained; reporting it inactive", target.Namespace, target.Name)
+			}
+		case idleDrained:
+			if used {
+				*state = idleState{phase: idleActive, since: now}
+				routed = true
+				scaled = append(scaled, target)
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	for _, target := range scaled {
+		t.registry.touch(target)
+	}
+	if routed && onChange != nil {
+		onChange()
+	}
+}
+
+// settingsLocked resolves the settings per target. Two policies for one target
+// are resolved to the one with the lowest key, so every replica picks the same.
+func (t *IdleTracker) settingsLocked() map[Target]trackedPolicy {
+	keys := make([]types.NamespacedName, 0, len(t.policies))
+	for key := range t.policies {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	out := make(map[Target]trackedPolicy, len(keys))
+	for _, key := range keys {
+		policy := t.policies[key]
+		if _, ok := out[policy.target]; !ok {
+			out[policy.target] = policy
+		}
+	}
+	return out
+}
@@ -0,0 +1,274 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type routing struct {
+	serving    bool
+	canReroute bool
+}
+
+func (r *routing) Serving(Target) bool    { return r.serving }
+func (r *routing) CanReroute(Target) bool { return r.canReroute }
+
+// newIdleTest tracks orders with a 10m idle window and a 30s drain period on a
+// clock the test advances. One workload that counts requests reports for the
+// whole test.
+func newIdleTest() (*IdleTracker, *Registry, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	clock := func() time.Time { return now }
+	registry := NewRegistry()
+	registry.now = clock
+	registry.ttl = 24 * time.Hour
+	registry.ReportSnapshot("workload-0", nil, true)
+	tracker := NewIdleTracker(registry)
+	tracker.now = clock
+	tracker.Track(types.NamespacedName{Namespace: "app", Name: "orders"}, orders,
+		IdleSettings{Window: 10 * time.Minute, Drain: 30 * time.Second})
+	return tracker, registry, &now
+}
+
+func draining(tracker *IdleTracker) bool {
+	return len(tracker.DrainingServices()) == 1
+}
+
+func TestIdleSettingsFromSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		idle    *networking.ActivationIdle
+		want    IdleSettings
+		wantErr bool
+	}{
+		{name: "unset leaves deactivation to the autoscaler"},
+		{
+			name: "window with the default drain",
+			idle: &networking.ActivationIdle{Window: durationpb.New(15 * time.Minute)},
+			want: IdleSettings{Window: 15 * time.Minute, Drain: defaultDrainPeriod},
+		},
+		{
+			name: "window and drain",
+			idle: &networking.ActivationIdle{Window: durationpb.New(time.Hour), DrainPeriod: durationpb.New(2 * time.Minute)},
+			want: IdleSettings{Window: time.Hour, Drain: 2 * time.Minute},
+		},
+		{
+			name:    "window shorter than a minute",
+			idle:    &networking.ActivationIdle{Window: durationpb.New(30 * time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "drain without a window",
+			idle:    &networking.ActivationIdle{DrainPeriod: durationpb.New(30 * time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "zero drain",
+			idle:    &networking.ActivationIdle{Window: durationpb.New(10 * time.Minute), DrainPeriod: durationpb.New(0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := idleSettingsFromSpec(tt.idle)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("settings = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The scaler may only report an idle target inactive once its callers have
+// been rerouted to the Activator for the whole drain period.
+func TestIdleTargetIsReroutedBeforeItIsReportedInactive(t *testing.T) {
+	tracker, registry, now := newIdleTest()
+	routes := &routing{serving: true, canReroute: true}
+	pushes := 0
+	check := func() { tracker.check(routes, func() { pushes++ }) }
+
+	check()
+	registry.RecordRequests(orders, 3)
+	*now = now.Add(9 * time.Minute)
+	check()
+	if draining(tracker) || !tracker.Retained(orders) {
+		t.Fatal("target drained within its idle window")
+	}
+
+	*now = now.Add(time.Minute)
+	check()
+	if !draining(tracker) || pushes != 1 {
+		t.Fatalf("draining = %v after %d pushes, want rerouted with one push", draining(tracker), pushes)
+	}
+	if !tracker.Retained(orders) {
+		t.Fatal("target reported inactive before its callers drained")
+	}
+
+	*now = now.Add(30 * time.Second)
+	check()
+	if tracker.Retained(orders) {
+		t.Fatal("target still retained after the drain period")
+	}
+	if !draining(tracker) || pushes != 1 {
+		t.Fatal("a drained target has to keep its callers on the Activator")
+	}
+}
+
+func TestTrafficWhileDrainingRestoresTheTarget(t *testing.T) {
+	tracker, registry, now := newIdleTest()
+	routes := &routing{serving: true, canReroute: true}
+	pushes := 0
+	check := func() { tracker.check(routes, func() { pushes++ }) }
+
+	check()
+	*now = now.Add(10 * time.Minute)
+	check()
+	if !draining(tracker) {
+		t.Fatal("idle target was not rerouted")
+	}
+
+	*now = now.Add(10 * time.Second)
+	registry.ReportSnapshot("workload-0", map[Target]int64{orders: 1}, true)
+	check()
+	if draining(tracker) || !tracker.Retained(orders) || pushes != 2 {
+		t.Fatalf("draining = %v, retained = %v, pushes = %d; want the endpoints restored",
+			draining(tracker), tracker.Retained(orders), pushes)
+	}
+
+	// The idle window starts over from the request that cancelled the drain.
+	*now = now.Add(9 * time.Minute)
+	check()
+	if draining(tracker) {
+		t.Fatal("idle window did not restart after the drain was cancelled")
+	}
+}
+
+// A gateway that only reports held requests could be passing traffic to the
+// target unseen, so while one is live nothing drains.
+func TestIdleTargetStaysUpWhileAReporterDoesNotCountRequests(t *testing.T) {
+	tracker, registry, now := newIdleTest()
+	routes := &routing{serving: true, canReroute: true}
+
+	tracker.check(routes, nil)
+	*now = now.Add(5 * time.Minute)
+	registry.ReportSnapshot("gateway-a", nil, false)
+	*now = now.Add(time.Hour)
+	tracker.check(routes, nil)
+	if draining(tracker) || !tracker.Retained(orders) {
+		t.Fatal("target drained while a reporter did not count requests")
+	}
+
+	// Once every reporter counts, the idle window runs in full.
+	registry.Forget("gateway-a")
+	*now = now.Add(9 * time.Minute)
+	tracker.check(routes, nil)
+	if draining(tracker) {
+		t.Fatal("idle window did not restart while a reporter did not count requests")
+	}
+	*now = now.Add(time.Minute)
+	tracker.check(routes, nil)
+	if !draining(tracker) {
+		t.Fatal("target did not drain once every reporter counted requests")
+	}
+
+	registry.ReportSnapshot("gateway-a", nil, false)
+	tracker.check(routes, nil)
+	if draining(tracker) || !tracker.Retained(orders) {
+		t.Fatal("drain continued after a reporter that does not count requests joined")
+	}
+}
+
+func TestIdleTargetStaysUpWithoutAnActivatorToCatchItsCallers(t *testing.T) {
+	tracker, _, now := newIdleTest()
+	routes := &routing{serving: true}
+
+	tracker.check(routes, nil)
+	*now = now.Add(time.Hour)
+	tracker.check(routes, nil)
+	if draining(tracker) || !tracker.Retained(orders) {
+		t.Fatal("target drained with nothing to reroute its callers to")
+	}
+
+	routes.canReroute = true
+	tracker.check(routes, nil)
+	*now = now.Add(time.Minute)
+	routes.canReroute = false
+	tracker.check(routes, nil)
+	if draining(tracker) {
+		t.Fatal("drain continued after the Activator went away")
+	}
+}
+
+// A restarted dubbod must not wake Services that were already at zero.
+func TestTargetFirstSeenWithoutEndpointsStartsDrained(t *testing.T) {
+	tracker, registry, now := newIdleTest()
+	routes := &routing{canReroute: true}
+
+	tracker.check(routes, nil)
+	if tracker.Retained(orders) || !draining(tracker) {
+		t.Fatal("a target at zero was retained on first sight")
+	}
+
+	*now = now.Add(time.Second)
+	registry.ReportSnapshot("workload-0", map[Target]int64{orders: 1}, true)
+	routes.serving = true
+	tracker.check(routes, nil)
+	if !tracker.Retained(orders) || draining(tracker) {
+		t.Fatal("a request for a drained target did not reactivate it")
+	}
+}
+
+func TestForgettingThePolicyReleasesTheTarget(t *testing.T) {
+	tracker, _, now := newIdleTest()
+	routes := &routing{serving: true, canReroute: true}
+	pushes := 0
+
+	tracker.check(routes, func() { pushes++ })
+	*now = now.Add(10 * time.Minute)
+	tracker.check(routes, func() { pushes++ })
+
+	tracker.Forget(types.NamespacedName{Namespace: "app", Name: "orders"})
+	tracker.check(routes, func() { pushes++ })
+	if draining(tracker) || tracker.Retained(orders) || pushes != 2 {
+		t.Fatalf("draining = %v, retained = %v, pushes = %d after the policy was removed",
+			draining(tracker), tracker.Retained(orders), pushes)
+	}
+}
+
+func TestScalerReportsRetainedTargetsActive(t *testing.T) {
+	tracker, _, _ := newIdleTest()
+	scaler := NewScaler(tracker.registry)
+	scaler.retain = append(scaler.retain, tracker)
+
+	tracker.check(&routing{serving: true, canReroute: true}, nil)
+	response, err := scaler.IsActive(context.Background(), serviceRef())
+	if err != nil {
+		t.Fatalf("IsActive() error = %v", err)
+	}
+	if !response.GetResult() {
+		t.Fatal("IsActive() = false for a target still in use")
+	}
+}
@@ -0,0 +1,425 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/features"
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/monitoring"
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Annotations dubbod stamps on a workload it scales, naming the replica that
+// made the change, the count it set and when. They are how ReplicasManaged
+// tells a count dubbod set apart from one something else set since.
+const (
+	ScaledByAnnotation       = "activation.dubbo.apache.org/scaled-by"
+	ScaledReplicasAnnotation = "activation.dubbo.apache.org/scaled-replicas"
+	ScaledAtAnnotation       = "activation.dubbo.apache.org/scaled-at"
+)
+
+// ConditionReplicasManaged reports, for a policy dubbod scales itself, whether
+// the current replica count of its workload is the one dubbod last set. Only
+// such policies carry it. The dubbod replica that set it is in the workload's
+// ScaledByAnnotation.
+const ConditionReplicasManaged = "ReplicasManaged"
+
+const (
+	defaultActiveReplicas = 1
+	defaultCooldown       = 5 * time.Minute
+
+	// minCooldown keeps a target up long enough for the requests that brought
+	// it up to be served. Shorter, and it would drop back to zero as soon as
+	// the held requests drained into it.
+	minCooldown = 30 * time.Second
+
+	// nativeCheckInterval is how often targets are rechecked without a demand
+	// change: for cooldowns running out, and for changes the rate limit held
+	// back.
+	nativeCheckInterval = time.Second
+
+	// scaleTimeout bounds the patches of one replica count change.
+	scaleTimeout = 10 * time.Second
+
+	// maxScaleBackoff bounds how long a workload whose patches keep failing
+	// waits between attempts.
+	maxScaleBackoff = 2 * time.Minute
+)
+
+var (
+	directionTag = monitoring.CreateLabel("direction")
+
+	nativeScales = monitoring.NewSum(
+		"dubbod_activation_native_scales",
+		"Replica count changes dubbod made or attempted for natively scaled targets, labeled by direction and result. "+
+			"A throttled change is retried on the next check.",
+		monitoring.WithLabels("direction", "result"),
+	)
+)
+
+// Workload is a Deployment or StatefulSet whose replicas dubbod sets itself.
+type Workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// nativeWorkloadOf returns the workload a policy's autoscalerRef names when it
+// asks dubbod to scale that workload directly rather than through KEDA.
+func nativeWorkloadOf(policy *clientnetworking.ServiceActivationPolicy) (Workload, bool) {
+	ref := policy.Spec.GetAutoscalerRef()
+	if ref == nil || !strings.EqualFold(ref.GetGroup(), "apps") {
+		return Workload{}, false
+	}
+	switch kind := ref.GetKind(); kind {
+	case "Deployment", "StatefulSet":
+		return Workload{Kind: kind, Namespace: policy.GetNamespace(), Name: ref.GetName()}, true
+	}
+	return Workload{}, false
+}
+
+// NativeSettings is how dubbod scales the workload of one policy.
+type NativeSettings struct {
+	Replicas int32
+	Cooldown time.Duration
+}
+
+// NativeSettingsOf reads spec.nativeScaling of a policy: the replica count its
+// workload is brought up to from zero, one by default, and how long the target
+// has to be inactive before it is taken back to zero, by default five minutes
+// like a ScaledObject's cooldownPeriod.
+func NativeSettingsOf(policy *clientnetworking.ServiceActivationPolicy) (NativeSettings, error) {
+	return nativeSettingsFromSpec(policy.Spec.GetNativeScaling())
+}
+
+func nativeSettingsFromSpec(native *networking.NativeScaling) (NativeSettings, error) {
+	settings := NativeSettings{Replicas: defaultActiveReplicas, Cooldown: defaultCooldown}
+	if replicas := native.GetActiveReplicas(); replicas < 0 {
+		return NativeSettings{}, fmt.Errorf("nativeScaling.activeReplicas must be positive, got %d", replicas)
+	} else if replicas > 0 {
+		settings.Replicas = replicas
+	}
+	if native.GetCooldown() != nil {
+		if settings.Cooldown = native.GetCooldown().AsDuration(); settings.Cooldown < minCooldown {
+			return NativeSettings{}, fmt.Errorf("nativeScaling.cooldown must be at least %v, got %v", minCooldown, settings.Cooldown)
+		}
+	}
+	return settings, nil
+}
+
+// workloadState is what the native scaler reads of a workload.
+type workloadState struct {
+	Replicas    int32
+	Annotations map[string]string
+}
+
+// workloadClient reads, watches and scales workloads. It is an interface so
+// the native scaler can be tested without a cluster.
+type workloadClient interface {
+	Get(Workload) (workloadState, bool)
+	// Scale sets the replica count, stamping the workload with the
+	// annotations in stamp first.
+	Scale(ctx context.Context, workload Workload, replicas int32, stamp map[string]string) error
+	AddEventHandler(changed func(Workload))
+	HasSynced() bool
+	ShutdownHandlers()
+}
+
+// NativeScaler takes the workloads of policies whose autoscalerRef names a
+// Deployment or StatefulSet from zero to their active replica count and back,
+// for clusters without KEDA. It reads the same demand, idle and prewarm state
+// the KEDA scaler answers from, and only the leader among the dubbod replicas
+// writes, so two replicas never fight over a count.
+//
+// It only ever moves a workload between zero and a positive count. A workload
+// already running is left at whatever count it has, so an HPA or an operator
+// can still size it while it is up.
+type NativeScaler struct {
+	scaler    *Scaler
+	workloads workloadClient
+	replica   string
+	limiter   *rate.Limiter
+
+	mu       sync.Mutex
+	policies map[types.NamespacedName]nativePolicy
+	// The rest is only used while leading.
+	leadingSince time.Time
+	lastActive   map[Target]time.Time
+	backoff      map[Workload]scaleBackoff
+
+	wake chan struct{}
+	// now is swappable so cooldowns can be tested without waiting for them.
+	now func() time.Time
+}
+
+type nativePolicy struct {
+	target   Target
+	workload Workload
+	settings NativeSettings
+}
+
+type scaleBackoff struct {
+	delay time.Duration
+	until time.Time
+}
+
+// NewNativeScaler scales workloads for the demand scaler reports, stamping
+// them with replica, the name of this dubbod replica.
+func NewNativeScaler(client kube.Client, scaler *Scaler, replica string) *NativeScaler {
+	return newNativeScaler(scaler, newKubeWorkloads(client), replica)
+}
+
+func newNativeScaler(scaler *Scaler, workloads workloadClient, replica string) *NativeScaler {
+	return &NativeScaler{
+		scaler:    scaler,
+		workloads: workloads,
+		replica:   replica,
+		limiter:   rate.NewLimiter(rate.Limit(features.ActivationNativeScaleQPS), features.ActivationNativeScaleBurst),
+		policies:  map[types.NamespacedName]nativePolicy{},
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+	}
+}
+
+// AddEventHandler calls changed with every workload that changes, so the
+// policies scaling it can be re-evaluated.
+func (n *NativeScaler) AddEventHandler(changed func(Workload)) {
+	n.workloads.AddEventHandler(changed)
+}
+
+// HasSynced reports whether the workloads have been read. Until then a
+// workload at zero cannot be told apart from one not yet seen.
+func (n *NativeScaler) HasSynced() bool {
+	return n.workloads.HasSynced()
+}
+
+func (n *NativeScaler) ShutdownHandlers() {
+	n.workloads.ShutdownHandlers()
+}
+
+// Track starts or updates native scaling for a policy.
+func (n *NativeScaler) Track(policy types.NamespacedName, target Target, workload Workload, settings NativeSettings) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.policies[policy] = nativePolicy{target: target, workload: workload, settings: settings}
+	n.notify()
+}
+
+// Forget stops native scaling for a policy, leaving its workload at whatever
+// count it has.
+func (n *NativeScaler) Forget(policy types.NamespacedName) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.policies, policy)
+}
+
+func (n *NativeScaler) notify() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run scales tracked workloads until stop is closed. Only the leader calls it.
+func (n *NativeScaler) Run(stop <-chan struct{}) {
+	n.startLeading()
+	subscriptions := map[Target]func(){}
+	defer func() {
+		for _, cancel := range subscriptions {
+			cancel()
+		}
+	}()
+	ticker := time.NewTicker(nativeCheckInterval)
+	defer ticker.Stop()
+	for {
+		n.subscribe(subscriptions)
+		n.check()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-n.wake:
+		}
+	}
+}
+
+// startLeading resets what a previous term knew. A new leader does not know
+// when targets were last active, so it gives every running target a full
+// cooldown before taking it down.
+func (n *NativeScaler) startLeading() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.leadingSince = n.now()
+	n.lastActive = map[Target]time.Time{}
+	n.backoff = map[Workload]scaleBackoff{}
+}
+
+// subscribe follows demand for every tracked target, so a request for a
+// target at zero brings it up without waiting for the tick.
+func (n *NativeScaler) subscribe(subscriptions map[Target]func()) {
+	n.mu.Lock()
+	targets := map[Target]bool{}
+	for _, policy := range n.policies {
+		targets[policy.target] = true
+	}
+	n.mu.Unlock()
+
+	for target, cancel := range subscriptions {
+		if !targets[target] {
+			cancel()
+			delete(subscriptions, target)
+		}
+	}
+	for target := range targets {
+		if _, ok := subscriptions[target]; ok {
+			continue
+		}
+		updates, cancel := n.scaler.demand.Subscribe(target)
+		subscriptions[target] = cancel
+		go func() {
+			for range updates {
+				n.notify()
+			}
+		}()
+	}
+}
+
+func (n *NativeScaler) check() {
+	n.mu.Lock()
+	policies := slices.Collect(maps.Values(n.policies))
+	n.mu.Unlock()
+
+	now := n.now()
+	for _, policy := range policies {
+		n.reconcile(policy, now)
+	}
+}
+
+// reconcile brings one workload up when its target is active and it is at
+// zero, or down once its target has been inactive for the cooldown.
+func (n *NativeScaler) reconcile(policy nativePolicy, now time.Time) {
+	state, ok := n.workloads.Get(policy.workload)
+	if !ok {
+		return
+	}
+	active := n.scaler.active(policy.target, n.scaler.demand.Pending(policy.target))
+
+	n.mu.Lock()
+	if active {
+		n.lastActive[policy.target] = now
+	}
+	inactiveSince := n.leadingSince
+	if last := n.lastActive[policy.target]; last.After(inactiveSince) {
+		inactiveSince = last
+	}
+	backoff := n.backoff[policy.workload]
+	n.mu.Unlock()
+
+	var replicas int32
+	var direction string
+	switch {
+	case active && state.Replicas == 0:
+		replicas, direction = policy.settings.Replicas, "up"
+	case !active && state.Replicas > 0 && now.Sub(inactiveSince) >= policy.settings.Cooldown:
+		replicas, direction = 0, "down"
+	default:
+		return
+	}
+	if now.Before(backoff.until) {
+		return
+	}
+	directionValue := directionTag.Value(direction)
+	if !n.limiter.AllowN(now, 1) {
+		nativeScales.With(directionValue, resultTag.Value("throttled")).Increment()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scaleTimeout)
+	defer cancel()
+	err := n.workloads.Scale(ctx, policy.workload, replicas, map[string]string{
+		ScaledByAnnotation:       n.replica,
+		ScaledReplicasAnnotation: strconv.Itoa(int(replicas)),
+		ScaledAtAnnotation:       now.UTC().Format(time.RFC3339),
+	})
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	workload := policy.workload
+	if err != nil {
+		delay := min(max(2*backoff.delay, nativeCheckInterval), maxScaleBackoff)
+		n.backoff[workload] = scaleBackoff{delay: delay, until: now.Add(delay)}
+		nativeScales.With(directionValue, resultTag.Value("error")).Increment()
+		logger.Warnf("unable to scale %s %s/%s to %d replicas, retrying in %v: %v",
+			workload.Kind, workload.Namespace, workload.Name, replicas, delay, err)
+		return
+	}
+	delete(n.backoff, workload)
+	nativeScales.With(directionValue, resultTag.Value("success")).Increment()
+	logger.Infof("scaled %s %s/%s to %d replicas for %s/%s",
+		workload.Kind, workload.Namespace, workload.Name, replicas, policy.target.Namespace, policy.target.Name)
+}
+
+// ScalerReady satisfies the native half of ScalerStatusLookup: the workload
+// to scale exists.
+func (n *NativeScaler) ScalerReady(policy *clientnetworking.ServiceActivationPolicy) bool {
+	workload, ok := nativeWorkloadOf(policy)
+	if !ok {
+		return false
+	}
+	_, found := n.workloads.Get(workload)
+	return found
+}
+
+// ReplicasStatus reports whether the replica count of a natively scaled
+// workload is the one dubbod last set, with the reason of its ReplicasManaged
+// condition.
+func (n *NativeScaler) ReplicasStatus(policy *clientnetworking.ServiceActivationPolicy) (bool, string) {
+	workload, ok := nativeWorkloadOf(policy)
+	if !ok {
+		return false, ""
+	}
+	state, found := n.workloads.Get(workload)
+	if !found {
+		return false, "WorkloadNotFound"
+	}
+	by := state.Annotations[ScaledByAnnotation]
+	stamped, err := strconv.ParseInt(state.Annotations[ScaledReplicasAnnotation], 10, 32)
+	switch {
+	case by == "" || err != nil:
+		// Nothing has been held for it yet; the count is still the one it was
+		// deployed with.
+		return true, "AwaitingDemand"
+	case int32(stamped) != state.Replicas:
+		return false, "ReplicasChangedElsewhere"
+	case stamped == 0:
+		return true, "ScaledToZero"
+	default:
+		return true, "ScaledUp"
+	}
+}
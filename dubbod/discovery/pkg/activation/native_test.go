@@ -0,0 +1,273 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakeWorkloads holds workload state in memory and records every change.
+type fakeWorkloads struct {
+	mu     sync.Mutex
+	states map[Workload]workloadState
+	scales []int32
+	err    error
+}
+
+func (w *fakeWorkloads) Get(workload Workload) (workloadState, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state, ok := w.states[workload]
+	return state, ok
+}
+
+func (w *fakeWorkloads) Scale(_ context.Context, workload Workload, replicas int32, stamp map[string]string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.scales = append(w.scales, replicas)
+	if w.err != nil {
+		return w.err
+	}
+	w.states[workload] = workloadState{Replicas: replicas, Annotations: stamp}
+	return nil
+}
+
+func (w *fakeWorkloads) AddEventHandler(func(Workload)) {}
+func (w *fakeWorkloads) HasSynced() bool                { return true }
+func (w *fakeWorkloads) ShutdownHandlers()              {}
+
+func (w *fakeWorkloads) set(workload Workload, replicas int32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state := w.states[workload]
+	state.Replicas = replicas
+	w.states[workload] = state
+}
+
+func (w *fakeWorkloads) replicas(workload Workload) int32 {
+	state, _ := w.Get(workload)
+	return state.Replicas
+}
+
+func (w *fakeWorkloads) changes() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.scales)
+}
+
+var ordersDeployment = Workload{Kind: "Deployment", Namespace: "app", Name: "orders"}
+
+// newNativeTest scales app/orders between zero and two replicas with a five
+// minute cooldown, leading from a clock the test sets.
+func newNativeTest(replicas int32) (*NativeScaler, *Registry, *fakeWorkloads, *time.Time) {
+	registry := NewRegistry()
+	workloads := &fakeWorkloads{states: map[Workload]workloadState{ordersDeployment: {Replicas: replicas}}}
+	native := newNativeScaler(NewScaler(registry), workloads, "dubbod-0")
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	native.now = func() time.Time { return now }
+	native.Track(types.NamespacedName{Namespace: "app", Name: "orders"}, orders, ordersDeployment,
+		NativeSettings{Replicas: 2, Cooldown: 5 * time.Minute})
+	native.startLeading()
+	return native, registry, workloads, &now
+}
+
+func nativeScaledPolicy() *clientnetworking.ServiceActivationPolicy {
+	return policy(serviceTarget("orders"), &networking.AutoscalerReference{Group: "apps", Kind: "Deployment", Name: "orders"})
+}
+
+func TestNativeSettingsFromSpec(t *testing.T) {
+	settings, err := nativeSettingsFromSpec(nil)
+	if err != nil || settings.Replicas != 1 || settings.Cooldown != 5*time.Minute {
+		t.Fatalf("defaults = %+v, %v; want one replica and a five minute cooldown", settings, err)
+	}
+	settings, err = nativeSettingsFromSpec(&networking.NativeScaling{
+		ActiveReplicas: 3,
+		Cooldown:       durationpb.New(90 * time.Second),
+	})
+	if err != nil || settings.Replicas != 3 || settings.Cooldown != 90*time.Second {
+		t.Fatalf("settings = %+v, %v; want three replicas and a 90s cooldown", settings, err)
+	}
+
+	for _, native := range []*networking.NativeScaling{
+		{ActiveReplicas: -1},
+		{Cooldown: durationpb.New(0)},
+		// Shorter than it takes the held requests to drain into the target.
+		{Cooldown: durationpb.New(10 * time.Second)},
+	} {
+		if _, err := nativeSettingsFromSpec(native); err == nil {
+			t.Errorf("nativeSettingsFromSpec(%v) accepted invalid settings", native)
+		}
+	}
+}
+
+func TestDemandScalesTheWorkloadUpFromZero(t *testing.T) {
+	native, registry, workloads, _ := newNativeTest(0)
+
+	native.check()
+	if workloads.changes() != 0 {
+		t.Fatal("workload scaled with nothing pending")
+	}
+
+	registry.Report("gateway-a", orders, 1)
+	native.check()
+	if got := workloads.replicas(ordersDeployment); got != 2 {
+		t.Fatalf("replicas = %d, want 2", got)
+	}
+	if managed, reason := native.ReplicasStatus(nativeScaledPolicy()); !managed || reason != "ScaledUp" {
+		t.Fatalf("status = %v %q, want ScaledUp", managed, reason)
+	}
+	if state, _ := workloads.Get(ordersDeployment); state.Annotations[ScaledByAnnotation] != "dubbod-0" {
+		t.Fatalf("scaled by = %q, want dubbod-0", state.Annotations[ScaledByAnnotation])
+	}
+}
+
+// An HPA or an operator may size a running workload; the native scaler only
+// ever takes it to and from zero.
+func TestRunningWorkloadIsNotResized(t *testing.T) {
+	native, registry, workloads, _ := newNativeTest(5)
+	registry.Report("gateway-a", orders, 10)
+	native.check()
+	if workloads.changes() != 0 {
+		t.Fatalf("running workload resized to %d", workloads.replicas(ordersDeployment))
+	}
+}
+
+func TestInactiveWorkloadScalesToZeroAfterTheCooldown(t *testing.T) {
+	native, registry, workloads, now := newNativeTest(2)
+
+	// Nothing is known from before this term, so the cooldown runs from its
+	// start.
+	*now = now.Add(4 * time.Minute)
+	registry.Report("gateway-a", orders, 1)
+	native.check()
+	registry.Report("gateway-a", orders, 0)
+
+	*now = now.Add(4 * time.Minute)
+	native.check()
+	if got := workloads.replicas(ordersDeployment); got != 2 {
+		t.Fatalf("replicas = %d inside the cooldown, want 2", got)
+	}
+
+	*now = now.Add(time.Minute)
+	native.check()
+	if got := workloads.replicas(ordersDeployment); got != 0 {
+		t.Fatalf("replicas = %d after the cooldown, want 0", got)
+	}
+	if managed, reason := native.ReplicasStatus(nativeScaledPolicy()); !managed || reason != "ScaledToZero" {
+		t.Fatalf("status = %v %q, want ScaledToZero", managed, reason)
+	}
+	if state, _ := workloads.Get(ordersDeployment); state.Annotations[ScaledByAnnotation] != "dubbod-0" {
+		t.Fatalf("scaled by = %q, want dubbod-0", state.Annotations[ScaledByAnnotation])
+	}
+}
+
+func TestRetainedTargetsAreNotScaledToZero(t *testing.T) {
+	native, _, workloads, now := newNativeTest(2)
+	native.scaler.retain = append(native.scaler.retain, retainAll{})
+
+	*now = now.Add(time.Hour)
+	native.check()
+	if workloads.changes() != 0 {
+		t.Fatal("retained target scaled to zero")
+	}
+}
+
+type retainAll struct{}
+
+func (retainAll) Retained(Target) bool { return true }
+
+func TestFailedScalesBackOff(t *testing.T) {
+	native, registry, workloads, now := newNativeTest(0)
+	workloads.err = errors.New("conflict")
+	registry.Report("gateway-a", orders, 1)
+
+	native.check()
+	*now = now.Add(500 * time.Millisecond)
+	native.check()
+	if got := workloads.changes(); got != 1 {
+		t.Fatalf("attempts = %d inside the backoff, want 1", got)
+	}
+
+	workloads.err = nil
+	*now = now.Add(500 * time.Millisecond)
+	native.check()
+	if got := workloads.replicas(ordersDeployment); got != 2 {
+		t.Fatalf("replicas = %d after the backoff, want 2", got)
+	}
+}
+
+func TestRateLimitedChangesWaitForTheNextCheck(t *testing.T) {
+	native, registry, workloads, now := newNativeTest(0)
+	native.limiter = rate.NewLimiter(rate.Every(time.Minute), 1)
+	reviews := Target{Namespace: "app", Name: "reviews"}
+	reviewsDeployment := Workload{Kind: "Deployment", Namespace: "app", Name: "reviews"}
+	workloads.states[reviewsDeployment] = workloadState{}
+	native.Track(types.NamespacedName{Namespace: "app", Name: "reviews"}, reviews, reviewsDeployment,
+		NativeSettings{Replicas: 1, Cooldown: 5 * time.Minute})
+
+	registry.Report("gateway-a", orders, 1)
+	registry.Report("gateway-a", reviews, 1)
+	native.check()
+	if got := workloads.changes(); got != 1 {
+		t.Fatalf("changes = %d, want 1 within the burst", got)
+	}
+
+	*now = now.Add(time.Minute)
+	native.check()
+	if workloads.replicas(ordersDeployment) != 2 || workloads.replicas(reviewsDeployment) != 1 {
+		t.Fatal("throttled change not made on a later check")
+	}
+}
+
+func TestReplicasChangedElsewhereAreReported(t *testing.T) {
+	native, registry, workloads, _ := newNativeTest(0)
+	if managed, reason := native.ReplicasStatus(nativeScaledPolicy()); !managed || reason != "AwaitingDemand" {
+		t.Fatalf("status = %v %q before any change, want AwaitingDemand", managed, reason)
+	}
+
+	registry.Report("gateway-a", orders, 1)
+	native.check()
+	workloads.set(ordersDeployment, 0)
+	if managed, reason := native.ReplicasStatus(nativeScaledPolicy()); managed || reason != "ReplicasChangedElsewhere" {
+		t.Fatalf("status = %v %q, want ReplicasChangedElsewhere", managed, reason)
+	}
+
+	delete(workloads.states, ordersDeployment)
+	if managed, reason := native.ReplicasStatus(nativeScaledPolicy()); managed || reason != "WorkloadNotFound" {
+		t.Fatalf("status = %v %q, want WorkloadNotFound", managed, reason)
+	}
+}
+
+func TestRunScalesUpAsSoonAsDemandArrives(t *testing.T) {
+	native, registry, workloads, _ := newNativeTest(0)
+	stop := make(chan struct{})
+	defer close(stop)
+	go native.Run(stop)
+
+	registry.Report("gateway-a", orders, 1)
+	waitFor(t, func() bool { return workloads.replicas(ordersDeployment) == 2 }, "workload never scaled up")
+}
@@ -37,8 +37,9 @@ const (
 	// and replayed at all.
 	ConditionEligible = "Eligible"
 
-	// ConditionScalerReady covers KEDA: whether the referenced ScaledObject is
-	// ready to obtain activation metrics.
+	// ConditionScalerReady covers the autoscaler: whether the referenced
+	// ScaledObject is ready to obtain activation metrics or, for a workload
+	// dubbod scales itself, whether that workload exists.
 	ConditionScalerReady = "ScalerReady"
 
 	// ConditionActivatorReady covers the data plane: whether a managed Gateway
@@ -62,18 +63,35 @@ type ScalerStatusLookup interface {
 	ScalerReady(*clientnetworking.ServiceActivationPolicy) bool
 }
 
+// NativeScaleLookup answers for policies whose workload dubbod scales itself:
+// whether the workload exists, and whether its replica count is the one dubbod
+// last set, with the reason of its ReplicasManaged condition.
+type NativeScaleLookup interface {
+	ScalerReady(*clientnetworking.ServiceActivationPolicy) bool
+	ReplicasStatus(*clientnetworking.ServiceActivationPolicy) (bool, string)
+}
+
 // ActivatorStatusLookup reports whether a managed gateway is programmed for
 // the policy's namespace.
 type ActivatorStatusLookup interface {
 	ActivatorReady(*clientnetworking.ServiceActivationPolicy) bool
 }
 
+// PrewarmStatusLookup reports whether a policy holds its target warm, with the
+// reason of its Prewarmed condition. An empty reason means the policy does not
+// prewarm.
+type PrewarmStatusLookup interface {
+	PrewarmStatus(*clientnetworking.ServiceActivationPolicy) (bool, string)
+}
+
 // PolicyEvaluator turns a policy plus live state into the conditions published
 // on its status.
 type PolicyEvaluator struct {
 	Services  ServiceLookup
 	Scaler    ScalerStatusLookup
 	Activator ActivatorStatusLookup
+	Prewarm   PrewarmStatusLookup
+	Native    NativeScaleLookup
 }
 
 // Evaluate returns the conditions for one policy, in a stable order so an
@@ -83,6 +101,16 @@ func (e PolicyEvaluator) Evaluate(policy *clientnetworking.ServiceActivationPoli
 	spec := &policy.Spec
 
 	accepted, reason := e.accepted(policy.GetNamespace(), spec)
+	if _, err := IdleSettingsOf(policy); accepted && err != nil {
+		accepted, reason = false, "IdleSettingsInvalid"
+	}
+	if _, err := PrewarmSettingsOf(policy); accepted && err != nil {
+		accepted, reason = false, "PrewarmSettingsInvalid"
+	}
+	_, native := nativeWorkloadOf(policy)
+	if _, err := NativeSettingsOf(policy); accepted && native && err != nil {
+		accepted, reason = false, "NativeScalingSettingsInvalid"
+	}
 	conditions := []*metav1alpha1.DubboCondition{
 		condition(ConditionAccepted, accepted, reason, generation),
 	}
@@ -96,23 +124,66 @@ func (e PolicyEvaluator) Evaluate(policy *clientnetworking.ServiceActivationPoli
 			condition(ConditionScalerReady, false, "PolicyNotAccepted", generation),
 			condition(ConditionActivatorReady, false, "PolicyNotAccepted", generation),
 		)
+		if prewarms(policy) {
+			conditions = append(conditions, condition(ConditionPrewarmed, false, "PolicyNotAccepted", generation))
+		}
+		if native {
+			conditions = append(conditions, condition(ConditionReplicasManaged, false, "PolicyNotAccepted", generation))
+		}
 		return conditions
 	}
 
 	eligible, eligibleReason := eligible(spec)
 	conditions = append(conditions, condition(ConditionEligible, eligible, eligibleReason, generation))
 
-	scalerReady := e.Scaler != nil && e.Scaler.ScalerReady(policy)
-	conditions = append(conditions,
-		condition(ConditionScalerReady, scalerReady, scalerReason(scalerReady), generation))
+	if native {
+		conditions = append(conditions, e.nativeScalerReady(policy, generation))
+	} else {
+		scalerReady := e.Scaler != nil && e.Scaler.ScalerReady(policy)
+		conditions = append(conditions,
+			condition(ConditionScalerReady, scalerReady, scalerReason(scalerReady), generation))
+	}
 
 	activatorReady := e.Activator != nil && e.Activator.ActivatorReady(policy)
 	conditions = append(conditions,
 		condition(ConditionActivatorReady, activatorReady, activatorReason(activatorReady), generation))
 
+	if prewarms(policy) && e.Prewarm != nil {
+		if warm, warmReason := e.Prewarm.PrewarmStatus(policy); warmReason != "" {
+			conditions = append(conditions, condition(ConditionPrewarmed, warm, warmReason, generation))
+		}
+	}
+
+	if native {
+		managed, managedReason := false, "NativeScalingUnavailable"
+		if e.Native != nil {
+			managed, managedReason = e.Native.ReplicasStatus(policy)
+		}
+		conditions = append(conditions, condition(ConditionReplicasManaged, managed, managedReason, generation))
+	}
+
 	return conditions
 }
 
+// nativeScalerReady is ScalerReady for a policy whose workload dubbod scales
+// itself. Without a native scaler, as when dubbod has no kube client, nothing
+// will ever scale it.
+func (e PolicyEvaluator) nativeScalerReady(policy *clientnetworking.ServiceActivationPolicy, generation int64) *metav1alpha1.DubboCondition {
+	if e.Native == nil {
+		return condition(ConditionScalerReady, false, "NativeScalingUnavailable", generation)
+	}
+	if e.Native.ScalerReady(policy) {
+		return condition(ConditionScalerReady, true, "WorkloadFound", generation)
+	}
+	return condition(ConditionScalerReady, false, "WorkloadNotFound", generation)
+}
+
+// prewarms reports whether a policy asks for prewarming at all, valid or not.
+func prewarms(policy *clientnetworking.ServiceActivationPolicy) bool {
+	prewarm := policy.Spec.GetPrewarm()
+	return len(prewarm.GetWindows()) > 0 || len(prewarm.GetCallers()) > 0
+}
+
 func (e PolicyEvaluator) accepted(namespace string, spec *networking.ServiceActivationPolicy) (bool, string) {
 	target := spec.GetTargetRef()
 	if target == nil || strings.TrimSpace(target.GetName()) == "" {
@@ -126,9 +197,16 @@ func (e PolicyEvaluator) accepted(namespace string, spec *networking.ServiceActi
 	if group := target.GetGroup(); group != "" {
 		return false, "TargetGroupUnsupported"
 	}
-	if autoscaler := spec.GetAutoscalerRef(); autoscaler == nil || strings.TrimSpace(autoscaler.GetName()) == "" {
+	autoscaler := spec.GetAutoscalerRef()
+	if autoscaler == nil || strings.TrimSpace(autoscaler.GetName()) == "" {
 		return false, "AutoscalerRefMissing"
 	}
+	// dubbod only knows how to scale the workloads it can reach through a
+	// scale subresource it has RBAC for.
+	if strings.EqualFold(autoscaler.GetGroup(), "apps") &&
+		autoscaler.GetKind() != "Deployment" && autoscaler.GetKind() != "StatefulSet" {
+		return false, "AutoscalerKindUnsupported"
+	}
 	if e.Services != nil && !e.Services.HasService(namespace, target.GetName()) {
 		return false, "TargetServiceNotFound"
 	}
@@ -188,25 +266,6 @@ func condition(conditionType string, ok bool, reason string, generation int64) *
 	}
 }
 
-// SameConditions reports whether two condition sets carry the same information,
-// so an unchanged policy is not written back on every resync. Status writes are
-// not free: a resync storm across every policy is a self-inflicted load spike
-// on the API server.
-func SameConditions(a, b []*metav1alpha1.DubboCondition) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i].GetType() != b[i].GetType() ||
-			a[i].GetStatus() != b[i].GetStatus() ||
-			a[i].GetReason() != b[i].GetReason() ||
-			a[i].GetObservedGeneration() != b[i].GetObservedGeneration() {
-			return false
-		}
-	}
-	return true
-}
-
 // Summary renders the conditions for logs and dubboctl output.
 func Summary(conditions []*metav1alpha1.DubboCondition) string {
 	parts := make([]string, 0, len(conditions))
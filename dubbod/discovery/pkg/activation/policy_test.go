@@ -20,8 +20,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/status"
 	networking "github.com/kdubbo/api/networking/v1alpha3"
 	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/durationpb"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -132,6 +134,13 @@ func TestEvaluateRejectsMalformedPolicies(t *testing.T) {
 			target: serviceTarget("orders"),
 			reason: "AutoscalerRefMissing",
 		},
+		{
+			// dubbod only scales Deployments and StatefulSets itself.
+			name:       "unscalable workload",
+			target:     serviceTarget("orders"),
+			autoscaler: &networking.AutoscalerReference{Group: "apps", Kind: "DaemonSet", Name: "orders"},
+			reason:     "AutoscalerKindUnsupported",
+		},
 	}
 
 	for _, test := range tests {
@@ -161,6 +170,96 @@ func TestEvaluateReportsMissingTargetService(t *testing.T) {
 	}
 }
 
+func TestEvaluateRejectsInvalidIdleSettings(t *testing.T) {
+	evaluator := PolicyEvaluator{Services: services{"app/orders": true}}
+	invalid := validPolicy()
+	invalid.Spec.Idle = &networking.ActivationIdle{Window: durationpb.New(5 * time.Second)}
+
+	got := conditionsByType(t, evaluator, invalid)
+	if want := "False/IdleSettingsInvalid"; got[ConditionAccepted] != want {
+		t.Fatalf("Accepted = %q, want %q", got[ConditionAccepted], want)
+	}
+}
+
+func TestEvaluateReportsPrewarming(t *testing.T) {
+	prewarmer := NewPrewarmer(NewRegistry())
+	prewarmer.now = func() time.Time { return time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) }
+	evaluator := PolicyEvaluator{
+		Services:  services{"app/orders": true},
+		Scaler:    scalerStatus(true),
+		Activator: activatorStatus(true),
+		Prewarm:   prewarmer,
+	}
+
+	if _, ok := conditionsByType(t, evaluator, validPolicy())[ConditionPrewarmed]; ok {
+		t.Fatal("Prewarmed reported for a policy that does not prewarm")
+	}
+
+	prewarming := validPolicy()
+	prewarming.Spec.Prewarm = &networking.ActivationPrewarm{
+		Windows: []*networking.PrewarmWindow{{Schedule: "0 8 * * 1-5", Duration: durationpb.New(2 * time.Hour)}},
+	}
+	got := conditionsByType(t, evaluator, prewarming)
+	if want := "True/PrewarmWindowOpen"; got[ConditionPrewarmed] != want {
+		t.Fatalf("Prewarmed = %q, want %q", got[ConditionPrewarmed], want)
+	}
+
+	prewarming.Spec.Prewarm.Windows = append(prewarming.Spec.Prewarm.Windows,
+		&networking.PrewarmWindow{Schedule: "every morning", Duration: durationpb.New(time.Hour)})
+	got = conditionsByType(t, evaluator, prewarming)
+	if want := "False/PrewarmSettingsInvalid"; got[ConditionAccepted] != want {
+		t.Fatalf("Accepted = %q, want %q", got[ConditionAccepted], want)
+	}
+	if want := "False/PolicyNotAccepted"; got[ConditionPrewarmed] != want {
+		t.Fatalf("Prewarmed = %q, want %q", got[ConditionPrewarmed], want)
+	}
+}
+
+func TestEvaluateReportsNativeScaling(t *testing.T) {
+	native, _, workloads, _ := newNativeTest(0)
+	evaluator := PolicyEvaluator{
+		Services:  services{"app/orders": true},
+		Scaler:    scalerStatus(false),
+		Activator: activatorStatus(true),
+		Native:    native,
+	}
+
+	if _, ok := conditionsByType(t, evaluator, validPolicy())[ConditionReplicasManaged]; ok {
+		t.Fatal("ReplicasManaged reported for a policy KEDA scales")
+	}
+
+	got := conditionsByType(t, evaluator, nativeScaledPolicy())
+	if want := "True/WorkloadFound"; got[ConditionScalerReady] != want {
+		t.Fatalf("ScalerReady = %q, want %q", got[ConditionScalerReady], want)
+	}
+	if want := "True/AwaitingDemand"; got[ConditionReplicasManaged] != want {
+		t.Fatalf("ReplicasManaged = %q, want %q", got[ConditionReplicasManaged], want)
+	}
+
+	delete(workloads.states, ordersDeployment)
+	got = conditionsByType(t, evaluator, nativeScaledPolicy())
+	if want := "False/WorkloadNotFound"; got[ConditionScalerReady] != want || got[ConditionReplicasManaged] != want {
+		t.Fatalf("ScalerReady = %q, ReplicasManaged = %q; want both %q",
+			got[ConditionScalerReady], got[ConditionReplicasManaged], want)
+	}
+
+	invalid := nativeScaledPolicy()
+	invalid.Spec.NativeScaling = &networking.NativeScaling{Cooldown: durationpb.New(time.Second)}
+	got = conditionsByType(t, evaluator, invalid)
+	if want := "False/NativeScalingSettingsInvalid"; got[ConditionAccepted] != want {
+		t.Fatalf("Accepted = %q, want %q", got[ConditionAccepted], want)
+	}
+	if want := "False/PolicyNotAccepted"; got[ConditionReplicasManaged] != want {
+		t.Fatalf("ReplicasManaged = %q, want %q", got[ConditionReplicasManaged], want)
+	}
+
+	evaluator.Native = nil
+	got = conditionsByType(t, evaluator, nativeScaledPolicy())
+	if want := "False/NativeScalingUnavailable"; got[ConditionScalerReady] != want {
+		t.Fatalf("ScalerReady = %q, want %q", got[ConditionScalerReady], want)
+	}
+}
+
 // A stream cannot be replayed once the backend is up, so a policy naming a
 // streaming protocol must not look ready.
 func TestEvaluateRejectsProtocolsThatCannotBeHeld(t *testing.T) {
@@ -235,7 +334,7 @@ func TestEvaluateIsStableAcrossCalls(t *testing.T) {
 	}
 	target := validPolicy()
 
-	if !SameConditions(evaluator.Evaluate(target), evaluator.Evaluate(target)) {
+	if !status.SameConditions(evaluator.Evaluate(target), evaluator.Evaluate(target)) {
 		t.Fatal("Evaluate() produced different conditions for an unchanged policy")
 	}
 
@@ -245,7 +344,7 @@ func TestEvaluateIsStableAcrossCalls(t *testing.T) {
 		Scaler:    scalerStatus(false),
 		Activator: activatorStatus(true),
 	}
-	if SameConditions(evaluator.Evaluate(target), changed.Evaluate(target)) {
+	if status.SameConditions(evaluator.Evaluate(target), changed.Evaluate(target)) {
 		t.Fatal("SameConditions() reported no change after the scaler unsubscribed")
 	}
 }
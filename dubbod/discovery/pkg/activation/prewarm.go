@@ -0,0 +1,307 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	// dubbod ships on an image without a zone database; prewarm time zones
+	// must still resolve.
+	_ "time/tzdata"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConditionPrewarmed reports whether a policy with prewarming holds its target
+// warm now. Only such policies carry it. The times and callers behind a reason
+// follow from spec.prewarm, and dubbod logs them as the target warms and cools.
+const ConditionPrewarmed = "Prewarmed"
+
+// minPrewarmWindow keeps a window open long enough to outlast the cold start
+// it is meant to hide.
+const minPrewarmWindow = time.Minute
+
+// PrewarmSettings is the prewarming of one policy. A zero value prewarms
+// nothing.
+type PrewarmSettings struct {
+	Windows  []PrewarmWindow
+	Location *time.Location
+	Callers  []Target
+}
+
+// PrewarmWindow keeps a target warm for Duration from every start of Schedule.
+type PrewarmWindow struct {
+	Schedule cronSchedule
+	Duration time.Duration
+}
+
+func (s PrewarmSettings) enabled() bool {
+	return len(s.Windows) > 0 || len(s.Callers) > 0
+}
+
+// PrewarmSettingsOf reads spec.prewarm of a policy: the cron windows its target
+// is kept warm in whether or not anything is pending, and the Services known to
+// call it, while any of which has endpoints the target is kept warm too. A
+// window should open a cold start ahead of the traffic it is meant for; a batch
+// caller starting up is the earliest sign its calls are coming.
+func PrewarmSettingsOf(policy *clientnetworking.ServiceActivationPolicy) (PrewarmSettings, error) {
+	return prewarmSettingsFromSpec(policy.GetNamespace(), policy.Spec.GetPrewarm())
+}
+
+func prewarmSettingsFromSpec(namespace string, prewarm *networking.ActivationPrewarm) (PrewarmSettings, error) {
+	settings := PrewarmSettings{Location: time.UTC}
+	if prewarm == nil {
+		return settings, nil
+	}
+	if raw := strings.TrimSpace(prewarm.GetTimeZone()); raw != "" {
+		location, err := time.LoadLocation(raw)
+		if err != nil {
+			return PrewarmSettings{}, fmt.Errorf("prewarm.timeZone: %v", err)
+		}
+		settings.Location = location
+	}
+
+	for i, window := range prewarm.GetWindows() {
+		schedule, err := parseCron(window.GetSchedule())
+		if err != nil {
+			return PrewarmSettings{}, fmt.Errorf("prewarm.windows[%d].schedule: %v", i, err)
+		}
+		duration := window.GetDuration().AsDuration()
+		if duration < minPrewarmWindow {
+			return PrewarmSettings{}, fmt.Errorf("prewarm.windows[%d].duration must be at least %v, got %v",
+				i, minPrewarmWindow, duration)
+		}
+		settings.Windows = append(settings.Windows, PrewarmWindow{Schedule: schedule, Duration: duration})
+	}
+
+	for i, ref := range prewarm.GetCallers() {
+		caller := Target{Namespace: ref.GetNamespace(), Name: strings.TrimSpace(ref.GetName())}
+		if caller.Namespace == "" {
+			caller.Namespace = namespace
+		}
+		if caller.Name == "" {
+			return PrewarmSettings{}, fmt.Errorf("prewarm.callers[%d].name is required", i)
+		}
+		settings.Callers = append(settings.Callers, caller)
+	}
+	return settings, nil
+}
+
+// openUntil reports whether a window is open at now, and until when. Windows
+// that overlap extend each other.
+func (s PrewarmSettings) openUntil(now time.Time) (time.Time, bool) {
+	now = now.In(s.Location)
+	var until time.Time
+	for _, window := range s.Windows {
+		start, ok := window.Schedule.next(now.Add(-window.Duration))
+		for ok && !start.After(now) {
+			if end := start.Add(window.Duration); end.After(until) {
+				until = end
+			}
+			start, ok = window.Schedule.next(start)
+		}
+	}
+	return until, until.After(now)
+}
+
+// nextOpening returns when the next window opens after now.
+func (s PrewarmSettings) nextOpening(now time.Time) (time.Time, bool) {
+	now = now.In(s.Location)
+	var next time.Time
+	for _, window := range s.Windows {
+		if start, ok := window.Schedule.next(now); ok && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// Prewarmer keeps activated Services warm ahead of their traffic: in the
+// windows their policies schedule, and while a caller known to use them is
+// serving. A warm target is reported active by the scaler and is never
+// drained for being idle.
+type Prewarmer struct {
+	registry *Registry
+
+	mu       sync.Mutex
+	policies map[types.NamespacedName]prewarmPolicy
+	// Per caller, whether it had endpoints at the last check.
+	serving map[Target]bool
+	// The targets found warm at the last check.
+	warm map[Target]bool
+
+	// now is swappable so windows can be tested without waiting for them.
+	now func() time.Time
+}
+
+type prewarmPolicy struct {
+	target   Target
+	settings PrewarmSettings
+}
+
+func NewPrewarmer(registry *Registry) *Prewarmer {
+	return &Prewarmer{
+		registry: registry,
+		policies: map[types.NamespacedName]prewarmPolicy{},
+		serving:  map[Target]bool{},
+		warm:     map[Target]bool{},
+		now:      time.Now,
+	}
+}
+
+// Track starts, updates or, for settings that prewarm nothing, stops
+// prewarming for a policy.
+func (p *Prewarmer) Track(policy types.NamespacedName, target Target, settings PrewarmSettings) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !settings.enabled() {
+		delete(p.policies, policy)
+		return
+	}
+	p.policies[policy] = prewarmPolicy{target: target, settings: settings}
+}
+
+// Forget stops prewarming for a deleted policy.
+func (p *Prewarmer) Forget(policy types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.policies, policy)
+}
+
+// Retained reports whether the target is warm, so the scaler reports it
+// active with nothing pending.
+func (p *Prewarmer) Retained(target Target) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.warm[target]
+}
+
+// Run checks windows and callers until stop is closed.
+func (p *Prewarmer) Run(stop <-chan struct{}, routing ActivationRouting) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.check(routing)
+		}
+	}
+}
+
+// check recomputes which targets are warm and tells the scaler streams of the
+// ones that changed.
+func (p *Prewarmer) check(routing ActivationRouting) {
+	p.mu.Lock()
+	now := p.now()
+	serving := map[Target]bool{}
+	warm := map[Target]bool{}
+	// Per target, why it is warm or when it next will be, for the logs.
+	why := map[Target]string{}
+	for _, policy := range p.policies {
+		for _, caller := range policy.settings.Callers {
+			if _, ok := serving[caller]; !ok {
+				serving[caller] = routing.Serving(caller)
+			}
+		}
+		until, open := policy.settings.openUntil(now)
+		servingCallers := servingNames(policy.settings.Callers, serving)
+		switch {
+		case open:
+			why[policy.target] = "window open until " + until.Format(time.RFC3339)
+		case len(servingCallers) > 0:
+			why[policy.target] = "callers serving: " + strings.Join(servingCallers, ", ")
+		default:
+			if next, ok := policy.settings.nextOpening(now); ok && !warm[policy.target] {
+				why[policy.target] = "next window opens " + next.Format(time.RFC3339)
+			}
+			continue
+		}
+		warm[policy.target] = true
+	}
+
+	var changed []Target
+	for target := range warm {
+		if !p.warm[target] {
+			changed = append(changed, target)
+			logger.Infof("prewarming %s/%s: %s", target.Namespace, target.Name, why[target])
+		}
+	}
+	for target := range p.warm {
+		if !warm[target] {
+			changed = append(changed, target)
+			if next := why[target]; next != "" {
+				logger.Infof("%s/%s no longer prewarmed; %s", target.Namespace, target.Name, next)
+			} else {
+				logger.Infof("%s/%s no longer prewarmed", target.Namespace, target.Name)
+			}
+		}
+	}
+	p.serving = serving
+	p.warm = warm
+	p.mu.Unlock()
+
+	for _, target := range changed {
+		p.registry.touch(target)
+	}
+}
+
+// servingNames lists the callers with endpoints as "namespace/name".
+func servingNames(callers []Target, serving map[Target]bool) []string {
+	var out []string
+	for _, caller := range callers {
+		if serving[caller] {
+			out = append(out, caller.Namespace+"/"+caller.Name)
+		}
+	}
+	return out
+}
+
+// PrewarmStatus satisfies PrewarmStatusLookup. Callers are judged by the last
+// check, windows by the current time, so the status follows a window that has
+// just opened or closed without waiting for a check.
+func (p *Prewarmer) PrewarmStatus(policy *clientnetworking.ServiceActivationPolicy) (bool, string) {
+	settings, err := PrewarmSettingsOf(policy)
+	if err != nil || !settings.enabled() {
+		return false, ""
+	}
+
+	p.mu.Lock()
+	now := p.now()
+	callersServing := slices.ContainsFunc(settings.Callers, func(caller Target) bool { return p.serving[caller] })
+	p.mu.Unlock()
+
+	if _, open := settings.openUntil(now); open {
+		return true, "PrewarmWindowOpen"
+	}
+	if callersServing {
+		return true, "CallersServing"
+	}
+	if _, ok := settings.nextOpening(now); ok {
+		return false, "PrewarmScheduled"
+	}
+	if len(settings.Windows) == 0 {
+		return false, "CallersNotServing"
+	}
+	return false, "NoUpcomingWindow"
+}
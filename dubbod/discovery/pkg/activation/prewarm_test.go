@@ -0,0 +1,196 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// callerRouting reports the listed targets as serving.
+type callerRouting map[Target]bool
+
+func (r callerRouting) Serving(target Target) bool { return r[target] }
+func (r callerRouting) CanReroute(Target) bool     { return true }
+
+var batch = Target{Namespace: "jobs", Name: "batch"}
+
+// officeHours keeps a target warm on weekdays from 07:30 to 18:30 Shanghai
+// time.
+func officeHours() *networking.ActivationPrewarm {
+	return &networking.ActivationPrewarm{
+		Windows:  []*networking.PrewarmWindow{{Schedule: "30 7 * * 1-5", Duration: durationpb.New(11 * time.Hour)}},
+		TimeZone: "Asia/Shanghai",
+	}
+}
+
+// newPrewarmTest prewarms orders in office hours and while jobs/batch is
+// serving, on a clock the test sets.
+func newPrewarmTest(t *testing.T) (*Prewarmer, *time.Time) {
+	t.Helper()
+	prewarm := officeHours()
+	prewarm.Callers = []*networking.PrewarmCaller{{Namespace: "jobs", Name: "batch"}}
+	settings, err := prewarmSettingsFromSpec("app", prewarm)
+	if err != nil {
+		t.Fatalf("prewarm settings: %v", err)
+	}
+	now := time.Time{}
+	prewarmer := NewPrewarmer(NewRegistry())
+	prewarmer.now = func() time.Time { return now }
+	prewarmer.Track(types.NamespacedName{Namespace: "app", Name: "orders"}, orders, settings)
+	return prewarmer, &now
+}
+
+func TestPrewarmSettingsFromSpec(t *testing.T) {
+	settings, err := prewarmSettingsFromSpec("app", &networking.ActivationPrewarm{
+		Windows: []*networking.PrewarmWindow{
+			{Schedule: "0 8 * * 1-5", Duration: durationpb.New(2 * time.Hour)},
+			{Schedule: "0 20 * * *", Duration: durationpb.New(30 * time.Minute)},
+		},
+		Callers: []*networking.PrewarmCaller{{Name: "batch"}, {Namespace: "jobs", Name: "report"}},
+	})
+	if err != nil {
+		t.Fatalf("prewarmSettingsFromSpec() error = %v", err)
+	}
+	if len(settings.Windows) != 2 || settings.Windows[1].Duration != 30*time.Minute || settings.Location != time.UTC {
+		t.Fatalf("windows = %+v in %v, want two windows in UTC", settings.Windows, settings.Location)
+	}
+	wantCallers := []Target{{Namespace: "app", Name: "batch"}, {Namespace: "jobs", Name: "report"}}
+	if len(settings.Callers) != 2 || settings.Callers[0] != wantCallers[0] || settings.Callers[1] != wantCallers[1] {
+		t.Fatalf("callers = %+v, want %+v", settings.Callers, wantCallers)
+	}
+
+	window := func(schedule string, duration time.Duration) []*networking.PrewarmWindow {
+		return []*networking.PrewarmWindow{{Schedule: schedule, Duration: durationpb.New(duration)}}
+	}
+	for _, prewarm := range []*networking.ActivationPrewarm{
+		{Windows: []*networking.PrewarmWindow{{Schedule: "0 8 * * 1-5"}}},
+		{Windows: window("0 8 * * 1-5", 30*time.Second)},
+		{Windows: window("0 25 * * *", time.Hour)},
+		{Windows: window("0 8 * * *", time.Hour), TimeZone: "Mars/Olympus"},
+		{Callers: []*networking.PrewarmCaller{{Namespace: "jobs"}}},
+	} {
+		if _, err := prewarmSettingsFromSpec("app", prewarm); err == nil {
+			t.Errorf("prewarmSettingsFromSpec(%v) accepted invalid settings", prewarm)
+		}
+	}
+}
+
+func TestPrewarmWindowsFollowTheirTimeZone(t *testing.T) {
+	prewarmer, now := newPrewarmTest(t)
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	policy := validPolicy()
+	policy.Spec.Prewarm = officeHours()
+
+	// Monday 07:29 in Shanghai is still Sunday in UTC.
+	*now = time.Date(2026, 3, 2, 7, 29, 0, 0, shanghai).UTC()
+	prewarmer.check(callerRouting{})
+	if prewarmer.Retained(orders) {
+		t.Fatal("target warm before its window opened")
+	}
+	if warm, reason := prewarmer.PrewarmStatus(policy); warm || reason != "PrewarmScheduled" {
+		t.Fatalf("status = %v %q, want the window opening in a minute", warm, reason)
+	}
+
+	*now = now.Add(time.Minute)
+	prewarmer.check(callerRouting{})
+	if !prewarmer.Retained(orders) {
+		t.Fatal("target not warm once its window opened")
+	}
+	if warm, reason := prewarmer.PrewarmStatus(policy); !warm || reason != "PrewarmWindowOpen" {
+		t.Fatalf("status = %v %q, want warm until 18:30", warm, reason)
+	}
+
+	*now = time.Date(2026, 3, 2, 18, 30, 0, 0, shanghai).UTC()
+	prewarmer.check(callerRouting{})
+	if prewarmer.Retained(orders) {
+		t.Fatal("target still warm after its window closed")
+	}
+}
+
+// A batch caller coming up is the earliest sign that its calls are coming.
+func TestTargetIsWarmWhileAKnownCallerServes(t *testing.T) {
+	prewarmer, now := newPrewarmTest(t)
+	// Saturday, outside every window.
+	*now = time.Date(2026, 3, 7, 3, 0, 0, 0, time.UTC)
+
+	prewarmer.check(callerRouting{batch: true})
+	if !prewarmer.Retained(orders) {
+		t.Fatal("target not warm while its caller serves")
+	}
+	policy := validPolicy()
+	policy.Spec.Prewarm = &networking.ActivationPrewarm{
+		Callers: []*networking.PrewarmCaller{{Namespace: "jobs", Name: "batch"}},
+	}
+	if warm, reason := prewarmer.PrewarmStatus(policy); !warm || reason != "CallersServing" {
+		t.Fatalf("status = %v %q, want warm for jobs/batch", warm, reason)
+	}
+
+	prewarmer.check(callerRouting{})
+	if prewarmer.Retained(orders) {
+		t.Fatal("target still warm after its caller stopped")
+	}
+	if warm, reason := prewarmer.PrewarmStatus(policy); warm || reason != "CallersNotServing" {
+		t.Fatalf("status = %v %q, want CallersNotServing", warm, reason)
+	}
+}
+
+func TestScalerStreamReportsPrewarmedTargetsAheadOfDemand(t *testing.T) {
+	prewarmer, now := newPrewarmTest(t)
+	*now = time.Date(2026, 3, 7, 3, 0, 0, 0, time.UTC)
+	scaler := NewScaler(prewarmer.registry)
+	scaler.retain = append(scaler.retain, prewarmer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newFakeStream(ctx)
+	go func() { _ = scaler.StreamIsActive(serviceRef(), stream) }()
+	if got := stream.next(t); got {
+		t.Fatal("initial state = active before anything is warm")
+	}
+
+	prewarmer.check(callerRouting{batch: true})
+	if got := stream.next(t); !got {
+		t.Fatal("stream did not report the prewarmed target active")
+	}
+}
+
+func TestPrewarmedTargetsAreNotDrained(t *testing.T) {
+	tracker, _, now := newIdleTest()
+	warm := true
+	tracker.warm = func(Target) bool { return warm }
+	routes := &routing{serving: true, canReroute: true}
+
+	tracker.check(routes, nil)
+	*now = now.Add(time.Hour)
+	tracker.check(routes, nil)
+	if draining(tracker) {
+		t.Fatal("a prewarmed target was drained for being idle")
+	}
+
+	warm = false
+	*now = now.Add(10 * time.Minute)
+	tracker.check(routes, nil)
+	if !draining(tracker) {
+		t.Fatal("the idle window did not run from the end of prewarming")
+	}
+}
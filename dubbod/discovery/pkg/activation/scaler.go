@@ -18,8 +18,11 @@
 //
 // KEDA owns the replica count. This package only answers "is anything waiting
 // on this Service, and how much", so KEDA can take a Service from zero to one
-// when a request arrives for it. Nothing here writes replicas, which is what
-// keeps a Service from being driven by two controllers at once.
+// when a request arrives for it. Nothing here writes the replicas of a
+// workload KEDA scales, which is what keeps a Service from being driven by two
+// controllers at once. Only a policy that names its Deployment or StatefulSet
+// as the autoscaler, for a cluster without KEDA, has its replicas written here,
+// by the NativeScaler.
 package activation
 
 import (
@@ -62,6 +65,10 @@ type Scaler struct {
 
 	demand DemandSource
 
+	// retain keeps a target active with nothing pending: until it has been
+	// drained, and while it is prewarmed. Empty leaves both to the autoscaler.
+	retain []interface{ Retained(Target) bool }
+
 	// streams counts the open StreamIsActive calls per target. A target with
 	// none is one KEDA is not listening to, which is the difference between a
 	// policy that will activate and one that only looks like it will.
@@ -86,6 +93,20 @@ func (s *Scaler) Subscribed(target Target) bool {
 	return s.streams[target] > 0
 }
 
+// active is what the scaler reports for a target: requests are waiting for
+// it, or it is in use and not yet drained, or it is prewarmed.
+func (s *Scaler) active(target Target, pending int64) bool {
+	if pending > 0 {
+		return true
+	}
+	for _, retain := range s.retain {
+		if retain.Retained(target) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scaler) streamOpened(target Target) {
 	s.streamsMu.Lock()
 	defer s.streamsMu.Unlock()
@@ -116,7 +137,7 @@ func (s *Scaler) IsActive(_ context.Context, ref *externalscaler.ScaledObjectRef
 		return nil, err
 	}
 	return &externalscaler.IsActiveResponse{
-		Result: s.demand.Pending(parsed.target) > 0,
+		Result: s.active(parsed.target, s.demand.Pending(parsed.target)),
 	}, nil
 }
 
@@ -140,7 +161,7 @@ func (s *Scaler) StreamIsActive(ref *externalscaler.ScaledObjectRef, stream exte
 	// KEDA expects the current state as soon as the stream opens, not only on
 	// the next change.
 	if err := stream.Send(&externalscaler.IsActiveResponse{
-		Result: s.demand.Pending(parsed.target) > 0,
+		Result: s.active(parsed.target, s.demand.Pending(parsed.target)),
 	}); err != nil {
 		return err
 	}
@@ -154,7 +175,7 @@ func (s *Scaler) StreamIsActive(ref *externalscaler.ScaledObjectRef, stream exte
 			if !ok {
 				return nil
 			}
-			if err := stream.Send(&externalscaler.IsActiveResponse{Result: pending > 0}); err != nil {
+			if err := stream.Send(&externalscaler.IsActiveResponse{Result: s.active(parsed.target, pending)}); err != nil {
 				return err
 			}
 		}
@@ -48,13 +48,21 @@ var enforcementPolicy = keepalive.EnforcementPolicy{
 type Server struct {
 	scaler   *Scaler
 	registry *Registry
+	idle     *IdleTracker
+	prewarm  *Prewarmer
+	demand   *DemandService
 	grpc     *grpc.Server
 }
 
 // NewServer builds the activation endpoint and the demand registry behind it.
 func NewServer() *Server {
 	registry := NewRegistry()
+	prewarm := NewPrewarmer(registry)
+	idle := NewIdleTracker(registry)
+	// A prewarmed target is in use as far as idle detection is concerned.
+	idle.warm = prewarm.Retained
 	scaler := NewScaler(registry)
+	scaler.retain = append(scaler.retain, idle, prewarm)
 
 	server := grpc.NewServer(
 		grpc.KeepaliveParams(keepaliveOptions),
@@ -64,9 +72,17 @@ func NewServer() *Server {
 	// Gateways report into the same registry the scaler reads, on the same
 	// listener: a gateway that can reach this replica can always be heard by
 	// the KEDA stream this replica is serving.
-	demandpb.RegisterActivationDemandServer(server, NewDemandService(registry))
+	demand := NewDemandService(registry)
+	demandpb.RegisterActivationDemandServer(server, demand)
 
-	return &Server{scaler: scaler, registry: registry, grpc: server}
+	return &Server{scaler: scaler, registry: registry, idle: idle, prewarm: prewarm, demand: demand, grpc: server}
+}
+
+// RegisterWorkloadDemand serves demand reports from proxyless workloads on an
+// mTLS server, typically the secure discovery server they already reach. The
+// reports feed the same registry as the gateways'.
+func (s *Server) RegisterWorkloadDemand(server grpc.ServiceRegistrar, identify IdentityFunc) {
+	demandpb.RegisterActivationDemandServer(server, NewWorkloadDemandService(s.registry, identify))
 }
 
 // Scaler exposes the KEDA subscription state the policy controller reports.
@@ -75,6 +91,21 @@ func (s *Server) Scaler() *Scaler { return s.scaler }
 // Registry exposes the demand store gateways report into.
 func (s *Server) Registry() *Registry { return s.registry }
 
+// Idle exposes the idle detection the policy controller configures.
+func (s *Server) Idle() *IdleTracker { return s.idle }
+
+// Prewarm exposes the prewarming the policy controller configures.
+func (s *Server) Prewarm() *Prewarmer { return s.prewarm }
+
+// Handoff exchanges this replica's demand reports with the other replicas
+// behind peers, a host:port resolving to all of them. replica names this one
+// to the others, which is how it tells its own answer apart. Call it before
+// Serve.
+func (s *Server) Handoff(replica, peers string) *Handoff {
+	s.demand.replica = replica
+	return NewHandoff(s.registry, replica, peers)
+}
+
 // Serve listens on addr until stop is closed. An empty address disables the
 // endpoint, which is how a cluster without KEDA installed runs unchanged.
 func (s *Server) Serve(addr string, stop <-chan struct{}) error {
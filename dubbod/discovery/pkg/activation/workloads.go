@@ -0,0 +1,129 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// nativeScalerFieldManager owns the fields the native scaler writes.
+const nativeScalerFieldManager = "dubbod-activation"
+
+// kubeWorkloads reads Deployments and StatefulSets from informers and scales
+// them through their scale subresource. Stamping a workload before each change
+// merge-patches its metadata, so besides the scale subresource it needs patch
+// on the workloads themselves.
+type kubeWorkloads struct {
+	client       kube.Client
+	deployments  kclient.Client[*appsv1.Deployment]
+	statefulSets kclient.Client[*appsv1.StatefulSet]
+}
+
+func newKubeWorkloads(client kube.Client) *kubeWorkloads {
+	filter := kclient.Filter{ObjectFilter: client.ObjectFilter()}
+	return &kubeWorkloads{
+		client:       client,
+		deployments:  kclient.NewFiltered[*appsv1.Deployment](client, filter),
+		statefulSets: kclient.NewFiltered[*appsv1.StatefulSet](client, filter),
+	}
+}
+
+func (w *kubeWorkloads) Get(workload Workload) (workloadState, bool) {
+	var replicas *int32
+	var meta metav1.Object
+	switch workload.Kind {
+	case "Deployment":
+		deployment := w.deployments.Get(workload.Name, workload.Namespace)
+		if deployment == nil {
+			return workloadState{}, false
+		}
+		replicas, meta = deployment.Spec.Replicas, deployment
+	case "StatefulSet":
+		statefulSet := w.statefulSets.Get(workload.Name, workload.Namespace)
+		if statefulSet == nil {
+			return workloadState{}, false
+		}
+		replicas, meta = statefulSet.Spec.Replicas, statefulSet
+	default:
+		return workloadState{}, false
+	}
+	// Both kinds default an unset count to one.
+	state := workloadState{Replicas: 1, Annotations: meta.GetAnnotations()}
+	if replicas != nil {
+		state.Replicas = *replicas
+	}
+	return state, true
+}
+
+// Scale stamps the workload before changing its count. A stamp whose count
+// never landed reads as ReplicasChangedElsewhere until the retry lands it,
+// where the other order could report a count dubbod set as set by someone
+// else.
+func (w *kubeWorkloads) Scale(ctx context.Context, workload Workload, replicas int32, stamp map[string]string) error {
+	annotations, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": stamp}})
+	if err != nil {
+		return err
+	}
+	scale, err := json.Marshal(map[string]any{"spec": map[string]any{"replicas": replicas}})
+	if err != nil {
+		return err
+	}
+	options := metav1.PatchOptions{FieldManager: nativeScalerFieldManager}
+	apps := w.client.Kube().AppsV1()
+	switch workload.Kind {
+	case "Deployment":
+		deployments := apps.Deployments(workload.Namespace)
+		if _, err := deployments.Patch(ctx, workload.Name, types.MergePatchType, annotations, options); err != nil {
+			return err
+		}
+		_, err = deployments.Patch(ctx, workload.Name, types.MergePatchType, scale, options, "scale")
+	case "StatefulSet":
+		statefulSets := apps.StatefulSets(workload.Namespace)
+		if _, err := statefulSets.Patch(ctx, workload.Name, types.MergePatchType, annotations, options); err != nil {
+			return err
+		}
+		_, err = statefulSets.Patch(ctx, workload.Name, types.MergePatchType, scale, options, "scale")
+	}
+	return err
+}
+
+// AddEventHandler calls changed with every workload that is added, updated or
+// deleted.
+func (w *kubeWorkloads) AddEventHandler(changed func(Workload)) {
+	w.deployments.AddEventHandler(controllers.ObjectHandler(func(object controllers.Object) {
+		changed(Workload{Kind: "Deployment", Namespace: object.GetNamespace(), Name: object.GetName()})
+	}))
+	w.statefulSets.AddEventHandler(controllers.ObjectHandler(func(object controllers.Object) {
+		changed(Workload{Kind: "StatefulSet", Namespace: object.GetNamespace(), Name: object.GetName()})
+	}))
+}
+
+func (w *kubeWorkloads) HasSynced() bool {
+	return w.deployments.HasSynced() && w.statefulSets.HasSynced()
+}
+
+func (w *kubeWorkloads) ShutdownHandlers() {
+	controllers.ShutdownAll(w.deployments, w.statefulSets)
+}
@@ -16,8 +16,18 @@
 package bootstrap
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/leaderelection"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/server/ca/authenticate"
+	"github.com/apache/dubbo-kubernetes/pkg/config/schema/kind"
+	kubelib "github.com/apache/dubbo-kubernetes/pkg/kube"
 	"github.com/apache/dubbo-kubernetes/pkg/log"
+	"github.com/apache/dubbo-kubernetes/pkg/security"
+	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
 )
 
 // initActivation starts the KEDA-facing scaler and the policy controller that
@@ -29,6 +39,18 @@ import (
 // rather than a mesh-wide dependency.
 func (s *Server) initActivation(args *DubboArgs) error {
 	s.activation = activation.NewServer()
+	s.activationDemandAddr = args.ServerOptions.ActivationDemandAddr
+
+	if peers := args.ServerOptions.ActivationPeerAddr; peers != "" && args.ServerOptions.ActivationAddr != "" {
+		handoff := s.activation.Handoff(args.PodName, peers)
+		s.addStartFunc("activation demand handoff", func(stop <-chan struct{}) error {
+			go handoff.Run(stop)
+			return nil
+		})
+		// KEDA is only routed to a replica once it is ready, so the first
+		// answer it gets already counts the demand the other replicas hold.
+		s.addReadinessProbe("activation demand handoff", handoff.HasSynced)
+	}
 
 	if err := s.activation.Serve(args.ServerOptions.ActivationAddr, s.internalStop); err != nil {
 		return err
@@ -39,11 +61,106 @@ func (s *Server) initActivation(args *DubboArgs) error {
 		return nil
 	}
 
-	controller := activation.NewController(s.kubeClient)
+	native := activation.NewNativeScaler(s.kubeClient, s.activation.Scaler(), args.PodName)
+	controller := activation.NewController(s.kubeClient, s.activation.Idle(), s.activation.Prewarm(), native)
 	s.addStartFunc("activation policy controller", func(stop <-chan struct{}) error {
 		go controller.Run(stop)
 		return nil
 	})
 
+	// Every replica tracks policies and demand, but only the leader writes
+	// replica counts. The election spans revisions: two revisions scaling the
+	// same workload would undo each other.
+	s.addTerminatingStartFunc("activation native scaler", func(stop <-chan struct{}) error {
+		leaderelection.
+			NewLeaderElection(args.Namespace, args.PodName, leaderelection.ActivationNativeScaler, args.Revision, s.kubeClient).
+			AddRunFunction(func(leaderStop <-chan struct{}) {
+				// A workload not yet read would look like one at zero.
+				if !kubelib.WaitForCacheSync("activation native scaler", leaderStop, native.HasSynced) {
+					return
+				}
+				log.Info("scaling activation workloads as leader")
+				native.Run(leaderStop)
+			}).
+			Run(stop)
+		return nil
+	})
+
+	s.environment.ActivationDrains = s.activation.Idle()
+	s.addStartFunc("activation idle and prewarm tracking", func(stop <-chan struct{}) error {
+		go func() {
+			// Whether a target is serving is read from the endpoint index; an
+			// index that is still filling would have every target start
+			// drained.
+			if !kubelib.WaitForCacheSync("activation tracking", stop, s.cachesSynced) {
+				return
+			}
+			routing := activationRouting{env: s.environment}
+			go s.activation.Prewarm().Run(stop, routing)
+			s.activation.Idle().Run(stop, routing, func() {
+				s.XDSServer.ConfigUpdate(&model.PushRequest{
+					Full:           true,
+					Forced:         true,
+					ConfigsUpdated: sets.New(model.ConfigKey{Kind: kind.ServiceActivationPolicy}),
+					Reason:         model.NewReasonStats(model.DependentResource),
+				})
+			})
+		}()
+		return nil
+	})
+
 	return nil
 }
+
+// activationRouting answers idle detection from the current push context and
+// endpoint index, the same state EDS is built from.
+type activationRouting struct {
+	env *model.Environment
+}
+
+// Serving answers for activated targets and for the callers prewarming waits
+// on alike, so it looks the Service up among all of them.
+func (r activationRouting) Serving(target activation.Target) bool {
+	for _, namespaces := range r.env.PushContext().ServiceIndex.HostnameAndNamespace {
+		if svc := namespaces[target.Namespace]; svc != nil && svc.Attributes.Name == target.Name {
+			return hasHealthyEndpoints(r.env.EndpointIndex, svc)
+		}
+	}
+	return false
+}
+
+func (r activationRouting) CanReroute(target activation.Target) bool {
+	activator := r.env.PushContext().ActivationGatewayService(target.Namespace)
+	return activator != nil && len(activator.Ports) > 0 && hasHealthyEndpoints(r.env.EndpointIndex, activator)
+}
+
+func hasHealthyEndpoints(index *model.EndpointIndex, svc *model.Service) bool {
+	shards, ok := index.ShardsForService(string(svc.Hostname), svc.Attributes.Namespace)
+	if !ok {
+		return false
+	}
+	shards.RLock()
+	defer shards.RUnlock()
+	for _, endpoints := range shards.Shards {
+		for _, endpoint := range endpoints {
+			if endpoint.HealthStatus == model.Healthy {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// workloadDemandIdentity returns the identity of the workload certificate a
+// demand stream was opened with. The secure discovery server has verified the
+// chain, but a client may connect without a certificate at all.
+func workloadDemandIdentity(ctx context.Context) (string, error) {
+	caller, err := (&authenticate.ClientCertAuthenticator{}).Authenticate(security.AuthContext{GrpcContext: ctx})
+	if err != nil {
+		return "", err
+	}
+	if caller == nil || len(caller.Identities) == 0 {
+		return "", fmt.Errorf("the client certificate carries no identity")
+	}
+	return caller.Identities[0], nil
+}
@@ -0,0 +1,223 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/features"
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/ca"
+	pkiutil "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
+	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
+	kubelib "github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
+	"github.com/apache/dubbo-kubernetes/pkg/log"
+	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// caRevocationCheckInterval bounds how long a rotated signing certificate
+// keeps serving a CRL signed by its predecessor.
+const (
+	caRevocationsControllerName = "CA revocations"
+	caRevocationCheckInterval   = time.Minute
+)
+
+// caRevocations signs a CRL for the revocations in ca.RevocationsConfigMap
+// and keeps it on the dubbod bundle watcher, from where it reaches the
+// namespace CRL ConfigMaps and the inherent gRPC workload Secrets.
+type caRevocations struct {
+	server     *Server
+	configMaps kclient.Client[*corev1.ConfigMap]
+	trigger    chan struct{}
+
+	// signedInput describes what the current CRL was signed from, so an
+	// unchanged list is only re-signed before it runs out.
+	signedInput string
+	signedAt    time.Time
+}
+
+func (s *Server) initCARevocations() {
+	if !features.EnableCACRL || s.CA == nil || s.RA != nil || s.kubeClient == nil {
+		return
+	}
+	r := &caRevocations{
+		server: s,
+		configMaps: kclient.NewFiltered[*corev1.ConfigMap](s.kubeClient, kclient.Filter{
+			FieldSelector: "metadata.name=" + ca.RevocationsConfigMap,
+			Namespace:     s.namespace,
+		}),
+		trigger: make(chan struct{}, 1),
+	}
+	r.configMaps.AddEventHandler(controllers.ObjectHandler(func(controllers.Object) {
+		r.requestRefresh()
+	}))
+	s.addStartFunc(caRevocationsControllerName, func(stop <-chan struct{}) error {
+		r.configMaps.Start(stop)
+		go r.run(stop)
+		return nil
+	})
+}
+
+func (r *caRevocations) requestRefresh() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (r *caRevocations) run(stop <-chan struct{}) {
+	if !kubelib.WaitForCacheSync(caRevocationsControllerName, stop, r.configMaps.HasSynced) {
+		return
+	}
+	ticker := time.NewTicker(caRevocationCheckInterval)
+	defer ticker.Stop()
+	for {
+		r.refresh()
+		select {
+		case <-ticker.C:
+		case <-r.trigger:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (r *caRevocations) refresh() {
+	var revocations []ca.Revocation
+	if cm := r.configMaps.Get(ca.RevocationsConfigMap, r.server.namespace); cm != nil {
+		var err error
+		revocations, err = ca.ParseRevocations(cm.Data[ca.RevocationsKey])
+		if err != nil {
+			log.Errorf("Ignoring invalid ConfigMap %s/%s: %v", r.server.namespace, ca.RevocationsConfigMap, err)
+			return
+		}
+	}
+	set := ca.NewRevocationSet(revocations)
+	r.server.CA.SetRevocations(set)
+
+	entries := set.Entries(r.issuedCertificates(set))
+	signingCert, _, _, _ := r.server.CA.GetCAKeyCertBundle().GetAllPem()
+	input := crlInput(entries, signingCert)
+
+	now := time.Now()
+	if input == r.signedInput && now.Sub(r.signedAt) < ca.CRLValidity/2 {
+		return
+	}
+	var crl []byte
+	if len(entries) > 0 {
+		var err error
+		crl, err = r.server.CA.GenerateCRL(entries, now)
+		if err != nil {
+			log.Errorf("Failed to sign the CA revocation list: %v", err)
+			return
+		}
+	}
+	r.signedInput = input
+	r.signedAt = now
+	if len(crl) == 0 && len(r.server.dubbodCertBundleWatcher.GetRevocationCRL()) == 0 {
+		return
+	}
+
+	log.Infof("CA revocation list updated with %d revoked certificates", len(entries))
+	// The bundle watcher hands the list to every workload Secret, which also
+	// replaces the workload certificates revoked by serial number.
+	r.server.dubbodCertBundleWatcher.SetRevocationCRLAndNotify(crl)
+}
+
+// issuedCertificates returns the serial numbers of the certificates known to
+// carry a revoked identity: those this dubbod signed since it started, and
+// those of the inherent gRPC workload Secrets of pods running as a revoked
+// service account. Certificates signed by another replica or before a restart
+// for other workloads are missing and stay valid until they expire; the
+// identity revocation only stops them from being renewed.
+func (r *caRevocations) issuedCertificates(set *ca.RevocationSet) map[string][]*big.Int {
+	identities := set.IdentityRevocations()
+	if len(identities) == 0 {
+		return nil
+	}
+	issued := r.server.CA.IssuedSerialNumbers(identities, time.Now())
+
+	controller := r.server.inherentGRPCWorkloadController
+	if controller == nil || controller.secrets == nil {
+		return issued
+	}
+	serviceAccounts := map[string]bool{}
+	for _, identity := range identities {
+		id, err := spiffe.ParseIdentity(identity)
+		if err != nil {
+			continue
+		}
+		serviceAccounts[id.Namespace+"/"+id.ServiceAccount] = true
+	}
+	for _, key := range controller.managedPodKeys() {
+		pod := controller.pods.Get(key.Name, key.Namespace)
+		if pod == nil {
+			continue
+		}
+		serviceAccount := pod.Spec.ServiceAccountName
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+		if !serviceAccounts[pod.Namespace+"/"+serviceAccount] {
+			continue
+		}
+		secret := controller.secrets.Get(inject.InherentGRPCSecretNameForMeta(pod.ObjectMeta), pod.Namespace)
+		if secret == nil {
+			continue
+		}
+		leaf, err := pkiutil.ParsePemEncodedCertificate(secret.Data[constants.CertChainFilename])
+		if err != nil {
+			continue
+		}
+		for _, uri := range leaf.URIs {
+			if set.IdentityRevoked(uri.String()) {
+				issued[uri.String()] = appendSerial(issued[uri.String()], leaf.SerialNumber)
+			}
+		}
+	}
+	return issued
+}
+
+// appendSerial adds serial to serials unless it is already listed.
+func appendSerial(serials []*big.Int, serial *big.Int) []*big.Int {
+	for _, s := range serials {
+		if s.Cmp(serial) == 0 {
+			return serials
+		}
+	}
+	return append(serials, serial)
+}
+
+// crlInput identifies the CRL signed for entries by signingCert.
+func crlInput(entries []x509.RevocationListEntry, signingCert []byte) string {
+	serials := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		serials = append(serials, fmt.Sprintf("%s/%d/%d", ca.FormatSerialNumber(entry.SerialNumber),
+			entry.ReasonCode, entry.RevocationTime.Unix()))
+	}
+	sort.Strings(serials)
+	return strings.Join(serials, ",") + "|" + string(bytes.TrimSpace(signingCert))
+}
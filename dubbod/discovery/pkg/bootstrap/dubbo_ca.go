@@ -31,12 +31,15 @@ import (
 	caserver "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/server/ca"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
 	"github.com/apache/dubbo-kubernetes/pkg/env"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
 	"github.com/apache/dubbo-kubernetes/pkg/log"
 	"github.com/apache/dubbo-kubernetes/pkg/security"
 	"github.com/fsnotify/fsnotify"
 	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
 )
 
 type caOptions struct {
@@ -59,7 +62,11 @@ var (
 		"External CA Integration Type. Permitted value is DUBBD_RA_KUBERNETES_API.").Get()
 	// TODO: Likely to be removed and added to mesh config
 	k8sSigner = env.Register("K8S_SIGNER", "",
-		"Kubernetes CA Signer type. Valid from Kubernetes 1.18").Get()
+		"Kubernetes CA Signer type. Valid from Kubernetes 1.18. Also accepts cert-manager issuers as "+
+			"clusterissuer:<name> or issuer:<namespace>/<name>.").Get()
+	externalCAAutoApprove = env.Register("EXTERNAL_CA_AUTO_APPROVE", true,
+		"If true, dubbod approves the CSRs it forwards to an external signer. Disable it when "+
+			"an external approver, such as cert-manager approver-policy, approves them.").Get()
 	workloadCertTTL = env.Register("DEFAULT_WORKLOAD_CERT_TTL",
 		cmd.DefaultWorkloadCertTTL,
 		"The default TTL of issued workload certificates. Applied when the client sets a "+
@@ -93,7 +100,10 @@ func (s *Server) initCAServer(ca caserver.CertificateAuthority, opts *caOptions)
 	caServer, startErr := caserver.New(ca, maxWorkloadCertTTL.Get(), opts.Authenticators)
 	if startErr != nil {
 		log.Errorf("failed to create dubbo ca server: %v", startErr)
+		return
 	}
+	caServer.CertSignerForNamespace = s.certSignerForNamespace
+	caServer.DefaultCertSigner = opts.ExternalCASigner
 	s.caServer = caServer
 }
 
@@ -180,6 +190,7 @@ func (s *Server) createDubboRA(opts *caOptions) (ra.RegistrationAuthority, error
 		K8sClient:        s.kubeClient.Kube(),
 		TrustDomain:      opts.TrustDomain,
 		CertSignerDomain: opts.CertSignerDomain,
+		ApproveCSR:       externalCAAutoApprove,
 	}
 	raServer, err := ra.NewDubboRA(raOpts)
 	if err != nil {
@@ -194,6 +205,54 @@ func (s *Server) createDubboRA(opts *caOptions) (ra.RegistrationAuthority, error
 	return raServer, err
 }
 
+// certSignerForNamespace returns the signer mesh config selects for workloads in
+// namespace. Only a registration authority forwards CSRs to a signer; the
+// built-in CA always signs itself.
+func (s *Server) certSignerForNamespace(namespace string) string {
+	if s.RA == nil {
+		return ""
+	}
+	return ra.SignerForNamespace(s.environment.Mesh().GetCaCertificates(), namespace)
+}
+
+// rootRotationProgress lists what still holds back a staged root rotation:
+// namespace root cert ConfigMaps without trustBundle and inherent gRPC
+// workloads that have not been re-issued yet.
+func (s *Server) rootRotationProgress(trustBundle, signingRoot []byte) ([]string, error) {
+	if s.caRootConfigMaps == nil {
+		return nil, nil
+	}
+	if !s.caRootConfigMaps.HasSynced() {
+		return nil, fmt.Errorf("root cert ConfigMaps are not synced yet")
+	}
+	var pending []string
+	for _, cm := range s.caRootConfigMaps.List(metav1.NamespaceAll, klabels.Everything()) {
+		if cm.Data[constants.CACertNamespaceConfigMapDataName] != string(trustBundle) {
+			pending = append(pending, "configmap "+cm.Namespace+"/"+cm.Name)
+		}
+	}
+
+	controllers := []*inherentGRPCWorkloadController{s.inherentGRPCWorkloadController}
+	if s.inherentGRPCRemoteControllers != nil {
+		for _, remote := range s.inherentGRPCRemoteControllers.All() {
+			controllers = append(controllers, remote.controller)
+		}
+	}
+	for _, c := range controllers {
+		if c == nil {
+			continue
+		}
+		pods, err := c.rootRotationPending(trustBundle, signingRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			pending = append(pending, "pod "+pod)
+		}
+	}
+	return pending, nil
+}
+
 func (s *Server) createDubboCA(opts *caOptions) (*ca.DubboCA, error) {
 	var caOpts *ca.DubboCAOptions
 	var signingCABundleComplete bool
@@ -235,6 +294,13 @@ func (s *Server) createDubboCA(opts *caOptions) (*ca.DubboCA, error) {
 			return nil, err
 		}
 		caOpts.OnRootCertUpdate = s.updateRootCertAndGenKeyCert
+		caOpts.RootRotationProgress = s.rootRotationProgress
+		if s.kubeClient != nil {
+			s.caRootConfigMaps = kclient.NewFiltered[*corev1.ConfigMap](s.kubeClient, kclient.Filter{
+				FieldSelector: "metadata.name=" + features.CACertConfigMapName,
+				ObjectFilter:  s.kubeClient.ObjectFilter(),
+			})
+		}
 	} else {
 		log.Info("Use local CA certificate")
 
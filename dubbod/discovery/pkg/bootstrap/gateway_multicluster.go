@@ -54,7 +54,7 @@ func (s *Server) initMulticlusterGatewayDeploymentControllers(args *DubboArgs) {
 				return
 			}
 			controller := gateway.NewDeploymentController(remote.Client, remote.ID, s.environment,
-				s.webhookInfo.getWebhookConfig, s.webhookInfo.addHandler, nil, args.Revision, args.Namespace)
+				s.webhookInfo.getWebhookConfig, s.webhookInfo.addHandler, gateway.NewTagWatcher(remote.Client, args.Revision), args.Revision, args.Namespace)
 			log.Infof("starting remote gateway deployment controller for cluster %s", remote.ID)
 			controller.Run(c.stop)
 		}()
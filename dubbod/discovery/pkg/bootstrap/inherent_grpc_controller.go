@@ -20,19 +20,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/activation/demandpb"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/features"
 	discoverymodel "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
 	pkgbootstrap "github.com/apache/dubbo-kubernetes/pkg/bootstrap"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
 	configlabels "github.com/apache/dubbo-kubernetes/pkg/config/labels"
 	meshconfig "github.com/apache/dubbo-kubernetes/pkg/config/mesh"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/kind"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	telemetryconfig "github.com/apache/dubbo-kubernetes/pkg/config/telemetry"
 	"github.com/apache/dubbo-kubernetes/pkg/grpcxds"
 	kubelib "github.com/apache/dubbo-kubernetes/pkg/kube"
@@ -75,6 +82,9 @@ type inherentGRPCWorkloadController struct {
 	server *Server
 	client kubelib.Client
 	pods   kclient.Client[*corev1.Pod]
+	// secrets caches the workload Secrets this controller writes, for the
+	// periodic checks that would otherwise read every one of them live.
+	secrets kclient.Client[*corev1.Secret]
 	// managedPods keeps bundle/cert updates from scanning every Pod in the cluster.
 	managedPods kclient.RawIndexer
 	queue       controllers.Queue
@@ -162,6 +172,10 @@ func newInherentGRPCWorkloadController(s *Server, client kubelib.Client) *inhere
 		pods: kclient.NewFiltered[*corev1.Pod](client, kclient.Filter{
 			ObjectFilter: client.ObjectFilter(),
 		}),
+		secrets: kclient.NewFiltered[*corev1.Secret](client, kclient.Filter{
+			LabelSelector: inject.InherentGRPCSecretLabel + "=true",
+			ObjectFilter:  client.ObjectFilter(),
+		}),
 		rotations: make(map[types.NamespacedName]time.Time),
 	}
 	c.managedPods = c.pods.Index(inherentGRPCManagedPodIndex, func(pod *corev1.Pod) []string {
@@ -187,7 +201,8 @@ func newInherentGRPCWorkloadController(s *Server, client kubelib.Client) *inhere
 
 func (c *inherentGRPCWorkloadController) Run(stop <-chan struct{}) {
 	c.pods.Start(stop)
-	if !kubelib.WaitForCacheSync(inherentGRPCControllerName, stop, c.pods.HasSynced) {
+	c.secrets.Start(stop)
+	if !kubelib.WaitForCacheSync(inherentGRPCControllerName, stop, c.pods.HasSynced, c.secrets.HasSynced) {
 		c.queue.ShutDownEarly()
 		return
 	}
@@ -202,7 +217,7 @@ func (c *inherentGRPCWorkloadController) Run(stop <-chan struct{}) {
 }
 
 func (c *inherentGRPCWorkloadController) HasSynced() bool {
-	return c.pods.HasSynced() && c.queue.HasSynced()
+	return c.pods.HasSynced() && c.secrets.HasSynced() && c.queue.HasSynced()
 }
 
 func (c *inherentGRPCWorkloadController) watchBundleChanges(stop <-chan struct{}) {
@@ -241,7 +256,7 @@ func inherentGRPCRuntimeConfigNeedsUpdate(req *discoverymodel.PushRequest) bool
 	}
 	for cfg := range req.ConfigsUpdated {
 		switch cfg.Kind {
-		case kind.HTTPRoute, kind.BackendTLSPolicy, kind.CircuitBreakerPolicy, kind.FaultInjectionPolicy, kind.PeerAuthentication, kind.RequestAuthentication, kind.AuthorizationPolicy, kind.Telemetry, kind.Service, kind.EndpointSlice, kind.Endpoints, kind.Pod, kind.Namespace:
+		case kind.HTTPRoute, kind.GRPCRoute, kind.BackendTLSPolicy, kind.CircuitBreakerPolicy, kind.FaultInjectionPolicy, kind.RateLimitPolicy, kind.PeerAuthentication, kind.RequestAuthentication, kind.AuthorizationPolicy, kind.Telemetry, kind.Service, kind.EndpointSlice, kind.Endpoints, kind.Pod, kind.Namespace:
 			return true
 		}
 	}
@@ -331,9 +346,14 @@ func (c *inherentGRPCWorkloadController) reconcile(key types.NamespacedName) err
 		if _, err := secrets.Create(context.Background(), desired, metav1.CreateOptions{}); err != nil {
 			return err
 		}
-	} else if !reflect.DeepEqual(current.Data, desired.Data) || !reflect.DeepEqual(current.OwnerReferences, desired.OwnerReferences) {
+	} else if !reflect.DeepEqual(current.Data, desired.Data) || !reflect.DeepEqual(current.OwnerReferences, desired.OwnerReferences) ||
+		current.Labels[inject.InherentGRPCSecretLabel] != "true" {
 		current.Data = desired.Data
 		current.OwnerReferences = desired.OwnerReferences
+		if current.Labels == nil {
+			current.Labels = map[string]string{}
+		}
+		current.Labels[inject.InherentGRPCSecretLabel] = "true"
 		if _, err := secrets.Update(context.Background(), current, metav1.UpdateOptions{}); err != nil {
 			return err
 		}
@@ -356,19 +376,43 @@ type inherentGRPCWorkloadContext struct {
 	clusterID        string
 	discoveryAddress string
 	caAddress        string
+	// activationAddress resolves to every dubbod replica; see
+	// DiscoveryServerOptions.ActivationDemandAddr.
+	activationAddress string
+	// hasCRL is set when the secret carries the CA revocation list.
+	hasCRL bool
+	// hasSPIFFEBundleMap is set when the secret carries the SPIFFE bundle map
+	// of federated trust domains.
+	hasSPIFFEBundleMap bool
 }
 
 type inherentGRPCRuntimeConfig struct {
-	Version      string                              `json:"version"`
-	Mode         string                              `json:"mode"`
-	Env          map[string]string                   `json:"env"`
-	Bootstrap    inherentGRPCBootstrapRuntimeConfig  `json:"bootstrap"`
-	Certificates inherentGRPCCertRuntimeConfig       `json:"certificates"`
-	Keepalive    inherentGRPCKeepaliveRuntimeConfig  `json:"keepalive"`
-	Workload     inherentGRPCWorkloadRuntimeConfig   `json:"workload"`
-	Telemetry    *inherentGRPCTelemetryRuntimeConfig `json:"telemetry,omitempty"`
-	Services     []inherentGRPCServiceRuntimeConfig  `json:"services,omitempty"`
-	Routes       []inherentGRPCRouteRuntimeConfig    `json:"routes,omitempty"`
+	Version      string                               `json:"version"`
+	Mode         string                               `json:"mode"`
+	Env          map[string]string                    `json:"env"`
+	Bootstrap    inherentGRPCBootstrapRuntimeConfig   `json:"bootstrap"`
+	Certificates inherentGRPCCertRuntimeConfig        `json:"certificates"`
+	Keepalive    inherentGRPCKeepaliveRuntimeConfig   `json:"keepalive"`
+	Workload     inherentGRPCWorkloadRuntimeConfig    `json:"workload"`
+	Telemetry    *inherentGRPCTelemetryRuntimeConfig  `json:"telemetry,omitempty"`
+	Services     []inherentGRPCServiceRuntimeConfig   `json:"services,omitempty"`
+	Routes       []inherentGRPCRouteRuntimeConfig     `json:"routes,omitempty"`
+	Activation   *inherentGRPCActivationRuntimeConfig `json:"activation,omitempty"`
+}
+
+// inherentGRPCActivationRuntimeConfig tells the workload where to report the
+// calls it holds or fails fast for scaleFromZero services without endpoints,
+// and the calls it sends them, which is how dubbod tells them idle.
+// The workload streams ActivationDemand snapshots, with its pod name as the
+// reporter, to every address Address resolves to; dubbod scopes the reporter
+// under the identity of the workload certificate. Each replica is verified
+// against ServerName, the discovery host its serving certificate names,
+// since neither the replicas Service nor the pod IPs it resolves to are.
+type inherentGRPCActivationRuntimeConfig struct {
+	Address        string `json:"address"`
+	ServerName     string `json:"serverName"`
+	Method         string `json:"method"`
+	ReportInterval string `json:"reportInterval"`
 }
 
 type inherentGRPCTelemetryRuntimeConfig struct {
@@ -413,6 +457,13 @@ type inherentGRPCCertRuntimeConfig struct {
 	CertChain  string `json:"certChain"`
 	PrivateKey string `json:"privateKey"`
 	RootCert   string `json:"rootCert"`
+	// CRL lists the certificates revoked by the mesh CA. Peers presenting
+	// one of them must be rejected.
+	CRL string `json:"crl,omitempty"`
+	// SPIFFEBundleMap maps each trust domain to its roots. When set, peers are
+	// verified against the roots of the trust domain in their SPIFFE ID rather
+	// than rootCert.
+	SPIFFEBundleMap string `json:"spiffeBundleMap,omitempty"`
 }
 
 type inherentGRPCKeepaliveRuntimeConfig struct {
@@ -433,11 +484,14 @@ type inherentGRPCWorkloadRuntimeConfig struct {
 }
 
 type inherentGRPCServiceRuntimeConfig struct {
-	Host      string                              `json:"host"`
-	Namespace string                              `json:"namespace"`
-	Name      string                              `json:"name"`
-	Ports     []inherentGRPCPortRuntimeConfig     `json:"ports,omitempty"`
-	Endpoints []inherentGRPCEndpointRuntimeConfig `json:"endpoints,omitempty"`
+	Host      string `json:"host"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// ScaleFromZero marks services an activation policy can start. Calls to
+	// them while they have no endpoints are reported as activation demand.
+	ScaleFromZero bool                                `json:"scaleFromZero,omitempty"`
+	Ports         []inherentGRPCPortRuntimeConfig     `json:"ports,omitempty"`
+	Endpoints     []inherentGRPCEndpointRuntimeConfig `json:"endpoints,omitempty"`
 }
 
 type inherentGRPCPortRuntimeConfig struct {
@@ -446,6 +500,49 @@ type inherentGRPCPortRuntimeConfig struct {
 	MTLSMode              string                                         `json:"mtlsMode,omitempty"`
 	AuthorizationPolicies []inherentGRPCAuthorizationPolicyRuntimeConfig `json:"authorizationPolicies,omitempty"`
 	Fault                 *inherentGRPCFaultRuntimeConfig                `json:"fault,omitempty"`
+	RateLimit             *inherentGRPCRateLimitRuntimeConfig            `json:"rateLimit,omitempty"`
+	ExtAuthz              *inherentGRPCExtAuthzRuntimeConfig             `json:"extAuthz,omitempty"`
+	ConnectionReport      *inherentGRPCConnectionReportRuntimeConfig     `json:"connectionReport,omitempty"`
+}
+
+// inherentGRPCConnectionReportRuntimeConfig asks the workload to count the
+// connections it accepts on a PERMISSIVE port by transport, so dubbod can
+// list the callers that still use plaintext before the port goes STRICT.
+// Callers are labelled with the workload owning their address among the
+// service endpoints of this config, never with the address itself.
+type inherentGRPCConnectionReportRuntimeConfig struct {
+	Metric string   `json:"metric"`
+	Labels []string `json:"labels"`
+}
+
+// inherentGRPCRateLimitRuntimeConfig limits the requests the workload accepts
+// on a port after authorization. Requests draw from the token bucket of their
+// Key: one shared bucket for "service", one per peer identity for "caller",
+// per Header value for "header" and per gRPC method for "method".
+type inherentGRPCRateLimitRuntimeConfig struct {
+	Policy        string `json:"policy"`
+	MaxTokens     uint32 `json:"maxTokens"`
+	TokensPerFill uint32 `json:"tokensPerFill"`
+	FillInterval  string `json:"fillInterval"`
+	Key           string `json:"key"`
+	Header        string `json:"header,omitempty"`
+}
+
+// inherentGRPCExtAuthzRuntimeConfig sends requests matching Rules to the
+// provider of the workload's CUSTOM authorization policies before the ALLOW
+// and DENY policies run.
+type inherentGRPCExtAuthzRuntimeConfig struct {
+	Provider                     string                                       `json:"provider"`
+	Service                      string                                       `json:"service"`
+	Port                         int                                          `json:"port"`
+	PathPrefix                   string                                       `json:"pathPrefix,omitempty"`
+	Timeout                      string                                       `json:"timeout"`
+	FailOpen                     bool                                         `json:"failOpen"`
+	StatusOnError                uint32                                       `json:"statusOnError"`
+	IncludeRequestHeadersInCheck []string                                     `json:"includeRequestHeadersInCheck,omitempty"`
+	HeadersToUpstreamOnAllow     []string                                     `json:"headersToUpstreamOnAllow,omitempty"`
+	HeadersToDownstreamOnDeny    []string                                     `json:"headersToDownstreamOnDeny,omitempty"`
+	Rules                        []inherentGRPCAuthorizationRuleRuntimeConfig `json:"rules"`
 }
 
 type inherentGRPCAuthorizationPolicyRuntimeConfig struct {
@@ -456,6 +553,16 @@ type inherentGRPCAuthorizationPolicyRuntimeConfig struct {
 
 type inherentGRPCAuthorizationRuleRuntimeConfig struct {
 	Sources []inherentGRPCAuthorizationSourceRuntimeConfig `json:"sources,omitempty"`
+	// When holds Dubbo RPC conditions. The runtime reads the interface and
+	// method from the gRPC path and the group and version from the
+	// tri-service-group and tri-service-version metadata.
+	When []inherentGRPCAuthorizationConditionRuntimeConfig `json:"when,omitempty"`
+}
+
+type inherentGRPCAuthorizationConditionRuntimeConfig struct {
+	Key       string   `json:"key"`
+	Values    []string `json:"values,omitempty"`
+	NotValues []string `json:"notValues,omitempty"`
 }
 
 type inherentGRPCAuthorizationSourceRuntimeConfig struct {
@@ -506,18 +613,24 @@ func (c *inherentGRPCWorkloadController) buildSecret(pod *corev1.Pod, current *c
 		return nil, time.Time{}, err
 	}
 
+	bundleMap := c.server.spiffeBundleMap(c.activeRootCert())
+	workload.hasSPIFFEBundleMap = len(bundleMap) > 0
+
 	bootstrapJSON, err := buildBootstrapJSON(workload)
 	if err != nil {
 		return nil, time.Time{}, err
 	}
 
+	crl := c.activeCRL()
+	workload.hasCRL = len(crl) > 0
+
 	services, routes := c.buildRuntimeTrafficConfig()
 	runtimeConfigJSON, err := buildRuntimeConfigJSON(workload, services, routes, c.resolveTelemetry(pod))
 	if err != nil {
 		return nil, time.Time{}, err
 	}
 
-	certChain, keyPEM, rootCert, expireAt, reusedCert := reusableWorkloadCertificate(current, c.activeRootCert())
+	certChain, keyPEM, rootCert, expireAt, reusedCert := reusableWorkloadCertificate(current, c.activeRootCert(), c.activeSigningCert(), c.activeRevocations())
 	if !reusedCert {
 		certChain, keyPEM, rootCert, expireAt, err = c.issueWorkloadCertificate(pod)
 		if err != nil {
@@ -525,7 +638,7 @@ func (c *inherentGRPCWorkloadController) buildSecret(pod *corev1.Pod, current *c
 		}
 	}
 
-	secret := buildInherentGRPCSecret(pod, bootstrapJSON, runtimeConfigJSON, certChain, keyPEM, rootCert)
+	secret := buildInherentGRPCSecret(pod, bootstrapJSON, runtimeConfigJSON, certChain, keyPEM, rootCert, crl, bundleMap)
 	return secret, expireAt, nil
 }
 
@@ -545,11 +658,12 @@ func (c *inherentGRPCWorkloadController) resolveTelemetry(pod *corev1.Pod) telem
 	)
 }
 
-func buildInherentGRPCSecret(pod *corev1.Pod, bootstrapJSON, runtimeConfigJSON, certChain, keyPEM, rootCert []byte) *corev1.Secret {
-	return &corev1.Secret{
+func buildInherentGRPCSecret(pod *corev1.Pod, bootstrapJSON, runtimeConfigJSON, certChain, keyPEM, rootCert, crl, bundleMap []byte) *corev1.Secret {
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      inject.InherentGRPCSecretNameForMeta(pod.ObjectMeta),
 			Namespace: pod.Namespace,
+			Labels:    map[string]string{inject.InherentGRPCSecretLabel: "true"},
 			OwnerReferences: []metav1.OwnerReference{{
 				APIVersion: "v1",
 				Kind:       "Pod",
@@ -566,9 +680,16 @@ func buildInherentGRPCSecret(pod *corev1.Pod, bootstrapJSON, runtimeConfigJSON,
 			constants.CACertNamespaceConfigMapDataName: rootCert,
 		},
 	}
+	if len(crl) > 0 {
+		secret.Data[constants.CACRLNamespaceConfigMapDataName] = crl
+	}
+	if len(bundleMap) > 0 {
+		secret.Data[constants.SPIFFEBundleMapFilename] = bundleMap
+	}
+	return secret
 }
 
-func reusableWorkloadCertificate(secret *corev1.Secret, activeRootCert []byte) ([]byte, []byte, []byte, time.Time, bool) {
+func reusableWorkloadCertificate(secret *corev1.Secret, activeRootCert, activeSigningCert []byte, revocations *ca.RevocationSet) ([]byte, []byte, []byte, time.Time, bool) {
 	if secret == nil || len(secret.Data) == 0 {
 		return nil, nil, nil, time.Time{}, false
 	}
@@ -581,6 +702,16 @@ func reusableWorkloadCertificate(secret *corev1.Secret, activeRootCert []byte) (
 	if len(activeRootCert) > 0 && !bytes.Equal(rootCert, activeRootCert) {
 		return nil, nil, nil, time.Time{}, false
 	}
+	// A root rotation swaps the signing key while the old root is still trusted.
+	if len(activeSigningCert) > 0 && !certIssuedBy(certChain, activeSigningCert) {
+		return nil, nil, nil, time.Time{}, false
+	}
+	if revocations != nil {
+		leaf, err := pkiutil.ParsePemEncodedCertificate(certChain)
+		if err != nil || revocations.CertificateRevoked(leaf) {
+			return nil, nil, nil, time.Time{}, false
+		}
+	}
 	expireAt, err := util.ParseCertAndGetExpiryTimestamp(certChain)
 	if err != nil {
 		return nil, nil, nil, time.Time{}, false
@@ -650,16 +781,17 @@ func (c *inherentGRPCWorkloadController) buildWorkloadContext(pod *corev1.Pod) (
 	}
 
 	return &inherentGRPCWorkloadContext{
-		node:             node,
-		nodeID:           nodeID,
-		podName:          pod.Name,
-		podNamespace:     pod.Namespace,
-		podIP:            podIP,
-		serviceAccount:   serviceAccount,
-		trustDomain:      trustDomain,
-		clusterID:        clusterID,
-		discoveryAddress: discoveryAddress,
-		caAddress:        caAddress,
+		node:              node,
+		nodeID:            nodeID,
+		podName:           pod.Name,
+		podNamespace:      pod.Namespace,
+		podIP:             podIP,
+		serviceAccount:    serviceAccount,
+		trustDomain:       trustDomain,
+		clusterID:         clusterID,
+		discoveryAddress:  discoveryAddress,
+		caAddress:         caAddress,
+		activationAddress: c.server.activationDemandAddr,
 	}, nil
 }
 
@@ -708,6 +840,7 @@ func buildBootstrapJSON(workload *inherentGRPCWorkloadContext) ([]byte, error) {
 		Node:             workload.node,
 		DiscoveryAddress: workload.discoveryAddress,
 		CertDir:          inject.InherentXDSMountPath,
+		SPIFFEBundleMap:  workload.hasSPIFFEBundleMap,
 	})
 	if err != nil {
 		return nil, err
@@ -752,11 +885,13 @@ func buildRuntimeConfigJSON(
 			Resolver:         "xds:///",
 		},
 		Certificates: inherentGRPCCertRuntimeConfig{
-			Provider:   grpcxds.FileWatcherCertProviderName,
-			Directory:  inject.InherentXDSMountPath,
-			CertChain:  inject.InherentXDSMountPath + "/" + constants.CertChainFilename,
-			PrivateKey: inject.InherentXDSMountPath + "/" + constants.KeyFilename,
-			RootCert:   inject.InherentXDSMountPath + "/" + constants.CACertNamespaceConfigMapDataName,
+			Provider:        grpcxds.FileWatcherCertProviderName,
+			Directory:       inject.InherentXDSMountPath,
+			CertChain:       inject.InherentXDSMountPath + "/" + constants.CertChainFilename,
+			PrivateKey:      inject.InherentXDSMountPath + "/" + constants.KeyFilename,
+			RootCert:        inject.InherentXDSMountPath + "/" + constants.CACertNamespaceConfigMapDataName,
+			CRL:             runtimeCRLPath(workload.hasCRL),
+			SPIFFEBundleMap: runtimeSPIFFEBundleMapPath(workload.hasSPIFFEBundleMap),
 		},
 		Keepalive: inherentGRPCKeepaliveRuntimeConfig{
 			Enabled:             true,
@@ -773,13 +908,36 @@ func buildRuntimeConfigJSON(
 			TrustDomain:    workload.trustDomain,
 			ClusterID:      workload.clusterID,
 		},
-		Telemetry: inherentGRPCTelemetryConfig(effectiveTelemetry),
-		Services:  services,
-		Routes:    routes,
+		Telemetry:  inherentGRPCTelemetryConfig(effectiveTelemetry),
+		Services:   services,
+		Routes:     routes,
+		Activation: runtimeActivation(workload, services),
 	}
 	return json.MarshalIndent(cfg, "", "  ")
 }
 
+// runtimeActivation returns where the workload reports activation demand,
+// or nil when none of the services it calls scales from zero.
+func runtimeActivation(workload *inherentGRPCWorkloadContext, services []inherentGRPCServiceRuntimeConfig) *inherentGRPCActivationRuntimeConfig {
+	if !slices.ContainsFunc(services, func(svc inherentGRPCServiceRuntimeConfig) bool { return svc.ScaleFromZero }) {
+		return nil
+	}
+	serverName, _, err := net.SplitHostPort(workload.discoveryAddress)
+	if err != nil {
+		serverName = workload.discoveryAddress
+	}
+	address := workload.activationAddress
+	if address == "" {
+		address = workload.discoveryAddress
+	}
+	return &inherentGRPCActivationRuntimeConfig{
+		Address:        address,
+		ServerName:     serverName,
+		Method:         demandpb.ActivationDemand_Report_FullMethodName,
+		ReportInterval: activation.WorkloadReportInterval.String(),
+	}
+}
+
 func inherentGRPCTelemetryConfig(effective telemetryconfig.EffectiveTracing) *inherentGRPCTelemetryRuntimeConfig {
 	if !effective.MetricsConfigured && !effective.LoggingConfigured {
 		return nil
@@ -862,20 +1020,26 @@ func (c *inherentGRPCWorkloadController) buildRuntimeTrafficConfig() ([]inherent
 
 func buildRuntimeServiceConfig(push *discoverymodel.PushContext, endpointIndex *discoverymodel.EndpointIndex, svc *discoverymodel.Service) inherentGRPCServiceRuntimeConfig {
 	cfg := inherentGRPCServiceRuntimeConfig{
-		Host:      string(svc.Hostname),
-		Namespace: svc.Attributes.Namespace,
-		Name:      svc.Attributes.Name,
+		Host:          string(svc.Hostname),
+		Namespace:     svc.Attributes.Namespace,
+		Name:          svc.Attributes.Name,
+		ScaleFromZero: push.ServiceActivationEnabled(svc.Attributes.Namespace, svc.Attributes.Name),
 	}
 	for _, port := range svc.Ports {
 		if port == nil {
 			continue
 		}
+		policies, extAuthz := runtimeWorkloadAuthorization(push, svc)
+		mtlsMode := runtimeInboundMTLSMode(push, svc.Attributes.Namespace, port.Port)
 		cfg.Ports = append(cfg.Ports, inherentGRPCPortRuntimeConfig{
 			Name:                  port.Name,
 			Port:                  port.Port,
-			MTLSMode:              runtimeInboundMTLSMode(push, svc.Attributes.Namespace, port.Port),
-			AuthorizationPolicies: runtimeWorkloadAuthorizationPolicies(push, svc),
+			MTLSMode:              mtlsMode,
+			AuthorizationPolicies: policies,
 			Fault:                 runtimeFaultInjection(push, svc.Attributes.Namespace, svc.Attributes.Name, port.Name),
+			RateLimit:             runtimeRateLimit(push, svc.Attributes.Namespace, svc.Attributes.Name, port.Name),
+			ExtAuthz:              extAuthz,
+			ConnectionReport:      runtimeConnectionReport(mtlsMode),
 		})
 		cfg.Endpoints = append(cfg.Endpoints, runtimeEndpointsForService(endpointIndex, svc, port.Port, nil)...)
 	}
@@ -883,18 +1047,79 @@ func buildRuntimeServiceConfig(push *discoverymodel.PushContext, endpointIndex *
 	return cfg
 }
 
-func runtimeWorkloadAuthorizationPolicies(
+func runtimeWorkloadAuthorization(
 	push *discoverymodel.PushContext,
 	svc *discoverymodel.Service,
-) []inherentGRPCAuthorizationPolicyRuntimeConfig {
+) ([]inherentGRPCAuthorizationPolicyRuntimeConfig, *inherentGRPCExtAuthzRuntimeConfig) {
 	if push == nil || svc == nil {
-		return nil
+		return nil, nil
 	}
 	workloadLabels := svc.Attributes.LabelSelectors
 	if len(workloadLabels) == 0 {
 		workloadLabels = svc.Attributes.Labels
 	}
-	configs := push.AuthorizationPoliciesForWorkload(svc.Attributes.Namespace, workloadLabels)
+	custom, configs := discoverymodel.SplitCustomAuthorizationPolicies(
+		push.AuthorizationPoliciesForWorkload(svc.Attributes.Namespace, workloadLabels))
+	policies := runtimeWorkloadAuthorizationPolicies(push, configs)
+	if len(custom) == 0 {
+		return policies, nil
+	}
+
+	rules := runtimeExtAuthzRules(push, custom)
+	if len(rules) == 0 {
+		return policies, nil
+	}
+	provider, err := push.ExtAuthzProviderForPolicies(custom)
+	if err != nil {
+		// Reject what the provider would have checked, as the xDS filter does.
+		log.Warnf("denying requests matched by CUSTOM authorization policies of %s: %v", svc.Hostname, err)
+		deny := inherentGRPCAuthorizationPolicyRuntimeConfig{
+			Name:   custom[0].Name,
+			Action: securityv1alpha3.AuthorizationPolicy_DENY.String(),
+			Rules:  rules,
+		}
+		return append([]inherentGRPCAuthorizationPolicyRuntimeConfig{deny}, policies...), nil
+	}
+	return policies, &inherentGRPCExtAuthzRuntimeConfig{
+		Provider:                     provider.Name,
+		Service:                      provider.Service,
+		Port:                         provider.Port,
+		PathPrefix:                   provider.PathPrefix,
+		Timeout:                      provider.CheckTimeout().String(),
+		FailOpen:                     provider.FailOpen,
+		StatusOnError:                provider.ErrorStatus(),
+		IncludeRequestHeadersInCheck: provider.IncludeRequestHeadersInCheck,
+		HeadersToUpstreamOnAllow:     provider.HeadersToUpstreamOnAllow,
+		HeadersToDownstreamOnDeny:    provider.HeadersToDownstreamOnDeny,
+		Rules:                        rules,
+	}
+}
+
+// runtimeExtAuthzRules projects the rules of CUSTOM policies. A rule the
+// runtime cannot evaluate natively widens to every request: checking too much
+// is safe, skipping the check is not.
+func runtimeExtAuthzRules(push *discoverymodel.PushContext, custom []config.Config) []inherentGRPCAuthorizationRuleRuntimeConfig {
+	var rules []inherentGRPCAuthorizationRuleRuntimeConfig
+	for _, cfg := range custom {
+		spec, ok := cfg.Spec.(*securityv1alpha3.AuthorizationPolicy)
+		if !ok || spec == nil {
+			continue
+		}
+		for _, rule := range spec.GetRules() {
+			projected, ok := runtimeWorkloadAuthorizationRule(push, rule)
+			if !ok {
+				return []inherentGRPCAuthorizationRuleRuntimeConfig{{}}
+			}
+			rules = append(rules, projected)
+		}
+	}
+	return rules
+}
+
+func runtimeWorkloadAuthorizationPolicies(
+	push *discoverymodel.PushContext,
+	configs []config.Config,
+) []inherentGRPCAuthorizationPolicyRuntimeConfig {
 	out := make([]inherentGRPCAuthorizationPolicyRuntimeConfig, 0, len(configs))
 	for _, cfg := range configs {
 		spec, ok := cfg.Spec.(*securityv1alpha3.AuthorizationPolicy)
@@ -906,7 +1131,7 @@ func runtimeWorkloadAuthorizationPolicies(
 			Action: spec.GetAction().String(),
 		}
 		for _, rule := range spec.GetRules() {
-			projected, ok := runtimeWorkloadAuthorizationRule(rule)
+			projected, ok := runtimeWorkloadAuthorizationRule(push, rule)
 			if ok {
 				policy.Rules = append(policy.Rules, projected)
 			}
@@ -922,15 +1147,26 @@ func runtimeWorkloadAuthorizationPolicies(
 }
 
 func runtimeWorkloadAuthorizationRule(
+	push *discoverymodel.PushContext,
 	rule *securityv1alpha3.Rule,
 ) (inherentGRPCAuthorizationRuleRuntimeConfig, bool) {
 	if rule == nil {
 		return inherentGRPCAuthorizationRuleRuntimeConfig{}, true
 	}
-	if len(rule.GetWhen()) > 0 {
-		return inherentGRPCAuthorizationRuleRuntimeConfig{}, false
-	}
 	projected := inherentGRPCAuthorizationRuleRuntimeConfig{}
+	for _, condition := range rule.GetWhen() {
+		if condition == nil {
+			continue
+		}
+		if !securityconfig.IsDubboRPCKey(condition.GetKey()) {
+			return inherentGRPCAuthorizationRuleRuntimeConfig{}, false
+		}
+		projected.When = append(projected.When, inherentGRPCAuthorizationConditionRuntimeConfig{
+			Key:       condition.GetKey(),
+			Values:    append([]string(nil), condition.GetValues()...),
+			NotValues: append([]string(nil), condition.GetNotValues()...),
+		})
+	}
 	for _, from := range rule.GetFrom() {
 		source := from.GetSource()
 		if source == nil {
@@ -941,7 +1177,7 @@ func runtimeWorkloadAuthorizationRule(
 			return inherentGRPCAuthorizationRuleRuntimeConfig{}, false
 		}
 		projected.Sources = append(projected.Sources, inherentGRPCAuthorizationSourceRuntimeConfig{
-			Principals: append([]string(nil), source.GetPrincipals()...),
+			Principals: append([]string(nil), push.ExpandPrincipals(source.GetPrincipals())...),
 		})
 	}
 	return projected, true
@@ -971,6 +1207,21 @@ func runtimeFaultInjection(push *discoverymodel.PushContext, namespace, name, po
 	return fault
 }
 
+func runtimeRateLimit(push *discoverymodel.PushContext, namespace, name, portName string) *inherentGRPCRateLimitRuntimeConfig {
+	settings, found := push.RateLimitForService(namespace, name, portName)
+	if !found {
+		return nil
+	}
+	return &inherentGRPCRateLimitRuntimeConfig{
+		Policy:        settings.Namespace + "/" + settings.Name,
+		MaxTokens:     settings.MaxTokens,
+		TokensPerFill: settings.TokensPerFill,
+		FillInterval:  settings.FillInterval.String(),
+		Key:           string(settings.Key),
+		Header:        settings.Header,
+	}
+}
+
 func buildRuntimeRouteConfig(_ *discoverymodel.PushContext, endpointIndex *discoverymodel.EndpointIndex, svc *discoverymodel.Service, port int) inherentGRPCRouteRuntimeConfig {
 	return inherentGRPCRouteRuntimeConfig{
 		Host: string(svc.Hostname),
@@ -990,6 +1241,16 @@ func runtimeInboundMTLSMode(push *discoverymodel.PushContext, namespace string,
 	return runtimeMutualTLSModeString(push.AuthenticationPolicies.EffectiveMutualTLSMode(namespace, nil, uint32(port)))
 }
 
+func runtimeConnectionReport(mtlsMode string) *inherentGRPCConnectionReportRuntimeConfig {
+	if mtlsMode != "PERMISSIVE" {
+		return nil
+	}
+	return &inherentGRPCConnectionReportRuntimeConfig{
+		Metric: securityconfig.InboundConnectionsMetric,
+		Labels: append([]string(nil), securityconfig.InboundConnectionLabels...),
+	}
+}
+
 func runtimeMutualTLSModeString(mode discoverymodel.MutualTLSMode) string {
 	switch mode {
 	case discoverymodel.MTLSDisable:
@@ -1133,6 +1394,7 @@ func (c *inherentGRPCWorkloadController) issueWorkloadCertificate(pod *corev1.Po
 		SubjectIDs: []string{identity.String()},
 		TTL:        workloadCertTTL.Get(),
 		ForCA:      false,
+		CertSigner: c.server.certSignerForNamespace(pod.Namespace),
 	})
 	if err != nil {
 		return nil, nil, nil, time.Time{}, err
@@ -1144,7 +1406,7 @@ func (c *inherentGRPCWorkloadController) issueWorkloadCertificate(pod *corev1.Po
 		return nil, nil, nil, time.Time{}, err
 	}
 
-	rootCert := authority.GetCAKeyCertBundle().GetRootCertPem()
+	rootCert := c.activeRootCert()
 	if len(rootCert) == 0 && len(respCertChain) > 0 {
 		rootCert = []byte(respCertChain[len(respCertChain)-1])
 	}
@@ -1159,7 +1421,13 @@ func (c *inherentGRPCWorkloadController) activeAuthority() caserver.CertificateA
 	return c.server.CA
 }
 
+// activeRootCert is the mesh trust bundle written to workload secrets. The
+// roots of federated trust domains travel separately in the SPIFFE bundle map.
 func (c *inherentGRPCWorkloadController) activeRootCert() []byte {
+	if c.server.RA != nil {
+		// Workloads in other namespaces may chain to a different signer's root.
+		return c.server.RA.GetTrustBundle()
+	}
 	authority := c.activeAuthority()
 	if authority == nil || authority.GetCAKeyCertBundle() == nil {
 		return nil
@@ -1167,6 +1435,80 @@ func (c *inherentGRPCWorkloadController) activeRootCert() []byte {
 	return authority.GetCAKeyCertBundle().GetRootCertPem()
 }
 
+// activeSigningCert is the self-signed CA certificate leaves are issued from.
+// External signers behind the RA are not tracked.
+func (c *inherentGRPCWorkloadController) activeSigningCert() []byte {
+	if c.server.RA != nil || c.server.CA == nil || c.server.CA.GetCAKeyCertBundle() == nil {
+		return nil
+	}
+	cert, _, _, _ := c.server.CA.GetCAKeyCertBundle().GetAllPem()
+	return cert
+}
+
+// activeRevocations are the revocations enforced by the dubbod CA. Certificates
+// signed through the RA are not tracked.
+func (c *inherentGRPCWorkloadController) activeRevocations() *ca.RevocationSet {
+	if c.server.RA != nil || c.server.CA == nil {
+		return nil
+	}
+	return c.server.CA.Revocations()
+}
+
+// activeCRL is the CA revocation list written to workload secrets.
+func (c *inherentGRPCWorkloadController) activeCRL() []byte {
+	if !features.EnableCACRL || c.server.dubbodCertBundleWatcher == nil {
+		return nil
+	}
+	return c.server.dubbodCertBundleWatcher.GetCRL()
+}
+
+func runtimeCRLPath(hasCRL bool) string {
+	if !hasCRL {
+		return ""
+	}
+	return inject.InherentXDSMountPath + "/" + constants.CACRLNamespaceConfigMapDataName
+}
+
+func runtimeSPIFFEBundleMapPath(hasBundleMap bool) string {
+	if !hasBundleMap {
+		return ""
+	}
+	return inject.InherentXDSMountPath + "/" + constants.SPIFFEBundleMapFilename
+}
+
+func certIssuedBy(certChain, issuerPEM []byte) bool {
+	leaf, err := pkiutil.ParsePemEncodedCertificate(certChain)
+	if err != nil {
+		return false
+	}
+	issuer, err := pkiutil.ParsePemEncodedCertificate(issuerPEM)
+	if err != nil {
+		return false
+	}
+	return leaf.CheckSignatureFrom(issuer) == nil
+}
+
+// rootRotationPending lists managed pods whose secret does not carry
+// trustBundle yet or, when signingCert is set, a leaf issued by it.
+func (c *inherentGRPCWorkloadController) rootRotationPending(trustBundle, signingCert []byte) ([]string, error) {
+	if !c.secrets.HasSynced() {
+		return nil, fmt.Errorf("inherent gRPC workload Secrets are not synced yet")
+	}
+	var pending []string
+	for _, key := range c.managedPodKeys() {
+		pod := c.pods.Get(key.Name, key.Namespace)
+		if pod == nil {
+			continue
+		}
+		secret := c.secrets.Get(inject.InherentGRPCSecretNameForMeta(pod.ObjectMeta), pod.Namespace)
+		if secret == nil || !bytes.Equal(secret.Data[constants.CACertNamespaceConfigMapDataName], trustBundle) ||
+			(len(signingCert) > 0 && !certIssuedBy(secret.Data[constants.CertChainFilename], signingCert)) {
+			pending = append(pending, key.String())
+		}
+	}
+	return pending, nil
+}
+
 func concatPEM(certs []string) []byte {
 	if len(certs) == 0 {
 		return nil
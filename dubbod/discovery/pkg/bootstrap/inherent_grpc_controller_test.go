@@ -22,6 +22,8 @@ import (
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/config/memory"
 	discoverymodel "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/ca"
+	pkiutil "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
 	"github.com/apache/dubbo-kubernetes/pkg/config"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
 	"github.com/apache/dubbo-kubernetes/pkg/config/host"
@@ -31,6 +33,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/collections"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/kind"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	telemetryconfig "github.com/apache/dubbo-kubernetes/pkg/config/telemetry"
 	"github.com/apache/dubbo-kubernetes/pkg/grpcxds"
 	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
@@ -120,7 +123,7 @@ func TestBuildInherentGRPCSecretIncludesRuntimeConfig(t *testing.T) {
 		UID:       "pod-uid",
 	}}
 
-	secret := buildInherentGRPCSecret(pod, []byte("bootstrap"), []byte("runtime"), []byte("cert"), []byte("key"), []byte("root"))
+	secret := buildInherentGRPCSecret(pod, []byte("bootstrap"), []byte("runtime"), []byte("cert"), []byte("key"), []byte("root"), []byte("crl"), nil)
 	if secret.Name != inject.InherentGRPCSecretNameForMeta(pod.ObjectMeta) {
 		t.Fatalf("secret name = %q, want inherent secret name", secret.Name)
 	}
@@ -133,11 +136,48 @@ func TestBuildInherentGRPCSecretIncludesRuntimeConfig(t *testing.T) {
 	if got := string(secret.Data[constants.CACertNamespaceConfigMapDataName]); got != "root" {
 		t.Fatalf("root cert data = %q, want root", got)
 	}
+	if got := string(secret.Data[constants.CACRLNamespaceConfigMapDataName]); got != "crl" {
+		t.Fatalf("crl data = %q, want crl", got)
+	}
 	if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].UID != pod.UID {
 		t.Fatalf("owner references = %+v, want pod owner", secret.OwnerReferences)
 	}
 }
 
+func TestReusableWorkloadCertificateReplacesRevokedCertificate(t *testing.T) {
+	identity := "spiffe://cluster.local/ns/app/sa/nginx"
+	certPEM, keyPEM, err := pkiutil.GenCertKeyFromOptions(pkiutil.CertOptions{
+		Host:         identity,
+		TTL:          workloadCertTTL.Get(),
+		IsSelfSigned: true,
+		RSAKeySize:   2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := pkiutil.ParsePemEncodedCertificate(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{Data: map[string][]byte{
+		constants.CertChainFilename:                certPEM,
+		constants.KeyFilename:                      keyPEM,
+		constants.CACertNamespaceConfigMapDataName: certPEM,
+	}}
+
+	if _, _, _, _, reused := reusableWorkloadCertificate(secret, nil, nil, ca.NewRevocationSet(nil)); !reused {
+		t.Fatal("valid certificate was not reused")
+	}
+	bySerial := ca.NewRevocationSet([]ca.Revocation{{SerialNumber: ca.FormatSerialNumber(leaf.SerialNumber)}})
+	if _, _, _, _, reused := reusableWorkloadCertificate(secret, nil, nil, bySerial); reused {
+		t.Fatal("certificate revoked by serial number was reused")
+	}
+	byIdentity := ca.NewRevocationSet([]ca.Revocation{{Identity: identity}})
+	if _, _, _, _, reused := reusableWorkloadCertificate(secret, nil, nil, byIdentity); reused {
+		t.Fatal("certificate of a revoked identity was reused")
+	}
+}
+
 func TestBuildRuntimeConfigJSON(t *testing.T) {
 	workload := &inherentGRPCWorkloadContext{
 		nodeID:           "inherent~10.0.0.1~nginx.app~app.svc.cluster.local",
@@ -149,6 +189,7 @@ func TestBuildRuntimeConfigJSON(t *testing.T) {
 		clusterID:        "remote",
 		discoveryAddress: "192.168.15.164:32049",
 		caAddress:        "192.168.15.164:32049",
+		hasCRL:           true,
 	}
 
 	effectiveTelemetry := telemetryconfig.EffectiveTracing{
@@ -234,6 +275,9 @@ func TestBuildRuntimeConfigJSON(t *testing.T) {
 	if got.Certificates.CertChain != inject.InherentXDSMountPath+"/"+constants.CertChainFilename {
 		t.Fatalf("certChain = %q, want mounted cert-chain path", got.Certificates.CertChain)
 	}
+	if got.Certificates.CRL != inject.InherentXDSMountPath+"/"+constants.CACRLNamespaceConfigMapDataName {
+		t.Fatalf("crl = %q, want mounted CRL path", got.Certificates.CRL)
+	}
 	if got.Workload.NodeID != workload.nodeID {
 		t.Fatalf("nodeId = %q, want %q", got.Workload.NodeID, workload.nodeID)
 	}
@@ -402,6 +446,31 @@ func TestBuildRuntimeTrafficConfigCapturesInherentSecurity(t *testing.T) {
 	}
 }
 
+func TestRuntimeWorkloadAuthorizationRuleProjectsDubboRPCConditions(t *testing.T) {
+	rule := &security.Rule{
+		From: []*security.From{{Source: &security.Source{Principals: []string{"cluster.local/ns/client/sa/caller"}}}},
+		When: []*security.Condition{
+			{Key: securityconfig.DubboInterfaceKey, Values: []string{"org.apache.dubbo.Greeter"}},
+			{Key: securityconfig.DubboMethodKey, NotValues: []string{"delete"}},
+		},
+	}
+	projected, ok := runtimeWorkloadAuthorizationRule(nil, rule)
+	if !ok {
+		t.Fatal("rule with Dubbo RPC conditions was not projected")
+	}
+	if len(projected.Sources) != 1 || len(projected.When) != 2 {
+		t.Fatalf("projected = %+v, want one source and two conditions", projected)
+	}
+	if got := projected.When[1]; got.Key != securityconfig.DubboMethodKey || len(got.NotValues) != 1 || got.NotValues[0] != "delete" {
+		t.Fatalf("method condition = %+v, want notValues [delete]", got)
+	}
+
+	rule.When = append(rule.When, &security.Condition{Key: "request.auth.claims[iss]", Values: []string{"issuer"}})
+	if _, ok := runtimeWorkloadAuthorizationRule(nil, rule); ok {
+		t.Fatal("rule with a JWT claim condition was projected")
+	}
+}
+
 func TestBuildRuntimeTrafficConfigProjectsOnlyWorkloadPrincipalAuthorization(t *testing.T) {
 	svc := newInherentRuntimeTestService("provider", "grpc-app", "provider.grpc-app.svc.cluster.local", 17070)
 	workloadPolicy := config.Config{
@@ -443,6 +512,36 @@ func TestBuildRuntimeTrafficConfigProjectsOnlyWorkloadPrincipalAuthorization(t *
 	}
 }
 
+func TestBuildRuntimeTrafficConfigDeniesCustomAuthorizationWithoutProvider(t *testing.T) {
+	svc := newInherentRuntimeTestService("provider", "grpc-app", "provider.grpc-app.svc.cluster.local", 17070)
+	customPolicy := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.AuthorizationPolicy,
+			Name:             "opa-check",
+			Namespace:        "grpc-app",
+		},
+		Spec: &security.AuthorizationPolicy{
+			Action: security.AuthorizationPolicy_CUSTOM,
+			ActionDetail: &security.AuthorizationPolicy_Provider{
+				Provider: &security.AuthorizationPolicy_ExtensionProvider{Name: "opa"},
+			},
+			Rules: []*security.Rule{{From: []*security.From{{Source: &security.Source{
+				Principals: []string{"cluster.local/ns/client/sa/caller"},
+			}}}}},
+		},
+	}
+	push := newInherentRuntimeTestPushContext(t, []config.Config{customPolicy}, []*discoverymodel.Service{svc})
+
+	port := buildRuntimeServiceConfig(push, nil, svc).Ports[0]
+	if port.ExtAuthz != nil {
+		t.Fatalf("extAuthz = %+v, want nil for an undeclared provider", port.ExtAuthz)
+	}
+	got := port.AuthorizationPolicies
+	if len(got) != 1 || got[0].Name != "opa-check" || got[0].Action != "DENY" {
+		t.Fatalf("authorization policies = %+v, want opa-check turned into DENY", got)
+	}
+}
+
 func TestBuildRuntimeTrafficConfigCapturesPermissivePeerAuthentication(t *testing.T) {
 	hostname := host.Name("provider.grpc-app.svc.cluster.local")
 	svc := newInherentRuntimeTestService("provider", "grpc-app", string(hostname), 17070)
@@ -457,6 +556,10 @@ func TestBuildRuntimeTrafficConfigCapturesPermissivePeerAuthentication(t *testin
 	if got := serviceConfig.Ports[0].MTLSMode; got != "PERMISSIVE" {
 		t.Fatalf("mtlsMode = %q, want PERMISSIVE", got)
 	}
+	report := serviceConfig.Ports[0].ConnectionReport
+	if report == nil || report.Metric != securityconfig.InboundConnectionsMetric {
+		t.Fatalf("connectionReport = %+v, want inbound connection counters", report)
+	}
 }
 
 func TestBuildRuntimeTrafficConfigCapturesFaultInjection(t *testing.T) {
@@ -498,6 +601,64 @@ func TestBuildRuntimeTrafficConfigCapturesFaultInjection(t *testing.T) {
 	}
 }
 
+func TestBuildRuntimeTrafficConfigCapturesRateLimit(t *testing.T) {
+	hostname := host.Name("provider.grpc-app.svc.cluster.local")
+	svc := newInherentRuntimeTestService("provider", "grpc-app", string(hostname), 17070)
+	push := newInherentRuntimeTestPushContext(t, []config.Config{{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.RateLimitPolicy,
+			Name:             "provider-limit",
+			Namespace:        "grpc-app",
+		},
+		Spec: &networking.RateLimitPolicy{
+			TargetRefs: []*networking.PolicyTargetReference{{
+				Kind: "Service",
+				Name: "provider",
+			}},
+			TokenBucket: &networking.TokenBucket{
+				MaxTokens:    50,
+				FillInterval: durationpb.New(time.Second),
+			},
+			Key: networking.RateLimitPolicy_CALLER,
+		},
+	}}, []*discoverymodel.Service{svc})
+
+	serviceConfig := buildRuntimeServiceConfig(push, nil, svc)
+	if len(serviceConfig.Ports) != 1 || serviceConfig.Ports[0].RateLimit == nil {
+		t.Fatalf("ports = %+v, want one port with a rate limit", serviceConfig.Ports)
+	}
+	want := inherentGRPCRateLimitRuntimeConfig{
+		Policy: "grpc-app/provider-limit", MaxTokens: 50, TokensPerFill: 50, FillInterval: "1s", Key: "caller",
+	}
+	if got := *serviceConfig.Ports[0].RateLimit; got != want {
+		t.Fatalf("rateLimit = %+v, want %+v", got, want)
+	}
+}
+
+func TestRuntimeActivationTargetsEveryDiscoveryReplica(t *testing.T) {
+	workload := &inherentGRPCWorkloadContext{
+		discoveryAddress:  "dubbod.dubbo-system.svc:26012",
+		activationAddress: "dubbod-activation-replicas.dubbo-system.svc:26012",
+	}
+	if got := runtimeActivation(workload, []inherentGRPCServiceRuntimeConfig{{}}); got != nil {
+		t.Fatalf("activation = %+v, want nil without scale-from-zero services", got)
+	}
+	got := runtimeActivation(workload, []inherentGRPCServiceRuntimeConfig{{}, {ScaleFromZero: true}})
+	if got == nil || got.Address != "dubbod-activation-replicas.dubbo-system.svc:26012" || got.ReportInterval != "5s" {
+		t.Fatalf("activation = %+v, want reports to the replicas Service every 5s", got)
+	}
+	// The replicas are verified as the discovery host their certificate names.
+	if got.ServerName != "dubbod.dubbo-system.svc" {
+		t.Fatalf("serverName = %q, want the discovery host", got.ServerName)
+	}
+
+	workload.activationAddress = ""
+	got = runtimeActivation(workload, []inherentGRPCServiceRuntimeConfig{{ScaleFromZero: true}})
+	if got == nil || got.Address != workload.discoveryAddress || got.ServerName != "dubbod.dubbo-system.svc" {
+		t.Fatalf("activation = %+v, want reports to the discovery address without a replicas address", got)
+	}
+}
+
 func TestInherentGRPCRuntimeConfigNeedsUpdate(t *testing.T) {
 	tests := []struct {
 		name string
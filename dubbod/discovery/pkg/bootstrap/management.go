@@ -793,20 +793,13 @@ func managementContainerEnv(container corev1.Container, name string) string {
 // managementActiveRootCert mirrors inherentGRPCWorkloadController.activeRootCert so
 // the console compares workload secrets against the same root the issuer uses.
 func (s *Server) managementActiveRootCert() []byte {
-	authority := s.RA
-	if authority == nil {
-		if s.CA == nil {
-			return nil
-		}
-		if s.CA.GetCAKeyCertBundle() == nil {
-			return nil
-		}
-		return s.CA.GetCAKeyCertBundle().GetRootCertPem()
+	if s.RA != nil {
+		return s.RA.GetTrustBundle()
 	}
-	if authority.GetCAKeyCertBundle() == nil {
+	if s.CA == nil || s.CA.GetCAKeyCertBundle() == nil {
 		return nil
 	}
-	return authority.GetCAKeyCertBundle().GetRootCertPem()
+	return s.CA.GetCAKeyCertBundle().GetRootCertPem()
 }
 
 // managementWorkloadSecretState reads the per-workload secret dubbod generates.
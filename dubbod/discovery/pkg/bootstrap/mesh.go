@@ -73,7 +73,7 @@ func (s *Server) getConfigurationSources(args *DubboArgs, fileWatcher filewatche
 		return nil
 	}
 
-	configMapName := getMeshConfigMapName("")
+	configMapName := getMeshConfigMapName(args.Revision)
 	primary := kubemesh.NewConfigMapSource(s.kubeClient, args.Namespace, configMapName, cmKey, opts)
 	return toSources(primary, userMeshConfig)
 }
@@ -0,0 +1,274 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	discoverymodel "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
+	"github.com/apache/dubbo-kubernetes/pkg/log"
+)
+
+const (
+	// mtlsReadinessPath serves the plaintext and mTLS callers of every
+	// PERMISSIVE service port; `dubboctl security mtls-readiness` reads it.
+	mtlsReadinessPath = "/debug/mtlsz"
+
+	mtlsReadinessScrapeTimeout     = 2 * time.Second
+	mtlsReadinessScrapeConcurrency = 16
+	// mtlsReadinessCacheTTL is how long a report is served before the
+	// workloads are scraped again. The counters only grow, so a report this
+	// old still names every caller seen up to GeneratedAt.
+	mtlsReadinessCacheTTL = 30 * time.Second
+)
+
+// mtlsReadinessCache holds the last report. mu is held while a report is
+// built, so concurrent requests wait for one scrape instead of starting
+// their own.
+type mtlsReadinessCache struct {
+	mu      sync.Mutex
+	report  securityconfig.MTLSReadinessReport
+	expires time.Time
+}
+
+// mtlsReadinessTarget is one PERMISSIVE service port and the proxyless pods
+// serving it.
+type mtlsReadinessTarget struct {
+	readiness securityconfig.ServiceMTLSReadiness
+	pods      []types.NamespacedName
+}
+
+func (s *Server) mtlsReadinessHandler(writer http.ResponseWriter, _ *http.Request) {
+	report := s.cachedMTLSReadinessReport()
+	writer.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(writer)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		http.Error(writer, fmt.Sprintf("marshal failure: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// cachedMTLSReadinessReport returns the cached report, building a new one
+// once it expired. The scrape is not bound to the request: a client going
+// away must not leave a report of unreachable pods in the cache.
+func (s *Server) cachedMTLSReadinessReport() securityconfig.MTLSReadinessReport {
+	cache := &s.mtlsReadiness
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if time.Now().Before(cache.expires) {
+		return cache.report
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 4*mtlsReadinessScrapeTimeout)
+	defer cancel()
+	cache.report = s.buildMTLSReadinessReport(ctx)
+	cache.expires = time.Now().Add(mtlsReadinessCacheTTL)
+	return cache.report
+}
+
+// buildMTLSReadinessReport reads the inbound connection counters of every
+// proxyless pod behind a PERMISSIVE port of this cluster.
+func (s *Server) buildMTLSReadinessReport(ctx context.Context) securityconfig.MTLSReadinessReport {
+	report := securityconfig.MTLSReadinessReport{GeneratedAt: time.Now(), Services: []securityconfig.ServiceMTLSReadiness{}}
+	if s.environment == nil || s.inherentGRPCWorkloadController == nil || s.inherentGRPCWorkloadController.pods == nil {
+		return report
+	}
+	push := s.environment.PushContext()
+	if push == nil || !push.InitDone.Load() {
+		return report
+	}
+	pods := s.inherentGRPCWorkloadController.pods.List(metav1.NamespaceAll, klabels.Everything())
+	targets := mtlsReadinessTargets(push, pods)
+	if len(targets) == 0 {
+		return report
+	}
+
+	podsByKey := make(map[types.NamespacedName]*corev1.Pod, len(pods))
+	for _, pod := range pods {
+		podsByKey[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = pod
+	}
+	wanted := map[types.NamespacedName]struct{}{}
+	for _, target := range targets {
+		for _, key := range target.pods {
+			wanted[key] = struct{}{}
+		}
+	}
+
+	client := &http.Client{Timeout: mtlsReadinessScrapeTimeout}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	scraped := make(map[types.NamespacedName][]securityconfig.InboundConnection, len(wanted))
+	limit := make(chan struct{}, mtlsReadinessScrapeConcurrency)
+	for key := range wanted {
+		pod := podsByKey[key]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			conns, err := scrapeInboundConnections(ctx, client, pod)
+			if err != nil {
+				log.Debugf("mTLS readiness: failed to read connection counters of %s: %v", key, err)
+				return
+			}
+			mu.Lock()
+			scraped[key] = conns
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report.Services = aggregateMTLSReadiness(targets, scraped)
+	return report
+}
+
+// mtlsReadinessTargets lists the PERMISSIVE service ports with the running
+// proxyless pods their selector picks.
+func mtlsReadinessTargets(push *discoverymodel.PushContext, pods []*corev1.Pod) []*mtlsReadinessTarget {
+	services := push.GetAllServices()
+	sort.Slice(services, func(i, j int) bool {
+		return string(services[i].Hostname) < string(services[j].Hostname)
+	})
+	targets := []*mtlsReadinessTarget{}
+	for _, svc := range services {
+		if svc == nil || len(svc.Attributes.LabelSelectors) == 0 {
+			continue
+		}
+		selector := klabels.SelectorFromSet(svc.Attributes.LabelSelectors)
+		var backing []types.NamespacedName
+		for _, pod := range pods {
+			if pod.Namespace != svc.Attributes.Namespace || pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			if pod.Status.PodIP == "" || !shouldManageInherentGRPCPod(pod) || !selector.Matches(klabels.Set(pod.Labels)) {
+				continue
+			}
+			backing = append(backing, types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name})
+		}
+		sort.Slice(backing, func(i, j int) bool { return backing[i].String() < backing[j].String() })
+		for _, port := range svc.Ports {
+			if port == nil {
+				continue
+			}
+			mode := runtimeInboundMTLSMode(push, svc.Attributes.Namespace, port.Port)
+			if mode != "PERMISSIVE" {
+				continue
+			}
+			targets = append(targets, &mtlsReadinessTarget{
+				readiness: securityconfig.ServiceMTLSReadiness{
+					Host:      string(svc.Hostname),
+					Namespace: svc.Attributes.Namespace,
+					Name:      svc.Attributes.Name,
+					Port:      port.Port,
+					Mode:      mode,
+				},
+				pods: backing,
+			})
+		}
+	}
+	return targets
+}
+
+func scrapeInboundConnections(ctx context.Context, client *http.Client, pod *corev1.Pod) ([]securityconfig.InboundConnection, error) {
+	url := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(inject.InherentGRPCMetricsPort)) + "/metrics"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return securityconfig.ParseInboundConnections(resp.Body)
+}
+
+type mtlsReadinessCallerKey struct {
+	workload  string
+	principal string
+}
+
+// aggregateMTLSReadiness sums the counters the pods of each target reported
+// for its port. A pod that could not be read keeps the port from counting as
+// ready: its plaintext callers are unknown.
+func aggregateMTLSReadiness(
+	targets []*mtlsReadinessTarget,
+	scraped map[types.NamespacedName][]securityconfig.InboundConnection,
+) []securityconfig.ServiceMTLSReadiness {
+	out := make([]securityconfig.ServiceMTLSReadiness, 0, len(targets))
+	for _, target := range targets {
+		readiness := target.readiness
+		plaintext := map[mtlsReadinessCallerKey]float64{}
+		mtls := map[mtlsReadinessCallerKey]float64{}
+		for _, key := range target.pods {
+			conns, ok := scraped[key]
+			if !ok {
+				readiness.UnreachablePods = append(readiness.UnreachablePods, key.String())
+				continue
+			}
+			readiness.ReportingPods++
+			for _, conn := range conns {
+				if conn.Service != readiness.Host || conn.Port != readiness.Port || conn.Count <= 0 {
+					continue
+				}
+				switch conn.Transport {
+				case securityconfig.TransportPlaintext:
+					plaintext[mtlsReadinessCallerKey{workload: conn.SourceWorkload}] += conn.Count
+				case securityconfig.TransportMTLS:
+					mtls[mtlsReadinessCallerKey{workload: conn.SourceWorkload, principal: conn.SourcePrincipal}] += conn.Count
+				}
+			}
+		}
+		readiness.PlaintextCallers = mtlsReadinessCallers(plaintext)
+		readiness.MTLSCallers = mtlsReadinessCallers(mtls)
+		out = append(out, readiness)
+	}
+	return out
+}
+
+func mtlsReadinessCallers(counts map[mtlsReadinessCallerKey]float64) []securityconfig.MTLSReadinessCaller {
+	if len(counts) == 0 {
+		return nil
+	}
+	callers := make([]securityconfig.MTLSReadinessCaller, 0, len(counts))
+	for key, count := range counts {
+		callers = append(callers, securityconfig.MTLSReadinessCaller{
+			Workload:    key.workload,
+			Principal:   key.principal,
+			Connections: count,
+		})
+	}
+	securityconfig.SortCallers(callers)
+	return callers
+}
@@ -0,0 +1,127 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"testing"
+	"time"
+
+	discoverymodel "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
+	security "github.com/kdubbo/api/security/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMTLSReadinessTargetsSelectPermissivePortsAndProxylessPods(t *testing.T) {
+	svc := newInherentRuntimeTestService("provider", "grpc-app", "provider.grpc-app.svc.cluster.local", 17070)
+	svc.Attributes.LabelSelectors = map[string]string{"app": "provider"}
+	push := newInherentRuntimeTestPushContext(t, []config.Config{
+		newInherentPeerAuthenticationConfig("grpc-app-permissive-mtls", "grpc-app", security.PeerAuthentication_MutualTLS_PERMISSIVE),
+	}, []*discoverymodel.Service{svc})
+
+	pods := []*corev1.Pod{
+		newMTLSReadinessTestPod("provider-1", "grpc-app", "10.0.0.10", map[string]string{"app": "provider"}),
+		newMTLSReadinessTestPod("consumer-1", "grpc-app", "10.0.0.20", map[string]string{"app": "consumer"}),
+	}
+	targets := mtlsReadinessTargets(push, pods)
+	if len(targets) != 1 {
+		t.Fatalf("targets = %d, want 1", len(targets))
+	}
+	if got := targets[0].readiness; got.Port != 17070 || got.Mode != "PERMISSIVE" {
+		t.Fatalf("target = %+v, want PERMISSIVE port 17070", got)
+	}
+	if len(targets[0].pods) != 1 || targets[0].pods[0].Name != "provider-1" {
+		t.Fatalf("pods = %v, want provider-1", targets[0].pods)
+	}
+
+	strictPush := newInherentRuntimeTestPushContext(t, []config.Config{
+		newInherentStrictPeerAuthenticationConfig("grpc-app-strict-mtls", "grpc-app"),
+	}, []*discoverymodel.Service{svc})
+	if targets := mtlsReadinessTargets(strictPush, pods); len(targets) != 0 {
+		t.Fatalf("targets = %d, want none for STRICT", len(targets))
+	}
+}
+
+func TestAggregateMTLSReadinessListsPlaintextCallers(t *testing.T) {
+	const hostname = "provider.grpc-app.svc.cluster.local"
+	provider1 := types.NamespacedName{Namespace: "grpc-app", Name: "provider-1"}
+	provider2 := types.NamespacedName{Namespace: "grpc-app", Name: "provider-2"}
+	provider3 := types.NamespacedName{Namespace: "grpc-app", Name: "provider-3"}
+	targets := []*mtlsReadinessTarget{{
+		readiness: securityconfig.ServiceMTLSReadiness{Host: hostname, Namespace: "grpc-app", Name: "provider", Port: 17070, Mode: "PERMISSIVE"},
+		pods:      []types.NamespacedName{provider1, provider2, provider3},
+	}}
+	scraped := map[types.NamespacedName][]securityconfig.InboundConnection{
+		provider1: {
+			{Service: hostname, Port: 17070, Transport: securityconfig.TransportPlaintext, SourceWorkload: "grpc-app/consumer", Count: 2},
+			{Service: hostname, Port: 17070, Transport: securityconfig.TransportMTLS, SourceWorkload: "grpc-app/web", SourcePrincipal: "spiffe://cluster.local/ns/grpc-app/sa/web", Count: 4},
+			{Service: hostname, Port: 18080, Transport: securityconfig.TransportPlaintext, SourceWorkload: securityconfig.UnknownSourceWorkload, Count: 9},
+		},
+		provider2: {
+			{Service: hostname, Port: 17070, Transport: securityconfig.TransportPlaintext, SourceWorkload: "grpc-app/consumer", Count: 3},
+		},
+	}
+
+	got := aggregateMTLSReadiness(targets, scraped)
+	if len(got) != 1 {
+		t.Fatalf("services = %d, want 1", len(got))
+	}
+	readiness := got[0]
+	if readiness.ReportingPods != 2 || len(readiness.UnreachablePods) != 1 || readiness.UnreachablePods[0] != provider3.String() {
+		t.Fatalf("reporting = %d, unreachable = %v", readiness.ReportingPods, readiness.UnreachablePods)
+	}
+	if len(readiness.PlaintextCallers) != 1 {
+		t.Fatalf("plaintext callers = %+v, want one", readiness.PlaintextCallers)
+	}
+	if caller := readiness.PlaintextCallers[0]; caller.Workload != "grpc-app/consumer" || caller.Connections != 5 {
+		t.Fatalf("plaintext caller = %+v, want grpc-app/consumer with 5 connections", caller)
+	}
+	if len(readiness.MTLSCallers) != 1 || readiness.MTLSCallers[0].Principal != "spiffe://cluster.local/ns/grpc-app/sa/web" {
+		t.Fatalf("mtls callers = %+v", readiness.MTLSCallers)
+	}
+	if readiness.ReadyForStrict() {
+		t.Fatal("expected port with plaintext callers not to be ready for STRICT")
+	}
+}
+
+func TestCachedMTLSReadinessReportServesUntilExpiry(t *testing.T) {
+	s := &Server{}
+	first := s.cachedMTLSReadinessReport()
+	if again := s.cachedMTLSReadinessReport(); !again.GeneratedAt.Equal(first.GeneratedAt) {
+		t.Fatalf("generatedAt = %v, want cached %v", again.GeneratedAt, first.GeneratedAt)
+	}
+	s.mtlsReadiness.expires = time.Now().Add(-time.Second)
+	if rebuilt := s.cachedMTLSReadinessReport(); !rebuilt.GeneratedAt.After(first.GeneratedAt) {
+		t.Fatalf("generatedAt = %v, want a report newer than %v", rebuilt.GeneratedAt, first.GeneratedAt)
+	}
+}
+
+func newMTLSReadinessTestPod(name, namespace, ip string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: map[string]string{inject.InherentInjectTemplatesAnnoName: inject.InherentGRPCTemplateName},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+	}
+}
@@ -18,6 +18,7 @@ package bootstrap
 
 import (
 	"os"
+	"time"
 
 	kubecontroller "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/kube/controller"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
@@ -32,6 +33,17 @@ type RegistryOptions struct {
 	KubeConfig                 string
 	KubeOptions                kubecontroller.Options
 	ClusterRegistriesNamespace string
+	// ZookeeperAddresses and NacosAddress locate the legacy Dubbo registries
+	// read by the Zookeeper and Nacos registry adapters.
+	ZookeeperAddresses []string
+	// ZookeeperSessionTimeout is the session timeout requested from the
+	// ensemble. Providers are reported gone only after it expires.
+	ZookeeperSessionTimeout time.Duration
+	NacosAddress            string
+	NacosNamespace          string
+	// DubboRegistryNamespace is the namespace services read from a legacy
+	// Dubbo registry are placed in. Defaults to the dubbod namespace.
+	DubboRegistryNamespace string
 }
 
 type InjectionOptions struct {
@@ -60,7 +72,14 @@ type DiscoveryServerOptions struct {
 	// ActivationAddr serves KEDA's external scaler contract. Empty disables it,
 	// which is how a cluster without KEDA runs unchanged.
 	ActivationAddr string
-	TLSOptions     TLSOptions
+	// ActivationPeerAddr resolves to the activation port of every replica, so
+	// they can hand demand off to each other. Empty disables the handoff.
+	ActivationPeerAddr string
+	// ActivationDemandAddr resolves to the secure xDS port of every replica,
+	// so proxyless workloads can report their demand to all of them. Empty
+	// makes them report to their discovery address, which reaches only one.
+	ActivationDemandAddr string
+	TLSOptions           TLSOptions
 }
 
 type TLSOptions struct {
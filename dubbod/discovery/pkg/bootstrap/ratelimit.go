@@ -0,0 +1,44 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/leaderelection"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/ratelimit"
+	"github.com/apache/dubbo-kubernetes/pkg/log"
+)
+
+// initRateLimitStatus starts the controller that reports where each
+// RateLimitPolicy is enforced. Only the leader of the revision writes status,
+// so replicas do not race on the same policy.
+func (s *Server) initRateLimitStatus(args *DubboArgs) {
+	if s.kubeClient == nil {
+		log.Info("rate limit status controller disabled; no kube client")
+		return
+	}
+	s.addTerminatingStartFunc("rate limit status controller", func(stop <-chan struct{}) error {
+		leaderelection.
+			NewPerRevisionLeaderElection(args.Namespace, args.PodName, leaderelection.RateLimitStatusController, args.Revision, s.kubeClient).
+			AddRunFunction(func(leaderStop <-chan struct{}) {
+				controller := ratelimit.NewController(s.kubeClient)
+				s.kubeClient.RunAndWait(stop)
+				controller.Run(leaderStop)
+			}).
+			Run(stop)
+		return nil
+	})
+}
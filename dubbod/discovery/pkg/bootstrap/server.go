@@ -43,6 +43,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/ca"
 	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/ra"
 	caserver "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/server/ca"
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/server/ca/authenticate"
 	"github.com/apache/dubbo-kubernetes/pkg/cluster"
 	"github.com/apache/dubbo-kubernetes/pkg/config"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
@@ -60,6 +61,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/log"
 	sec_model "github.com/apache/dubbo-kubernetes/pkg/model"
 	"github.com/apache/dubbo-kubernetes/pkg/network"
+	"github.com/apache/dubbo-kubernetes/pkg/security"
 	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
 	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
 	"github.com/fsnotify/fsnotify"
@@ -117,6 +119,15 @@ type Server struct {
 	dubbodCert              *tls.Certificate
 	RA                      ra.RegistrationAuthority
 	CA                      *ca.DubboCA
+	// peerCertVerifier checks xDS client certificates. It is rebuilt whenever
+	// the bundles of federated trust domains change; peerCertTrustDomain and
+	// peerCertRoots are the mesh half it is rebuilt from.
+	peerCertVerifier    atomic.Pointer[spiffe.PeerCertVerifier]
+	peerCertTrustDomain string
+	peerCertRoots       []byte
+	// caRootConfigMaps caches the per-namespace root cert ConfigMaps a staged
+	// root rotation waits on.
+	caRootConfigMaps kclient.Client[*corev1.ConfigMap]
 
 	dnsNames []string
 
@@ -132,6 +143,13 @@ type Server struct {
 	inherentGRPCRemoteControllers  *multicluster.Component[*inherentGRPCClusterController]
 	statusManager                  *status.Manager
 	activation                     *activation.Server
+	// activationDemandAddr is where proxyless workloads report the demand of
+	// the cold targets they call.
+	activationDemandAddr string
+
+	// mtlsReadiness caches the last mTLS readiness report, so polling the
+	// endpoint does not scrape every workload each time.
+	mtlsReadiness mtlsReadinessCache
 }
 
 type readinessFlags struct {
@@ -232,6 +250,14 @@ func NewServer(args *DubboArgs, initFuncs ...func(*Server)) (*Server, error) {
 	if caOpts.ExternalCAType == ra.ExtCAK8s {
 		caOpts.ExternalCASigner = k8sSigner
 	}
+	if s.kubeClient != nil {
+		// Workloads holding a mesh certificate renew with it; anyone else, such as
+		// VMs and jobs, bootstraps with a projected service account token.
+		caOpts.Authenticators = []security.Authenticator{
+			&authenticate.ClientCertAuthenticator{},
+			authenticate.NewKubeJWTAuthenticator(s.environment.Watcher, s.kubeClient.Kube(), features.TokenAudiences),
+		}
+	}
 
 	if err := s.maybeCreateCA(caOpts); err != nil {
 		return nil, err
@@ -261,6 +287,7 @@ func NewServer(args *DubboArgs, initFuncs ...func(*Server)) (*Server, error) {
 	// Debug endpoints (/debug/syncz etc.) share the monitoring mux and power
 	// `dubboctl proxy-status`.
 	s.XDSServer.AppendDebugHandlers(s.monitoringMux)
+	s.monitoringMux.HandleFunc(mtlsReadinessPath, s.mtlsReadinessHandler)
 
 	dubbodHost, _, err := e.GetDiscoveryAddress()
 	if err != nil {
@@ -420,22 +447,32 @@ func (s *Server) initSecureDiscoveryService(args *DubboArgs, trustDomain string)
 	}
 	log.Info("initializing secure discovery service")
 
+	s.peerCertVerifier.Store(peerCertVerifier)
+
 	cfg := &tls.Config{
 		GetCertificate: s.getDubbodCertificate,
 		ClientAuth:     tls.VerifyClientCertIfGiven,
-		ClientCAs:      peerCertVerifier.GetGeneralCertPool(),
-		VerifyPeerCertificate: func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-			err := peerCertVerifier.VerifyPeerCert(rawCerts, verifiedChains)
+		MinVersion:     tls.VersionTLS12,
+		CipherSuites:   args.ServerOptions.TLSOptions.CipherSuits,
+	}
+	// Compliance for xDS server TLS.
+	sec_model.EnforceGoCompliance(cfg)
+	// The verifier is swapped when federated trust bundles change, so every
+	// handshake picks up the current one.
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		verifier := s.peerCertVerifier.Load()
+		conn := cfg.Clone()
+		conn.GetConfigForClient = nil
+		conn.ClientCAs = verifier.GetGeneralCertPool()
+		conn.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			err := verifier.VerifyPeerCert(rawCerts, verifiedChains)
 			if err != nil {
 				log.Infof("Could not verify certificate: %v", err)
 			}
 			return err
-		},
-		MinVersion:   tls.VersionTLS12,
-		CipherSuites: args.ServerOptions.TLSOptions.CipherSuits,
+		}
+		return conn, nil
 	}
-	// Compliance for xDS server TLS.
-	sec_model.EnforceGoCompliance(cfg)
 
 	tlsCreds := credentials.NewTLS(cfg)
 
@@ -450,6 +487,11 @@ func (s *Server) initSecureDiscoveryService(args *DubboArgs, trustDomain string)
 
 	s.secureGrpcServer = grpc.NewServer(opts...)
 	s.XDSServer.Register(s.secureGrpcServer)
+	if s.activation != nil {
+		// Proxyless workloads report the demand of the cold targets they call
+		// directly here, where their certificate names them.
+		s.activation.RegisterWorkloadDemand(s.secureGrpcServer, workloadDemandIdentity)
+	}
 	reflection.Register(s.secureGrpcServer)
 
 	s.addStartFunc("secure gRPC", func(stop <-chan struct{}) error {
@@ -525,15 +567,17 @@ func (s *Server) initRegistryEventHandlers() {
 		// Some configs (security policies/HTTPRoute) require Full push to ensure
 		// PushContext is re-initialized and configuration is reloaded.
 		// Security policies must rebuild AuthenticationPolicies to update inbound mTLS/JWT/authz filters.
-		// HTTPRoute must rebuild HTTPRoute index to enable Gateway API routing.
+		// HTTPRoute/GRPCRoute must rebuild the route indexes to enable Gateway API routing.
 		needsFullPush := configKind == kind.PeerAuthentication ||
 			configKind == kind.RequestAuthentication ||
 			configKind == kind.AuthorizationPolicy ||
 			configKind == kind.HTTPRoute ||
+			configKind == kind.GRPCRoute ||
 			configKind == kind.BackendTLSPolicy ||
 			configKind == kind.ReferenceGrant ||
 			configKind == kind.CircuitBreakerPolicy ||
 			configKind == kind.FaultInjectionPolicy ||
+			configKind == kind.RateLimitPolicy ||
 			configKind == kind.DxgateService ||
 			configKind == kind.ServiceActivationPolicy
 
@@ -612,12 +656,16 @@ func configKindForSchemaIdentifier(schemaID string) (kind.Kind, bool) {
 		return kind.KubernetesGateway, true
 	case "HTTPRoute":
 		return kind.HTTPRoute, true
+	case "GRPCRoute":
+		return kind.GRPCRoute, true
 	case "BackendTLSPolicy":
 		return kind.BackendTLSPolicy, true
 	case "CircuitBreakerPolicy":
 		return kind.CircuitBreakerPolicy, true
 	case "FaultInjectionPolicy":
 		return kind.FaultInjectionPolicy, true
+	case "RateLimitPolicy":
+		return kind.RateLimitPolicy, true
 	case "DxgateService":
 		return kind.DxgateService, true
 	case "ServiceActivationPolicy":
@@ -720,6 +768,10 @@ func (s *Server) initControllers(args *DubboArgs) error {
 	if err := s.initInherentGRPCWorkloads(); err != nil {
 		return fmt.Errorf("error initializing Inherent gRPC workloads: %v", err)
 	}
+	s.initTrustDomainFederation()
+	s.initCARevocations()
+	s.initCircuitBreakerStatus(args)
+	s.initRateLimitStatus(args)
 	return nil
 }
 
@@ -747,7 +799,6 @@ func (s *Server) createPeerCertVerifier(tlsOptions TLSOptions, trustDomain strin
 		// Running locally without configured certs - no TLS mode
 		return nil, nil
 	}
-	peerCertVerifier := spiffe.NewPeerCertVerifier()
 	var rootCertBytes []byte
 	var err error
 	if caCertPath != "" {
@@ -769,17 +820,45 @@ func (s *Server) createPeerCertVerifier(tlsOptions TLSOptions, trustDomain strin
 		}
 	}
 
-	if len(rootCertBytes) != 0 {
-		// TODO: trustDomain here is static and will not update if it dynamically changes in mesh config
-		err := peerCertVerifier.AddMappingFromPEM(trustDomain, rootCertBytes)
+	// TODO: trustDomain here is static and will not update if it dynamically changes in mesh config
+	s.peerCertTrustDomain = trustDomain
+	s.peerCertRoots = rootCertBytes
+	return s.buildPeerCertVerifier()
+}
+
+// buildPeerCertVerifier maps the mesh roots to the mesh trust domain and the
+// roots of each federated trust domain to that trust domain only, so a foreign
+// CA can never vouch for a local identity.
+func (s *Server) buildPeerCertVerifier() (*spiffe.PeerCertVerifier, error) {
+	peerCertVerifier := spiffe.NewPeerCertVerifier()
+	if len(s.peerCertRoots) != 0 {
+		err := peerCertVerifier.AddMappingFromPEM(s.peerCertTrustDomain, s.peerCertRoots)
 		if err != nil {
 			return nil, fmt.Errorf("add root CAs into peerCertVerifier failed: %v", err)
 		}
 	}
-
+	for trustDomain, bundle := range s.dubbodCertBundleWatcher.GetFederatedCABundles() {
+		if err := peerCertVerifier.AddMappingFromPEM(trustDomain, bundle); err != nil {
+			return nil, fmt.Errorf("add roots of federated trust domain %s into peerCertVerifier failed: %v", trustDomain, err)
+		}
+	}
 	return peerCertVerifier, nil
 }
 
+// refreshPeerCertVerifier rebuilds the xDS peer verifier after the federated
+// trust bundles changed. It is a no-op while the secure discovery service is off.
+func (s *Server) refreshPeerCertVerifier() {
+	if s.peerCertVerifier.Load() == nil {
+		return
+	}
+	peerCertVerifier, err := s.buildPeerCertVerifier()
+	if err != nil {
+		log.Errorf("Keeping the previous peer cert verifier: %v", err)
+		return
+	}
+	s.peerCertVerifier.Store(peerCertVerifier)
+}
+
 func (s *Server) maybeCreateCA(caOpts *caOptions) error {
 	if features.EnableCAServer {
 		log.Info("creating CA and initializing public key")
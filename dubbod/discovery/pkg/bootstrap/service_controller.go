@@ -22,6 +22,9 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/log"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/aggregate"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/dubboregistry"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/dubboregistry/nacos"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/dubboregistry/zookeeper"
 	kubecontroller "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/kube/controller"
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/provider"
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/serviceentry"
@@ -49,6 +52,21 @@ func (s *Server) initServiceControllers(args *DubboArgs) error {
 			if err := s.initKubeRegistry(args); err != nil {
 				return err
 			}
+		case provider.Zookeeper:
+			if len(args.RegistryOptions.ZookeeperAddresses) == 0 {
+				return fmt.Errorf("%s registry requires --zookeeperAddresses", r)
+			}
+			source := zookeeper.NewSource(zookeeper.NewConn(args.RegistryOptions.ZookeeperAddresses, args.RegistryOptions.ZookeeperSessionTimeout), zookeeper.Options{})
+			serviceControllers.AddRegistryAndRun(s.newDubboRegistry(args, serviceRegistry, source), s.internalStop)
+		case provider.Nacos:
+			if args.RegistryOptions.NacosAddress == "" {
+				return fmt.Errorf("%s registry requires --nacosAddress", r)
+			}
+			source := nacos.NewSource(nacos.Options{
+				Address:     args.RegistryOptions.NacosAddress,
+				NamespaceID: args.RegistryOptions.NacosNamespace,
+			})
+			serviceControllers.AddRegistryAndRun(s.newDubboRegistry(args, serviceRegistry, source), s.internalStop)
 		default:
 			return fmt.Errorf("service registry %s is not supported", r)
 		}
@@ -87,6 +105,19 @@ func (s *Server) initKubeRegistry(args *DubboArgs) (err error) {
 	return
 }
 
+func (s *Server) newDubboRegistry(args *DubboArgs, id provider.ID, source dubboregistry.Source) *dubboregistry.Controller {
+	namespace := args.RegistryOptions.DubboRegistryNamespace
+	if namespace == "" {
+		namespace = args.Namespace
+	}
+	return dubboregistry.NewController(dubboregistry.Options{
+		Provider:   id,
+		ClusterID:  s.clusterID,
+		Namespace:  namespace,
+		XDSUpdater: s.XDSServer,
+	}, source)
+}
+
 func hasKubeRegistry(registries []string) bool {
 	for _, r := range registries {
 		if provider.ID(r) == provider.Kubernetes {
@@ -0,0 +1,152 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/features"
+	"github.com/apache/dubbo-kubernetes/pkg/log"
+	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
+)
+
+const federatedBundleFetchTimeout = 30 * time.Second
+
+// trustDomainFederation keeps the roots of federated trust domains on the
+// dubbod bundle watcher, from where they reach the peer verifier of dubbod, the
+// namespace root cert ConfigMaps and the inherent gRPC workload Secrets. The
+// roots stay bound to their trust domain all the way; they are never merged
+// into the mesh root cert.
+type trustDomainFederation struct {
+	server  *Server
+	trigger chan struct{}
+
+	mu sync.Mutex
+	// bundles holds the last bundle fetched for each trust domain, so a
+	// failing endpoint does not drop trust it already established.
+	bundles map[string][]byte
+}
+
+func (s *Server) initTrustDomainFederation() {
+	f := &trustDomainFederation{
+		server:  s,
+		trigger: make(chan struct{}, 1),
+		bundles: map[string][]byte{},
+	}
+	s.environment.AddMeshHandler(f.requestRefresh)
+	s.addStartFunc("trust domain federation", func(stop <-chan struct{}) error {
+		go f.run(stop)
+		return nil
+	})
+}
+
+func (f *trustDomainFederation) requestRefresh() {
+	select {
+	case f.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (f *trustDomainFederation) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(features.FederatedTrustBundleRefreshInterval)
+	defer ticker.Stop()
+	for {
+		f.refresh()
+		select {
+		case <-ticker.C:
+		case <-f.trigger:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (f *trustDomainFederation) refresh() {
+	meshConfig := f.server.environment.Mesh()
+	domains := spiffe.FederatedTrustDomains(meshConfig.GetCaCertificates(),
+		meshConfig.GetTrustDomain(), meshConfig.GetTrustDomainAliases())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	next := make(map[string][]byte, len(domains))
+	for _, domain := range domains {
+		bundle, err := f.fetch(domain)
+		if err != nil {
+			previous, found := f.bundles[domain.TrustDomain]
+			log.Warnf("Failed to fetch bundle of federated trust domain %s (keeping previous: %v): %v",
+				domain.TrustDomain, found, err)
+			if !found {
+				continue
+			}
+			bundle = previous
+		}
+		next[domain.TrustDomain] = bundle
+	}
+	f.bundles = next
+
+	if maps.EqualFunc(next, f.server.dubbodCertBundleWatcher.GetFederatedCABundles(), bytes.Equal) {
+		return
+	}
+	log.Infof("Federated trust bundles updated for %d trust domains", len(next))
+	f.server.dubbodCertBundleWatcher.SetFederatedCABundlesAndNotify(next)
+	f.server.refreshPeerCertVerifier()
+}
+
+func (f *trustDomainFederation) fetch(domain spiffe.FederatedTrustDomain) ([]byte, error) {
+	if domain.BundleURL != "" {
+		roots, err := spiffe.RetrieveSpiffeBundleRootCerts(
+			map[string]string{domain.TrustDomain: domain.BundleURL}, nil, federatedBundleFetchTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return spiffe.CertsToPEM(roots[domain.TrustDomain]), nil
+	}
+
+	roots, err := spiffe.DecodeTrustBundle([]byte(domain.PEM))
+	if err != nil {
+		return nil, err
+	}
+	return spiffe.CertsToPEM(roots), nil
+}
+
+// spiffeBundleMap returns the SPIFFE bundle map workloads verify peers with:
+// the mesh root under the mesh trust domain and each of its aliases, and the roots of
+// every federated trust domain under that trust domain alone. It is nil while
+// no trust domain is federated, leaving workloads on the plain root cert.
+func (s *Server) spiffeBundleMap(root []byte) []byte {
+	if s.dubbodCertBundleWatcher == nil || len(root) == 0 {
+		return nil
+	}
+	federated := s.dubbodCertBundleWatcher.GetFederatedCABundles()
+	if len(federated) == 0 {
+		return nil
+	}
+	meshConfig := s.environment.Mesh()
+	bundles := maps.Clone(federated)
+	for _, td := range append([]string{meshConfig.GetTrustDomain()}, meshConfig.GetTrustDomainAliases()...) {
+		bundles[td] = root
+	}
+	bundleMap, err := spiffe.EncodeBundleMap(bundles)
+	if err != nil {
+		log.Errorf("Failed to encode the SPIFFE bundle map: %v", err)
+		return nil
+	}
+	return bundleMap
+}
@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/status"
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
+	"github.com/apache/dubbo-kubernetes/pkg/log"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var logger = log.RegisterScope("circuitbreaker", "Circuit breaker policies")
+
+// Controller publishes which parts of a CircuitBreakerPolicy the proxyless
+// data plane cannot enforce. The policy itself is compiled into CDS and the
+// dxgate runtime config elsewhere; this controller only writes status.
+type Controller struct {
+	policies kclient.Client[*clientnetworking.CircuitBreakerPolicy]
+	queue    controllers.Queue
+}
+
+func NewController(client kube.Client) *Controller {
+	c := &Controller{
+		policies: kclient.New[*clientnetworking.CircuitBreakerPolicy](client),
+	}
+	c.queue = controllers.NewQueue("circuit breaker policy",
+		controllers.WithReconciler(c.Reconcile),
+		controllers.WithMaxAttempts(5))
+	c.policies.AddEventHandler(controllers.EventHandler[*clientnetworking.CircuitBreakerPolicy]{
+		AddFunc: func(policy *clientnetworking.CircuitBreakerPolicy) {
+			c.queue.AddObject(policy)
+		},
+		UpdateFunc: func(oldPolicy, newPolicy *clientnetworking.CircuitBreakerPolicy) {
+			// Status writes do not bump the generation; skip them.
+			if oldPolicy.GetGeneration() != newPolicy.GetGeneration() {
+				c.queue.AddObject(newPolicy)
+			}
+		},
+	})
+	return c
+}
+
+func (c *Controller) Run(stop <-chan struct{}) {
+	kube.WaitForCacheSync("circuit breaker controller", stop, c.policies.HasSynced)
+	c.queue.Run(stop)
+	controllers.ShutdownAll(c.policies)
+}
+
+func (c *Controller) Reconcile(key types.NamespacedName) error {
+	policy := c.policies.Get(key.Name, key.Namespace)
+	if policy == nil {
+		return nil
+	}
+
+	conditions := Conditions(policy)
+	if status.SameConditions(policy.Status.GetConditions(), conditions) {
+		return nil
+	}
+
+	updated := policy.DeepCopy()
+	updated.Status.Conditions = conditions
+	if _, err := c.policies.UpdateStatus(updated); err != nil {
+		return err
+	}
+	logger.Debugf("updated %s/%s: %s=%s(%s)", key.Namespace, key.Name,
+		conditions[0].GetType(), conditions[0].GetStatus(), conditions[0].GetReason())
+	return nil
+}
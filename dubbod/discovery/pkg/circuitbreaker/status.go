@@ -0,0 +1,77 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	metav1alpha1 "github.com/kdubbo/api/meta/v1alpha1"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+)
+
+// ConditionProxylessCompatible reports whether proxyless gRPC clients enforce
+// the whole policy. dxgate honors every field; gRPC only limits concurrent
+// requests and ejects by success rate or failure percentage.
+const ConditionProxylessCompatible = "ProxylessCompatible"
+
+// conditionIgnoresPrefix starts the type of the condition added per field a
+// False ProxylessCompatible is about, e.g.
+// "ProxylessIgnoresConnectionPoolMaxConnections". DubboCondition has no
+// message, and a reason cannot list several fields and stay CamelCase.
+const conditionIgnoresPrefix = "ProxylessIgnores"
+
+const (
+	reasonAllFieldsHonored = "AllFieldsHonored"
+	reasonUnsupported      = "UnsupportedFields"
+	reasonNotSupported     = "NotSupportedByProxylessGRPC"
+)
+
+// Conditions evaluates the status conditions of a CircuitBreakerPolicy.
+func Conditions(policy *clientnetworking.CircuitBreakerPolicy) []*metav1alpha1.DubboCondition {
+	condition := &metav1alpha1.DubboCondition{
+		Type:               ConditionProxylessCompatible,
+		Status:             "True",
+		Reason:             reasonAllFieldsHonored,
+		ObservedGeneration: policy.GetGeneration(),
+	}
+	conditions := []*metav1alpha1.DubboCondition{condition}
+	for _, field := range model.ProxylessUnsupportedCircuitBreakerFields(&policy.Spec) {
+		condition.Status = "False"
+		condition.Reason = reasonUnsupported
+		conditions = append(conditions, &metav1alpha1.DubboCondition{
+			Type:               conditionIgnoresPrefix + fieldConditionName(field),
+			Status:             "True",
+			Reason:             reasonNotSupported,
+			ObservedGeneration: policy.GetGeneration(),
+		})
+	}
+	return conditions
+}
+
+// fieldConditionName turns a field path such as "connectionPool.maxRetries"
+// into "ConnectionPoolMaxRetries".
+func fieldConditionName(field string) string {
+	var out strings.Builder
+	for _, part := range strings.Split(field, ".") {
+		if part == "" {
+			continue
+		}
+		out.WriteString(strings.ToUpper(part[:1]))
+		out.WriteString(part[1:])
+	}
+	return out.String()
+}
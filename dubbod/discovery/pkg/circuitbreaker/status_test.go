@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"strings"
+	"testing"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConditionsReportFieldsProxylessCannotHonor(t *testing.T) {
+	policy := &clientnetworking.CircuitBreakerPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "app", Generation: 3},
+		Spec: networking.CircuitBreakerPolicy{
+			ConnectionPool: &networking.ConnectionPoolSettings{
+				MaxConnections:   10,
+				Http2MaxRequests: 64,
+				MaxRetries:       2,
+			},
+			OutlierDetection: &networking.OutlierDetection{
+				Consecutive_5XxErrors: wrapperspb.UInt32(5),
+				MaxEjectionPercent:    50,
+			},
+		},
+	}
+
+	conditions := Conditions(policy)
+	got := conditions[0]
+	want := "UnsupportedFields"
+	if got.GetType() != ConditionProxylessCompatible || got.GetStatus() != "False" || got.GetReason() != want {
+		t.Fatalf("condition = %s=%s(%s), want False(%s)", got.GetType(), got.GetStatus(), got.GetReason(), want)
+	}
+	if got.GetObservedGeneration() != 3 {
+		t.Fatalf("observed generation = %d, want 3", got.GetObservedGeneration())
+	}
+
+	// Each ignored field gets a condition of its own naming it.
+	var ignored []string
+	for _, condition := range conditions[1:] {
+		if condition.GetStatus() != "True" || condition.GetReason() != reasonNotSupported || condition.GetObservedGeneration() != 3 {
+			t.Fatalf("condition = %s=%s(%s), want True(%s)",
+				condition.GetType(), condition.GetStatus(), condition.GetReason(), reasonNotSupported)
+		}
+		ignored = append(ignored, condition.GetType())
+	}
+	want = "ProxylessIgnoresConnectionPoolMaxConnections,ProxylessIgnoresConnectionPoolMaxRetries," +
+		"ProxylessIgnoresOutlierDetectionConsecutive5xxErrors"
+	if got := strings.Join(ignored, ","); got != want {
+		t.Fatalf("ignored fields = %s, want %s", got, want)
+	}
+}
+
+func TestConditionsAcceptFullyHonoredPolicy(t *testing.T) {
+	policy := &clientnetworking.CircuitBreakerPolicy{
+		Spec: networking.CircuitBreakerPolicy{
+			ConnectionPool:   &networking.ConnectionPoolSettings{Http2MaxRequests: 64},
+			OutlierDetection: &networking.OutlierDetection{MaxEjectionPercent: 50},
+		},
+	}
+
+	conditions := Conditions(policy)
+	if len(conditions) != 1 {
+		t.Fatalf("conditions = %d, want only %s", len(conditions), ConditionProxylessCompatible)
+	}
+	got := conditions[0]
+	if got.GetStatus() != "True" || got.GetReason() != reasonAllFieldsHonored {
+		t.Fatalf("condition = %s(%s), want True(%s)", got.GetStatus(), got.GetReason(), reasonAllFieldsHonored)
+	}
+}
@@ -66,6 +66,7 @@ type Controller struct {
 type Outputs struct {
 	Gateways        krt.Collection[config.Config]
 	HTTPRoutes      krt.Collection[config.Config]
+	GRPCRoutes      krt.Collection[config.Config]
 	ReferenceGrants referenceGrantStore
 }
 
@@ -82,6 +83,7 @@ type Inputs struct {
 	GatewayClasses  krt.Collection[*gateway.GatewayClass]
 	Gateways        krt.Collection[*gateway.Gateway]
 	HTTPRoutes      krt.Collection[*gateway.HTTPRoute]
+	GRPCRoutes      krt.Collection[*gateway.GRPCRoute]
 	ReferenceGrants krt.Collection[*gatewayv1beta1.ReferenceGrant]
 }
 
@@ -109,6 +111,7 @@ func NewController(kc kube.Client, waitForCRD func(class schema.GroupVersionReso
 		GatewayClasses: buildClient[*gateway.GatewayClass](c, kc, gvr.GatewayClass, opts, "informer/GatewayClasses"),
 		Gateways:       buildClient[*gateway.Gateway](c, kc, gvr.KubernetesGateway, opts, "informer/Gateways"),
 		HTTPRoutes:     buildClient[*gateway.HTTPRoute](c, kc, gvr.HTTPRoute, opts, "informer/HTTPRoutes"),
+		GRPCRoutes:     buildClient[*gateway.GRPCRoute](c, kc, gvr.GRPCRoute, opts, "informer/GRPCRoutes"),
 		ReferenceGrants: buildClient[*gatewayv1beta1.ReferenceGrant](
 			c,
 			kc,
@@ -132,19 +135,34 @@ func NewController(kc kube.Client, waitForCRD func(class schema.GroupVersionReso
 
 	ReferenceGrants := newReferenceGrantStore(ReferenceGrantsCollection(inputs.ReferenceGrants, opts))
 
-	HTTPRoutes := krt.NewCollection(inputs.HTTPRoutes, func(ctx krt.HandlerContext, hr *gateway.HTTPRoute) *config.Config {
-		cfg := convertHTTPRouteToConfig(hr)
-		return &cfg
-	}, opts.WithName("HTTPRoutes")...)
+	HTTPRoutesStatus, HTTPRoutes := HTTPRoutesCollection(
+		inputs.HTTPRoutes,
+		inputs.Gateways,
+		GatewayClasses,
+		inputs.Services,
+		opts,
+	)
+	status.RegisterStatus(c.status, HTTPRoutesStatus, GetStatus)
+
+	GRPCRoutesStatus, GRPCRoutes := GRPCRoutesCollection(
+		inputs.GRPCRoutes,
+		inputs.Gateways,
+		GatewayClasses,
+		inputs.Services,
+		opts,
+	)
+	status.RegisterStatus(c.status, GRPCRoutesStatus, GetStatus)
 
 	outputs := Outputs{
 		Gateways:        Gateways,
 		HTTPRoutes:      HTTPRoutes,
+		GRPCRoutes:      GRPCRoutes,
 		ReferenceGrants: ReferenceGrants,
 	}
 	data := map[config.GroupVersionKind]kindStore{
 		gvk.KubernetesGateway: newKindStore(Gateways),
 		gvk.HTTPRoute:         newKindStore(HTTPRoutes),
+		gvk.GRPCRoute:         newKindStore(GRPCRoutes),
 	}
 
 	c.outputs = outputs
@@ -219,6 +237,25 @@ func convertHTTPRouteToConfig(hr *gateway.HTTPRoute) config.Config {
 	}
 }
 
+func convertGRPCRouteToConfig(gr *gateway.GRPCRoute) config.Config {
+	return config.Config{
+		Meta: config.Meta{
+			GroupVersionKind:  gvk.GRPCRoute,
+			Name:              gr.Name,
+			Namespace:         gr.Namespace,
+			Labels:            gr.Labels,
+			Annotations:       gr.Annotations,
+			ResourceVersion:   gr.ResourceVersion,
+			CreationTimestamp: gr.CreationTimestamp.Time,
+			OwnerReferences:   gr.OwnerReferences,
+			UID:               string(gr.UID),
+			Generation:        gr.Generation,
+		},
+		Spec:   gr.Spec.DeepCopy(),
+		Status: gr.Status.DeepCopy(),
+	}
+}
+
 func (c *Controller) Create(config config.Config) (revision string, err error) {
 	return "", errUnsupportedOp
 }
@@ -245,6 +282,7 @@ func (c *Controller) HasSynced() bool {
 		!collectionHasSynced(c.inputs.GatewayClasses) ||
 		!collectionHasSynced(c.inputs.Gateways) ||
 		!collectionHasSynced(c.inputs.HTTPRoutes) ||
+		!collectionHasSynced(c.inputs.GRPCRoutes) ||
 		!collectionHasSynced(c.inputs.ReferenceGrants) {
 		return false
 	}
@@ -292,6 +330,7 @@ func (c *Controller) Schemas() collection.Schemas {
 	return collection.SchemasFor(
 		collections.KubernetesGateway,
 		collections.HTTPRoute,
+		collections.GRPCRoute,
 	)
 }
 
@@ -325,7 +364,7 @@ func buildClient[I controllers.ComparableObject](
 	}
 
 	// Gateway API resources select revisions through their parent/attachment semantics.
-	if res == gvr.KubernetesGateway || res == gvr.HTTPRoute || res == gvr.ReferenceGrant {
+	if res == gvr.KubernetesGateway || res == gvr.HTTPRoute || res == gvr.GRPCRoute || res == gvr.ReferenceGrant {
 		filter.ObjectFilter = kc.ObjectFilter()
 	}
 
@@ -17,6 +17,7 @@
 package gateway
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -294,6 +295,169 @@ func TestConvertHTTPRouteToConfigCopiesSpec(t *testing.T) {
 	}
 }
 
+func TestHTTPRoutesCollectionReportsMissingRequestMirrorBackend(t *testing.T) {
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	opts := krt.NewOptionsBuilder(stop, "test", nil)
+
+	serviceKind := gatewayv1.Kind("Service")
+	port := gatewayv1.PortNumber(8080)
+	routes := krt.NewStaticCollection[*gatewayv1.HTTPRoute](nil, []*gatewayv1.HTTPRoute{{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "app", Generation: 3},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Kind: &serviceKind, Name: "orders"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+						BackendRef: gatewayv1.BackendObjectReference{Name: "orders-shadow", Port: &port},
+					},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "orders", Port: &port},
+					},
+				}},
+			}},
+		},
+	}}, krt.WithStop(stop))
+	services := krt.NewStaticCollection[*corev1.Service](nil, []*corev1.Service{{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "app"},
+	}}, krt.WithStop(stop))
+	gateways := krt.NewStaticCollection[*gatewayv1.Gateway](nil, nil, krt.WithStop(stop))
+	gatewayClasses := krt.NewStaticCollection[GatewayClass](nil, nil, krt.WithStop(stop))
+
+	statuses, configs := HTTPRoutesCollection(routes, gateways, gatewayClasses, services, opts)
+	waitSynced(t, configs)
+	waitSynced(t, statuses)
+
+	if got := configs.List(); len(got) != 1 {
+		t.Fatalf("HTTPRoute configs = %d, want 1", len(got))
+	}
+	statusList := statuses.List()
+	if len(statusList) != 1 || len(statusList[0].Status.Parents) != 1 {
+		t.Fatalf("HTTPRoute statuses = %#v, want one parent status", statusList)
+	}
+	parent := statusList[0].Status.Parents[0]
+	if parent.ControllerName != gatewayv1.GatewayController(features.ManagedGatewayController) {
+		t.Fatalf("controller name = %q, want %q", parent.ControllerName, features.ManagedGatewayController)
+	}
+	var resolved *metav1.Condition
+	for i := range parent.Conditions {
+		if parent.Conditions[i].Type == string(gatewayv1.RouteConditionResolvedRefs) {
+			resolved = &parent.Conditions[i]
+		}
+	}
+	if resolved == nil || resolved.Status != metav1.ConditionFalse || resolved.Reason != string(gatewayv1.RouteReasonBackendNotFound) {
+		t.Fatalf("ResolvedRefs = %#v, want False/BackendNotFound", resolved)
+	}
+}
+
+func TestGRPCRoutesCollectionReportsServiceParentStatus(t *testing.T) {
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	opts := krt.NewOptionsBuilder(stop, "test", nil)
+
+	serviceKind := gatewayv1.Kind("Service")
+	otherController := gatewayv1.GatewayController("example.com/other")
+	routes := krt.NewStaticCollection[*gatewayv1.GRPCRoute](nil, []*gatewayv1.GRPCRoute{{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeter", Namespace: "app", Generation: 2},
+		Spec: gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{Kind: &serviceKind, Name: "greeter"},
+					{Name: "unmanaged-gateway"},
+				},
+			},
+			Rules: []gatewayv1.GRPCRouteRule{{
+				BackendRefs: []gatewayv1.GRPCBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter"},
+					},
+				}},
+			}},
+		},
+		Status: gatewayv1.GRPCRouteStatus{RouteStatus: gatewayv1.RouteStatus{
+			Parents: []gatewayv1.RouteParentStatus{{
+				ParentRef:      gatewayv1.ParentReference{Name: "unmanaged-gateway"},
+				ControllerName: otherController,
+			}},
+		}},
+	}}, krt.WithStop(stop))
+	services := krt.NewStaticCollection[*corev1.Service](nil, nil, krt.WithStop(stop))
+	gateways := krt.NewStaticCollection[*gatewayv1.Gateway](nil, nil, krt.WithStop(stop))
+	gatewayClasses := krt.NewStaticCollection[GatewayClass](nil, nil, krt.WithStop(stop))
+
+	statuses, configs := GRPCRoutesCollection(routes, gateways, gatewayClasses, services, opts)
+	waitSynced(t, configs)
+	waitSynced(t, statuses)
+
+	cfgs := configs.List()
+	if len(cfgs) != 1 || cfgs[0].GroupVersionKind != gvk.GRPCRoute {
+		t.Fatalf("GRPCRoute configs = %#v, want one GRPCRoute config", cfgs)
+	}
+	statusList := statuses.List()
+	if len(statusList) != 1 || len(statusList[0].Status.Parents) != 2 {
+		t.Fatalf("GRPCRoute statuses = %#v, want foreign and managed parent status", statusList)
+	}
+	parents := statusList[0].Status.Parents
+	if parents[0].ControllerName != otherController {
+		t.Fatalf("foreign parent status was not preserved: %#v", parents[0])
+	}
+	managed := parents[1]
+	if managed.ControllerName != gatewayv1.GatewayController(features.ManagedGatewayController) || managed.ParentRef.Name != "greeter" {
+		t.Fatalf("managed parent status = %#v", managed)
+	}
+	for _, cond := range managed.Conditions {
+		if cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != 2 {
+			t.Fatalf("condition %s = %s (generation %d), want True at generation 2", cond.Type, cond.Status, cond.ObservedGeneration)
+		}
+	}
+}
+
+func TestSetRouteParentConditionsReportsClaimMatchesOnServiceParents(t *testing.T) {
+	serviceKind := gatewayv1.Kind("Service")
+	serviceParent := gatewayv1.ParentReference{Kind: &serviceKind, Name: "reviews"}
+	gatewayParent := gatewayv1.ParentReference{Name: "dxgate-gateway"}
+	route := metav1.ObjectMeta{Name: "reviews", Namespace: "app", Generation: 4}
+	claimMatches := httpRouteClaimMatches([]gatewayv1.HTTPRouteRule{{
+		Matches: []gatewayv1.HTTPRouteMatch{
+			{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-plan", Value: "gold"}}},
+			{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "X-JWT-Claim-plan", Value: "gold"}}},
+		},
+	}})
+	managed := func(gatewayv1.ParentReference) bool { return true }
+	partiallyInvalid := func(parent gatewayv1.RouteParentStatus) *metav1.Condition {
+		for i := range parent.Conditions {
+			if parent.Conditions[i].Type == string(gatewayv1.RouteConditionPartiallyInvalid) {
+				return &parent.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	status := setRouteParentConditions(gatewayv1.RouteStatus{}, route,
+		[]gatewayv1.ParentReference{serviceParent, gatewayParent}, managed, nil, claimMatches)
+	if len(status.Parents) != 2 {
+		t.Fatalf("parents = %#v, want service and gateway parent", status.Parents)
+	}
+	cond := partiallyInvalid(status.Parents[0])
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != string(gatewayv1.RouteReasonUnsupportedValue) ||
+		!strings.Contains(cond.Message, "rule[0].matches[1]") {
+		t.Fatalf("service parent PartiallyInvalid = %#v, want True/UnsupportedValue naming rule[0].matches[1]", cond)
+	}
+	if cond := partiallyInvalid(status.Parents[1]); cond != nil {
+		t.Fatalf("gateway parent PartiallyInvalid = %#v, want none", cond)
+	}
+
+	status = setRouteParentConditions(status, route, []gatewayv1.ParentReference{serviceParent}, managed, nil, nil)
+	if cond := partiallyInvalid(status.Parents[0]); cond != nil {
+		t.Fatalf("PartiallyInvalid = %#v, want it cleared once the claim match is gone", cond)
+	}
+}
+
 func expectEvent(t *testing.T, events <-chan model.Event, want model.Event) {
 	t.Helper()
 	select {
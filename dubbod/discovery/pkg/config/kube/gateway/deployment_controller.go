@@ -46,6 +46,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/config"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvr"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	telemetryconfig "github.com/apache/dubbo-kubernetes/pkg/config/telemetry"
 	"github.com/apache/dubbo-kubernetes/pkg/grpcxds"
 	"github.com/apache/dubbo-kubernetes/pkg/kube"
@@ -631,6 +632,7 @@ type dxgateListener struct {
 	Protocol     string              `json:"protocol" yaml:"protocol"`
 	VirtualHosts []dxgateVirtualHost `json:"virtual_hosts" yaml:"virtual_hosts"`
 	TLSSecret    *string             `json:"tls_secret,omitempty" yaml:"tls_secret,omitempty"`
+	ExtAuthz     *dxgateExtAuthz     `json:"ext_authz,omitempty" yaml:"ext_authz,omitempty"`
 }
 
 type dxgateVirtualHost struct {
@@ -643,11 +645,28 @@ type dxgateRoute struct {
 	Name             string                  `json:"name" yaml:"name"`
 	Matches          []dxgateRouteMatch      `json:"matches" yaml:"matches"`
 	WeightedClusters []dxgateWeightedCluster `json:"weighted_clusters" yaml:"weighted_clusters"`
+	RequestMirrors   []dxgateRequestMirror   `json:"request_mirrors,omitempty" yaml:"request_mirrors,omitempty"`
+}
+
+// dxgateRequestMirror shadows numerator out of every denominator requests,
+// keeping the HTTPRoute's own fraction so shares under one percent survive.
+type dxgateRequestMirror struct {
+	Cluster     string `json:"cluster" yaml:"cluster"`
+	Numerator   uint32 `json:"numerator" yaml:"numerator"`
+	Denominator uint32 `json:"denominator" yaml:"denominator"`
 }
 
 type dxgateRouteMatch struct {
 	Path    dxgatePathMatch     `json:"path" yaml:"path"`
 	Headers []dxgateHeaderMatch `json:"headers" yaml:"headers"`
+	// Claims match on claims of the token verified by the gateway, written as
+	// x-jwt-claim-<claim> header matches in the HTTPRoute.
+	Claims []dxgateClaimMatch `json:"claims,omitempty" yaml:"claims,omitempty"`
+}
+
+type dxgateClaimMatch struct {
+	Claim string `json:"claim" yaml:"claim"`
+	Value string `json:"value" yaml:"value"`
 }
 
 type dxgatePathMatch struct {
@@ -720,11 +739,21 @@ func (d *DeploymentController) activationControlPlane() string {
 		return ""
 	}
 	return net.JoinHostPort(
-		fmt.Sprintf("dubbod-activation-replicas.%s.svc.%s", d.systemNamespace, d.domainSuffix()),
+		fmt.Sprintf("%s.%s.svc.%s", d.revisionedName("dubbod-activation-replicas"), d.systemNamespace, d.domainSuffix()),
 		strconv.Itoa(features.ActivationDemandPort),
 	)
 }
 
+// revisionedName returns the name a control plane resource is installed under
+// for this controller's revision. Only the default revision keeps the bare
+// name, so a canary's gateways connect to the canary and not the stable dubbod.
+func (d *DeploymentController) revisionedName(name string) string {
+	if d.revision == "" || d.revision == "default" {
+		return name
+	}
+	return name + "-" + d.revision
+}
+
 func (d *DeploymentController) domainSuffix() string {
 	if d.env != nil && d.env.DomainSuffix != "" {
 		return d.env.DomainSuffix
@@ -737,7 +766,7 @@ func (d *DeploymentController) buildDxgateBootstrapConfig(gw gateway.Gateway, se
 	if systemNamespace == "" {
 		systemNamespace = constants.DubboSystemNamespace
 	}
-	xdsAddress := fmt.Sprintf("http://dubbod.%s.svc:26010", systemNamespace)
+	xdsAddress := fmt.Sprintf("http://%s.%s.svc:26010", d.revisionedName("dubbod"), systemNamespace)
 	if gw.Annotations[xdsAddressAnnotation] != "" {
 		xdsAddress = gw.Annotations[xdsAddressAnnotation]
 	}
@@ -795,7 +824,7 @@ func formatGatewayServicePorts(ports []corev1.ServicePort) string {
 	return strings.Join(out, ",")
 }
 
-func buildDxgateRuntimeConfig(gw gateway.Gateway, routes []*gateway.HTTPRoute, services []*corev1.Service, backendTLSPolicies []*gateway.BackendTLSPolicy, policies []config.Config, domainSuffix string) (string, string, error) {
+func buildDxgateRuntimeConfig(gw gateway.Gateway, routes []*gateway.HTTPRoute, grpcRoutes []*gateway.GRPCRoute, services []*corev1.Service, backendTLSPolicies []*gateway.BackendTLSPolicy, policies []config.Config, extAuthz *dxgateExtAuthz, domainSuffix string) (string, string, error) {
 	if domainSuffix == "" {
 		domainSuffix = constants.DefaultClusterLocalDomain
 	}
@@ -809,9 +838,15 @@ func buildDxgateRuntimeConfig(gw gateway.Gateway, routes []*gateway.HTTPRoute, s
 		}
 		return routes[i].Name < routes[j].Name
 	})
+	sort.Slice(grpcRoutes, func(i, j int) bool {
+		if grpcRoutes[i].Namespace != grpcRoutes[j].Namespace {
+			return grpcRoutes[i].Namespace < grpcRoutes[j].Namespace
+		}
+		return grpcRoutes[i].Name < grpcRoutes[j].Name
+	})
 
 	cfg := dxgateRuntimeConfig{
-		Version: dxgateRuntimeVersion(gw, routes),
+		Version: dxgateRuntimeVersion(gw, routes, grpcRoutes),
 		Listeners: []dxgateListener{
 			{
 				Name:         "http-80",
@@ -825,13 +860,9 @@ func buildDxgateRuntimeConfig(gw gateway.Gateway, routes []*gateway.HTTPRoute, s
 	}
 
 	clusterNames := map[string]struct{}{}
-	for _, hr := range routes {
-		if !httpRouteReferencesGateway(hr, &gw) {
-			continue
-		}
-		vh, clusters := buildDxgateVirtualHost(gw, hr, servicesByKey, backendTLS, circuitBreakers, domainSuffix)
+	addVirtualHost := func(vh dxgateVirtualHost, clusters []dxgateCluster) {
 		if len(vh.Routes) == 0 {
-			continue
+			return
 		}
 		cfg.Listeners[0].VirtualHosts = append(cfg.Listeners[0].VirtualHosts, vh)
 		for _, cluster := range clusters {
@@ -842,6 +873,29 @@ func buildDxgateRuntimeConfig(gw gateway.Gateway, routes []*gateway.HTTPRoute, s
 			cfg.Clusters = append(cfg.Clusters, cluster)
 		}
 	}
+	// GRPCRoutes match on the method path, so their virtual hosts go first
+	// to keep HTTPRoute prefix matches on the same domains from shadowing
+	// them.
+	for _, gr := range grpcRoutes {
+		if grpcRouteReferencesGateway(gr, &gw) {
+			addVirtualHost(buildDxgateGRPCVirtualHost(gw, gr, servicesByKey, backendTLS, circuitBreakers, domainSuffix))
+		}
+	}
+	for _, hr := range routes {
+		if httpRouteReferencesGateway(hr, &gw) {
+			addVirtualHost(buildDxgateVirtualHost(gw, hr, servicesByKey, backendTLS, circuitBreakers, domainSuffix))
+		}
+	}
+
+	if extAuthz != nil {
+		cfg.Listeners[0].ExtAuthz = extAuthz
+		if cluster := dxgateExtAuthzCluster(extAuthz); cluster != nil {
+			if _, found := clusterNames[cluster.Name]; !found {
+				clusterNames[cluster.Name] = struct{}{}
+				cfg.Clusters = append(cfg.Clusters, *cluster)
+			}
+		}
+	}
 
 	rendered, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -851,7 +905,7 @@ func buildDxgateRuntimeConfig(gw gateway.Gateway, routes []*gateway.HTTPRoute, s
 	return string(rendered), hex.EncodeToString(sum[:]), nil
 }
 
-func dxgateRuntimeVersion(gw gateway.Gateway, routes []*gateway.HTTPRoute) string {
+func dxgateRuntimeVersion(gw gateway.Gateway, routes []*gateway.HTTPRoute, grpcRoutes []*gateway.GRPCRoute) string {
 	parts := []string{
 		fmt.Sprintf("gateway/%s/%s/%s", gw.Namespace, gw.Name, gw.ResourceVersion),
 	}
@@ -860,72 +914,179 @@ func dxgateRuntimeVersion(gw gateway.Gateway, routes []*gateway.HTTPRoute) strin
 			parts = append(parts, fmt.Sprintf("httproute/%s/%s/%s", hr.Namespace, hr.Name, hr.ResourceVersion))
 		}
 	}
+	for _, gr := range grpcRoutes {
+		if grpcRouteReferencesGateway(gr, &gw) {
+			parts = append(parts, fmt.Sprintf("grpcroute/%s/%s/%s", gr.Namespace, gr.Name, gr.ResourceVersion))
+		}
+	}
 	return strings.Join(parts, ";")
 }
 
 func buildDxgateVirtualHost(gw gateway.Gateway, hr *gateway.HTTPRoute, services map[string]*corev1.Service, backendTLS map[string]*dxgateUpstreamTLS, circuitBreakers map[string]dxgateBackendCircuitBreaker, domainSuffix string) (dxgateVirtualHost, []dxgateCluster) {
 	vh := dxgateVirtualHost{
 		Name:    fmt.Sprintf("%s-%s", hr.Namespace, hr.Name),
-		Domains: dxgateRouteDomains(gw, hr),
+		Domains: dxgateRouteDomains(gw, hr.Spec.Hostnames),
 		Routes:  []dxgateRoute{},
 	}
 	clusters := []dxgateCluster{}
 
 	for ruleIdx, rule := range hr.Spec.Rules {
-		for matchIdx, match := range dxgateMatches(rule.Matches) {
-			route := dxgateRoute{
-				Name:             fmt.Sprintf("%s-%s-%d-%d", hr.Namespace, hr.Name, ruleIdx, matchIdx),
-				Matches:          []dxgateRouteMatch{match},
-				WeightedClusters: []dxgateWeightedCluster{},
-			}
-			for backendIdx, backendRef := range rule.BackendRefs {
-				if !isServiceBackend(backendRef) || backendRef.Port == nil {
-					continue
-				}
-				weight := uint32(1)
-				if backendRef.Weight != nil {
-					if *backendRef.Weight == 0 {
-						continue
-					}
-					weight = uint32(*backendRef.Weight)
-				}
-
-				backendNamespace := hr.Namespace
-				if backendRef.Namespace != nil {
-					backendNamespace = string(*backendRef.Namespace)
-				}
-				port := uint16(*backendRef.Port)
-				clusterName := fmt.Sprintf("%s-%s-%d-%d", hr.Namespace, hr.Name, ruleIdx, backendIdx)
-				backendKey := namespacedServiceKey(backendNamespace, string(backendRef.Name))
-				policy := circuitBreakers[backendKey]
-				upstreamTLS := backendTLS[backendKey]
-
-				route.WeightedClusters = append(route.WeightedClusters, dxgateWeightedCluster{
-					Name:   clusterName,
-					Weight: weight,
-				})
-				clusters = append(clusters, dxgateCluster{
-					Name:           clusterName,
-					TLS:            upstreamTLS,
-					CircuitBreaker: policy.CircuitBreaker,
-					Outlier:        policy.Outlier,
-					Endpoints: []dxgateEndpoint{
-						{
-							Address: dxgateBackendAddress(backendNamespace, string(backendRef.Name), domainSuffix, services),
-							Port:    port,
-							Healthy: true,
-						},
-					},
-				})
+		backendRefs := make([]gateway.BackendRef, 0, len(rule.BackendRefs))
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		mirrors := make([]*gateway.HTTPRequestMirrorFilter, len(rule.Filters))
+		for filterIdx, filter := range rule.Filters {
+			if filter.Type == gateway.HTTPRouteFilterRequestMirror {
+				mirrors[filterIdx] = filter.RequestMirror
 			}
-			if len(route.WeightedClusters) > 0 {
-				vh.Routes = append(vh.Routes, route)
+		}
+		routes, ruleClusters := buildDxgateRuleRoutes(vh.Name, hr.Namespace, ruleIdx, dxgateMatches(rule.Matches), backendRefs, mirrors, services, backendTLS, circuitBreakers, domainSuffix)
+		vh.Routes = append(vh.Routes, routes...)
+		clusters = append(clusters, ruleClusters...)
+	}
+	return vh, clusters
+}
+
+// buildDxgateGRPCVirtualHost is buildDxgateVirtualHost for a GRPCRoute. Its
+// names start with "grpc/" so they never collide with those of an HTTPRoute.
+func buildDxgateGRPCVirtualHost(gw gateway.Gateway, gr *gateway.GRPCRoute, services map[string]*corev1.Service, backendTLS map[string]*dxgateUpstreamTLS, circuitBreakers map[string]dxgateBackendCircuitBreaker, domainSuffix string) (dxgateVirtualHost, []dxgateCluster) {
+	vh := dxgateVirtualHost{
+		Name:    fmt.Sprintf("grpc/%s-%s", gr.Namespace, gr.Name),
+		Domains: dxgateRouteDomains(gw, gr.Spec.Hostnames),
+		Routes:  []dxgateRoute{},
+	}
+	clusters := []dxgateCluster{}
+
+	for ruleIdx, rule := range gr.Spec.Rules {
+		backendRefs := make([]gateway.BackendRef, 0, len(rule.BackendRefs))
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		mirrors := make([]*gateway.HTTPRequestMirrorFilter, len(rule.Filters))
+		for filterIdx, filter := range rule.Filters {
+			if filter.Type == gateway.GRPCRouteFilterRequestMirror {
+				mirrors[filterIdx] = filter.RequestMirror
 			}
 		}
+		routes, ruleClusters := buildDxgateRuleRoutes(vh.Name, gr.Namespace, ruleIdx, dxgateGRPCMatches(rule.Matches), backendRefs, mirrors, services, backendTLS, circuitBreakers, domainSuffix)
+		vh.Routes = append(vh.Routes, routes...)
+		clusters = append(clusters, ruleClusters...)
 	}
 	return vh, clusters
 }
 
+// buildDxgateRuleRoutes builds one route per match of a route rule, all
+// sending to the rule's backends. Routes and clusters are named after
+// namePrefix, the name of the route's virtual host.
+func buildDxgateRuleRoutes(namePrefix, routeNamespace string, ruleIdx int, matches []dxgateRouteMatch, backendRefs []gateway.BackendRef, mirrorFilters []*gateway.HTTPRequestMirrorFilter, services map[string]*corev1.Service, backendTLS map[string]*dxgateUpstreamTLS, circuitBreakers map[string]dxgateBackendCircuitBreaker, domainSuffix string) ([]dxgateRoute, []dxgateCluster) {
+	weighted := []dxgateWeightedCluster{}
+	clusters := []dxgateCluster{}
+	for backendIdx, backendRef := range backendRefs {
+		if !isServiceBackendObjectReference(backendRef.BackendObjectReference) || backendRef.Port == nil {
+			continue
+		}
+		weight := uint32(1)
+		if backendRef.Weight != nil {
+			if *backendRef.Weight == 0 {
+				continue
+			}
+			weight = uint32(*backendRef.Weight)
+		}
+
+		backendNamespace := routeNamespace
+		if backendRef.Namespace != nil {
+			backendNamespace = string(*backendRef.Namespace)
+		}
+		port := uint16(*backendRef.Port)
+		clusterName := fmt.Sprintf("%s-%d-%d", namePrefix, ruleIdx, backendIdx)
+		backendKey := namespacedServiceKey(backendNamespace, string(backendRef.Name))
+		policy := circuitBreakers[backendKey]
+		upstreamTLS := backendTLS[backendKey]
+
+		weighted = append(weighted, dxgateWeightedCluster{
+			Name:   clusterName,
+			Weight: weight,
+		})
+		clusters = append(clusters, dxgateCluster{
+			Name:           clusterName,
+			TLS:            upstreamTLS,
+			CircuitBreaker: policy.CircuitBreaker,
+			Outlier:        policy.Outlier,
+			Endpoints: []dxgateEndpoint{
+				{
+					Address: dxgateBackendAddress(backendNamespace, string(backendRef.Name), domainSuffix, services),
+					Port:    port,
+					Healthy: true,
+				},
+			},
+		})
+	}
+	if len(weighted) == 0 {
+		return nil, nil
+	}
+	mirrors, mirrorClusters := dxgateRequestMirrors(namePrefix, routeNamespace, ruleIdx, mirrorFilters, services, domainSuffix)
+	clusters = append(clusters, mirrorClusters...)
+
+	routes := make([]dxgateRoute, 0, len(matches))
+	for matchIdx, match := range matches {
+		routes = append(routes, dxgateRoute{
+			Name:             fmt.Sprintf("%s-%d-%d", namePrefix, ruleIdx, matchIdx),
+			Matches:          []dxgateRouteMatch{match},
+			WeightedClusters: weighted,
+			RequestMirrors:   mirrors,
+		})
+	}
+	return routes, clusters
+}
+
+// dxgateRequestMirrors shadows a share of the rule's traffic to the mirror
+// backend. dxgate discards mirrored responses, so callers never observe them.
+// Mirrors to unknown Services are dropped and surface in the route status.
+// filters holds the rule's RequestMirror filters at their index among all its
+// filters, and nil for filters of any other type.
+func dxgateRequestMirrors(namePrefix, routeNamespace string, ruleIdx int, filters []*gateway.HTTPRequestMirrorFilter, services map[string]*corev1.Service, domainSuffix string) ([]dxgateRequestMirror, []dxgateCluster) {
+	var mirrors []dxgateRequestMirror
+	var clusters []dxgateCluster
+	for filterIdx, filter := range filters {
+		if filter == nil {
+			continue
+		}
+		backendRef := filter.BackendRef
+		if !isServiceBackendObjectReference(backendRef) || backendRef.Port == nil {
+			continue
+		}
+		backendNamespace := routeNamespace
+		if backendRef.Namespace != nil {
+			backendNamespace = string(*backendRef.Namespace)
+		}
+		if services[namespacedServiceKey(backendNamespace, string(backendRef.Name))] == nil {
+			continue
+		}
+		numerator, denominator := model.RequestMirrorFraction(filter)
+		if numerator == 0 {
+			continue
+		}
+		clusterName := fmt.Sprintf("%s-%d-mirror-%d", namePrefix, ruleIdx, filterIdx)
+		mirrors = append(mirrors, dxgateRequestMirror{
+			Cluster:     clusterName,
+			Numerator:   numerator,
+			Denominator: denominator,
+		})
+		clusters = append(clusters, dxgateCluster{
+			Name: clusterName,
+			Endpoints: []dxgateEndpoint{
+				{
+					Address: dxgateBackendAddress(backendNamespace, string(backendRef.Name), domainSuffix, services),
+					Port:    uint16(*backendRef.Port),
+					Healthy: true,
+				},
+			},
+		})
+	}
+	return mirrors, clusters
+}
+
 func servicesByNamespacedName(services []*corev1.Service) map[string]*corev1.Service {
 	out := map[string]*corev1.Service{}
 	for _, svc := range services {
@@ -1094,9 +1255,9 @@ func namespacedServiceKey(namespace, name string) string {
 	return namespace + "/" + name
 }
 
-func dxgateRouteDomains(gw gateway.Gateway, hr *gateway.HTTPRoute) []string {
+func dxgateRouteDomains(gw gateway.Gateway, hostnames []gateway.Hostname) []string {
 	domains := map[string]struct{}{}
-	for _, hostname := range hr.Spec.Hostnames {
+	for _, hostname := range hostnames {
 		if hostname != "" {
 			domains[string(hostname)] = struct{}{}
 		}
@@ -1126,9 +1287,11 @@ func dxgateMatches(matches []gateway.HTTPRouteMatch) []dxgateRouteMatch {
 	}
 	out := make([]dxgateRouteMatch, 0, len(matches))
 	for _, match := range matches {
+		headers, claims := dxgateHeaders(match.Headers)
 		out = append(out, dxgateRouteMatch{
 			Path:    dxgatePath(match.Path),
-			Headers: dxgateHeaders(match.Headers),
+			Headers: headers,
+			Claims:  claims,
 		})
 	}
 	return out
@@ -1158,32 +1321,120 @@ func dxgatePath(path *gateway.HTTPPathMatch) dxgatePathMatch {
 	return dxgatePathMatch{Type: "prefix", Value: value}
 }
 
-func dxgateHeaders(headers []gateway.HTTPHeaderMatch) []dxgateHeaderMatch {
+func dxgateHeaders(headers []gateway.HTTPHeaderMatch) ([]dxgateHeaderMatch, []dxgateClaimMatch) {
 	out := make([]dxgateHeaderMatch, 0, len(headers))
+	var claims []dxgateClaimMatch
 	for _, header := range headers {
 		if header.Type != nil && *header.Type != gateway.HeaderMatchExact {
 			continue
 		}
+		if claim, ok := securityconfig.ClaimFromRouteHeader(string(header.Name)); ok {
+			claims = append(claims, dxgateClaimMatch{Claim: claim, Value: header.Value})
+			continue
+		}
 		out = append(out, dxgateHeaderMatch{
 			Name:  string(header.Name),
 			Value: header.Value,
 		})
 	}
+	return out, claims
+}
+
+// dxgateGRPCMatches compiles GRPCRoute matches the way dxgateMatches does
+// HTTPRoute ones. gRPC requests carry "/<service>/<method>" as the path, so
+// method matches become path matches; matches dxgate cannot express as an
+// exact or prefix path are left out rather than widened.
+func dxgateGRPCMatches(matches []gateway.GRPCRouteMatch) []dxgateRouteMatch {
+	if len(matches) == 0 {
+		return []dxgateRouteMatch{defaultDxgateRouteMatch()}
+	}
+	out := make([]dxgateRouteMatch, 0, len(matches))
+	for _, match := range matches {
+		path, ok := dxgateGRPCMethodPath(match.Method)
+		if !ok {
+			continue
+		}
+		headers, claims := dxgateGRPCHeaders(match.Headers)
+		out = append(out, dxgateRouteMatch{
+			Path:    path,
+			Headers: headers,
+			Claims:  claims,
+		})
+	}
 	return out
 }
 
+func dxgateGRPCMethodPath(method *gateway.GRPCMethodMatch) (dxgatePathMatch, bool) {
+	if method == nil {
+		return defaultDxgateRouteMatch().Path, true
+	}
+	if method.Type != nil && *method.Type == gateway.GRPCMethodMatchRegularExpression {
+		return dxgatePathMatch{}, false
+	}
+	service, name := "", ""
+	if method.Service != nil {
+		service = *method.Service
+	}
+	if method.Method != nil {
+		name = *method.Method
+	}
+	switch {
+	case service != "" && name != "":
+		return dxgatePathMatch{Type: "exact", Value: "/" + service + "/" + name}, true
+	case service != "":
+		return dxgatePathMatch{Type: "prefix", Value: "/" + service + "/"}, true
+	case name != "":
+		return dxgatePathMatch{}, false
+	default:
+		return defaultDxgateRouteMatch().Path, true
+	}
+}
+
+func dxgateGRPCHeaders(headers []gateway.GRPCHeaderMatch) ([]dxgateHeaderMatch, []dxgateClaimMatch) {
+	out := make([]dxgateHeaderMatch, 0, len(headers))
+	var claims []dxgateClaimMatch
+	for _, header := range headers {
+		if header.Type != nil && *header.Type != gateway.GRPCHeaderMatchExact {
+			continue
+		}
+		if claim, ok := securityconfig.ClaimFromRouteHeader(string(header.Name)); ok {
+			claims = append(claims, dxgateClaimMatch{Claim: claim, Value: header.Value})
+			continue
+		}
+		out = append(out, dxgateHeaderMatch{
+			Name:  string(header.Name),
+			Value: header.Value,
+		})
+	}
+	return out, claims
+}
+
 func httpRouteReferencesGateway(hr *gateway.HTTPRoute, gw *gateway.Gateway) bool {
-	if hr == nil || gw == nil {
+	if hr == nil {
+		return false
+	}
+	return routeReferencesGateway(hr.Namespace, hr.Spec.ParentRefs, gw)
+}
+
+func grpcRouteReferencesGateway(gr *gateway.GRPCRoute, gw *gateway.Gateway) bool {
+	if gr == nil {
+		return false
+	}
+	return routeReferencesGateway(gr.Namespace, gr.Spec.ParentRefs, gw)
+}
+
+func routeReferencesGateway(routeNamespace string, parentRefs []gateway.ParentReference, gw *gateway.Gateway) bool {
+	if gw == nil {
 		return false
 	}
-	for _, parentRef := range hr.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		if parentRef.Group != nil && string(*parentRef.Group) != gateway.GroupName {
 			continue
 		}
 		if parentRef.Kind != nil && string(*parentRef.Kind) != "Gateway" {
 			continue
 		}
-		namespace := hr.Namespace
+		namespace := routeNamespace
 		if parentRef.Namespace != nil {
 			namespace = string(*parentRef.Namespace)
 		}
@@ -1214,7 +1465,7 @@ func parentRefMatchesHTTPListener(parentRef gateway.ParentReference, gw *gateway
 	return false
 }
 
-func isServiceBackend(ref gateway.HTTPBackendRef) bool {
+func isServiceBackendObjectReference(ref gateway.BackendObjectReference) bool {
 	if ref.Group != nil && string(*ref.Group) != "" {
 		return false
 	}
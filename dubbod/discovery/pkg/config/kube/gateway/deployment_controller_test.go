@@ -30,10 +30,12 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
 	"github.com/google/go-cmp/cmp"
 	networking "github.com/kdubbo/api/networking/v1alpha3"
+	security "github.com/kdubbo/api/security/v1alpha3"
 	apitelemetry "github.com/kdubbo/api/telemetry/v1alpha3"
 	typeapi "github.com/kdubbo/api/type/v1alpha3"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -125,7 +127,7 @@ func TestBuildDxgateRuntimeConfigFromHTTPRoute(t *testing.T) {
 		},
 	}
 
-	raw, hash, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, nil, nil, "cluster.local")
+	raw, hash, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, nil, nil, nil, nil, "cluster.local")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -162,6 +164,110 @@ func TestBuildDxgateRuntimeConfigFromHTTPRoute(t *testing.T) {
 	}
 }
 
+func TestDxgateMatchesSplitsJWTClaimMatches(t *testing.T) {
+	regex := gatewayv1.HeaderMatchRegularExpression
+	matches := dxgateMatches([]gatewayv1.HTTPRouteMatch{{
+		Headers: []gatewayv1.HTTPHeaderMatch{
+			{Name: "x-env", Value: "prod"},
+			{Name: "X-JWT-Claim-plan", Value: "premium"},
+			{Type: &regex, Name: "x-jwt-claim-tenant", Value: "acme-.*"},
+		},
+	}})
+	if diff := cmp.Diff([]dxgateHeaderMatch{{Name: "x-env", Value: "prod"}}, matches[0].Headers); diff != "" {
+		t.Fatalf("unexpected header matches (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]dxgateClaimMatch{{Claim: "plan", Value: "premium"}}, matches[0].Claims); diff != "" {
+		t.Fatalf("unexpected claim matches (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildDxgateRuntimeConfigFromGRPCRoute(t *testing.T) {
+	backendPort := gatewayv1.PortNumber(50051)
+	regex := gatewayv1.GRPCMethodMatchRegularExpression
+	service := "org.apache.dubbo.Greeter"
+	method := "SayHello"
+	gw := gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "app", ResourceVersion: "10"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "dubbo",
+			Listeners:        []gatewayv1.Listener{{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80}},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeter", Namespace: "app", ResourceVersion: "20"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "public"}}},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: "web", Port: &backendPort},
+				}}},
+			}},
+		},
+	}
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeter", Namespace: "app", ResourceVersion: "30"},
+		Spec: gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "public"}}},
+			Rules: []gatewayv1.GRPCRouteRule{{
+				Matches: []gatewayv1.GRPCRouteMatch{
+					{
+						Method:  &gatewayv1.GRPCMethodMatch{Service: &service, Method: &method},
+						Headers: []gatewayv1.GRPCHeaderMatch{{Name: "X-JWT-Claim-plan", Value: "gold"}},
+					},
+					{Method: &gatewayv1.GRPCMethodMatch{Service: &service}},
+					{Method: &gatewayv1.GRPCMethodMatch{Type: &regex, Service: &service}},
+				},
+				BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter", Port: &backendPort},
+				}}},
+			}},
+		},
+	}
+
+	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{httpRoute}, []*gatewayv1.GRPCRoute{grpcRoute}, nil, nil, nil, nil, "cluster.local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg dxgateRuntimeConfig
+	if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(cfg.Version, "grpcroute/app/greeter/30") {
+		t.Fatalf("version = %q, want the GRPCRoute resource version", cfg.Version)
+	}
+	vhs := cfg.Listeners[0].VirtualHosts
+	if len(vhs) != 2 || vhs[0].Name != "grpc/app-greeter" || vhs[1].Name != "app-greeter" {
+		t.Fatalf("virtual hosts = %#v, want the GRPCRoute ahead of the HTTPRoute", vhs)
+	}
+	routes := vhs[0].Routes
+	if len(routes) != 2 {
+		t.Fatalf("routes = %#v, want the exact and prefix method matches only", routes)
+	}
+	if diff := cmp.Diff([]dxgateRouteMatch{{
+		Path:    dxgatePathMatch{Type: "exact", Value: "/org.apache.dubbo.Greeter/SayHello"},
+		Headers: []dxgateHeaderMatch{},
+		Claims:  []dxgateClaimMatch{{Claim: "plan", Value: "gold"}},
+	}}, routes[0].Matches); diff != "" {
+		t.Fatalf("unexpected first match (-want +got):\n%s", diff)
+	}
+	if got := routes[1].Matches[0].Path; got != (dxgatePathMatch{Type: "prefix", Value: "/org.apache.dubbo.Greeter/"}) {
+		t.Fatalf("unexpected second path match: %#v", got)
+	}
+	if diff := cmp.Diff([]dxgateWeightedCluster{{Name: "grpc/app-greeter-0-0", Weight: 1}}, routes[0].WeightedClusters); diff != "" {
+		t.Fatalf("unexpected weighted clusters (-want +got):\n%s", diff)
+	}
+	clusters := map[string]string{}
+	for _, cluster := range cfg.Clusters {
+		clusters[cluster.Name] = cluster.Endpoints[0].Address
+	}
+	if diff := cmp.Diff(map[string]string{
+		"grpc/app-greeter-0-0": "greeter.app.svc.cluster.local",
+		"app-greeter-0-0":      "web.app.svc.cluster.local",
+	}, clusters); diff != "" {
+		t.Fatalf("unexpected clusters (-want +got):\n%s", diff)
+	}
+}
+
 func TestBuildDxgateRuntimeConfigAppliesCircuitBreakerPolicy(t *testing.T) {
 	backendPort := gatewayv1.PortNumber(9080)
 	gw := gatewayv1.Gateway{
@@ -223,7 +329,7 @@ func TestBuildDxgateRuntimeConfigAppliesCircuitBreakerPolicy(t *testing.T) {
 		},
 	}
 
-	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, nil, policies, "cluster.local")
+	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, nil, nil, policies, nil, "cluster.local")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -281,7 +387,7 @@ func TestBuildDxgateRuntimeConfigFiltersUnattachedHTTPRoutes(t *testing.T) {
 		},
 	}
 
-	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, nil, nil, "cluster.local")
+	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, nil, nil, nil, nil, "cluster.local")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -294,6 +400,134 @@ func TestBuildDxgateRuntimeConfigFiltersUnattachedHTTPRoutes(t *testing.T) {
 	}
 }
 
+func TestBuildDxgateRuntimeConfigRendersExtAuthz(t *testing.T) {
+	gw := gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "app"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "dubbo",
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+			},
+		},
+	}
+	extAuthz := &dxgateExtAuthz{
+		Provider:  "opa",
+		Cluster:   "ext-authz-opa",
+		Authority: "opa.policy.svc.cluster.local",
+		Port:      9191,
+		Timeout:   "1s",
+		Rules:     []dxgateAuthzRule{{}},
+	}
+
+	raw, _, err := buildDxgateRuntimeConfig(gw, nil, nil, nil, nil, nil, extAuthz, "cluster.local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg dxgateRuntimeConfig
+	if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Listeners[0].ExtAuthz; got == nil || got.Cluster != "ext-authz-opa" || got.FailureModeAllow {
+		t.Fatalf("listener ext_authz = %#v", got)
+	}
+	if len(cfg.Clusters) != 1 || cfg.Clusters[0].Name != "ext-authz-opa" ||
+		cfg.Clusters[0].Endpoints[0].Address != "opa.policy.svc.cluster.local" || cfg.Clusters[0].Endpoints[0].Port != 9191 {
+		t.Fatalf("clusters = %#v, want the ext_authz provider cluster", cfg.Clusters)
+	}
+}
+
+func TestDxgateExtAuthzDeniesWithoutProvider(t *testing.T) {
+	custom := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.AuthorizationPolicy,
+			Name:             "opa-check",
+			Namespace:        "app",
+		},
+		Spec: &security.AuthorizationPolicy{
+			Action: security.AuthorizationPolicy_CUSTOM,
+			ActionDetail: &security.AuthorizationPolicy_Provider{
+				Provider: &security.AuthorizationPolicy_ExtensionProvider{Name: "opa"},
+			},
+			Rules: []*security.Rule{{When: []*security.Condition{{
+				Key:    "request.auth.claims[groups]",
+				Values: []string{"contractors"},
+			}}}},
+		},
+	}
+	got := dxgateExtAuthzFromPolicies(nil, []config.Config{custom})
+	if got == nil || !got.Deny || got.Cluster != "" || len(got.Rules) != 1 {
+		t.Fatalf("ext_authz = %#v, want a deny without cluster", got)
+	}
+	if dxgateExtAuthzCluster(got) != nil {
+		t.Fatal("a denying ext_authz must not add a cluster")
+	}
+}
+
+func TestBuildDxgateRuntimeConfigAppliesRequestMirror(t *testing.T) {
+	backendPort := gatewayv1.PortNumber(8080)
+	percent := int32(10)
+	gw := gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "app"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "dubbo",
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+			},
+		},
+	}
+	mirrorFilter := func(name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+				BackendRef: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(name), Port: &backendPort},
+				Percent:    &percent,
+			},
+		}
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "app"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "public"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Filters: []gatewayv1.HTTPRouteFilter{mirrorFilter("orders-v2"), mirrorFilter("missing")},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1.BackendRef{
+								BackendObjectReference: gatewayv1.BackendObjectReference{Name: "orders-v1", Port: &backendPort},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	services := []*corev1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "orders-v1", Namespace: "app"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "orders-v2", Namespace: "app"}},
+	}
+
+	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, services, nil, nil, nil, "cluster.local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg dxgateRuntimeConfig
+	if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	routeCfg := cfg.Listeners[0].VirtualHosts[0].Routes[0]
+	if diff := cmp.Diff([]dxgateRequestMirror{
+		{Cluster: "app-orders-0-mirror-0", Numerator: 10, Denominator: 100},
+	}, routeCfg.RequestMirrors); diff != "" {
+		t.Fatalf("unexpected request mirrors (-want +got):\n%s", diff)
+	}
+	if len(cfg.Clusters) != 2 || cfg.Clusters[1].Endpoints[0].Address != "orders-v2.app.svc.cluster.local" {
+		t.Fatalf("unexpected clusters: %#v", cfg.Clusters)
+	}
+}
+
 func TestBuildDxgateRuntimeConfigExternalNameBackendTLS(t *testing.T) {
 	backendPort := gatewayv1.PortNumber(443)
 	hostname := gatewayv1.Hostname("httpbin-egress.app.svc.cluster.local")
@@ -361,7 +595,7 @@ func TestBuildDxgateRuntimeConfigExternalNameBackendTLS(t *testing.T) {
 		},
 	}
 
-	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, []*corev1.Service{service}, []*gatewayv1.BackendTLSPolicy{policy}, nil, "cluster.local")
+	raw, _, err := buildDxgateRuntimeConfig(gw, []*gatewayv1.HTTPRoute{route}, nil, []*corev1.Service{service}, []*gatewayv1.BackendTLSPolicy{policy}, nil, nil, "cluster.local")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -914,6 +1148,37 @@ func TestActivationControlPlaneResolvesHeadlessService(t *testing.T) {
 	}
 }
 
+func TestActivationControlPlaneUsesRevisionedService(t *testing.T) {
+	controller := &DeploymentController{systemNamespace: "dubbo-system", revision: "canary"}
+	got := controller.activationControlPlane()
+	want := "dubbod-activation-replicas-canary.dubbo-system.svc.cluster.local:26030"
+	if got != want {
+		t.Fatalf("activation control plane = %q, want %q", got, want)
+	}
+}
+
+func TestRevisionTagsIncludesTagsPointingAtRevision(t *testing.T) {
+	webhook := func(tag, rev string) *admissionregistrationv1.MutatingWebhookConfiguration {
+		return &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Name:   "dubbo-revision-tag-" + tag,
+			Labels: map[string]string{revisionTagLabel: tag, revisionLabel: rev},
+		}}
+	}
+	got := revisionTags("1-1", []*admissionregistrationv1.MutatingWebhookConfiguration{
+		webhook("stable", "1-0"),
+		webhook("canary", "1-1"),
+		webhook("prod", "1-1"),
+	})
+	for _, want := range []string{"1-1", "canary", "prod"} {
+		if !got.Contains(want) {
+			t.Fatalf("tags = %v, want %q", got, want)
+		}
+	}
+	if got.Contains("stable") {
+		t.Fatalf("tags = %v, stable points at another revision", got)
+	}
+}
+
 func TestKubeGatewayTemplateRendersActivationEnv(t *testing.T) {
 	templatePath := filepath.Join("..", "..", "..", "..", "..", "..", "manifests", "charts", "dubbod", "files", "kube-gateway.yaml")
 	raw, err := os.ReadFile(templatePath)
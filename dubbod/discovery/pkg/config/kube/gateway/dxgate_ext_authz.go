@@ -0,0 +1,142 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	security "github.com/kdubbo/api/security/v1alpha3"
+)
+
+// dxgateExtAuthz checks the requests matching Rules against the provider of
+// the CUSTOM authorization policies selecting the gateway. Deny is set when
+// the provider cannot be resolved: matching requests are then rejected
+// without a check.
+type dxgateExtAuthz struct {
+	Provider                  string            `json:"provider" yaml:"provider"`
+	Cluster                   string            `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Authority                 string            `json:"authority,omitempty" yaml:"authority,omitempty"`
+	Port                      uint16            `json:"port,omitempty" yaml:"port,omitempty"`
+	PathPrefix                string            `json:"path_prefix,omitempty" yaml:"path_prefix,omitempty"`
+	Timeout                   string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	FailureModeAllow          bool              `json:"failure_mode_allow" yaml:"failure_mode_allow"`
+	StatusOnError             uint32            `json:"status_on_error,omitempty" yaml:"status_on_error,omitempty"`
+	AllowedHeaders            []string          `json:"allowed_headers,omitempty" yaml:"allowed_headers,omitempty"`
+	HeadersToUpstreamOnAllow  []string          `json:"headers_to_upstream_on_allow,omitempty" yaml:"headers_to_upstream_on_allow,omitempty"`
+	HeadersToDownstreamOnDeny []string          `json:"headers_to_downstream_on_deny,omitempty" yaml:"headers_to_downstream_on_deny,omitempty"`
+	Deny                      bool              `json:"deny,omitempty" yaml:"deny,omitempty"`
+	Rules                     []dxgateAuthzRule `json:"rules" yaml:"rules"`
+}
+
+// dxgateAuthzRule matches a request when any source matches and every
+// condition holds. A rule without sources or conditions matches everything.
+type dxgateAuthzRule struct {
+	Sources []dxgateAuthzSource    `json:"sources,omitempty" yaml:"sources,omitempty"`
+	When    []dxgateAuthzCondition `json:"when,omitempty" yaml:"when,omitempty"`
+}
+
+type dxgateAuthzSource struct {
+	Principals        []string `json:"principals,omitempty" yaml:"principals,omitempty"`
+	RequestPrincipals []string `json:"request_principals,omitempty" yaml:"request_principals,omitempty"`
+}
+
+type dxgateAuthzCondition struct {
+	Key       string   `json:"key" yaml:"key"`
+	Values    []string `json:"values,omitempty" yaml:"values,omitempty"`
+	NotValues []string `json:"not_values,omitempty" yaml:"not_values,omitempty"`
+}
+
+// dxgateExtAuthzFromPolicies compiles the CUSTOM authorization policies that
+// select the gateway's pods.
+func dxgateExtAuthzFromPolicies(push *model.PushContext, custom []config.Config) *dxgateExtAuthz {
+	var rules []dxgateAuthzRule
+	for _, cfg := range custom {
+		spec, ok := cfg.Spec.(*security.AuthorizationPolicy)
+		if !ok || spec == nil {
+			continue
+		}
+		for _, rule := range spec.GetRules() {
+			rules = append(rules, dxgateAuthzRuleFromAPI(push, rule))
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	provider, err := push.ExtAuthzProviderForPolicies(custom)
+	if err != nil {
+		name, _ := model.ExtAuthzProviderForPolicy(custom[0])
+		logger.Warnf("dxgate denies requests matched by CUSTOM authorization policies: %v", err)
+		return &dxgateExtAuthz{Provider: name, Deny: true, Rules: rules}
+	}
+	return &dxgateExtAuthz{
+		Provider:                  provider.Name,
+		Cluster:                   "ext-authz-" + provider.Name,
+		Authority:                 provider.Service,
+		Port:                      uint16(provider.Port),
+		PathPrefix:                provider.PathPrefix,
+		Timeout:                   provider.CheckTimeout().String(),
+		FailureModeAllow:          provider.FailOpen,
+		StatusOnError:             provider.ErrorStatus(),
+		AllowedHeaders:            provider.IncludeRequestHeadersInCheck,
+		HeadersToUpstreamOnAllow:  provider.HeadersToUpstreamOnAllow,
+		HeadersToDownstreamOnDeny: provider.HeadersToDownstreamOnDeny,
+		Rules:                     rules,
+	}
+}
+
+func dxgateAuthzRuleFromAPI(push *model.PushContext, rule *security.Rule) dxgateAuthzRule {
+	out := dxgateAuthzRule{}
+	for _, from := range rule.GetFrom() {
+		source := from.GetSource()
+		if source == nil {
+			out.Sources = append(out.Sources, dxgateAuthzSource{})
+			continue
+		}
+		out.Sources = append(out.Sources, dxgateAuthzSource{
+			Principals:        push.ExpandPrincipals(source.GetPrincipals()),
+			RequestPrincipals: source.GetRequestPrincipals(),
+		})
+	}
+	for _, condition := range rule.GetWhen() {
+		if condition == nil {
+			continue
+		}
+		key, values := securityconfig.TripleCondition(condition.GetKey(), condition.GetValues())
+		_, notValues := securityconfig.TripleCondition(condition.GetKey(), condition.GetNotValues())
+		out.When = append(out.When, dxgateAuthzCondition{
+			Key:       key,
+			Values:    values,
+			NotValues: notValues,
+		})
+	}
+	return out
+}
+
+func dxgateExtAuthzCluster(extAuthz *dxgateExtAuthz) *dxgateCluster {
+	if extAuthz.Cluster == "" {
+		return nil
+	}
+	return &dxgateCluster{
+		Name: extAuthz.Cluster,
+		Endpoints: []dxgateEndpoint{{
+			Address: extAuthz.Authority,
+			Port:    extAuthz.Port,
+			Healthy: true,
+		}},
+	}
+}
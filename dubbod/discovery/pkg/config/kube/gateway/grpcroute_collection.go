@@ -0,0 +1,77 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/krt"
+	corev1 "k8s.io/api/core/v1"
+	gateway "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func GRPCRoutesCollection(
+	grpcRoutes krt.Collection[*gateway.GRPCRoute],
+	gateways krt.Collection[*gateway.Gateway],
+	gatewayClasses krt.Collection[GatewayClass],
+	services krt.Collection[*corev1.Service],
+	opts krt.OptionsBuilder,
+) (
+	krt.StatusCollection[*gateway.GRPCRoute, gateway.GRPCRouteStatus],
+	krt.Collection[config.Config],
+) {
+	return krt.NewStatusCollection(grpcRoutes, func(ctx krt.HandlerContext, obj *gateway.GRPCRoute) (*gateway.GRPCRouteStatus, *config.Config) {
+		cfg := convertGRPCRouteToConfig(obj)
+		var mirrors []*gateway.HTTPRequestMirrorFilter
+		for _, rule := range obj.Spec.Rules {
+			for _, filter := range rule.Filters {
+				if filter.Type == gateway.GRPCRouteFilterRequestMirror && filter.RequestMirror != nil {
+					mirrors = append(mirrors, filter.RequestMirror)
+				}
+			}
+		}
+		status := obj.Status.DeepCopy()
+		status.RouteStatus = setRouteParentConditions(
+			status.RouteStatus,
+			obj.ObjectMeta,
+			obj.Spec.ParentRefs,
+			managedRouteParent(ctx, gateways, gatewayClasses, obj.Namespace),
+			missingMirrorBackends(obj.Namespace, mirrors, routeServiceExists(ctx, services)),
+			grpcRouteClaimMatches(obj.Spec.Rules),
+		)
+		return status, &cfg
+	}, opts.WithName("GRPCRoutes")...)
+}
+
+// grpcRouteClaimMatches names the matches of a route that match on a JWT
+// claim, as "rule[i].matches[j]".
+func grpcRouteClaimMatches(rules []gateway.GRPCRouteRule) []string {
+	var out []string
+	for ruleIdx, rule := range rules {
+		for matchIdx, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if _, ok := securityconfig.ClaimFromRouteHeader(string(header.Name)); ok {
+					out = append(out, fmt.Sprintf("rule[%d].matches[%d]", ruleIdx, matchIdx))
+					break
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,245 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/features"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model/kstatus"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/krt"
+	"github.com/apache/dubbo-kubernetes/pkg/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gateway "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func HTTPRoutesCollection(
+	httpRoutes krt.Collection[*gateway.HTTPRoute],
+	gateways krt.Collection[*gateway.Gateway],
+	gatewayClasses krt.Collection[GatewayClass],
+	services krt.Collection[*corev1.Service],
+	opts krt.OptionsBuilder,
+) (
+	krt.StatusCollection[*gateway.HTTPRoute, gateway.HTTPRouteStatus],
+	krt.Collection[config.Config],
+) {
+	return krt.NewStatusCollection(httpRoutes, func(ctx krt.HandlerContext, obj *gateway.HTTPRoute) (*gateway.HTTPRouteStatus, *config.Config) {
+		cfg := convertHTTPRouteToConfig(obj)
+		var mirrors []*gateway.HTTPRequestMirrorFilter
+		for _, rule := range obj.Spec.Rules {
+			for _, filter := range rule.Filters {
+				if filter.Type == gateway.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+					mirrors = append(mirrors, filter.RequestMirror)
+				}
+			}
+		}
+		status := obj.Status.DeepCopy()
+		status.RouteStatus = setRouteParentConditions(
+			status.RouteStatus,
+			obj.ObjectMeta,
+			obj.Spec.ParentRefs,
+			managedRouteParent(ctx, gateways, gatewayClasses, obj.Namespace),
+			missingMirrorBackends(obj.Namespace, mirrors, routeServiceExists(ctx, services)),
+			httpRouteClaimMatches(obj.Spec.Rules),
+		)
+		return status, &cfg
+	}, opts.WithName("HTTPRoutes")...)
+}
+
+// httpRouteClaimMatches names the matches of a route that match on a JWT
+// claim, as "rule[i].matches[j]".
+func httpRouteClaimMatches(rules []gateway.HTTPRouteRule) []string {
+	var out []string
+	for ruleIdx, rule := range rules {
+		for matchIdx, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if _, ok := securityconfig.ClaimFromRouteHeader(string(header.Name)); ok {
+					out = append(out, fmt.Sprintf("rule[%d].matches[%d]", ruleIdx, matchIdx))
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// managedRouteParent reports whether a route parent is handled by Dubbo: a
+// Service for mesh routing, or a Gateway of a class Dubbo controls.
+func managedRouteParent(
+	ctx krt.HandlerContext,
+	gateways krt.Collection[*gateway.Gateway],
+	gatewayClasses krt.Collection[GatewayClass],
+	routeNamespace string,
+) func(gateway.ParentReference) bool {
+	return func(parentRef gateway.ParentReference) bool {
+		if isServiceParentReference(parentRef) {
+			return true
+		}
+		namespace := routeNamespace
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		gw := krt.FetchOne(ctx, gateways, krt.FilterObjectName(types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}))
+		if gw == nil {
+			return false
+		}
+		class := fetchClass(ctx, gatewayClasses, (*gw).Spec.GatewayClassName)
+		if class == nil {
+			return false
+		}
+		_, known := classInfos[class.Controller]
+		return known
+	}
+}
+
+func routeServiceExists(ctx krt.HandlerContext, services krt.Collection[*corev1.Service]) func(namespace, name string) bool {
+	return func(namespace, name string) bool {
+		return krt.FetchOne(ctx, services, krt.FilterObjectName(types.NamespacedName{Namespace: namespace, Name: name})) != nil
+	}
+}
+
+// missingMirrorBackends lists the RequestMirror Services of a route that do
+// not exist. Such mirrors are dropped from the generated config.
+func missingMirrorBackends(
+	routeNamespace string,
+	mirrors []*gateway.HTTPRequestMirrorFilter,
+	serviceExists func(namespace, name string) bool,
+) []string {
+	var missing []string
+	for _, mirror := range mirrors {
+		backendRef := mirror.BackendRef
+		if !isServiceBackendObjectReference(backendRef) {
+			continue
+		}
+		namespace := routeNamespace
+		if backendRef.Namespace != nil {
+			namespace = string(*backendRef.Namespace)
+		}
+		if !serviceExists(namespace, string(backendRef.Name)) {
+			missing = append(missing, namespacedServiceKey(namespace, string(backendRef.Name)))
+		}
+	}
+	return missing
+}
+
+// setRouteParentConditions reports Accepted and ResolvedRefs for every parent
+// handled by Dubbo, leaving parent statuses of other controllers intact.
+// Service parents also report PartiallyInvalid when the route matches on JWT
+// claims: proxyless callers match before any token is verified, so those
+// matches are left out of the mesh routes.
+func setRouteParentConditions(
+	existing gateway.RouteStatus,
+	route metav1.ObjectMeta,
+	parentRefs []gateway.ParentReference,
+	managedParent func(gateway.ParentReference) bool,
+	missingMirrors []string,
+	claimMatches []string,
+) gateway.RouteStatus {
+	controllerName := gateway.GatewayController(features.ManagedGatewayController)
+
+	resolved := metav1.Condition{
+		Type:               string(gateway.RouteConditionResolvedRefs),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: route.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(gateway.RouteReasonResolvedRefs),
+		Message:            "All references resolved",
+	}
+	if len(missingMirrors) > 0 {
+		resolved.Status = metav1.ConditionFalse
+		resolved.Reason = string(gateway.RouteReasonBackendNotFound)
+		resolved.Message = fmt.Sprintf("RequestMirror backend Service not found: %s", strings.Join(missingMirrors, ", "))
+	}
+
+	parents := make([]gateway.RouteParentStatus, 0, len(existing.Parents))
+	previous := map[string]gateway.RouteParentStatus{}
+	for _, parent := range existing.Parents {
+		if parent.ControllerName != controllerName {
+			parents = append(parents, parent)
+			continue
+		}
+		previous[parentRefKey(parent.ParentRef, route.Namespace)] = parent
+	}
+	for _, parentRef := range parentRefs {
+		if !managedParent(parentRef) {
+			continue
+		}
+		parent := gateway.RouteParentStatus{
+			ParentRef:      parentRef,
+			ControllerName: controllerName,
+		}
+		conds := previous[parentRefKey(parentRef, route.Namespace)].Conditions
+		conds = kstatus.UpdateConditionIfChanged(conds, metav1.Condition{
+			Type:               string(gateway.RouteConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: route.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(gateway.RouteReasonAccepted),
+			Message:            "Route was valid",
+		})
+		conds = kstatus.UpdateConditionIfChanged(conds, resolved)
+		if isServiceParentReference(parentRef) && len(claimMatches) > 0 {
+			conds = kstatus.UpdateConditionIfChanged(conds, metav1.Condition{
+				Type:               string(gateway.RouteConditionPartiallyInvalid),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: route.Generation,
+				LastTransitionTime: metav1.Now(),
+				Reason:             string(gateway.RouteReasonUnsupportedValue),
+				Message: fmt.Sprintf("JWT claim matches are only supported on Gateway parents and were dropped for mesh traffic: %s",
+					strings.Join(claimMatches, ", ")),
+			})
+		} else {
+			conds = slices.Filter(conds, func(cond metav1.Condition) bool {
+				return cond.Type != string(gateway.RouteConditionPartiallyInvalid)
+			})
+		}
+		parent.Conditions = conds
+		parents = append(parents, parent)
+	}
+	existing.Parents = parents
+	return existing
+}
+
+func parentRefKey(parentRef gateway.ParentReference, routeNamespace string) string {
+	namespace := routeNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	key := namespacedServiceKey(namespace, string(parentRef.Name))
+	if parentRef.Kind != nil {
+		key = string(*parentRef.Kind) + "/" + key
+	}
+	if parentRef.SectionName != nil {
+		key += "#" + string(*parentRef.SectionName)
+	}
+	if parentRef.Port != nil {
+		key += fmt.Sprintf(":%d", *parentRef.Port)
+	}
+	return key
+}
+
+func isServiceParentReference(parentRef gateway.ParentReference) bool {
+	if parentRef.Kind == nil || string(*parentRef.Kind) != "Service" {
+		return false
+	}
+	return parentRef.Group == nil || string(*parentRef.Group) == ""
+}
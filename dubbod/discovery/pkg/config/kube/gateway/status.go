@@ -25,6 +25,8 @@ func GetStatus[I, IS any](spec I) IS {
 	switch t := any(spec).(type) {
 	case *k8sv1.HTTPRoute:
 		return any(t.Status).(IS)
+	case *k8sv1.GRPCRoute:
+		return any(t.Status).(IS)
 	case *k8sv1.Gateway:
 		return any(t.Status).(IS)
 	case *k8sv1.GatewayClass:
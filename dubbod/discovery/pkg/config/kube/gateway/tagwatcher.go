@@ -17,7 +17,20 @@
 package gateway
 
 import (
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
+	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	revisionLabel = "dubbo.apache.org/rev"
+	// revisionTagLabel marks the MutatingWebhookConfiguration that
+	// "dubboctl tag set" creates for a tag; its rev label names the target.
+	revisionTagLabel = "dubbo.apache.org/tag"
 )
 
 // TagWatcher is a simplified implementation for Dubbo
@@ -31,3 +44,70 @@ type TagWatcher interface {
 
 // TagHandler is a callback for when the tags revision change.
 type TagHandler func(any)
+
+type tagWatcher struct {
+	revision string
+	webhooks kclient.Client[*admissionregistrationv1.MutatingWebhookConfiguration]
+	handlers []TagHandler
+}
+
+// NewTagWatcher watches revision tag webhooks so a Gateway labelled with a
+// tag is reconciled by whichever revision the tag currently points at.
+func NewTagWatcher(client kube.Client, revision string) TagWatcher {
+	if revision == "" {
+		revision = "default"
+	}
+	w := &tagWatcher{
+		revision: revision,
+		webhooks: kclient.NewFiltered[*admissionregistrationv1.MutatingWebhookConfiguration](client, kclient.Filter{
+			LabelSelector: revisionTagLabel,
+		}),
+	}
+	w.webhooks.AddEventHandler(controllers.ObjectHandler(func(controllers.Object) {
+		tags := w.tags()
+		for _, handler := range w.handlers {
+			handler(tags)
+		}
+	}))
+	return w
+}
+
+func (w *tagWatcher) Run(stopCh <-chan struct{}) {
+	w.webhooks.Start(stopCh)
+	kube.WaitForCacheSync("tag watcher", stopCh, w.webhooks.HasSynced)
+}
+
+func (w *tagWatcher) HasSynced() bool {
+	return w.webhooks.HasSynced()
+}
+
+// AddHandler must be called before Run.
+func (w *tagWatcher) AddHandler(handler TagHandler) {
+	w.handlers = append(w.handlers, handler)
+}
+
+// IsMine reports whether the object's revision label selects this revision,
+// either directly or through a tag. Unlabelled objects belong to the default
+// revision.
+func (w *tagWatcher) IsMine(meta metav1.ObjectMeta) bool {
+	rev := meta.Labels[revisionLabel]
+	if rev == "" {
+		return w.revision == "default"
+	}
+	return w.tags().Contains(rev)
+}
+
+func (w *tagWatcher) tags() sets.String {
+	return revisionTags(w.revision, w.webhooks.List(metav1.NamespaceAll, klabels.Everything()))
+}
+
+// revisionTags returns the revision itself plus every tag pointing at it.
+func revisionTags(revision string, webhooks []*admissionregistrationv1.MutatingWebhookConfiguration) sets.String {
+	out := sets.New(revision)
+	for _, wh := range webhooks {
+		if tag := wh.Labels[revisionTagLabel]; tag != "" && wh.Labels[revisionLabel] == revision {
+			out.Insert(tag)
+		}
+	}
+	return out
+}
@@ -76,4 +76,10 @@ var (
 	ActivationHoldTimeout = env.Register("DUBBO_ACTIVATION_HOLD_TIMEOUT", 30,
 		"Seconds a gateway holds a request waiting for a scaled-to-zero target to come up before"+
 			" failing it. Longer than a cold start, shorter than the caller's own timeout").Get()
+	ActivationNativeScaleQPS = env.Register("DUBBO_ACTIVATION_NATIVE_SCALE_QPS", 5.0,
+		"Replica count changes per second dubbod makes for natively scaled activation targets, across"+
+			" all of them. Changes beyond it wait for the next check rather than queue up").Get()
+	ActivationNativeScaleBurst = env.Register("DUBBO_ACTIVATION_NATIVE_SCALE_BURST", 10,
+		"Replica count changes dubbod may make at once for natively scaled activation targets, so a"+
+			" burst of cold starts is not serialized behind DUBBO_ACTIVATION_NATIVE_SCALE_QPS").Get()
 )
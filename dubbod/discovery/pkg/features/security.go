@@ -16,7 +16,12 @@
 
 package features
 
-import "github.com/apache/dubbo-kubernetes/pkg/env"
+import (
+	"strings"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/pkg/env"
+)
 
 var (
 	CertSignerDomain          = env.Register("CERT_SIGNER_DOMAIN", "", "The cert signer domain info").Get()
@@ -28,4 +33,11 @@ var (
 		true,
 		"If enabled, dubbo will authorize XDS clients, to ensure they are acting only as namespaces they have permissions for.",
 	).Get()
+	TokenAudiences = func() []string {
+		return strings.Split(env.Register("TOKEN_AUDIENCES", "dubbo-ca",
+			"A comma separated list of audiences accepted on Kubernetes service account tokens presented to the CA. "+
+				"The mesh trust domain is always accepted as well.").Get(), ",")
+	}()
+	FederatedTrustBundleRefreshInterval = env.Register("FEDERATED_TRUST_BUNDLE_REFRESH_INTERVAL", 5*time.Minute,
+		"How often the bundles of federated trust domains are fetched again from their SPIFFE bundle endpoint or ConfigMap.").Get()
 )
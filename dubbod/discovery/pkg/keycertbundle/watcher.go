@@ -26,6 +26,13 @@ type KeyCertBundle struct {
 	KeyPem   []byte
 	CABundle []byte
 	CRL      []byte
+	// RevocationCRL is the CRL dubbod signs for revocations made through its
+	// own API, kept apart from the operator-provided CRL.
+	RevocationCRL []byte
+	// FederatedCABundles holds the PEM roots of each federated trust domain.
+	// They are kept per trust domain so a foreign root is only ever trusted
+	// for identities of its own trust domain.
+	FederatedCABundles map[string][]byte
 }
 
 type Watcher struct {
@@ -102,6 +109,37 @@ func (w *Watcher) SetAndNotifyCACRL(crl []byte) {
 	}
 }
 
+// SetRevocationCRLAndNotify sets the CRL signed by dubbod and notifies the
+// watchers. An empty CRL clears the previous one.
+func (w *Watcher) SetRevocationCRLAndNotify(crl []byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.bundle.RevocationCRL = crl
+
+	for _, ch := range w.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetFederatedCABundlesAndNotify sets the federated roots by trust domain and
+// notifies the watchers. Unlike the other setters an empty map clears the
+// previous one, so trust in a removed trust domain is revoked.
+func (w *Watcher) SetFederatedCABundlesAndNotify(bundles map[string][]byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.bundle.FederatedCABundles = bundles
+
+	for _, ch := range w.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // SetFromFilesAndNotify sets the key cert and root cert from files and notify the watchers.
 func (w *Watcher) SetFromFilesAndNotify(keyFile, certFile, rootCert string) error {
 	cert, err := os.ReadFile(certFile)
@@ -134,9 +172,36 @@ func (w *Watcher) GetKeyCertBundle() KeyCertBundle {
 	return w.bundle
 }
 
-// GetCRL returns the CRL data.
+// GetCRL returns the CRL data: the operator-provided CRL followed by the CRL
+// signed by dubbod.
 func (w *Watcher) GetCRL() []byte {
 	w.mutex.RLock()
 	defer w.mutex.RUnlock()
-	return w.bundle.CRL
+	if len(w.bundle.RevocationCRL) == 0 {
+		return w.bundle.CRL
+	}
+	if len(w.bundle.CRL) == 0 {
+		return w.bundle.RevocationCRL
+	}
+	out := make([]byte, 0, len(w.bundle.CRL)+len(w.bundle.RevocationCRL)+1)
+	out = append(out, w.bundle.CRL...)
+	if out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	return append(out, w.bundle.RevocationCRL...)
+}
+
+// GetRevocationCRL returns the CRL signed by dubbod.
+func (w *Watcher) GetRevocationCRL() []byte {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.bundle.RevocationCRL
+}
+
+// GetFederatedCABundles returns the roots of federated trust domains by trust
+// domain. The map must not be modified.
+func (w *Watcher) GetFederatedCABundles() map[string][]byte {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.bundle.FederatedCABundles
 }
@@ -35,9 +35,12 @@ import (
 )
 
 const (
-	NamespaceController         = "dubbo-namespace-controller-election"
-	GatewayStatusController     = "dubbo-gateway-status-leader"
-	GatewayDeploymentController = "dubbo-gateway-deployment"
+	NamespaceController            = "dubbo-namespace-controller-election"
+	GatewayStatusController        = "dubbo-gateway-status-leader"
+	GatewayDeploymentController    = "dubbo-gateway-deployment"
+	ActivationNativeScaler         = "dubbo-activation-native-scaler"
+	CircuitBreakerStatusController = "dubbo-circuit-breaker-status-leader"
+	RateLimitStatusController      = "dubbo-rate-limit-status-leader"
 )
 
 type LeaderElection struct {
@@ -96,6 +96,7 @@ type Environment struct {
 	EndpointIndex        *EndpointIndex
 	Cache                XdsCache
 	GatewayAPIController GatewayController
+	ActivationDrains     ServiceActivationDrainSource
 }
 
 type GatewayController interface {
@@ -234,6 +235,19 @@ func (node *Proxy) IsInherent() bool {
 	return node != nil && node.Type == Inherent
 }
 
+// Locality returns the proxy's region/zone/subzone as reported in its xDS
+// node, or an empty string when the client did not report one.
+func (node *Proxy) Locality() string {
+	if node == nil || node.XdsNode == nil || node.XdsNode.GetLocality() == nil {
+		return ""
+	}
+	l := node.XdsNode.GetLocality()
+	if l.GetRegion() == "" && l.GetZone() == "" && l.GetSubZone() == "" {
+		return ""
+	}
+	return strings.TrimRight(l.GetRegion()+"/"+l.GetZone()+"/"+l.GetSubZone(), "/")
+}
+
 func (node *Proxy) NewWatchedResource(typeURL string, names []string) {
 	node.Lock()
 	defer node.Unlock()
@@ -0,0 +1,157 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DubboInterfacesAnnotation lists the Dubbo interfaces a Service exposes as
+// comma separated service keys, "[group/]interface[:version]", e.g.
+// "org.example.Greeter:1.0.0,gray/org.example.Greeter:2.0.0".
+const DubboInterfacesAnnotation = "dubbo.apache.org/interfaces"
+
+// Labels that identify the Dubbo interface, version and group an endpoint
+// provides. Pods set them directly; registry adapters copy them from the
+// provider URL.
+const (
+	DubboInterfaceLabel = "dubbo.apache.org/interface"
+	DubboVersionLabel   = "dubbo.apache.org/version"
+	DubboGroupLabel     = "dubbo.apache.org/group"
+)
+
+// Headers a Triple client sends to address a version and group of an
+// interface. Route matches on them select the matching subset.
+const (
+	DubboVersionHeader = "tri-service-version"
+	DubboGroupHeader   = "tri-service-group"
+)
+
+// dubboSubsetPrefix marks subset names built by DubboSubsetName.
+const dubboSubsetPrefix = "dubbo:"
+
+// DubboInterface is one interface:group:version a Service serves.
+type DubboInterface struct {
+	Name    string
+	Group   string
+	Version string
+}
+
+// ServiceKey returns the Dubbo service key, "[group/]interface[:version]".
+func (i DubboInterface) ServiceKey() string {
+	key := i.Name
+	if i.Group != "" {
+		key = i.Group + "/" + key
+	}
+	if i.Version != "" {
+		key += ":" + i.Version
+	}
+	return key
+}
+
+// SubsetName returns the name of the cluster subset holding the endpoints of
+// this group and version. It is empty for an interface that pins neither.
+func (i DubboInterface) SubsetName() string {
+	if i.Group == "" && i.Version == "" {
+		return ""
+	}
+	return DubboSubsetName(i.Group, i.Version)
+}
+
+// DubboSubsetName builds a cluster subset name, "dubbo:<group>:<version>".
+func DubboSubsetName(group, version string) string {
+	return dubboSubsetPrefix + group + ":" + version
+}
+
+// ParseDubboSubsetName reverses DubboSubsetName.
+func ParseDubboSubsetName(subset string) (group, version string, ok bool) {
+	rest, found := strings.CutPrefix(subset, dubboSubsetPrefix)
+	if !found {
+		return "", "", false
+	}
+	group, version, found = strings.Cut(rest, ":")
+	if !found || (group == "" && version == "") {
+		return "", "", false
+	}
+	return group, version, true
+}
+
+// DubboSubsetMatches reports whether endpoint labels belong to the subset of
+// a group and version. An empty version matches any version, but the group
+// always matches exactly: Dubbo treats the group as part of the service's
+// identity, so an empty group selects only providers registered without one.
+func DubboSubsetMatches(labels map[string]string, group, version string) bool {
+	if version != "" && labels[DubboVersionLabel] != version {
+		return false
+	}
+	return labels[DubboGroupLabel] == group
+}
+
+// ParseDubboServiceKey parses "[group/]interface[:version]".
+func ParseDubboServiceKey(key string) (DubboInterface, error) {
+	raw := strings.TrimSpace(key)
+	key = raw
+	var out DubboInterface
+	if group, rest, found := strings.Cut(key, "/"); found {
+		out.Group, key = group, rest
+	}
+	if name, version, found := strings.Cut(key, ":"); found {
+		key, out.Version = name, version
+	}
+	out.Name = key
+	if out.Name == "" || strings.ContainsAny(out.Name, "/:|") ||
+		strings.ContainsAny(out.Group, ":|") || strings.ContainsAny(out.Version, ":/|") {
+		return DubboInterface{}, fmt.Errorf("invalid dubbo service key %q", raw)
+	}
+	return out, nil
+}
+
+// DubboInterfacesFromAnnotations reads DubboInterfacesAnnotation. Keys are
+// sorted and deduplicated so equal annotations produce equal services.
+func DubboInterfacesFromAnnotations(annotations map[string]string) ([]DubboInterface, error) {
+	value := strings.TrimSpace(annotations[DubboInterfacesAnnotation])
+	if value == "" {
+		return nil, nil
+	}
+	var out []DubboInterface
+	for _, key := range strings.Split(value, ",") {
+		if strings.TrimSpace(key) == "" {
+			continue
+		}
+		iface, err := ParseDubboServiceKey(key)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, iface)
+	}
+	return SortDubboInterfaces(out), nil
+}
+
+// SortDubboInterfaces orders interfaces by service key and drops duplicates.
+func SortDubboInterfaces(in []DubboInterface) []DubboInterface {
+	sort.Slice(in, func(i, j int) bool { return in[i].ServiceKey() < in[j].ServiceKey() })
+	out := in[:0]
+	for i, iface := range in {
+		if i > 0 && iface == in[i-1] {
+			continue
+		}
+		out = append(out, iface)
+	}
+	return out
+}
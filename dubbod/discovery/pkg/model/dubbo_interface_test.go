@@ -0,0 +1,80 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDubboInterfacesFromAnnotations(t *testing.T) {
+	got, err := DubboInterfacesFromAnnotations(map[string]string{
+		DubboInterfacesAnnotation: "org.example.Greeter:1.0.0, gray/org.example.Greeter:2.0.0,org.example.Stock,org.example.Greeter:1.0.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DubboInterface{
+		{Name: "org.example.Greeter", Group: "gray", Version: "2.0.0"},
+		{Name: "org.example.Greeter", Version: "1.0.0"},
+		{Name: "org.example.Stock"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	for _, value := range []string{"gray/:1.0.0", "org.example.Greeter:1.0.0:extra", "a|b"} {
+		if _, err := DubboInterfacesFromAnnotations(map[string]string{DubboInterfacesAnnotation: value}); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestDubboSubsetName(t *testing.T) {
+	iface := DubboInterface{Name: "org.example.Greeter", Group: "gray", Version: "2.0.0"}
+	if got := iface.ServiceKey(); got != "gray/org.example.Greeter:2.0.0" {
+		t.Fatalf("service key = %q", got)
+	}
+	group, version, ok := ParseDubboSubsetName(iface.SubsetName())
+	if !ok || group != "gray" || version != "2.0.0" {
+		t.Fatalf("round trip = %q %q %v", group, version, ok)
+	}
+	if (DubboInterface{Name: "org.example.Stock"}).SubsetName() != "" {
+		t.Fatal("an unversioned interface must not have a subset")
+	}
+	for _, subset := range []string{"", "v1", "dubbo::", "dubbo:gray"} {
+		if _, _, ok := ParseDubboSubsetName(subset); ok {
+			t.Errorf("expected %q not to parse", subset)
+		}
+	}
+
+	labels := map[string]string{DubboVersionLabel: "2.0.0", DubboGroupLabel: "gray"}
+	if !DubboSubsetMatches(labels, "gray", "2.0.0") || !DubboSubsetMatches(labels, "gray", "") {
+		t.Fatal("expected labels to match")
+	}
+	if DubboSubsetMatches(labels, "gray", "1.0.0") || DubboSubsetMatches(labels, "blue", "") {
+		t.Fatal("expected labels not to match")
+	}
+	// An empty group selects only providers without a group.
+	if DubboSubsetMatches(labels, "", "2.0.0") {
+		t.Fatal("expected a grouped provider not to match an empty group")
+	}
+	ungrouped := map[string]string{DubboVersionLabel: "2.0.0"}
+	if !DubboSubsetMatches(ungrouped, "", "2.0.0") || DubboSubsetMatches(ungrouped, "gray", "2.0.0") {
+		t.Fatal("expected an ungrouped provider to match only an empty group")
+	}
+}
@@ -0,0 +1,213 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/config/host"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+	security "github.com/kdubbo/api/security/v1alpha3"
+)
+
+// DefaultExtAuthzTimeout bounds a check request when the provider sets none.
+const DefaultExtAuthzTimeout = time.Second
+
+// ExtAuthzProvider is an HTTP decision service CUSTOM AuthorizationPolicies
+// delegate to, declared as an envoyExtAuthzHttp entry of mesh config
+// extensionProviders. The service receives the original request method, path
+// (behind PathPrefix) and the included headers; any 2xx response admits it.
+type ExtAuthzProvider struct {
+	Name string
+	// Service is the hostname of the decision service, e.g.
+	// opa.policy.svc.cluster.local.
+	Service    string
+	Port       int
+	PathPrefix string
+	Timeout    time.Duration
+	// FailOpen admits requests when the service cannot be reached or does
+	// not answer in time. Otherwise they are rejected with StatusOnError.
+	FailOpen      bool
+	StatusOnError uint32
+	// IncludeRequestHeadersInCheck lists the request headers forwarded to the
+	// service. Authorization, Host, Method and Path always are.
+	IncludeRequestHeadersInCheck []string
+	// HeadersToUpstreamOnAllow lists the response headers of an allowing
+	// check that are added to the request.
+	HeadersToUpstreamOnAllow []string
+	// HeadersToDownstreamOnDeny lists the response headers of a denying
+	// check that are returned to the caller.
+	HeadersToDownstreamOnDeny []string
+}
+
+// CheckTimeout returns Timeout, or DefaultExtAuthzTimeout when it is unset.
+func (p ExtAuthzProvider) CheckTimeout() time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return DefaultExtAuthzTimeout
+}
+
+// ErrorStatus is the HTTP status of a request rejected because the check
+// failed.
+func (p ExtAuthzProvider) ErrorStatus() uint32 {
+	if p.StatusOnError == 0 {
+		return 403
+	}
+	return p.StatusOnError
+}
+
+// ClusterName is the outbound cluster of the decision service.
+func (p ExtAuthzProvider) ClusterName() string {
+	return BuildSubsetKey(TrafficDirectionOutbound, "", host.Name(p.Service), p.Port)
+}
+
+// ExtAuthzProviderFromMesh converts an envoyExtAuthzHttp extension provider.
+// It returns false for the other kinds of providers.
+func ExtAuthzProviderFromMesh(provider *meshv1alpha1.MeshConfig_ExtensionProvider) (ExtAuthzProvider, bool, error) {
+	http := provider.GetEnvoyExtAuthzHttp()
+	if http == nil {
+		return ExtAuthzProvider{}, false, nil
+	}
+	p := ExtAuthzProvider{
+		Name:                         provider.GetName(),
+		Service:                      http.GetService(),
+		Port:                         int(http.GetPort()),
+		PathPrefix:                   http.GetPathPrefix(),
+		FailOpen:                     http.GetFailOpen(),
+		IncludeRequestHeadersInCheck: lowerHeaderNames(http.GetIncludeRequestHeadersInCheck()),
+		HeadersToUpstreamOnAllow:     lowerHeaderNames(http.GetHeadersToUpstreamOnAllow()),
+		HeadersToDownstreamOnDeny:    lowerHeaderNames(http.GetHeadersToDownstreamOnDeny()),
+	}
+	if p.Name == "" {
+		return p, true, fmt.Errorf("external authorization provider has no name")
+	}
+	if p.Service == "" {
+		return p, true, fmt.Errorf("external authorization provider %s has no service", p.Name)
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		return p, true, fmt.Errorf("external authorization provider %s has invalid port %d", p.Name, p.Port)
+	}
+	if timeout := http.GetTimeout(); timeout != nil {
+		if p.Timeout = timeout.AsDuration(); p.Timeout <= 0 {
+			return p, true, fmt.Errorf("external authorization provider %s has invalid timeout %v", p.Name, p.Timeout)
+		}
+	}
+	if raw := http.GetStatusOnError(); raw != "" {
+		status, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || status < 200 || status > 599 {
+			return p, true, fmt.Errorf("external authorization provider %s has invalid statusOnError %q", p.Name, raw)
+		}
+		p.StatusOnError = uint32(status)
+	}
+	if p.PathPrefix != "" && !strings.HasPrefix(p.PathPrefix, "/") {
+		return p, true, fmt.Errorf("external authorization provider %s pathPrefix must start with /", p.Name)
+	}
+	return p, true, nil
+}
+
+func lowerHeaderNames(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// ExtAuthzProviderForPolicy returns the provider a CUSTOM AuthorizationPolicy
+// delegates to. The name is empty for a CUSTOM policy without a provider,
+// which resolves to no provider and therefore denies.
+func ExtAuthzProviderForPolicy(cfg config.Config) (string, bool) {
+	spec, ok := cfg.Spec.(*security.AuthorizationPolicy)
+	if !ok || spec.GetAction() != security.AuthorizationPolicy_CUSTOM {
+		return "", false
+	}
+	return strings.TrimSpace(spec.GetProvider().GetName()), true
+}
+
+// SplitCustomAuthorizationPolicies separates CUSTOM policies from the ALLOW
+// and DENY ones, keeping the order of configs.
+func SplitCustomAuthorizationPolicies(configs []config.Config) (custom, rest []config.Config) {
+	for _, cfg := range configs {
+		if _, ok := ExtAuthzProviderForPolicy(cfg); ok {
+			custom = append(custom, cfg)
+		} else {
+			rest = append(rest, cfg)
+		}
+	}
+	return custom, rest
+}
+
+// initExtAuthzProviders indexes the envoyExtAuthzHttp extension providers.
+// An invalid provider is left out, so the policies naming it deny.
+func (ps *PushContext) initExtAuthzProviders() {
+	ps.extAuthzProviders = nil
+	for _, provider := range ps.Mesh.GetExtensionProviders() {
+		p, ok, err := ExtAuthzProviderFromMesh(provider)
+		if !ok {
+			continue
+		}
+		if err != nil {
+			log.Warnf("ignoring mesh config extension provider: %v", err)
+			continue
+		}
+		if _, found := ps.extAuthzProviders[p.Name]; found {
+			log.Warnf("ignoring mesh config extension provider %s: declared twice", p.Name)
+			continue
+		}
+		if ps.extAuthzProviders == nil {
+			ps.extAuthzProviders = map[string]ExtAuthzProvider{}
+		}
+		ps.extAuthzProviders[p.Name] = p
+	}
+}
+
+// ExtAuthzProviderForPolicies resolves the single provider the CUSTOM
+// policies of a workload delegate to. Like a missing provider, more than one
+// provider per workload is an error: callers must then reject the requests
+// the policies match rather than skip the check.
+func (ps *PushContext) ExtAuthzProviderForPolicies(custom []config.Config) (ExtAuthzProvider, error) {
+	names := map[string]struct{}{}
+	for _, cfg := range custom {
+		name, _ := ExtAuthzProviderForPolicy(cfg)
+		names[name] = struct{}{}
+	}
+	if len(names) != 1 {
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		return ExtAuthzProvider{}, fmt.Errorf("CUSTOM policies of a workload must use one provider, found %v", sorted)
+	}
+	for name := range names {
+		if ps != nil {
+			if p, found := ps.extAuthzProviders[name]; found {
+				return p, nil
+			}
+		}
+		return ExtAuthzProvider{}, fmt.Errorf("external authorization provider %q is not declared in mesh config extensionProviders", name)
+	}
+	return ExtAuthzProvider{}, nil
+}
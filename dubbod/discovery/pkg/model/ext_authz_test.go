@@ -0,0 +1,112 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+	security "github.com/kdubbo/api/security/v1alpha3"
+)
+
+func extAuthzHTTPProvider(name string, http *meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationHttpProvider) *meshv1alpha1.MeshConfig_ExtensionProvider {
+	return &meshv1alpha1.MeshConfig_ExtensionProvider{
+		Name:     name,
+		Provider: &meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExtAuthzHttp{EnvoyExtAuthzHttp: http},
+	}
+}
+
+func TestExtAuthzProviderFromMesh(t *testing.T) {
+	p, ok, err := ExtAuthzProviderFromMesh(extAuthzHTTPProvider("opa",
+		&meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationHttpProvider{
+			Service:                      "opa.policy.svc.cluster.local",
+			Port:                         9191,
+			IncludeRequestHeadersInCheck: []string{"X-Tenant", " "},
+		}))
+	if !ok || err != nil {
+		t.Fatalf("convert provider = %v, %v", ok, err)
+	}
+	if p.CheckTimeout() != DefaultExtAuthzTimeout || p.ErrorStatus() != 403 {
+		t.Fatalf("defaults = %v/%d, want %v/403", p.CheckTimeout(), p.ErrorStatus(), DefaultExtAuthzTimeout)
+	}
+	if got := p.IncludeRequestHeadersInCheck; len(got) != 1 || got[0] != "x-tenant" {
+		t.Fatalf("headers = %v, want [x-tenant]", got)
+	}
+	if got := p.ClusterName(); got != "outbound|9191||opa.policy.svc.cluster.local" {
+		t.Fatalf("cluster = %q", got)
+	}
+
+	if _, ok, _ := ExtAuthzProviderFromMesh(&meshv1alpha1.MeshConfig_ExtensionProvider{Name: "tracing"}); ok {
+		t.Fatal("a provider of another kind converted")
+	}
+	for name, http := range map[string]*meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationHttpProvider{
+		"no service":      {Port: 9191},
+		"no port":         {Service: "opa"},
+		"zero timeout":    {Service: "opa", Port: 9191, Timeout: durationpb.New(0)},
+		"status on error": {Service: "opa", Port: 9191, StatusOnError: "42"},
+		"path prefix":     {Service: "opa", Port: 9191, PathPrefix: "authz"},
+	} {
+		if _, _, err := ExtAuthzProviderFromMesh(extAuthzHTTPProvider("opa", http)); err == nil {
+			t.Errorf("%s: conversion succeeded, want error", name)
+		}
+	}
+}
+
+func TestExtAuthzProviderForPolicies(t *testing.T) {
+	policy := func(provider string) config.Config {
+		return config.Config{
+			Meta: config.Meta{Name: provider},
+			Spec: &security.AuthorizationPolicy{
+				Action: security.AuthorizationPolicy_CUSTOM,
+				ActionDetail: &security.AuthorizationPolicy_Provider{
+					Provider: &security.AuthorizationPolicy_ExtensionProvider{Name: provider},
+				},
+			},
+		}
+	}
+	ps := &PushContext{Mesh: &meshv1alpha1.MeshConfig{
+		ExtensionProviders: []*meshv1alpha1.MeshConfig_ExtensionProvider{
+			extAuthzHTTPProvider("opa", &meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationHttpProvider{
+				Service: "opa", Port: 9191, Timeout: durationpb.New(250 * time.Millisecond),
+			}),
+			extAuthzHTTPProvider("broken", &meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationHttpProvider{}),
+		},
+	}}
+	ps.initExtAuthzProviders()
+
+	allow := config.Config{Meta: config.Meta{Name: "allow"}, Spec: &security.AuthorizationPolicy{}}
+	custom, rest := SplitCustomAuthorizationPolicies([]config.Config{policy("opa"), allow})
+	if len(custom) != 1 || len(rest) != 1 || rest[0].Name != "allow" {
+		t.Fatalf("split = %v / %v", custom, rest)
+	}
+	p, err := ps.ExtAuthzProviderForPolicies(custom)
+	if err != nil || p.CheckTimeout() != 250*time.Millisecond {
+		t.Fatalf("provider = %+v, %v", p, err)
+	}
+	for _, name := range []string{"missing", "broken"} {
+		if _, err := ps.ExtAuthzProviderForPolicies([]config.Config{policy(name)}); err == nil {
+			t.Fatalf("provider %s resolved", name)
+		}
+	}
+	if _, err := ps.ExtAuthzProviderForPolicies([]config.Config{policy("opa"), policy("other")}); err == nil {
+		t.Fatal("two providers for one workload resolved")
+	}
+}
@@ -0,0 +1,43 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
+	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
+)
+
+func insertRoutedClaim(claims sets.String, headerName string) {
+	if claim, ok := securityconfig.ClaimFromRouteHeader(headerName); ok {
+		claims.Insert(claim)
+	}
+}
+
+// RoutedJWTClaims returns the JWT claims HTTPRoutes and GRPCRoutes attached
+// to a Gateway match on, sorted. Gateway JWT providers copy each of them into
+// its claim route header so routes can match on it once the token is
+// verified. Routes attached to a Service do not count: proxyless callers
+// match those before any token is verified.
+func (ps *PushContext) RoutedJWTClaims() []string {
+	if ps == nil {
+		return nil
+	}
+	claims := sets.New[string]()
+	claims.Merge(ps.httpRouteIndex.routedClaims)
+	claims.Merge(ps.grpcRouteIndex.routedClaims)
+	return sets.SortedList(claims)
+}
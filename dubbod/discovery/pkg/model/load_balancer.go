@@ -0,0 +1,177 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/pkg/slices"
+)
+
+const (
+	// LoadBalancerPolicyAnnotation selects the per-service load balancing
+	// policy: ROUND_ROBIN, LEAST_REQUEST, RANDOM, RING_HASH or MAGLEV.
+	LoadBalancerPolicyAnnotation = "networking.dubbo.apache.org/load-balancer"
+	// LoadBalancerHashKeyAnnotation lists the comma separated hash keys used by
+	// RING_HASH and MAGLEV, e.g. "header:x-user-id", "cookie:session",
+	// "source-ip" or "attachment:tenant".
+	LoadBalancerHashKeyAnnotation = "networking.dubbo.apache.org/hash-key"
+	// LoadBalancerRingSizeAnnotation sets the minimum ring size for RING_HASH.
+	LoadBalancerRingSizeAnnotation = "networking.dubbo.apache.org/ring-size"
+	// LoadBalancerMaglevTableSizeAnnotation sets the lookup table size for MAGLEV.
+	LoadBalancerMaglevTableSizeAnnotation = "networking.dubbo.apache.org/maglev-table-size"
+)
+
+type LoadBalancerPolicy string
+
+const (
+	LoadBalancerRoundRobin   LoadBalancerPolicy = "ROUND_ROBIN"
+	LoadBalancerLeastRequest LoadBalancerPolicy = "LEAST_REQUEST"
+	LoadBalancerRandom       LoadBalancerPolicy = "RANDOM"
+	LoadBalancerRingHash     LoadBalancerPolicy = "RING_HASH"
+	LoadBalancerMaglev       LoadBalancerPolicy = "MAGLEV"
+)
+
+type HashKeyType string
+
+const (
+	HashKeyHeader     HashKeyType = "header"
+	HashKeyCookie     HashKeyType = "cookie"
+	HashKeySourceIP   HashKeyType = "source-ip"
+	HashKeyAttachment HashKeyType = "attachment"
+)
+
+// HashKey is one input of a consistent-hash load balancer. Name is the header,
+// cookie or Dubbo attachment key and is empty for source-ip.
+type HashKey struct {
+	Type HashKeyType
+	Name string
+}
+
+// LoadBalancerSettings is the per-service load balancing configuration. A nil
+// value means the mesh-wide DUBBO_DEFAULT_LB_POLICY applies.
+type LoadBalancerSettings struct {
+	Policy          LoadBalancerPolicy
+	HashKeys        []HashKey
+	MinimumRingSize uint64
+	MaglevTableSize uint64
+}
+
+// IsConsistentHash reports whether the policy routes by hash key.
+func (s *LoadBalancerSettings) IsConsistentHash() bool {
+	return s != nil && (s.Policy == LoadBalancerRingHash || s.Policy == LoadBalancerMaglev)
+}
+
+func (s *LoadBalancerSettings) DeepCopy() *LoadBalancerSettings {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.HashKeys = slices.Clone(s.HashKeys)
+	return &out
+}
+
+func (s *LoadBalancerSettings) Equals(other *LoadBalancerSettings) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.Policy == other.Policy &&
+		s.MinimumRingSize == other.MinimumRingSize &&
+		s.MaglevTableSize == other.MaglevTableSize &&
+		slices.Equal(s.HashKeys, other.HashKeys)
+}
+
+// LoadBalancerSettingsFromAnnotations parses the load balancing annotations of
+// a Service. It returns nil when no policy is configured.
+func LoadBalancerSettingsFromAnnotations(annotations map[string]string) (*LoadBalancerSettings, error) {
+	rawPolicy := strings.TrimSpace(annotations[LoadBalancerPolicyAnnotation])
+	rawKeys := strings.TrimSpace(annotations[LoadBalancerHashKeyAnnotation])
+	if rawPolicy == "" && rawKeys == "" {
+		return nil, nil
+	}
+
+	settings := &LoadBalancerSettings{Policy: LoadBalancerPolicy(strings.ToUpper(rawPolicy))}
+	switch settings.Policy {
+	case LoadBalancerRoundRobin, LoadBalancerLeastRequest, LoadBalancerRandom, LoadBalancerRingHash, LoadBalancerMaglev:
+	case "":
+		// A hash key alone implies consistent hashing.
+		settings.Policy = LoadBalancerRingHash
+	default:
+		return nil, fmt.Errorf("unknown load balancer policy %q", rawPolicy)
+	}
+
+	if rawKeys != "" {
+		for _, raw := range strings.Split(rawKeys, ",") {
+			key, err := parseHashKey(raw)
+			if err != nil {
+				return nil, err
+			}
+			settings.HashKeys = append(settings.HashKeys, key)
+		}
+	}
+	if len(settings.HashKeys) > 0 && !settings.IsConsistentHash() {
+		return nil, fmt.Errorf("hash keys require RING_HASH or MAGLEV, got %s", settings.Policy)
+	}
+
+	var err error
+	if settings.MinimumRingSize, err = parseSizeAnnotation(annotations, LoadBalancerRingSizeAnnotation); err != nil {
+		return nil, err
+	}
+	if settings.MaglevTableSize, err = parseSizeAnnotation(annotations, LoadBalancerMaglevTableSizeAnnotation); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func parseHashKey(raw string) (HashKey, error) {
+	raw = strings.TrimSpace(raw)
+	keyType, name, _ := strings.Cut(raw, ":")
+	key := HashKey{Type: HashKeyType(strings.ToLower(strings.TrimSpace(keyType))), Name: strings.TrimSpace(name)}
+	switch key.Type {
+	case HashKeySourceIP:
+		if key.Name != "" {
+			return HashKey{}, fmt.Errorf("hash key %q does not take a name", raw)
+		}
+	case HashKeyHeader, HashKeyAttachment:
+		if key.Name == "" {
+			return HashKey{}, fmt.Errorf("hash key %q requires a name", raw)
+		}
+		// HTTP/2 header names and Triple attachments travel lower-cased.
+		key.Name = strings.ToLower(key.Name)
+	case HashKeyCookie:
+		if key.Name == "" {
+			return HashKey{}, fmt.Errorf("hash key %q requires a name", raw)
+		}
+	default:
+		return HashKey{}, fmt.Errorf("unknown hash key type %q", raw)
+	}
+	return key, nil
+}
+
+func parseSizeAnnotation(annotations map[string]string, name string) (uint64, error) {
+	raw := strings.TrimSpace(annotations[name])
+	if raw == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || size == 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	return size, nil
+}
@@ -0,0 +1,56 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+)
+
+func TestLoadBalancerSettingsFromAnnotations(t *testing.T) {
+	settings, err := LoadBalancerSettingsFromAnnotations(map[string]string{
+		LoadBalancerHashKeyAnnotation:  "header:X-User-Id, cookie:session, source-ip",
+		LoadBalancerRingSizeAnnotation: "1024",
+	})
+	if err != nil {
+		t.Fatalf("parse annotations: %v", err)
+	}
+	if settings.Policy != LoadBalancerRingHash {
+		t.Fatalf("policy = %s, want RING_HASH implied by hash keys", settings.Policy)
+	}
+	want := []HashKey{
+		{Type: HashKeyHeader, Name: "x-user-id"},
+		{Type: HashKeyCookie, Name: "session"},
+		{Type: HashKeySourceIP},
+	}
+	if !settings.Equals(&LoadBalancerSettings{Policy: LoadBalancerRingHash, HashKeys: want, MinimumRingSize: 1024}) {
+		t.Fatalf("settings = %+v, want hash keys %+v", settings, want)
+	}
+
+	if settings, err := LoadBalancerSettingsFromAnnotations(nil); err != nil || settings != nil {
+		t.Fatalf("no annotations = (%+v, %v), want nil", settings, err)
+	}
+	for _, annotations := range []map[string]string{
+		{LoadBalancerPolicyAnnotation: "sticky"},
+		{LoadBalancerPolicyAnnotation: "ROUND_ROBIN", LoadBalancerHashKeyAnnotation: "source-ip"},
+		{LoadBalancerHashKeyAnnotation: "header:"},
+		{LoadBalancerPolicyAnnotation: "MAGLEV", LoadBalancerMaglevTableSizeAnnotation: "zero"},
+	} {
+		if _, err := LoadBalancerSettingsFromAnnotations(annotations); err == nil {
+			t.Fatalf("annotations %v parsed without error", annotations)
+		}
+	}
+}
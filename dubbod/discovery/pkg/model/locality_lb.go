@@ -0,0 +1,234 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/pkg/slices"
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+)
+
+// LocalityLoadBalancerAnnotation carries a per-service LocalityLbSetting as
+// JSON. It replaces MeshConfig localityLbSetting for that service.
+const LocalityLoadBalancerAnnotation = "networking.dubbo.apache.org/locality-lb"
+
+// LocalityLbSetting controls how EDS orders and weighs localities relative to
+// the calling proxy. Localities are "region/zone/subzone" strings.
+//
+// Without Distribute, endpoints are split into priorities: the caller's
+// subzone, zone and region first, then the Failover regions in the order they
+// are listed, then everything else. Distribute instead keeps a single priority
+// and assigns each locality a share of the traffic.
+type LocalityLbSetting struct {
+	// Enabled turns locality load balancing off when explicitly false.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Failover lists region failover edges. Several entries with the same
+	// From give the order in which the other regions are tried.
+	Failover []LocalityFailover `json:"failover,omitempty"`
+	// Distribute gives the traffic split for callers in a locality.
+	Distribute []LocalityDistribute `json:"distribute,omitempty"`
+}
+
+type LocalityFailover struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// LocalityDistribute maps callers matching From to weighted destination
+// localities. Both sides accept "*" for a trailing wildcard, e.g.
+// "us-east/zone-a/*". Weights must add up to 100.
+type LocalityDistribute struct {
+	From string            `json:"from"`
+	To   map[string]uint32 `json:"to"`
+}
+
+// IsEnabled reports whether the setting applies at all.
+func (s *LocalityLbSetting) IsEnabled() bool {
+	return s != nil && (s.Enabled == nil || *s.Enabled)
+}
+
+// FailoverRegions returns the explicit failover order for a caller region.
+func (s *LocalityLbSetting) FailoverRegions(region string) []string {
+	var out []string
+	for _, failover := range s.Failover {
+		if failover.From == region {
+			out = append(out, failover.To)
+		}
+	}
+	return out
+}
+
+// DistributeFor returns the weights for a caller locality, or nil when no
+// Distribute rule matches it.
+func (s *LocalityLbSetting) DistributeFor(locality string) map[string]uint32 {
+	for _, distribute := range s.Distribute {
+		if LocalityMatch(locality, distribute.From) {
+			return distribute.To
+		}
+	}
+	return nil
+}
+
+func (s *LocalityLbSetting) DeepCopy() *LocalityLbSetting {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	if s.Enabled != nil {
+		enabled := *s.Enabled
+		out.Enabled = &enabled
+	}
+	out.Failover = slices.Clone(s.Failover)
+	out.Distribute = nil
+	for _, distribute := range s.Distribute {
+		out.Distribute = append(out.Distribute, LocalityDistribute{From: distribute.From, To: maps.Clone(distribute.To)})
+	}
+	return &out
+}
+
+func (s *LocalityLbSetting) Equals(other *LocalityLbSetting) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	if s.IsEnabled() != other.IsEnabled() || !slices.Equal(s.Failover, other.Failover) || len(s.Distribute) != len(other.Distribute) {
+		return false
+	}
+	for i := range s.Distribute {
+		if s.Distribute[i].From != other.Distribute[i].From || !maps.Equal(s.Distribute[i].To, other.Distribute[i].To) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLocalityLbSetting decodes and validates a JSON LocalityLbSetting. It
+// returns nil for an empty value.
+func ParseLocalityLbSetting(raw string) (*LocalityLbSetting, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	setting := &LocalityLbSetting{}
+	if err := json.Unmarshal([]byte(raw), setting); err != nil {
+		return nil, fmt.Errorf("invalid locality load balancer setting: %v", err)
+	}
+	if err := setting.validate(); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+// LocalityLbSettingFromMesh converts MeshConfig localityLbSetting. It returns
+// nil when the mesh leaves the setting unset.
+func LocalityLbSettingFromMesh(in *networking.LocalityLoadBalancerSetting) (*LocalityLbSetting, error) {
+	if in == nil {
+		return nil, nil
+	}
+	setting := &LocalityLbSetting{}
+	if in.GetEnabled() != nil {
+		enabled := in.GetEnabled().GetValue()
+		setting.Enabled = &enabled
+	}
+	for _, failover := range in.GetFailover() {
+		setting.Failover = append(setting.Failover, LocalityFailover{From: failover.GetFrom(), To: failover.GetTo()})
+	}
+	for _, distribute := range in.GetDistribute() {
+		setting.Distribute = append(setting.Distribute, LocalityDistribute{From: distribute.GetFrom(), To: maps.Clone(distribute.GetTo())})
+	}
+	if err := setting.validate(); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+func (s *LocalityLbSetting) validate() error {
+	if len(s.Failover) > 0 && len(s.Distribute) > 0 {
+		return fmt.Errorf("locality load balancer setting cannot combine failover and distribute")
+	}
+	for _, failover := range s.Failover {
+		if failover.From == "" || failover.To == "" {
+			return fmt.Errorf("locality failover requires from and to regions")
+		}
+		if strings.Contains(failover.From, "/") || strings.Contains(failover.To, "/") {
+			return fmt.Errorf("locality failover %s -> %s must name regions, not zones", failover.From, failover.To)
+		}
+		if failover.From == failover.To {
+			return fmt.Errorf("locality failover from region %s to itself", failover.From)
+		}
+	}
+	for _, distribute := range s.Distribute {
+		if distribute.From == "" || len(distribute.To) == 0 {
+			return fmt.Errorf("locality distribute requires from and to")
+		}
+		var total uint32
+		for to, weight := range distribute.To {
+			if to == "" || weight == 0 {
+				return fmt.Errorf("locality distribute from %s has an empty locality or zero weight", distribute.From)
+			}
+			total += weight
+		}
+		if total != 100 {
+			return fmt.Errorf("locality distribute from %s has weights adding up to %d, want 100", distribute.From, total)
+		}
+	}
+	return nil
+}
+
+// LocalityLbSettingFromAnnotations parses the per-service locality annotation.
+func LocalityLbSettingFromAnnotations(annotations map[string]string) (*LocalityLbSetting, error) {
+	return ParseLocalityLbSetting(annotations[LocalityLoadBalancerAnnotation])
+}
+
+// LocalityMatch reports whether a locality matches a pattern. A pattern
+// segment of "*" matches anything, and a shorter pattern matches every
+// locality below it.
+func LocalityMatch(locality, pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	have := strings.Split(locality, "/")
+	for i, want := range strings.Split(pattern, "/") {
+		if want == "*" {
+			continue
+		}
+		if i >= len(have) || have[i] != want {
+			return false
+		}
+	}
+	return true
+}
+
+func (ps *PushContext) initLocalityLbSetting() {
+	setting, err := LocalityLbSettingFromMesh(ps.Mesh.GetLocalityLbSetting())
+	if err != nil {
+		log.Warnf("ignoring mesh localityLbSetting: %v", err)
+	}
+	ps.localityLbSetting = setting
+}
+
+// LocalityLbSetting returns the mesh-wide locality setting, or nil when the
+// mesh does not configure one.
+func (ps *PushContext) LocalityLbSetting() *LocalityLbSetting {
+	if ps == nil {
+		return nil
+	}
+	return ps.localityLbSetting
+}
@@ -0,0 +1,98 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestLocalityLbSettingFromAnnotations(t *testing.T) {
+	setting, err := LocalityLbSettingFromAnnotations(map[string]string{
+		LocalityLoadBalancerAnnotation: `{"failover":[{"from":"us-east","to":"us-west"},{"from":"us-east","to":"eu-west"}]}`,
+	})
+	if err != nil {
+		t.Fatalf("parse annotation: %v", err)
+	}
+	if !setting.IsEnabled() {
+		t.Fatal("setting without enabled should be on")
+	}
+	if got := setting.FailoverRegions("us-east"); len(got) != 2 || got[0] != "us-west" || got[1] != "eu-west" {
+		t.Fatalf("failover regions = %v, want [us-west eu-west]", got)
+	}
+	if !setting.Equals(setting.DeepCopy()) {
+		t.Fatal("deep copy is not equal")
+	}
+
+	if setting, err := LocalityLbSettingFromAnnotations(nil); err != nil || setting != nil {
+		t.Fatalf("no annotation = (%+v, %v), want nil", setting, err)
+	}
+	for _, raw := range []string{
+		`{"failover":[{"from":"us-east/zone-a","to":"us-west"}]}`,
+		`{"failover":[{"from":"us-east","to":"us-east"}]}`,
+		`{"distribute":[{"from":"us-east/*","to":{"us-east/zone-a/*":60,"us-east/zone-b/*":30}}]}`,
+		`{"failover":[{"from":"us-east","to":"us-west"}],"distribute":[{"from":"*","to":{"*":100}}]}`,
+		`not json`,
+	} {
+		if _, err := ParseLocalityLbSetting(raw); err == nil {
+			t.Fatalf("setting %s parsed without error", raw)
+		}
+	}
+}
+
+func TestLocalityMatch(t *testing.T) {
+	cases := []struct {
+		locality, pattern string
+		want              bool
+	}{
+		{"us-east/zone-a/rack-1", "*", true},
+		{"us-east/zone-a/rack-1", "us-east", true},
+		{"us-east/zone-a/rack-1", "us-east/*/rack-1", true},
+		{"us-east/zone-a", "us-east/zone-a/*", true},
+		{"us-east/zone-a", "us-east/zone-b/*", false},
+		{"us-east", "us-east/zone-a", false},
+	}
+	for _, tc := range cases {
+		if got := LocalityMatch(tc.locality, tc.pattern); got != tc.want {
+			t.Errorf("LocalityMatch(%q, %q) = %v, want %v", tc.locality, tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestLocalityLbSettingFromMesh(t *testing.T) {
+	setting, err := LocalityLbSettingFromMesh(&networking.LocalityLoadBalancerSetting{
+		Enabled:  wrapperspb.Bool(true),
+		Failover: []*networking.LocalityLoadBalancerSetting_Failover{{From: "us-east", To: "us-west"}},
+	})
+	if err != nil {
+		t.Fatalf("convert mesh setting: %v", err)
+	}
+	if !setting.IsEnabled() || len(setting.FailoverRegions("us-east")) != 1 {
+		t.Fatalf("setting = %+v, want enabled us-east failover", setting)
+	}
+
+	if setting, err := LocalityLbSettingFromMesh(nil); err != nil || setting != nil {
+		t.Fatalf("unset mesh setting = %v, %v; want nil, nil", setting, err)
+	}
+	if _, err := LocalityLbSettingFromMesh(&networking.LocalityLoadBalancerSetting{
+		Failover: []*networking.LocalityLoadBalancerSetting_Failover{{From: "us-east", To: "us-east"}},
+	}); err == nil {
+		t.Fatal("failover to the same region should be rejected")
+	}
+}
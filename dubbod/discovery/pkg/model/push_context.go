@@ -40,6 +40,7 @@ import (
 	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type TriggerReason string
@@ -72,11 +73,17 @@ type PushContext struct {
 	exportToDefaults       exportToDefaults
 	ServiceIndex           serviceIndex
 	httpRouteIndex         httpRouteIndex
+	grpcRouteIndex         grpcRouteIndex
 	dxgateServiceIndex     dxgateServiceIndex
 	backendTLSPolicyIndex  backendTLSPolicyIndex
 	faultInjectionIndex    faultInjectionPolicyIndex
+	circuitBreakerIndex    circuitBreakerPolicyIndex
 	serviceActivationIndex serviceActivationPolicyIndex
+	activationDrains       sets.String
 	serviceAccounts        map[serviceAccountKey][]string
+	extAuthzProviders      map[string]ExtAuthzProvider
+	localityLbSetting      *LocalityLbSetting
+	rateLimitIndex         map[string]RateLimitSettings
 	AuthenticationPolicies *AuthenticationPolicies
 	PushVersion            string
 	ProxyStatus            map[string]map[string]ProxyPushStatus
@@ -144,6 +151,21 @@ type exportToDefaults struct {
 type httpRouteIndex struct {
 	// hostToRoutes keeps the Gateway API HTTPRoutes keyed by hostname
 	hostToRoutes map[host.Name][]config.Config
+	// routedClaims keeps the JWT claims HTTPRoutes attached to a Gateway
+	// match on.
+	routedClaims sets.String
+}
+
+type grpcRouteIndex struct {
+	// serviceRoutes keeps the Gateway API GRPCRoutes keyed by the
+	// namespace/name of every parent Service.
+	serviceRoutes map[string][]config.Config
+	// gatewayRoutes keeps them keyed by the namespace/name of every parent
+	// Gateway.
+	gatewayRoutes map[string][]config.Config
+	// routedClaims keeps the JWT claims GRPCRoutes attached to a Gateway
+	// match on.
+	routedClaims sets.String
 }
 
 type dxgateServiceIndex struct {
@@ -395,6 +417,9 @@ func (ps *PushContext) InitContext(env *Environment, oldPushContext *PushContext
 	ps.Mesh = env.Mesh()
 
 	ps.initDefaultExportMaps()
+	ps.initExtAuthzProviders()
+	ps.initLocalityLbSetting()
+	ps.initServiceActivationDrains(env)
 
 	if pushReq == nil || oldPushContext == nil || !oldPushContext.InitDone.Load() || pushReq.Forced {
 		ps.createNewContext(env)
@@ -569,9 +594,12 @@ func (ps *PushContext) createNewContext(env *Environment) {
 	// Initialize Kubernetes Gateway API resources if the controller is enabled.
 	ps.initKubernetesGateways(env)
 	ps.initHTTPRoutes(env)
+	ps.initGRPCRoutes(env)
 	ps.initDxgateServices(env)
 	ps.initBackendTLSPolicies(env)
 	ps.initFaultInjectionPolicies(env)
+	ps.initRateLimitPolicies(env)
+	ps.initCircuitBreakerPolicies(env)
 	ps.initServiceActivationPolicies(env)
 	ps.initAuthenticationPolicies(env)
 }
@@ -629,6 +657,12 @@ func (ps *PushContext) updateContext(env *Environment, oldPushContext *PushConte
 		ps.httpRouteIndex = oldPushContext.httpRouteIndex
 	}
 
+	if pushReq != nil && HasConfigsOfKind(pushReq.ConfigsUpdated, kind.GRPCRoute) {
+		ps.initGRPCRoutes(env)
+	} else {
+		ps.grpcRouteIndex = oldPushContext.grpcRouteIndex
+	}
+
 	dxgateServicesChanged := pushReq != nil && HasConfigsOfKind(pushReq.ConfigsUpdated, kind.DxgateService)
 	if dxgateServicesChanged {
 		ps.initDxgateServices(env)
@@ -653,6 +687,18 @@ func (ps *PushContext) updateContext(env *Environment, oldPushContext *PushConte
 		ps.faultInjectionIndex = oldPushContext.faultInjectionIndex
 	}
 
+	if pushReq != nil && HasConfigsOfKind(pushReq.ConfigsUpdated, kind.RateLimitPolicy) {
+		ps.initRateLimitPolicies(env)
+	} else {
+		ps.rateLimitIndex = oldPushContext.rateLimitIndex
+	}
+
+	if pushReq != nil && HasConfigsOfKind(pushReq.ConfigsUpdated, kind.CircuitBreakerPolicy) {
+		ps.initCircuitBreakerPolicies(env)
+	} else {
+		ps.circuitBreakerIndex = oldPushContext.circuitBreakerIndex
+	}
+
 	ps.serviceActivationIndex = copyServiceActivationPolicyIndex(oldPushContext.serviceActivationIndex)
 	ps.applyServiceActivationPolicyUpdates(pushReq)
 
@@ -777,12 +823,22 @@ func (ps *PushContext) initHTTPRoutes(env *Environment) {
 	log.Debugf("found %d HTTPRoute configs", len(httproutes))
 
 	hostToRoutes := make(map[host.Name][]config.Config)
+	routedClaims := sets.New[string]()
 	for _, hr := range httproutes {
 		hrSpec, ok := hr.Spec.(*sigsk8siogatewayapiapisv1.HTTPRouteSpec)
 		if !ok {
 			log.Debugf("HTTPRoute %s/%s spec is not HTTPRouteSpec", hr.Namespace, hr.Name)
 			continue
 		}
+		if attachedToGateway(hrSpec.ParentRefs) {
+			for _, rule := range hrSpec.Rules {
+				for _, match := range rule.Matches {
+					for _, header := range match.Headers {
+						insertRoutedClaim(routedClaims, string(header.Name))
+					}
+				}
+			}
+		}
 
 		// Process hostnames from HTTPRoute
 		if len(hrSpec.Hostnames) == 0 {
@@ -807,6 +863,7 @@ func (ps *PushContext) initHTTPRoutes(env *Environment) {
 		}
 	}
 	ps.httpRouteIndex.hostToRoutes = hostToRoutes
+	ps.httpRouteIndex.routedClaims = routedClaims
 	log.Debugf("indexed HTTPRoutes for %d hostnames", len(hostToRoutes))
 	if len(hostToRoutes) > 0 {
 		for hostname, routes := range hostToRoutes {
@@ -815,6 +872,88 @@ func (ps *PushContext) initHTTPRoutes(env *Environment) {
 	}
 }
 
+func (ps *PushContext) initGRPCRoutes(env *Environment) {
+	routes := sortConfigByCreationTime(env.List(gvk.GRPCRoute, NamespaceAll))
+	serviceRoutes := map[string][]config.Config{}
+	gatewayRoutes := map[string][]config.Config{}
+	routedClaims := sets.New[string]()
+	for _, cfg := range routes {
+		spec, ok := cfg.Spec.(*sigsk8siogatewayapiapisv1.GRPCRouteSpec)
+		if !ok {
+			continue
+		}
+		indexed := sets.New[string]()
+		for _, parentRef := range spec.ParentRefs {
+			var index map[string][]config.Config
+			var kind string
+			switch {
+			case isServiceParent(parentRef):
+				index, kind = serviceRoutes, "Service"
+			case isGatewayParent(parentRef):
+				index, kind = gatewayRoutes, "Gateway"
+			default:
+				continue
+			}
+			namespace := cfg.Namespace
+			if parentRef.Namespace != nil {
+				namespace = string(*parentRef.Namespace)
+			}
+			key := namespace + "/" + string(parentRef.Name)
+			if indexed.InsertContains(kind + "/" + key) {
+				continue
+			}
+			index[key] = append(index[key], cfg)
+		}
+		if attachedToGateway(spec.ParentRefs) {
+			for _, rule := range spec.Rules {
+				for _, match := range rule.Matches {
+					for _, header := range match.Headers {
+						insertRoutedClaim(routedClaims, string(header.Name))
+					}
+				}
+			}
+		}
+	}
+	ps.grpcRouteIndex.serviceRoutes = serviceRoutes
+	ps.grpcRouteIndex.gatewayRoutes = gatewayRoutes
+	ps.grpcRouteIndex.routedClaims = routedClaims
+	log.Debugf("indexed GRPCRoutes for %d parent services and %d parent gateways", len(serviceRoutes), len(gatewayRoutes))
+}
+
+// GRPCRoutesForService returns the GRPCRoutes attached to the Service, oldest first.
+func (ps *PushContext) GRPCRoutesForService(namespace, name string) []config.Config {
+	if ps == nil {
+		return nil
+	}
+	return ps.grpcRouteIndex.serviceRoutes[namespace+"/"+name]
+}
+
+// GRPCRoutesForGateway returns the GRPCRoutes attached to the Gateway, oldest first.
+func (ps *PushContext) GRPCRoutesForGateway(namespace, name string) []config.Config {
+	if ps == nil {
+		return nil
+	}
+	return ps.grpcRouteIndex.gatewayRoutes[namespace+"/"+name]
+}
+
+func isServiceParent(parentRef sigsk8siogatewayapiapisv1.ParentReference) bool {
+	return parentRef.Kind != nil && *parentRef.Kind == "Service" && (parentRef.Group == nil || *parentRef.Group == "")
+}
+
+func isGatewayParent(parentRef sigsk8siogatewayapiapisv1.ParentReference) bool {
+	return (parentRef.Kind == nil || *parentRef.Kind == "Gateway") &&
+		(parentRef.Group == nil || *parentRef.Group == sigsk8siogatewayapiapisv1.GroupName)
+}
+
+func attachedToGateway(parentRefs []sigsk8siogatewayapiapisv1.ParentReference) bool {
+	for _, parentRef := range parentRefs {
+		if isGatewayParent(parentRef) {
+			return true
+		}
+	}
+	return false
+}
+
 func (ps *PushContext) initDxgateServices(env *Environment) {
 	services := sortConfigByCreationTime(env.List(gvk.DxgateService, NamespaceAll))
 	index := make(map[string]map[string]config.Config)
@@ -910,7 +1049,7 @@ func (ps *PushContext) initBackendTLSPolicies(env *Environment) {
 			if !isBackendTLSPolicyServiceTarget(target) {
 				continue
 			}
-			key := backendTLSPolicyServiceKey(cfg.Namespace, string(target.Name))
+			key := namespacedServiceKey(cfg.Namespace, string(target.Name))
 			if _, found := serviceTLS[key]; !found {
 				serviceTLS[key] = settings
 			}
@@ -924,7 +1063,7 @@ func (ps *PushContext) BackendTLSForService(namespace, name string) (BackendTLSS
 	if ps == nil || ps.backendTLSPolicyIndex.serviceTLS == nil {
 		return BackendTLSSettings{}, false
 	}
-	settings, found := ps.backendTLSPolicyIndex.serviceTLS[backendTLSPolicyServiceKey(namespace, name)]
+	settings, found := ps.backendTLSPolicyIndex.serviceTLS[namespacedServiceKey(namespace, name)]
 	return settings, found
 }
 
@@ -962,7 +1101,7 @@ func (ps *PushContext) initFaultInjectionPolicies(env *Environment) {
 			continue
 		}
 		for _, target := range spec.GetTargetRefs() {
-			if !isFaultInjectionServiceTarget(target) {
+			if !isPolicyServiceTarget(target) {
 				continue
 			}
 			key := faultInjectionServiceKey(cfg.Namespace, target.GetName(), target.GetSectionName())
@@ -995,7 +1134,10 @@ func faultPercentage(value *wrapperspb.UInt32Value) uint32 {
 	return value.GetValue()
 }
 
-func isFaultInjectionServiceTarget(target *networking.PolicyTargetReference) bool {
+// isPolicyServiceTarget reports whether a policy targetRef names a core
+// Service, the only target fault injection, rate limiting, circuit breaking
+// and activation attach to.
+func isPolicyServiceTarget(target *networking.PolicyTargetReference) bool {
 	if target == nil || target.GetName() == "" {
 		return false
 	}
@@ -1007,6 +1149,112 @@ func faultInjectionServiceKey(namespace, name, section string) string {
 	return namespace + "/" + name + "#" + section
 }
 
+// CircuitBreakerSettings is the part of a CircuitBreakerPolicy that a
+// proxyless gRPC client enforces: the concurrent request limit and
+// success-rate/failure-percentage outlier ejection (gRFC A32 and A50).
+type CircuitBreakerSettings struct {
+	MaxRequests uint32
+	Outlier     *OutlierDetectionSettings
+}
+
+type OutlierDetectionSettings struct {
+	Interval           time.Duration
+	BaseEjectionTime   time.Duration
+	MaxEjectionPercent uint32
+}
+
+type circuitBreakerPolicyIndex struct {
+	services map[string]CircuitBreakerSettings
+}
+
+func (ps *PushContext) initCircuitBreakerPolicies(env *Environment) {
+	policies := sortConfigByCreationTime(env.List(gvk.CircuitBreakerPolicy, NamespaceAll))
+	services := map[string]CircuitBreakerSettings{}
+	for _, cfg := range policies {
+		spec, ok := cfg.Spec.(*networking.CircuitBreakerPolicy)
+		if !ok || spec == nil {
+			continue
+		}
+		settings, ok := proxylessCircuitBreaker(spec)
+		if !ok {
+			continue
+		}
+		for _, target := range spec.GetTargetRefs() {
+			// Section-scoped targets are not honored by dxgate either; keep
+			// both data planes on the same set of policies.
+			if !isPolicyServiceTarget(target) || target.GetSectionName() != "" {
+				continue
+			}
+			key := namespacedServiceKey(cfg.Namespace, target.GetName())
+			if _, found := services[key]; !found {
+				services[key] = settings
+			}
+		}
+	}
+	ps.circuitBreakerIndex.services = services
+	log.Debugf("indexed CircuitBreakerPolicies for %d services", len(services))
+}
+
+func (ps *PushContext) CircuitBreakerForService(namespace, name string) (CircuitBreakerSettings, bool) {
+	if ps == nil || ps.circuitBreakerIndex.services == nil {
+		return CircuitBreakerSettings{}, false
+	}
+	settings, found := ps.circuitBreakerIndex.services[namespacedServiceKey(namespace, name)]
+	return settings, found
+}
+
+func proxylessCircuitBreaker(spec *networking.CircuitBreakerPolicy) (CircuitBreakerSettings, bool) {
+	settings := CircuitBreakerSettings{}
+	if cp := spec.GetConnectionPool(); cp != nil && cp.GetHttp2MaxRequests() > 0 {
+		settings.MaxRequests = uint32(cp.GetHttp2MaxRequests())
+	}
+	if od := spec.GetOutlierDetection(); od != nil {
+		outlier := &OutlierDetectionSettings{}
+		if value := od.GetInterval(); value != nil && value.CheckValid() == nil && value.AsDuration() > 0 {
+			outlier.Interval = value.AsDuration()
+		}
+		if value := od.GetBaseEjectionTime(); value != nil && value.CheckValid() == nil && value.AsDuration() > 0 {
+			outlier.BaseEjectionTime = value.AsDuration()
+		}
+		if od.GetMaxEjectionPercent() > 0 {
+			outlier.MaxEjectionPercent = uint32(min(od.GetMaxEjectionPercent(), 100))
+		}
+		settings.Outlier = outlier
+	}
+	return settings, settings.MaxRequests > 0 || settings.Outlier != nil
+}
+
+// ProxylessUnsupportedCircuitBreakerFields lists the fields of a
+// CircuitBreakerPolicy that a proxyless gRPC client ignores. gRPC only limits
+// concurrent requests per cluster and has no consecutive-error or panic
+// threshold ejection, so these fields take effect at dxgate only.
+func ProxylessUnsupportedCircuitBreakerFields(spec *networking.CircuitBreakerPolicy) []string {
+	var fields []string
+	if cp := spec.GetConnectionPool(); cp != nil {
+		if cp.GetMaxConnections() > 0 {
+			fields = append(fields, "connectionPool.maxConnections")
+		}
+		if cp.GetHttp1MaxPendingRequests() > 0 {
+			fields = append(fields, "connectionPool.http1MaxPendingRequests")
+		}
+		if cp.GetMaxRequestsPerConnection() > 0 {
+			fields = append(fields, "connectionPool.maxRequestsPerConnection")
+		}
+		if cp.GetMaxRetries() > 0 {
+			fields = append(fields, "connectionPool.maxRetries")
+		}
+	}
+	if od := spec.GetOutlierDetection(); od != nil {
+		if od.GetConsecutive_5XxErrors() != nil {
+			fields = append(fields, "outlierDetection.consecutive5xxErrors")
+		}
+		if od.GetMinHealthPercent() > 0 {
+			fields = append(fields, "outlierDetection.minHealthPercent")
+		}
+	}
+	return fields
+}
+
 func supportsSystemBackendTLS(spec *sigsk8siogatewayapiapisv1.BackendTLSPolicySpec) bool {
 	if spec == nil || spec.Validation.Hostname == "" {
 		return false
@@ -1024,7 +1272,8 @@ func isBackendTLSPolicyServiceTarget(target sigsk8siogatewayapiapisv1.LocalPolic
 	return (group == "" || group == "core") && strings.EqualFold(kind, "Service")
 }
 
-func backendTLSPolicyServiceKey(namespace, name string) string {
+// namespacedServiceKey keys the per-Service policy indexes.
+func namespacedServiceKey(namespace, name string) string {
 	return namespace + "/" + name
 }
 
@@ -1035,6 +1284,16 @@ func (ps *PushContext) ServiceAccounts(hostname host.Name, namespace string) []s
 	return ps.serviceAccounts[serviceAccountKey{hostname: hostname, namespace: namespace}]
 }
 
+// ExpandPrincipals returns authorization principals extended with the trust
+// domain aliases of the mesh. Principals of federated trust domains are kept
+// as written.
+func (ps *PushContext) ExpandPrincipals(principals []string) []string {
+	if ps == nil || ps.Mesh == nil {
+		return principals
+	}
+	return spiffe.ExpandPrincipals(principals, ps.Mesh.GetTrustDomain(), ps.Mesh.TrustDomainAliases)
+}
+
 // ServiceActivationEnabled reports whether a structurally valid policy targets
 // this Service. The live ActivatorReady condition cannot gate routing: the first
 // cold request is what makes an Activator report demand for this target.
@@ -1042,10 +1301,39 @@ func (ps *PushContext) ServiceActivationEnabled(namespace, name string) bool {
 	if ps == nil {
 		return false
 	}
-	_, found := ps.serviceActivationIndex.services[backendTLSPolicyServiceKey(namespace, name)]
+	_, found := ps.serviceActivationIndex.services[namespacedServiceKey(namespace, name)]
 	return found
 }
 
+// ServiceActivationDrainSource lists the activated Services that have gone
+// idle, so their callers are rerouted to the Activator before the Service is
+// scaled to zero.
+type ServiceActivationDrainSource interface {
+	DrainingServices() []types.NamespacedName
+}
+
+func (ps *PushContext) initServiceActivationDrains(env *Environment) {
+	ps.activationDrains = nil
+	if env == nil || env.ActivationDrains == nil {
+		return
+	}
+	for _, service := range env.ActivationDrains.DrainingServices() {
+		if ps.activationDrains == nil {
+			ps.activationDrains = sets.New[string]()
+		}
+		ps.activationDrains.Insert(namespacedServiceKey(service.Namespace, service.Name))
+	}
+}
+
+// ServiceActivationDraining reports whether callers of an activated Service
+// go to the Activator even though the Service may still have endpoints.
+func (ps *PushContext) ServiceActivationDraining(namespace, name string) bool {
+	if ps == nil || !ps.activationDrains.Contains(namespacedServiceKey(namespace, name)) {
+		return false
+	}
+	return ps.ServiceActivationEnabled(namespace, name)
+}
+
 // ActivationGatewayService returns the dedicated namespace-local Activator.
 func (ps *PushContext) ActivationGatewayService(namespace string) *Service {
 	if ps == nil {
@@ -1067,7 +1355,7 @@ func (ps *PushContext) ActivationGatewaySANs(namespace string) []string {
 		return nil
 	}
 	identities := sets.New(spiffe.MustGenSpiffeURI(ps.Mesh, namespace, ActivationGatewayServiceName))
-	return sets.SortedList(spiffe.ExpandWithTrustDomains(identities, ps.Mesh.TrustDomainAliases))
+	return sets.SortedList(spiffe.ExpandWithTrustDomainAliases(identities, ps.Mesh.GetTrustDomain(), ps.Mesh.TrustDomainAliases))
 }
 
 // ActivationBackendSANs returns the backend identities declared by the policy.
@@ -1077,7 +1365,7 @@ func (ps *PushContext) ActivationBackendSANs(namespace, name string) []string {
 	if ps == nil || ps.Mesh == nil || namespace == "" {
 		return nil
 	}
-	accounts, found := ps.serviceActivationIndex.services[backendTLSPolicyServiceKey(namespace, name)]
+	accounts, found := ps.serviceActivationIndex.services[namespacedServiceKey(namespace, name)]
 	if !found {
 		return nil
 	}
@@ -1093,7 +1381,7 @@ func (ps *PushContext) ActivationBackendSANs(namespace, name string) []string {
 		}
 		generated := sets.New(spiffe.MustGenSpiffeURI(ps.Mesh, namespace, account))
 		identities.InsertAll(sets.SortedList(
-			spiffe.ExpandWithTrustDomains(generated, ps.Mesh.TrustDomainAliases),
+			spiffe.ExpandWithTrustDomainAliases(generated, ps.Mesh.GetTrustDomain(), ps.Mesh.TrustDomainAliases),
 		)...)
 	}
 	return sets.SortedList(identities)
@@ -1153,7 +1441,7 @@ func (ps *PushContext) deleteServiceActivationPolicy(cfg config.Config) {
 	spec, ok := cfg.Spec.(*networking.ServiceActivationPolicy)
 	if ok && spec != nil && spec.GetTargetRef() != nil {
 		delete(ps.serviceActivationIndex.services,
-			backendTLSPolicyServiceKey(cfg.Namespace, spec.GetTargetRef().GetName()))
+			namespacedServiceKey(cfg.Namespace, spec.GetTargetRef().GetName()))
 	}
 }
 
@@ -1170,7 +1458,7 @@ func (ps *PushContext) upsertServiceActivationPolicy(cfg config.Config) {
 		len(spec.GetBackendServiceAccounts()) == 0 {
 		return
 	}
-	ps.serviceActivationIndex.services[backendTLSPolicyServiceKey(cfg.Namespace, target.GetName())] =
+	ps.serviceActivationIndex.services[namespacedServiceKey(cfg.Namespace, target.GetName())] =
 		append([]string(nil), spec.GetBackendServiceAccounts()...)
 }
 
@@ -1191,7 +1479,7 @@ func (ps *PushContext) initServiceAccounts(env *Environment, services []*Service
 				accounts = accounts.InsertAll(svc.ServiceAccounts...)
 			}
 		}
-		sa := sets.SortedList(spiffe.ExpandWithTrustDomains(accounts, ps.Mesh.TrustDomainAliases))
+		sa := sets.SortedList(spiffe.ExpandWithTrustDomainAliases(accounts, ps.Mesh.GetTrustDomain(), ps.Mesh.TrustDomainAliases))
 		key := serviceAccountKey{
 			hostname:  svc.Hostname,
 			namespace: svc.Attributes.Namespace,
@@ -0,0 +1,149 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+)
+
+// RateLimitKey selects the token bucket a request draws from.
+type RateLimitKey string
+
+const (
+	// RateLimitKeyService shares one bucket between all callers.
+	RateLimitKeyService RateLimitKey = "service"
+	// RateLimitKeyCaller gives every mTLS peer identity its own bucket.
+	// Plaintext callers share one bucket.
+	RateLimitKeyCaller RateLimitKey = "caller"
+	// RateLimitKeyHeader gives every value of Header its own bucket.
+	// Requests without the header share one bucket.
+	RateLimitKeyHeader RateLimitKey = "header"
+	// RateLimitKeyMethod gives every gRPC method its own bucket.
+	RateLimitKeyMethod RateLimitKey = "method"
+)
+
+// MinRateLimitFillInterval bounds how often buckets are refilled.
+const MinRateLimitFillInterval = 50 * time.Millisecond
+
+// RateLimitSettings is the part of a RateLimitPolicy a workload enforces on
+// its inbound requests. Every workload behind a target keeps its own bucket,
+// so the service as a whole admits up to the limit times its replica count.
+// A bucket holds up to MaxTokens tokens and gains TokensPerFill of them every
+// FillInterval; requests finding it empty are rejected with
+// RESOURCE_EXHAUSTED (HTTP 429).
+type RateLimitSettings struct {
+	Name          string
+	Namespace     string
+	MaxTokens     uint32
+	TokensPerFill uint32
+	FillInterval  time.Duration
+	Key           RateLimitKey
+	Header        string
+}
+
+// RateLimitSettingsFromPolicy compiles the spec of a RateLimitPolicy. It
+// reports false when the spec cannot be enforced; validation rejects such
+// policies before they are stored.
+func RateLimitSettingsFromPolicy(cfg config.Config) (RateLimitSettings, bool) {
+	spec, ok := cfg.Spec.(*networking.RateLimitPolicy)
+	if !ok || spec == nil {
+		return RateLimitSettings{}, false
+	}
+	bucket := spec.GetTokenBucket()
+	interval := bucket.GetFillInterval()
+	if bucket.GetMaxTokens() == 0 || interval == nil || interval.CheckValid() != nil || interval.AsDuration() < MinRateLimitFillInterval {
+		return RateLimitSettings{}, false
+	}
+	settings := RateLimitSettings{
+		Name:          cfg.Name,
+		Namespace:     cfg.Namespace,
+		MaxTokens:     bucket.GetMaxTokens(),
+		TokensPerFill: bucket.GetTokensPerFill(),
+		FillInterval:  interval.AsDuration(),
+	}
+	if settings.TokensPerFill == 0 || settings.TokensPerFill > settings.MaxTokens {
+		settings.TokensPerFill = settings.MaxTokens
+	}
+	switch spec.GetKey() {
+	case networking.RateLimitPolicy_CALLER:
+		settings.Key = RateLimitKeyCaller
+	case networking.RateLimitPolicy_HEADER:
+		if spec.GetHeader() == "" {
+			return RateLimitSettings{}, false
+		}
+		settings.Key = RateLimitKeyHeader
+		settings.Header = spec.GetHeader()
+	case networking.RateLimitPolicy_METHOD:
+		settings.Key = RateLimitKeyMethod
+	default:
+		settings.Key = RateLimitKeyService
+	}
+	return settings, true
+}
+
+// IndexRateLimitPolicies maps every Service port target to the settings of
+// the policy that applies to it. The oldest policy wins when several target
+// the same port.
+func IndexRateLimitPolicies(policies []config.Config) map[string]RateLimitSettings {
+	index := map[string]RateLimitSettings{}
+	for _, cfg := range sortConfigByCreationTime(policies) {
+		settings, ok := RateLimitSettingsFromPolicy(cfg)
+		if !ok {
+			continue
+		}
+		for _, target := range cfg.Spec.(*networking.RateLimitPolicy).GetTargetRefs() {
+			if !isPolicyServiceTarget(target) {
+				continue
+			}
+			key := faultInjectionServiceKey(cfg.Namespace, target.GetName(), target.GetSectionName())
+			if _, found := index[key]; !found {
+				index[key] = settings
+			}
+		}
+	}
+	return index
+}
+
+// RateLimitPolicyFor looks up the settings of a Service port in an index
+// built by IndexRateLimitPolicies. Port targets take precedence over Service
+// ones.
+func RateLimitPolicyFor(index map[string]RateLimitSettings, namespace, name, portName string) (RateLimitSettings, bool) {
+	if portName != "" {
+		if settings, found := index[faultInjectionServiceKey(namespace, name, portName)]; found {
+			return settings, true
+		}
+	}
+	settings, found := index[faultInjectionServiceKey(namespace, name, "")]
+	return settings, found
+}
+
+func (ps *PushContext) initRateLimitPolicies(env *Environment) {
+	ps.rateLimitIndex = IndexRateLimitPolicies(env.List(gvk.RateLimitPolicy, NamespaceAll))
+	log.Debugf("indexed RateLimitPolicies for %d service targets", len(ps.rateLimitIndex))
+}
+
+// RateLimitForService returns the rate limit a Service port enforces.
+func (ps *PushContext) RateLimitForService(namespace, name, portName string) (RateLimitSettings, bool) {
+	if ps == nil || ps.rateLimitIndex == nil {
+		return RateLimitSettings{}, false
+	}
+	return RateLimitPolicyFor(ps.rateLimitIndex, namespace, name, portName)
+}
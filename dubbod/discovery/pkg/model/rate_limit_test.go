@@ -0,0 +1,93 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func rateLimitPolicy(name string, created time.Time, spec *networking.RateLimitPolicy) config.Config {
+	return config.Config{
+		Meta: config.Meta{GroupVersionKind: gvk.RateLimitPolicy, Name: name, Namespace: "app", CreationTimestamp: created},
+		Spec: spec,
+	}
+}
+
+func TestRateLimitSettingsFromPolicy(t *testing.T) {
+	settings, ok := RateLimitSettingsFromPolicy(rateLimitPolicy("orders", time.Time{}, &networking.RateLimitPolicy{
+		TokenBucket: &networking.TokenBucket{MaxTokens: 100, FillInterval: durationpb.New(time.Second)},
+		Key:         networking.RateLimitPolicy_CALLER,
+	}))
+	if !ok {
+		t.Fatal("expected the policy to compile")
+	}
+	if settings.Namespace != "app" || settings.Name != "orders" {
+		t.Fatalf("policy = %s/%s, want app/orders", settings.Namespace, settings.Name)
+	}
+	if settings.TokensPerFill != 100 || settings.FillInterval != time.Second || settings.Key != RateLimitKeyCaller {
+		t.Fatalf("bucket = %d per %v by %s, want 100 per 1s by caller", settings.TokensPerFill, settings.FillInterval, settings.Key)
+	}
+
+	for name, spec := range map[string]*networking.RateLimitPolicy{
+		"no bucket":   {},
+		"no tokens":   {TokenBucket: &networking.TokenBucket{FillInterval: durationpb.New(time.Second)}},
+		"fast refill": {TokenBucket: &networking.TokenBucket{MaxTokens: 1, FillInterval: durationpb.New(time.Millisecond)}},
+		"no header": {
+			TokenBucket: &networking.TokenBucket{MaxTokens: 1, FillInterval: durationpb.New(time.Second)},
+			Key:         networking.RateLimitPolicy_HEADER,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := RateLimitSettingsFromPolicy(rateLimitPolicy("orders", time.Time{}, spec)); ok {
+				t.Fatal("expected the policy to be rejected")
+			}
+		})
+	}
+}
+
+func TestIndexRateLimitPoliciesPrefersOldestAndPortTargets(t *testing.T) {
+	now := time.Now()
+	bucket := &networking.TokenBucket{MaxTokens: 10, FillInterval: durationpb.New(time.Second)}
+	older := rateLimitPolicy("older", now.Add(-time.Hour), &networking.RateLimitPolicy{
+		TargetRefs:  []*networking.PolicyTargetReference{{Kind: "Service", Name: "orders"}},
+		TokenBucket: bucket,
+	})
+	newer := rateLimitPolicy("newer", now, &networking.RateLimitPolicy{
+		TargetRefs: []*networking.PolicyTargetReference{
+			{Kind: "Service", Name: "orders"},
+			{Kind: "Service", Name: "orders", SectionName: "grpc"},
+		},
+		TokenBucket: bucket,
+	})
+	index := IndexRateLimitPolicies([]config.Config{newer, older})
+
+	if settings, _ := RateLimitPolicyFor(index, "app", "orders", "http"); settings.Name != "older" {
+		t.Fatalf("http port policy = %q, want the older Service-wide policy", settings.Name)
+	}
+	if settings, _ := RateLimitPolicyFor(index, "app", "orders", "grpc"); settings.Name != "newer" {
+		t.Fatalf("grpc port policy = %q, want the port-targeted policy", settings.Name)
+	}
+	if _, found := RateLimitPolicyFor(index, "other", "orders", ""); found {
+		t.Fatal("policies must not apply to Services of other namespaces")
+	}
+}
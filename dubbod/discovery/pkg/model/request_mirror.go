@@ -0,0 +1,40 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	sigsk8siogatewayapiapisv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// RequestMirrorFraction returns the share of requests a RequestMirror filter
+// shadows as numerator/denominator, keeping the filter's own denominator so
+// shares under one percent survive translation. Neither Percent nor Fraction
+// set means every request is mirrored.
+func RequestMirrorFraction(mirror *sigsk8siogatewayapiapisv1.HTTPRequestMirrorFilter) (numerator, denominator uint32) {
+	switch {
+	case mirror.Percent != nil:
+		return uint32(min(max(*mirror.Percent, 0), 100)), 100
+	case mirror.Fraction != nil:
+		d := int32(100)
+		if mirror.Fraction.Denominator != nil && *mirror.Fraction.Denominator > 0 {
+			d = *mirror.Fraction.Denominator
+		}
+		return uint32(min(max(mirror.Fraction.Numerator, 0), d)), uint32(d)
+	default:
+		return 100, 100
+	}
+}
@@ -0,0 +1,56 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	sigsk8siogatewayapiapisv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRequestMirrorFraction(t *testing.T) {
+	percent := int32(10)
+	thousand := int32(1000)
+	cases := []struct {
+		name                   string
+		mirror                 *sigsk8siogatewayapiapisv1.HTTPRequestMirrorFilter
+		numerator, denominator uint32
+	}{
+		{name: "unset", mirror: &sigsk8siogatewayapiapisv1.HTTPRequestMirrorFilter{}, numerator: 100, denominator: 100},
+		{name: "percent", mirror: &sigsk8siogatewayapiapisv1.HTTPRequestMirrorFilter{Percent: &percent}, numerator: 10, denominator: 100},
+		{
+			name:        "sub-percent fraction",
+			mirror:      &sigsk8siogatewayapiapisv1.HTTPRequestMirrorFilter{Fraction: &sigsk8siogatewayapiapisv1.Fraction{Numerator: 5, Denominator: &thousand}},
+			numerator:   5,
+			denominator: 1000,
+		},
+		{
+			name:        "fraction above one",
+			mirror:      &sigsk8siogatewayapiapisv1.HTTPRequestMirrorFilter{Fraction: &sigsk8siogatewayapiapisv1.Fraction{Numerator: 2000, Denominator: &thousand}},
+			numerator:   1000,
+			denominator: 1000,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			numerator, denominator := RequestMirrorFraction(tc.mirror)
+			if numerator != tc.numerator || denominator != tc.denominator {
+				t.Fatalf("RequestMirrorFraction() = %d/%d, want %d/%d", numerator, denominator, tc.numerator, tc.denominator)
+			}
+		})
+	}
+}
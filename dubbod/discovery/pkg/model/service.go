@@ -127,6 +127,14 @@ type ServiceAttributes struct {
 	// Namespace is "destination.service.namespace" attribute
 	Namespace       string
 	ServiceRegistry provider.ID
+	// LoadBalancer overrides the mesh-wide load balancing policy for this service.
+	LoadBalancer *LoadBalancerSettings
+	// LocalityLoadBalancer overrides the mesh-wide locality load balancing
+	// setting for this service.
+	LocalityLoadBalancer *LocalityLbSetting
+	// DubboInterfaces lists the interface, group and version combinations the
+	// service provides, sorted by service key.
+	DubboInterfaces []DubboInterface
 	K8sAttributes
 }
 
@@ -264,6 +272,9 @@ func (s *ServiceAttributes) DeepCopy() ServiceAttributes {
 
 	out.Aliases = slices.Clone(s.Aliases)
 	out.PassthroughTargetPorts = maps.Clone(out.PassthroughTargetPorts)
+	out.LoadBalancer = s.LoadBalancer.DeepCopy()
+	out.LocalityLoadBalancer = s.LocalityLoadBalancer.DeepCopy()
+	out.DubboInterfaces = slices.Clone(s.DubboInterfaces)
 
 	// nolint: govet
 	return out
@@ -312,6 +323,15 @@ func (s *ServiceAttributes) Equals(other *ServiceAttributes) bool {
 			return false
 		}
 	}
+	if !s.LoadBalancer.Equals(other.LoadBalancer) {
+		return false
+	}
+	if !s.LocalityLoadBalancer.Equals(other.LocalityLoadBalancer) {
+		return false
+	}
+	if !slices.Equal(s.DubboInterfaces, other.DubboInterfaces) {
+		return false
+	}
 	return s.Name == other.Name && s.Namespace == other.Namespace &&
 		s.ServiceRegistry == other.ServiceRegistry && s.K8sAttributes == other.K8sAttributes
 }
@@ -292,7 +292,7 @@ func compileAgentMatches(hostnames []gatewayv1.Hostname, matches []gatewayv1.HTT
 			}
 			for _, header := range match.Headers {
 				compiled.Headers = append(compiled.Headers, &route.AgentHeaderMatch{
-					Name: string(header.Name), Value: header.Value,
+					Name: routeHeaderName(string(header.Name)), Value: header.Value,
 				})
 			}
 			out = append(out, compiled)
@@ -31,6 +31,8 @@ import (
 	cluster "github.com/kdubbo/xds-api/cluster/v1"
 	core "github.com/kdubbo/xds-api/core/v1"
 	tlsv1 "github.com/kdubbo/xds-api/extensions/transport_sockets/tls/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type clusterBuilder struct {
@@ -111,13 +113,28 @@ func newClusterBuilder(node *model.Proxy, push *model.PushContext, defaultCluste
 }
 
 func (b *clusterBuilder) build() []*cluster.Cluster {
-	defaultRequested := b.filter == nil || b.filter.Contains(b.defaultClusterName)
-	if !defaultRequested {
-		return nil
+	var clusters []*cluster.Cluster
+	if b.filter == nil || b.filter.Contains(b.defaultClusterName) {
+		clusters = append(clusters, b.buildCluster(b.defaultClusterName))
+	}
+	// Dubbo group/version subsets share every setting of the default
+	// cluster; only their EDS assignment is narrowed to matching endpoints.
+	for _, name := range sets.SortedList(b.filter) {
+		if name == b.defaultClusterName {
+			continue
+		}
+		_, subset, _, _ := model.ParseSubsetKey(name)
+		if _, _, ok := model.ParseDubboSubsetName(subset); !ok {
+			continue
+		}
+		clusters = append(clusters, b.buildCluster(name))
 	}
+	return clusters
+}
 
-	defaultCluster := b.edsCluster(b.defaultClusterName)
-	defaultCluster.CommonLbConfig = &cluster.Cluster_CommonLbConfig{
+func (b *clusterBuilder) buildCluster(name string) *cluster.Cluster {
+	c := b.edsCluster(name)
+	c.CommonLbConfig = &cluster.Cluster_CommonLbConfig{
 		OverrideHostStatus: &core.HealthStatusSet{
 			Statuses: []core.HealthStatus{
 				core.HealthStatus_HEALTHY,
@@ -128,14 +145,16 @@ func (b *clusterBuilder) build() []*cluster.Cluster {
 			},
 		},
 	}
+	b.applyLoadBalancer(c)
+	b.applyCircuitBreaker(c)
 	if b.requiresPeerAuthenticationMTLS() {
-		b.applyPeerAuthenticationMTLS(defaultCluster)
+		b.applyPeerAuthenticationMTLS(c)
 	}
 	if b.node != nil && b.node.IsRouter() {
-		b.applyBackendTLSPolicy(defaultCluster)
+		b.applyBackendTLSPolicy(c)
 	}
-	log.Debugf("generated cluster %s", b.defaultClusterName)
-	return []*cluster.Cluster{defaultCluster}
+	log.Debugf("generated cluster %s", name)
+	return c
 }
 
 func (b *clusterBuilder) requiresPeerAuthenticationMTLS() bool {
@@ -189,6 +208,8 @@ func defaultLbPolicy() cluster.Cluster_LbPolicy {
 		return cluster.Cluster_LEAST_REQUEST
 	case "RING_HASH":
 		return cluster.Cluster_RING_HASH
+	case "MAGLEV":
+		return cluster.Cluster_MAGLEV
 	case "RANDOM":
 		return cluster.Cluster_RANDOM
 	case "ROUND_ROBIN", "":
@@ -199,6 +220,84 @@ func defaultLbPolicy() cluster.Cluster_LbPolicy {
 	}
 }
 
+// applyLoadBalancer overrides the mesh-wide policy with the service's own
+// load balancing settings. Hash keys are carried by the route's HashPolicy; the
+// cluster only selects the consistent-hash algorithm and its sizing.
+func (b *clusterBuilder) applyLoadBalancer(c *cluster.Cluster) {
+	if c == nil || b.svc == nil || b.svc.Attributes.LoadBalancer == nil {
+		return
+	}
+	settings := b.svc.Attributes.LoadBalancer
+	switch settings.Policy {
+	case model.LoadBalancerRoundRobin:
+		c.LbPolicy = cluster.Cluster_ROUND_ROBIN
+	case model.LoadBalancerLeastRequest:
+		c.LbPolicy = cluster.Cluster_LEAST_REQUEST
+	case model.LoadBalancerRandom:
+		c.LbPolicy = cluster.Cluster_RANDOM
+	case model.LoadBalancerRingHash:
+		c.LbPolicy = cluster.Cluster_RING_HASH
+		if settings.MinimumRingSize > 0 {
+			c.LbConfig = &cluster.Cluster_RingHashLbConfig_{
+				RingHashLbConfig: &cluster.Cluster_RingHashLbConfig{
+					MinimumRingSize: wrapperspb.UInt64(settings.MinimumRingSize),
+				},
+			}
+		}
+	case model.LoadBalancerMaglev:
+		c.LbPolicy = cluster.Cluster_MAGLEV
+		if settings.MaglevTableSize > 0 {
+			c.LbConfig = &cluster.Cluster_MaglevLbConfig_{
+				MaglevLbConfig: &cluster.Cluster_MaglevLbConfig{
+					TableSize: wrapperspb.UInt64(settings.MaglevTableSize),
+				},
+			}
+		}
+	}
+	if settings.IsConsistentHash() && len(settings.HashKeys) == 0 {
+		log.Warnf("service %s uses %s without hash keys; requests will be spread randomly", b.svc.Hostname, settings.Policy)
+	}
+}
+
+// applyCircuitBreaker compiles the service's CircuitBreakerPolicy into the
+// parts gRPC enforces. gRPC ignores the consecutive-error fields of
+// OutlierDetection, so the policy turns on success-rate and failure-percentage
+// ejection instead, both with Envoy's default thresholds.
+func (b *clusterBuilder) applyCircuitBreaker(c *cluster.Cluster) {
+	if c == nil || b.svc == nil || b.push == nil {
+		return
+	}
+	settings, found := b.push.CircuitBreakerForService(b.svc.Attributes.Namespace, b.svc.Attributes.Name)
+	if !found {
+		return
+	}
+	if settings.MaxRequests > 0 {
+		c.CircuitBreakers = &cluster.CircuitBreakers{
+			Thresholds: []*cluster.CircuitBreakers_Thresholds{{
+				Priority:    core.RoutingPriority_DEFAULT,
+				MaxRequests: wrapperspb.UInt32(settings.MaxRequests),
+			}},
+		}
+	}
+	if settings.Outlier != nil {
+		outlier := &cluster.OutlierDetection{
+			EnforcingSuccessRate:       wrapperspb.UInt32(100),
+			EnforcingFailurePercentage: wrapperspb.UInt32(100),
+		}
+		if settings.Outlier.Interval > 0 {
+			outlier.Interval = durationpb.New(settings.Outlier.Interval)
+		}
+		if settings.Outlier.BaseEjectionTime > 0 {
+			outlier.BaseEjectionTime = durationpb.New(settings.Outlier.BaseEjectionTime)
+		}
+		if settings.Outlier.MaxEjectionPercent > 0 {
+			outlier.MaxEjectionPercent = wrapperspb.UInt32(settings.Outlier.MaxEjectionPercent)
+		}
+		c.OutlierDetection = outlier
+	}
+	log.Debugf("applied CircuitBreakerPolicy to cluster %s", c.Name)
+}
+
 func (b *clusterBuilder) applyBackendTLSPolicy(c *cluster.Cluster) {
 	if c == nil || c.TransportSocket != nil || b.svc == nil || b.push == nil {
 		return
@@ -27,6 +27,8 @@ import (
 	security "github.com/kdubbo/api/security/v1alpha3"
 	cluster "github.com/kdubbo/xds-api/cluster/v1"
 	tlsv1 "github.com/kdubbo/xds-api/extensions/transport_sockets/tls/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -190,3 +192,112 @@ func newActivationPolicyConfig(name, namespace, serviceName string) config.Confi
 		},
 	}
 }
+
+func TestBuildClustersAppliesServiceConsistentHashPolicy(t *testing.T) {
+	hostName := "session.app.svc.cluster.local"
+	service := newRDSTestService("session", "app", hostName, 20880)
+	service.Attributes.LoadBalancer = &model.LoadBalancerSettings{
+		Policy:          model.LoadBalancerRingHash,
+		HashKeys:        []model.HashKey{{Type: model.HashKeyHeader, Name: "x-user-id"}},
+		MinimumRingSize: 2048,
+	}
+	push := newRDSTestPushContext(t, nil, []*model.Service{service})
+
+	resources := (&GrpcConfigGenerator{}).BuildClusters(&model.Proxy{
+		ID:   "inherent~10.0.0.2~caller.app~app.svc.cluster.local",
+		Type: model.Inherent,
+	}, push, []string{"outbound|20880||" + hostName})
+	if len(resources) != 1 {
+		t.Fatalf("resources = %d, want 1", len(resources))
+	}
+	c := &cluster.Cluster{}
+	if err := resources[0].GetResource().UnmarshalTo(c); err != nil {
+		t.Fatalf("unmarshal cluster: %v", err)
+	}
+	if c.GetLbPolicy() != cluster.Cluster_RING_HASH {
+		t.Fatalf("lb policy = %v, want RING_HASH", c.GetLbPolicy())
+	}
+	if got := c.GetRingHashLbConfig().GetMinimumRingSize().GetValue(); got != 2048 {
+		t.Fatalf("minimum ring size = %d, want 2048", got)
+	}
+}
+
+func TestBuildClustersAppliesCircuitBreakerPolicy(t *testing.T) {
+	hostName := "reviews.moviereview.svc.cluster.local"
+	service := newRDSTestService("reviews", "moviereview", hostName, 9080)
+	policy := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.CircuitBreakerPolicy,
+			Name:             "reviews-cb",
+			Namespace:        "moviereview",
+		},
+		Spec: &networking.CircuitBreakerPolicy{
+			TargetRefs: []*networking.PolicyTargetReference{{Kind: "Service", Name: "reviews"}},
+			ConnectionPool: &networking.ConnectionPoolSettings{
+				MaxConnections:   10,
+				Http2MaxRequests: 64,
+			},
+			OutlierDetection: &networking.OutlierDetection{
+				Consecutive_5XxErrors: wrapperspb.UInt32(5),
+				Interval:              durationpb.New(5 * time.Second),
+				BaseEjectionTime:      durationpb.New(30 * time.Second),
+				MaxEjectionPercent:    50,
+			},
+		},
+	}
+	push := newRDSTestPushContext(t, []config.Config{policy}, []*model.Service{service})
+
+	resources := (&GrpcConfigGenerator{}).BuildClusters(&model.Proxy{
+		ID:   "inherent~10.0.0.2~productpage.moviereview~moviereview.svc.cluster.local",
+		Type: model.Inherent,
+	}, push, []string{"outbound|9080||" + hostName})
+	if len(resources) != 1 {
+		t.Fatalf("resources = %d, want 1", len(resources))
+	}
+	c := &cluster.Cluster{}
+	if err := resources[0].GetResource().UnmarshalTo(c); err != nil {
+		t.Fatalf("unmarshal cluster: %v", err)
+	}
+	thresholds := c.GetCircuitBreakers().GetThresholds()
+	if len(thresholds) != 1 || thresholds[0].GetMaxRequests().GetValue() != 64 {
+		t.Fatalf("circuit breaker thresholds = %v, want max_requests 64", thresholds)
+	}
+	outlier := c.GetOutlierDetection()
+	if outlier == nil {
+		t.Fatal("expected outlier detection on cluster")
+	}
+	if outlier.GetInterval().AsDuration() != 5*time.Second ||
+		outlier.GetBaseEjectionTime().AsDuration() != 30*time.Second ||
+		outlier.GetMaxEjectionPercent().GetValue() != 50 {
+		t.Fatalf("outlier detection = %v", outlier)
+	}
+	if outlier.GetEnforcingSuccessRate().GetValue() != 100 || outlier.GetEnforcingFailurePercentage().GetValue() != 100 {
+		t.Fatalf("expected success-rate and failure-percentage ejection enforced, got %v", outlier)
+	}
+}
+
+func TestBuildClustersBuildsDubboSubsetClusters(t *testing.T) {
+	hostName := "greeter.app.svc.cluster.local"
+	service := newRDSTestService("greeter", "app", hostName, 50051)
+	service.Attributes.DubboInterfaces = []model.DubboInterface{{Name: "org.example.Greeter", Version: "2.0.0"}}
+	push := newRDSTestPushContext(t, nil, []*model.Service{service})
+
+	subsetCluster := "outbound|50051|" + model.DubboSubsetName("", "2.0.0") + "|" + hostName
+	resources := (&GrpcConfigGenerator{}).BuildClusters(&model.Proxy{
+		ID:   "inherent~10.0.0.2~caller.app~app.svc.cluster.local",
+		Type: model.Inherent,
+	}, push, []string{"outbound|50051||" + hostName, subsetCluster, "outbound|50051|unknown|" + hostName})
+	if len(resources) != 2 {
+		t.Fatalf("resources = %d, want default and dubbo subset clusters", len(resources))
+	}
+	if resources[1].GetName() != subsetCluster {
+		t.Fatalf("second cluster = %q, want %q", resources[1].GetName(), subsetCluster)
+	}
+	c := &cluster.Cluster{}
+	if err := resources[1].GetResource().UnmarshalTo(c); err != nil {
+		t.Fatalf("unmarshal cluster: %v", err)
+	}
+	if c.GetEdsClusterConfig().GetServiceName() != subsetCluster {
+		t.Fatalf("EDS service name = %q, want %q", c.GetEdsClusterConfig().GetServiceName(), subsetCluster)
+	}
+}
@@ -0,0 +1,114 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	route "github.com/kdubbo/xds-api/route/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// dubboInterfaceRoutes sends Triple calls that pin a version or group of one
+// of the service's Dubbo interfaces to the subset cluster of those
+// providers, so one Service can carry several versions of an interface.
+// Calls that pin nothing fall through to the default route.
+func dubboInterfaceRoutes(svc *model.Service, port int, faultPolicy *route.FaultPolicy) []*route.Route {
+	var routes []*route.Route
+	for _, iface := range svc.Attributes.DubboInterfaces {
+		subset := iface.SubsetName()
+		if subset == "" {
+			continue
+		}
+		r := defaultSingleClusterRoute(model.BuildSubsetKey(model.TrafficDirectionOutbound, subset, svc.Hostname, port), faultPolicy)
+		r.Match = &route.RouteMatch{
+			PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/" + iface.Name + "/"},
+			Headers:       dubboSubsetHeaderMatchers(iface.Group, iface.Version),
+		}
+		routes = append(routes, r)
+	}
+	// A group and version pair is more specific than a version alone.
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].Match.Headers) > len(routes[j].Match.Headers)
+	})
+	return routes
+}
+
+func dubboSubsetHeaderMatchers(group, version string) []*route.HeaderMatcher {
+	var headers []*route.HeaderMatcher
+	if version != "" {
+		headers = append(headers, &route.HeaderMatcher{
+			Name:                 model.DubboVersionHeader,
+			HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{ExactMatch: version},
+		})
+	}
+	if group != "" {
+		headers = append(headers, &route.HeaderMatcher{
+			Name:                 model.DubboGroupHeader,
+			HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{ExactMatch: group},
+		})
+	}
+	return headers
+}
+
+// selectDubboSubset narrows a route whose match pins the Triple version or
+// group header to an exact value. Backends that declare Dubbo interfaces are
+// replaced by their subset for that group and version; other backends are
+// left alone, as they may already be one Service per version.
+func selectDubboSubset(push *model.PushContext, r *route.Route) {
+	if push == nil || r.GetMatch() == nil || r.GetRoute().GetWeightedClusters() == nil {
+		return
+	}
+	var group, version string
+	for _, header := range r.Match.Headers {
+		exact, ok := header.HeaderMatchSpecifier.(*route.HeaderMatcher_ExactMatch)
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.EqualFold(header.Name, model.DubboVersionHeader):
+			version = exact.ExactMatch
+		case strings.EqualFold(header.Name, model.DubboGroupHeader):
+			group = exact.ExactMatch
+		}
+	}
+	if group == "" && version == "" {
+		return
+	}
+	subset := model.DubboSubsetName(group, version)
+
+	// The action is shared by every match of the rule; copy before editing.
+	action := proto.Clone(r.GetRoute()).(*route.RouteAction)
+	changed := false
+	for _, weight := range action.GetWeightedClusters().GetClusters() {
+		dir, existing, hostname, port := model.ParseSubsetKey(weight.Name)
+		if existing != "" || hostname == "" {
+			continue
+		}
+		svc := push.ServiceForHostname(nil, hostname)
+		if svc == nil || len(svc.Attributes.DubboInterfaces) == 0 {
+			continue
+		}
+		weight.Name = model.BuildSubsetKey(dir, subset, hostname, port)
+		changed = true
+	}
+	if changed {
+		r.Action = &route.Route_Route{Route: action}
+	}
+}
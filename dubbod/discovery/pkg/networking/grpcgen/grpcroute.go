@@ -0,0 +1,178 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"regexp"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	route "github.com/kdubbo/xds-api/route/v1"
+	matcher "github.com/kdubbo/xds-api/type/matcher/v1"
+	sigsk8siogatewayapiapisv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// filterGRPCRoutesByService keeps the GRPCRoutes whose Service parentRef
+// selects the given port of svc.
+func filterGRPCRoutesByService(grpcRoutes []config.Config, svc *model.Service, port int) []config.Config {
+	if svc == nil {
+		return nil
+	}
+	var filtered []config.Config
+	for _, gr := range grpcRoutes {
+		grSpec, ok := gr.Spec.(*sigsk8siogatewayapiapisv1.GRPCRouteSpec)
+		if !ok {
+			continue
+		}
+		if routeReferencesService(grSpec.ParentRefs, gr.Namespace, svc, port) {
+			filtered = append(filtered, gr)
+		}
+	}
+	return filtered
+}
+
+// buildRoutesFromGatewayGRPCRoute converts Gateway API GRPCRoute resources to
+// XDS routes. gRPC requests carry "/<service>/<method>" as the HTTP/2 path, so
+// method matches compile to path matches. claimMatches keeps matches on JWT
+// claims, see matchesJWTClaim.
+func buildRoutesFromGatewayGRPCRoute(push *model.PushContext, grpcRoutes []config.Config, defaultPort int, faultPolicy *route.FaultPolicy, claimMatches bool) []*route.Route {
+	var allRoutes []*route.Route
+	for _, grConfig := range grpcRoutes {
+		grSpec, ok := grConfig.Spec.(*sigsk8siogatewayapiapisv1.GRPCRouteSpec)
+		if !ok {
+			log.Warnf("GRPCRoute %s/%s spec is not GRPCRouteSpec", grConfig.Namespace, grConfig.Name)
+			continue
+		}
+		for ruleIdx, rule := range grSpec.Rules {
+			backendRefs := make([]sigsk8siogatewayapiapisv1.BackendRef, 0, len(rule.BackendRefs))
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
+			}
+			weightedClusters := gatewayAPIWeightedClusters(backendRefs, grConfig.Namespace, defaultPort)
+			if weightedClusters == nil {
+				log.Debugf("GRPCRoute %s/%s rule[%d] has no backendRefs, skipping", grConfig.Namespace, grConfig.Name, ruleIdx)
+				continue
+			}
+
+			filters := grpcRouteFiltersAsHTTP(rule.Filters)
+			routeAction := &route.RouteAction{
+				ClusterSpecifier: &route.RouteAction_WeightedClusters{
+					WeightedClusters: weightedClusters,
+				},
+				FaultPolicy:           faultPolicy,
+				RequestMirrorPolicies: gatewayAPIRequestMirrorPolicies(push, filters, grConfig.Namespace),
+			}
+
+			routeMatches := buildRouteMatchesFromGRPCRouteMatches(rule.Matches, claimMatches)
+			for _, routeMatch := range routeMatches {
+				r := &route.Route{
+					Match: routeMatch,
+					Action: &route.Route_Route{
+						Route: routeAction,
+					},
+				}
+				applyGatewayAPIHeaderFilters(r, filters)
+				selectDubboSubset(push, r)
+				allRoutes = append(allRoutes, r)
+			}
+			log.Debugf("GRPCRoute %s/%s rule[%d] -> built %d routes with %d clusters",
+				grConfig.Namespace, grConfig.Name, ruleIdx, len(routeMatches), len(weightedClusters.Clusters))
+		}
+	}
+	return allRoutes
+}
+
+// grpcRouteFiltersAsHTTP reuses the HTTPRoute filter translation: every
+// GRPCRoute filter type shares its name and payload with an HTTPRoute filter.
+func grpcRouteFiltersAsHTTP(filters []sigsk8siogatewayapiapisv1.GRPCRouteFilter) []sigsk8siogatewayapiapisv1.HTTPRouteFilter {
+	out := make([]sigsk8siogatewayapiapisv1.HTTPRouteFilter, 0, len(filters))
+	for _, filter := range filters {
+		out = append(out, sigsk8siogatewayapiapisv1.HTTPRouteFilter{
+			Type:                   sigsk8siogatewayapiapisv1.HTTPRouteFilterType(filter.Type),
+			RequestHeaderModifier:  filter.RequestHeaderModifier,
+			ResponseHeaderModifier: filter.ResponseHeaderModifier,
+			RequestMirror:          filter.RequestMirror,
+			ExtensionRef:           filter.ExtensionRef,
+		})
+	}
+	return out
+}
+
+// buildRouteMatchesFromGRPCRouteMatches preserves Gateway API OR semantics:
+// every GRPCRouteMatch in a rule becomes an independent xDS route. Matches
+// on a JWT claim are left out unless claimMatches is set, see
+// matchesJWTClaim.
+func buildRouteMatchesFromGRPCRouteMatches(matches []sigsk8siogatewayapiapisv1.GRPCRouteMatch, claimMatches bool) []*route.RouteMatch {
+	if len(matches) == 0 {
+		matches = []sigsk8siogatewayapiapisv1.GRPCRouteMatch{{}}
+	}
+	out := make([]*route.RouteMatch, 0, len(matches))
+	for _, match := range matches {
+		headerNames := make([]string, 0, len(match.Headers))
+		for _, header := range match.Headers {
+			headerNames = append(headerNames, string(header.Name))
+		}
+		if !claimMatches && matchesJWTClaim(headerNames) {
+			continue
+		}
+		routeMatch := &route.RouteMatch{}
+		setGRPCMethodPathSpecifier(routeMatch, match.Method)
+		for _, header := range match.Headers {
+			headerMatcher := &route.HeaderMatcher{Name: routeHeaderName(string(header.Name))}
+			if header.Type != nil && *header.Type == sigsk8siogatewayapiapisv1.GRPCHeaderMatchRegularExpression {
+				headerMatcher.HeaderMatchSpecifier = &route.HeaderMatcher_SafeRegexMatch{
+					SafeRegexMatch: &matcher.RegexMatcher{Regex: header.Value},
+				}
+			} else {
+				headerMatcher.HeaderMatchSpecifier = &route.HeaderMatcher_ExactMatch{ExactMatch: header.Value}
+			}
+			routeMatch.Headers = append(routeMatch.Headers, headerMatcher)
+		}
+		out = append(out, routeMatch)
+	}
+	return out
+}
+
+func setGRPCMethodPathSpecifier(routeMatch *route.RouteMatch, method *sigsk8siogatewayapiapisv1.GRPCMethodMatch) {
+	service, name := "", ""
+	if method != nil && method.Service != nil {
+		service = *method.Service
+	}
+	if method != nil && method.Method != nil {
+		name = *method.Method
+	}
+	if method != nil && method.Type != nil && *method.Type == sigsk8siogatewayapiapisv1.GRPCMethodMatchRegularExpression {
+		if service == "" {
+			service = "[^/]+"
+		}
+		if name == "" {
+			name = "[^/]+"
+		}
+		routeMatch.PathSpecifier = &route.RouteMatch_SafeRegex{SafeRegex: &matcher.RegexMatcher{Regex: "/" + service + "/" + name}}
+		return
+	}
+	switch {
+	case service != "" && name != "":
+		routeMatch.PathSpecifier = &route.RouteMatch_Path{Path: "/" + service + "/" + name}
+	case service != "":
+		routeMatch.PathSpecifier = &route.RouteMatch_Prefix{Prefix: "/" + service + "/"}
+	case name != "":
+		routeMatch.PathSpecifier = &route.RouteMatch_SafeRegex{SafeRegex: &matcher.RegexMatcher{Regex: "/[^/]+/" + regexp.QuoteMeta(name)}}
+	default:
+		routeMatch.PathSpecifier = &route.RouteMatch_Prefix{Prefix: "/"}
+	}
+}
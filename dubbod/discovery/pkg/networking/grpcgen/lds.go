@@ -235,7 +235,7 @@ func buildInboundListeners(node *model.Proxy, push *model.PushContext, names []s
 						RouteConfigName: routeName,
 					},
 				},
-				HttpFilters: buildInboundHTTPFilters(push, si),
+				HttpFilters: buildInboundHTTPFilters(push, si, push.RoutedJWTClaims()),
 			}
 			log.Infof(" Gateway Pod (router) using RDS for listener %s, routeName=%s, node.ID=%s, node.Type=%v", name, routeName, node.ID, node.Type)
 		} else {
@@ -266,7 +266,7 @@ func buildInboundListeners(node *model.Proxy, push *model.PushContext, names []s
 						},
 					},
 				},
-				HttpFilters: buildInboundHTTPFilters(push, si),
+				HttpFilters: buildInboundHTTPFilters(push, si, nil),
 			}
 			log.Debugf(" regular service Pod using inline RouteConfig for listener %s", name)
 		}
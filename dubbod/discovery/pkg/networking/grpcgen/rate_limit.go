@@ -0,0 +1,68 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/wellknown"
+	"github.com/apache/dubbo-kubernetes/pkg/xds/filterpb"
+	hcmv1 "github.com/kdubbo/xds-api/extensions/filters/v1/network/http_connection_manager"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// buildLocalRateLimitFilter compiles the RateLimitPolicy of the target
+// Service port. It runs after authorization, so rejected requests do not
+// take tokens. The filter is optional: a workload too old to know it keeps
+// serving, unlimited, rather than rejecting the whole listener.
+func buildLocalRateLimitFilter(push *model.PushContext, serviceTarget model.ServiceTarget) *hcmv1.HttpFilter {
+	if serviceTarget.Service == nil {
+		return nil
+	}
+	portName := ""
+	if serviceTarget.Port.ServicePort != nil {
+		portName = serviceTarget.Port.Name
+	}
+	settings, found := push.RateLimitForService(serviceTarget.Service.Attributes.Namespace, serviceTarget.Service.Attributes.Name, portName)
+	if !found {
+		return nil
+	}
+	filter := typedHTTPFilter(wellknown.HTTPLocalRateLimit, localRateLimitFilterConfig(settings))
+	filter.IsOptional = true
+	return filter
+}
+
+var localRateLimitKeys = map[model.RateLimitKey]filterpb.LocalRateLimit_Key{
+	model.RateLimitKeyService: filterpb.LocalRateLimit_SERVICE,
+	model.RateLimitKeyCaller:  filterpb.LocalRateLimit_CALLER,
+	model.RateLimitKeyHeader:  filterpb.LocalRateLimit_HEADER,
+	model.RateLimitKeyMethod:  filterpb.LocalRateLimit_METHOD,
+}
+
+// localRateLimitFilterConfig encodes the local_ratelimit filter config.
+// Caller buckets use the peer principal, method buckets the request path.
+func localRateLimitFilterConfig(settings model.RateLimitSettings) *filterpb.LocalRateLimit {
+	return &filterpb.LocalRateLimit{
+		Policy:        settings.Namespace + "/" + settings.Name,
+		StatPrefix:    "local_rate_limit",
+		MaxTokens:     settings.MaxTokens,
+		TokensPerFill: settings.TokensPerFill,
+		FillInterval:  durationpb.New(settings.FillInterval),
+		Key:           localRateLimitKeys[settings.Key],
+		Header:        settings.Header,
+		Status:        429,
+	}
+}
@@ -28,9 +28,12 @@ import (
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
 	"github.com/apache/dubbo-kubernetes/pkg/config"
 	"github.com/apache/dubbo-kubernetes/pkg/config/host"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
+	core "github.com/kdubbo/xds-api/core/v1"
 	route "github.com/kdubbo/xds-api/route/v1"
 	matcher "github.com/kdubbo/xds-api/type/matcher/v1"
+	xdstype "github.com/kdubbo/xds-api/type/v1"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -139,25 +142,36 @@ func buildHTTPRoute(node *model.Proxy, push *model.PushContext, routeName string
 					}
 				}
 
-				if routes := buildRoutesFromGatewayHTTPRoute(httpRoutes, host.Name("*"), parsedPort, faultPolicy); len(routes) > 0 {
+				if routes := buildRoutesFromGatewayHTTPRoute(push, httpRoutes, host.Name("*"), parsedPort, faultPolicy, false); len(routes) > 0 {
 					log.Infof("built %d routes from Gateway API HTTPRoute", len(routes))
 					outboundRoutes = routes
 				} else {
 					log.Warnf("HTTPRoute found but no routes built")
 				}
 			}
-		} else if httpRoutes := filterHTTPRoutesByService(push.HTTPRouteForHost(host.Name(hostStr)), svc, parsedPort); len(httpRoutes) > 0 {
-			log.Infof("found %d service-attached HTTPRoute(s) for host %s", len(httpRoutes), hostStr)
-			if routes := buildRoutesFromGatewayHTTPRoute(httpRoutes, host.Name(hostStr), parsedPort, faultPolicy); len(routes) > 0 {
-				log.Infof("built %d routes from service-attached HTTPRoute for host %s", len(routes), hostStr)
+		} else {
+			// GRPCRoute rules come first: Gateway API gives GRPCRoute precedence
+			// over an HTTPRoute attached to the same parent.
+			var routes []*route.Route
+			if grpcRoutes := filterGRPCRoutesByService(push.GRPCRoutesForService(svc.Attributes.Namespace, svc.Attributes.Name), svc, parsedPort); len(grpcRoutes) > 0 {
+				log.Infof("found %d service-attached GRPCRoute(s) for host %s", len(grpcRoutes), hostStr)
+				routes = append(routes, buildRoutesFromGatewayGRPCRoute(push, grpcRoutes, parsedPort, faultPolicy, false)...)
+			}
+			if httpRoutes := filterHTTPRoutesByService(push.HTTPRouteForHost(host.Name(hostStr)), svc, parsedPort); len(httpRoutes) > 0 {
+				log.Infof("found %d service-attached HTTPRoute(s) for host %s", len(httpRoutes), hostStr)
+				routes = append(routes, buildRoutesFromGatewayHTTPRoute(push, httpRoutes, host.Name(hostStr), parsedPort, faultPolicy, false)...)
+			}
+			if len(routes) > 0 {
+				log.Infof("built %d routes from service-attached routes for host %s", len(routes), hostStr)
 				outboundRoutes = routes
 			} else {
-				log.Warnf("service-attached HTTPRoute found but no routes built for host %s", hostStr)
+				log.Debugf("no service-attached route built for host %s, using default route", hostStr)
+				outboundRoutes = append(dubboInterfaceRoutes(svc, parsedPort, faultPolicy), outboundRoutes...)
 			}
-		} else {
-			log.Debugf("no service-attached HTTPRoute found for host %s, using default route", hostStr)
 		}
 
+		applyServiceHashPolicy(outboundRoutes, svc)
+
 		virtualHosts := []*route.VirtualHost{
 			{
 				Name:    fmt.Sprintf("%s|http|%d", hostStr, parsedPort),
@@ -243,31 +257,37 @@ func buildHTTPRoute(node *model.Proxy, push *model.PushContext, routeName string
 		httpRoutes := filterHTTPRoutesByGateway(allHTTPRoutes, gatewayName, gatewayNamespace, gatewayListenerPort)
 		agentConfig := buildAgentConfig(push, httpRoutes)
 		log.Debugf("Gateway Pod inbound listener, filtered to %d HTTPRoute(s) matching gateway %s/%s listener port %d", len(httpRoutes), gatewayNamespace, gatewayName, gatewayListenerPort)
+		grpcRoutes := push.GRPCRoutesForGateway(gatewayNamespace, gatewayName)
 
 		// For Gateway Pod, we also need to collect HTTPRoutes with specific hostnames
 		// because Gateway Pods route traffic based on HTTPRoute hostnames in the request
-		if len(httpRoutes) > 0 {
-			log.Infof("Gateway Pod inbound listener found %d HTTPRoute(s) for port %s", len(httpRoutes), routeName)
-			// Collect all HTTPRoute hostnames and add them to domains
+		if len(httpRoutes) > 0 || len(grpcRoutes) > 0 {
+			log.Infof("Gateway Pod inbound listener found %d HTTPRoute(s) and %d GRPCRoute(s) for port %s", len(httpRoutes), len(grpcRoutes), routeName)
+			// Collect all HTTPRoute and GRPCRoute hostnames and add them to domains
 			httpRouteHostnames := make(map[string]bool)
-			for _, hr := range httpRoutes {
-				hrSpec, ok := hr.Spec.(*sigsk8siogatewayapiapisv1.HTTPRouteSpec)
-				if !ok {
-					continue
-				}
-				if len(hrSpec.Hostnames) == 0 {
+			addHostnames := func(hostnames []sigsk8siogatewayapiapisv1.Hostname) {
+				if len(hostnames) == 0 {
 					httpRouteHostnames["*"] = true
-				} else {
-					for _, hostname := range hrSpec.Hostnames {
-						hostnameStr := string(hostname)
-						if hostnameStr == "" || hostnameStr == "*" {
-							httpRouteHostnames["*"] = true
-						} else {
-							httpRouteHostnames[hostnameStr] = true
-						}
+				}
+				for _, hostname := range hostnames {
+					hostnameStr := string(hostname)
+					if hostnameStr == "" || hostnameStr == "*" {
+						httpRouteHostnames["*"] = true
+					} else {
+						httpRouteHostnames[hostnameStr] = true
 					}
 				}
 			}
+			for _, hr := range httpRoutes {
+				if hrSpec, ok := hr.Spec.(*sigsk8siogatewayapiapisv1.HTTPRouteSpec); ok {
+					addHostnames(hrSpec.Hostnames)
+				}
+			}
+			for _, gr := range grpcRoutes {
+				if grSpec, ok := gr.Spec.(*sigsk8siogatewayapiapisv1.GRPCRouteSpec); ok {
+					addHostnames(grSpec.Hostnames)
+				}
+			}
 			// Add HTTPRoute hostnames to domains
 			for hostnameStr := range httpRouteHostnames {
 				if hostnameStr != "*" {
@@ -276,11 +296,16 @@ func buildHTTPRoute(node *model.Proxy, push *model.PushContext, routeName string
 				}
 			}
 
-			if routes := buildRoutesFromGatewayHTTPRoute(httpRoutes, host.Name("*"), gatewayListenerPort, nil); len(routes) > 0 {
-				log.Infof("Gateway Pod inbound listener built %d routes from HTTPRoute", len(routes))
-				outboundRoutes = routes
+			// GRPCRoutes match on the method path, so they go ahead of the
+			// HTTPRoute prefix matches that would otherwise shadow them. The
+			// listener's JWT filter has verified the token by now, so claim
+			// matches are kept.
+			outboundRoutes = append(outboundRoutes, buildRoutesFromGatewayGRPCRoute(push, grpcRoutes, gatewayListenerPort, nil, true)...)
+			outboundRoutes = append(outboundRoutes, buildRoutesFromGatewayHTTPRoute(push, httpRoutes, host.Name("*"), gatewayListenerPort, nil, true)...)
+			if len(outboundRoutes) > 0 {
+				log.Infof("Gateway Pod inbound listener built %d routes from HTTPRoute and GRPCRoute", len(outboundRoutes))
 			} else {
-				log.Warnf("Gateway Pod inbound listener HTTPRoute found but no routes built")
+				log.Warnf("Gateway Pod inbound listener routes found but no routes built")
 			}
 		} else {
 			log.Warnf("Gateway Pod inbound listener no HTTPRoute found for port %s", routeName)
@@ -411,8 +436,9 @@ func defaultSingleClusterRoute(clusterName string, faultPolicy *route.FaultPolic
 	}
 }
 
-// buildRoutesFromGatewayHTTPRoute converts Gateway API HTTPRoute resources to XDS Route configurations
-func buildRoutesFromGatewayHTTPRoute(httpRoutes []config.Config, hostName host.Name, defaultPort int, faultPolicy *route.FaultPolicy) []*route.Route {
+// buildRoutesFromGatewayHTTPRoute converts Gateway API HTTPRoute resources to XDS Route configurations.
+// claimMatches keeps matches on JWT claims, see matchesJWTClaim.
+func buildRoutesFromGatewayHTTPRoute(push *model.PushContext, httpRoutes []config.Config, hostName host.Name, defaultPort int, faultPolicy *route.FaultPolicy, claimMatches bool) []*route.Route {
 	if len(httpRoutes) == 0 {
 		return nil
 	}
@@ -427,6 +453,17 @@ func buildRoutesFromGatewayHTTPRoute(httpRoutes []config.Config, hostName host.N
 
 		// Process each rule in the HTTPRoute
 		for ruleIdx, rule := range hrSpec.Rules {
+			if redirect := gatewayAPIRedirectAction(rule.Filters); redirect != nil {
+				for _, routeMatch := range buildRouteMatchesFromHTTPRouteMatches(rule.Matches, claimMatches) {
+					r := &route.Route{
+						Match:  routeMatch,
+						Action: &route.Route_Redirect{Redirect: redirectActionForMatch(redirect, routeMatch)},
+					}
+					applyGatewayAPIHeaderFilters(r, rule.Filters)
+					allRoutes = append(allRoutes, r)
+				}
+				continue
+			}
 			if len(rule.BackendRefs) == 0 {
 				log.Debugf("HTTPRoute %s/%s rule[%d] has no backendRefs, skipping", hrConfig.Namespace, hrConfig.Name, ruleIdx)
 				continue
@@ -440,57 +477,11 @@ func buildRoutesFromGatewayHTTPRoute(httpRoutes []config.Config, hostName host.N
 			}
 
 			// Build weighted clusters from backendRefs
-			weights := make([]*route.WeightedCluster_ClusterWeight, 0, len(rule.BackendRefs))
-			var totalWeight uint32
-
-			for backendIdx, backendRef := range rule.BackendRefs {
-				// Get backend service name and namespace
-				backendName := string(backendRef.Name)
-				backendNamespace := hrConfig.Namespace
-				if backendRef.Namespace != nil {
-					backendNamespace = string(*backendRef.Namespace)
-				}
-
-				// Get backend port
-				backendPort := defaultPort
-				if backendRef.Port != nil {
-					backendPort = int(*backendRef.Port)
-				}
-
-				// Build service FQDN
-				backendHost := fmt.Sprintf("%s.%s.svc.cluster.local", backendName, backendNamespace)
-				clusterName := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", host.Name(backendHost), backendPort)
-
-				// Get weight (default to 1 if not specified)
-				weight := uint32(1)
-				if backendRef.Weight != nil {
-					weight = uint32(*backendRef.Weight)
-				}
-				if weight == 0 {
-					weight = 1
-				}
-				totalWeight += weight
-
-				log.Debugf("HTTPRoute %s/%s rule[%d] backend[%d] -> cluster=%s, weight=%d, host=%s, port=%d",
-					hrConfig.Namespace, hrConfig.Name, ruleIdx, backendIdx, clusterName, weight, backendHost, backendPort)
-
-				weights = append(weights, &route.WeightedCluster_ClusterWeight{
-					Name:   clusterName,
-					Weight: wrapperspb.UInt32(weight),
-				})
-			}
-
-			if len(weights) == 0 {
-				log.Warnf("HTTPRoute %s/%s rule[%d] has no valid backends", hrConfig.Namespace, hrConfig.Name, ruleIdx)
-				continue
-			}
-
-			weightedClusters := &route.WeightedCluster{
-				Clusters: weights,
-			}
-			if totalWeight > 0 {
-				weightedClusters.TotalWeight = wrapperspb.UInt32(totalWeight)
+			backendRefs := make([]sigsk8siogatewayapiapisv1.BackendRef, 0, len(rule.BackendRefs))
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
 			}
+			weightedClusters := gatewayAPIWeightedClusters(backendRefs, hrConfig.Namespace, defaultPort)
 
 			routeAction := &route.RouteAction{
 				ClusterSpecifier: &route.RouteAction_WeightedClusters{
@@ -504,25 +495,110 @@ func buildRoutesFromGatewayHTTPRoute(httpRoutes []config.Config, hostName host.N
 			}
 			routeAction.RetryPolicy = gatewayAPIRetryPolicy(rule.Retry, rule.Timeouts)
 			routeAction.FaultPolicy = faultPolicy
+			routeAction.RequestMirrorPolicies = gatewayAPIRequestMirrorPolicies(push, rule.Filters, hrConfig.Namespace)
+			applyGatewayAPIURLRewrite(routeAction, rule.Filters)
 
-			routeMatches := buildRouteMatchesFromHTTPRouteMatches(rule.Matches)
+			routeMatches := buildRouteMatchesFromHTTPRouteMatches(rule.Matches, claimMatches)
 			for _, routeMatch := range routeMatches {
-				allRoutes = append(allRoutes, &route.Route{
+				r := &route.Route{
 					Match: routeMatch,
 					Action: &route.Route_Route{
-						Route: routeAction,
+						Route: routeActionForMatch(routeAction, routeMatch),
 					},
-				})
+				}
+				applyGatewayAPIHeaderFilters(r, rule.Filters)
+				selectDubboSubset(push, r)
+				allRoutes = append(allRoutes, r)
 			}
 
 			log.Infof("HTTPRoute %s/%s rule[%d] -> built %d routes with %d clusters, totalWeight=%d",
-				hrConfig.Namespace, hrConfig.Name, ruleIdx, len(routeMatches), len(weights), totalWeight)
+				hrConfig.Namespace, hrConfig.Name, ruleIdx, len(routeMatches), len(weightedClusters.Clusters), weightedClusters.TotalWeight.GetValue())
 		}
 	}
 
 	return allRoutes
 }
 
+// gatewayAPIWeightedClusters turns Service backendRefs into weighted outbound
+// clusters. It returns nil when there are no backends.
+func gatewayAPIWeightedClusters(backendRefs []sigsk8siogatewayapiapisv1.BackendRef, routeNamespace string, defaultPort int) *route.WeightedCluster {
+	if len(backendRefs) == 0 {
+		return nil
+	}
+	weights := make([]*route.WeightedCluster_ClusterWeight, 0, len(backendRefs))
+	var totalWeight uint32
+	for _, backendRef := range backendRefs {
+		backendNamespace := routeNamespace
+		if backendRef.Namespace != nil {
+			backendNamespace = string(*backendRef.Namespace)
+		}
+		backendPort := defaultPort
+		if backendRef.Port != nil {
+			backendPort = int(*backendRef.Port)
+		}
+		backendHost := fmt.Sprintf("%s.%s.svc.cluster.local", backendRef.Name, backendNamespace)
+		weight := uint32(1)
+		if backendRef.Weight != nil && *backendRef.Weight > 0 {
+			weight = uint32(*backendRef.Weight)
+		}
+		totalWeight += weight
+		weights = append(weights, &route.WeightedCluster_ClusterWeight{
+			Name:   model.BuildSubsetKey(model.TrafficDirectionOutbound, "", host.Name(backendHost), backendPort),
+			Weight: wrapperspb.UInt32(weight),
+		})
+	}
+	return &route.WeightedCluster{
+		Clusters:    weights,
+		TotalWeight: wrapperspb.UInt32(totalWeight),
+	}
+}
+
+// applyServiceHashPolicy attaches the consistent-hash keys of the target
+// service to every forwarding route so RING_HASH and MAGLEV clusters keep
+// stable affinity instead of hashing randomly.
+func applyServiceHashPolicy(routes []*route.Route, svc *model.Service) {
+	hashPolicy := serviceHashPolicy(svc)
+	if len(hashPolicy) == 0 {
+		return
+	}
+	for _, r := range routes {
+		if action := r.GetRoute(); action != nil {
+			action.HashPolicy = hashPolicy
+		}
+	}
+}
+
+func serviceHashPolicy(svc *model.Service) []*route.RouteAction_HashPolicy {
+	if svc == nil || !svc.Attributes.LoadBalancer.IsConsistentHash() {
+		return nil
+	}
+	hashPolicy := make([]*route.RouteAction_HashPolicy, 0, len(svc.Attributes.LoadBalancer.HashKeys))
+	for _, key := range svc.Attributes.LoadBalancer.HashKeys {
+		switch key.Type {
+		case model.HashKeyHeader, model.HashKeyAttachment:
+			// Triple carries Dubbo attachments as request headers.
+			hashPolicy = append(hashPolicy, &route.RouteAction_HashPolicy{
+				PolicySpecifier: &route.RouteAction_HashPolicy_Header_{
+					Header: &route.RouteAction_HashPolicy_Header{HeaderName: key.Name},
+				},
+			})
+		case model.HashKeyCookie:
+			hashPolicy = append(hashPolicy, &route.RouteAction_HashPolicy{
+				PolicySpecifier: &route.RouteAction_HashPolicy_Cookie_{
+					Cookie: &route.RouteAction_HashPolicy_Cookie{Name: key.Name},
+				},
+			})
+		case model.HashKeySourceIP:
+			hashPolicy = append(hashPolicy, &route.RouteAction_HashPolicy{
+				PolicySpecifier: &route.RouteAction_HashPolicy_ConnectionProperties_{
+					ConnectionProperties: &route.RouteAction_HashPolicy_ConnectionProperties{SourceIp: true},
+				},
+			})
+		}
+	}
+	return hashPolicy
+}
+
 func serviceFaultPolicy(push *model.PushContext, svc *model.Service, port int) *route.FaultPolicy {
 	if push == nil || svc == nil {
 		return nil
@@ -596,6 +672,65 @@ func gatewayAPIRetryPolicy(retry *sigsk8siogatewayapiapisv1.HTTPRouteRetry, time
 	return policy
 }
 
+// gatewayAPIRequestMirrorPolicies translates RequestMirror filters into shadow
+// clusters. Mirrors to a Service that does not exist are dropped, as required
+// by the Gateway API; the HTTPRoute status reports them as unresolved. Like
+// dxgate, a mirror without a backend port is skipped rather than defaulted to
+// the route port, since the Gateway API requires one for Service references.
+func gatewayAPIRequestMirrorPolicies(push *model.PushContext, filters []sigsk8siogatewayapiapisv1.HTTPRouteFilter, routeNamespace string) []*route.RouteAction_RequestMirrorPolicy {
+	var policies []*route.RouteAction_RequestMirrorPolicy
+	for _, filter := range filters {
+		if filter.Type != sigsk8siogatewayapiapisv1.HTTPRouteFilterRequestMirror || filter.RequestMirror == nil {
+			continue
+		}
+		backendRef := filter.RequestMirror.BackendRef
+		if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != "Service") || backendRef.Port == nil {
+			continue
+		}
+		backendNamespace := routeNamespace
+		if backendRef.Namespace != nil {
+			backendNamespace = string(*backendRef.Namespace)
+		}
+		backendHost := host.Name(fmt.Sprintf("%s.%s.svc.cluster.local", backendRef.Name, backendNamespace))
+		if push != nil && push.ServiceForHostname(nil, backendHost) == nil {
+			log.Warnf("dropping RequestMirror to %s: service not found", backendHost)
+			continue
+		}
+		numerator, denominator := model.RequestMirrorFraction(filter.RequestMirror)
+		if numerator == 0 {
+			continue
+		}
+		policies = append(policies, &route.RouteAction_RequestMirrorPolicy{
+			Cluster: model.BuildSubsetKey(model.TrafficDirectionOutbound, "", backendHost, int(*backendRef.Port)),
+			RuntimeFraction: &core.RuntimeFractionalPercent{
+				DefaultValue: fractionalPercent(numerator, denominator),
+			},
+		})
+	}
+	return policies
+}
+
+// fractionalPercent expresses numerator/denominator in the smallest xDS
+// denominator that represents it exactly. Shares that none of them represent
+// exactly are rounded up in millionths, so a configured mirror never rounds
+// down to nothing.
+func fractionalPercent(numerator, denominator uint32) *xdstype.FractionalPercent {
+	for _, d := range []struct {
+		value uint64
+		kind  xdstype.FractionalPercent_DenominatorType
+	}{
+		{100, xdstype.FractionalPercent_HUNDRED},
+		{10_000, xdstype.FractionalPercent_TEN_THOUSAND},
+		{1_000_000, xdstype.FractionalPercent_MILLION},
+	} {
+		if scaled := uint64(numerator) * d.value; scaled%uint64(denominator) == 0 {
+			return &xdstype.FractionalPercent{Numerator: uint32(scaled / uint64(denominator)), Denominator: d.kind}
+		}
+	}
+	scaled := (uint64(numerator)*1_000_000 + uint64(denominator) - 1) / uint64(denominator)
+	return &xdstype.FractionalPercent{Numerator: uint32(scaled), Denominator: xdstype.FractionalPercent_MILLION}
+}
+
 func gatewayAPIDurationToProto(duration *sigsk8siogatewayapiapisv1.Duration) *durationpb.Duration {
 	if duration == nil {
 		return nil
@@ -667,7 +802,7 @@ func filterHTTPRoutesByService(httpRoutes []config.Config, svc *model.Service, p
 		if !ok {
 			continue
 		}
-		if httpRouteReferencesService(hrSpec, hr.Namespace, svc, port) {
+		if routeReferencesService(hrSpec.ParentRefs, hr.Namespace, svc, port) {
 			filtered = append(filtered, hr)
 			log.Debugf("HTTPRoute %s/%s matches Service %s/%s port %d",
 				hr.Namespace, hr.Name, svc.Attributes.Namespace, svc.Attributes.Name, port)
@@ -676,8 +811,8 @@ func filterHTTPRoutesByService(httpRoutes []config.Config, svc *model.Service, p
 	return filtered
 }
 
-func httpRouteReferencesService(hrSpec *sigsk8siogatewayapiapisv1.HTTPRouteSpec, routeNamespace string, svc *model.Service, port int) bool {
-	for _, parentRef := range hrSpec.ParentRefs {
+func routeReferencesService(parentRefs []sigsk8siogatewayapiapisv1.ParentReference, routeNamespace string, svc *model.Service, port int) bool {
+	for _, parentRef := range parentRefs {
 		if !isServiceParentRef(parentRef) {
 			continue
 		}
@@ -710,19 +845,47 @@ func isServiceParentRef(parentRef sigsk8siogatewayapiapisv1.ParentReference) boo
 }
 
 // buildRouteMatchesFromHTTPRouteMatches preserves Gateway API OR semantics:
-// every HTTPRouteMatch in a rule becomes an independent xDS route.
-func buildRouteMatchesFromHTTPRouteMatches(matches []sigsk8siogatewayapiapisv1.HTTPRouteMatch) []*route.RouteMatch {
+// every HTTPRouteMatch in a rule becomes an independent xDS route. Matches
+// on a JWT claim are left out unless claimMatches is set, see
+// matchesJWTClaim.
+func buildRouteMatchesFromHTTPRouteMatches(matches []sigsk8siogatewayapiapisv1.HTTPRouteMatch, claimMatches bool) []*route.RouteMatch {
 	if len(matches) == 0 {
 		matches = []sigsk8siogatewayapiapisv1.HTTPRouteMatch{{}}
 	}
 
 	out := make([]*route.RouteMatch, 0, len(matches))
 	for _, match := range matches {
+		if !claimMatches && matchesJWTClaim(httpHeaderMatchNames(match.Headers)) {
+			continue
+		}
 		out = append(out, buildRouteMatchFromHTTPRouteMatch(match))
 	}
 	return out
 }
 
+// matchesJWTClaim reports whether a route match refers to a claim of the
+// verified token. Proxyless routes are matched in the caller, before the
+// callee's JWT filter has verified anything, so the claim header would be
+// whatever the caller sent. Such matches are dropped there, and the route
+// status says so; only the gateway listener, whose JWT filter copies the
+// verified claims in before it routes, builds them with claimMatches.
+func matchesJWTClaim(headerNames []string) bool {
+	for _, name := range headerNames {
+		if _, ok := securityconfig.ClaimFromRouteHeader(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func httpHeaderMatchNames(headers []sigsk8siogatewayapiapisv1.HTTPHeaderMatch) []string {
+	names := make([]string, 0, len(headers))
+	for _, header := range headers {
+		names = append(names, string(header.Name))
+	}
+	return names
+}
+
 func buildRouteMatchFromHTTPRouteMatch(match sigsk8siogatewayapiapisv1.HTTPRouteMatch) *route.RouteMatch {
 	routeMatch := &route.RouteMatch{}
 
@@ -775,7 +938,7 @@ func buildRouteMatchFromHTTPRouteMatch(match sigsk8siogatewayapiapisv1.HTTPRoute
 		headerMatchers := make([]*route.HeaderMatcher, 0, len(match.Headers))
 		for _, headerMatch := range match.Headers {
 			headerMatcher := &route.HeaderMatcher{
-				Name: string(headerMatch.Name),
+				Name: routeHeaderName(string(headerMatch.Name)),
 			}
 
 			if headerMatch.Type != nil {
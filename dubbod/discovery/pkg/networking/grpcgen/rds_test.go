@@ -17,6 +17,7 @@ package grpcgen
 
 import (
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -31,8 +32,11 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/collections"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
 	"github.com/apache/dubbo-kubernetes/pkg/kube/krt"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
 	networking "github.com/kdubbo/api/networking/v1alpha3"
+	core "github.com/kdubbo/xds-api/core/v1"
 	route "github.com/kdubbo/xds-api/route/v1"
+	xdstype "github.com/kdubbo/xds-api/type/v1"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -45,7 +49,7 @@ func TestBuildRouteMatchesPreservesHTTPRouteORSemantics(t *testing.T) {
 	matches := buildRouteMatchesFromHTTPRouteMatches([]gatewayv1.HTTPRouteMatch{
 		{Path: &gatewayv1.HTTPPathMatch{Type: &pathType, Value: &users}},
 		{Path: &gatewayv1.HTTPPathMatch{Type: &pathType, Value: &orders}},
-	})
+	}, false)
 
 	if len(matches) != 2 {
 		t.Fatalf("matches = %d, want 2 independent xDS routes", len(matches))
@@ -265,6 +269,438 @@ func TestBuildHTTPRouteSetsServiceFaultInjectionPolicy(t *testing.T) {
 	}
 }
 
+func TestBuildHTTPRouteSetsServiceHashPolicy(t *testing.T) {
+	service := newRDSTestService("session", "app", "session.app.svc.cluster.local", 20880)
+	service.Attributes.LoadBalancer = &model.LoadBalancerSettings{
+		Policy: model.LoadBalancerMaglev,
+		HashKeys: []model.HashKey{
+			{Type: model.HashKeyAttachment, Name: "tenant"},
+			{Type: model.HashKeySourceIP},
+		},
+	}
+	push := newRDSTestPushContext(t, nil, []*model.Service{service})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "caller.app", Type: model.Inherent},
+		push,
+		"outbound|20880||session.app.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	hashPolicy := rc.VirtualHosts[0].Routes[0].GetRoute().GetHashPolicy()
+	if len(hashPolicy) != 2 {
+		t.Fatalf("hash policies = %d, want 2", len(hashPolicy))
+	}
+	if got := hashPolicy[0].GetHeader().GetHeaderName(); got != "tenant" {
+		t.Fatalf("attachment hash header = %q, want tenant", got)
+	}
+	if !hashPolicy[1].GetConnectionProperties().GetSourceIp() {
+		t.Fatal("second hash policy does not hash on source IP")
+	}
+}
+
+func TestBuildHTTPRouteSetsRequestMirrorPolicy(t *testing.T) {
+	routeConfig := newServiceAttachedHTTPRouteConfig("reviews-shadow", "moviereview", "reviews", 9080)
+	port := gatewayv1.PortNumber(9080)
+	spec := routeConfig.Spec.(*gatewayv1.HTTPRouteSpec)
+	spec.Rules[1].Filters = []gatewayv1.HTTPRouteFilter{
+		{
+			Type: gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+				BackendRef: gatewayv1.BackendObjectReference{Name: "reviews-v3", Port: &port},
+				Fraction:   &gatewayv1.Fraction{Numerator: 1, Denominator: ptrTo(int32(4))},
+			},
+		},
+		{
+			Type: gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+				BackendRef: gatewayv1.BackendObjectReference{Name: "reviews-missing", Port: &port},
+			},
+		},
+	}
+	push := newRDSTestPushContext(t, []config.Config{routeConfig}, []*model.Service{
+		newRDSTestService("reviews", "moviereview", "reviews.moviereview.svc.cluster.local", 9080),
+		newRDSTestService("reviews-v1", "moviereview", "reviews-v1.moviereview.svc.cluster.local", 9080),
+		newRDSTestService("reviews-v2", "moviereview", "reviews-v2.moviereview.svc.cluster.local", 9080),
+		newRDSTestService("reviews-v3", "moviereview", "reviews-v3.moviereview.svc.cluster.local", 9080),
+	})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "moviepage.moviereview", Type: model.Inherent},
+		push,
+		"outbound|9080||reviews.moviereview.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	routes := rc.VirtualHosts[0].Routes
+	if got := routes[0].GetRoute().GetRequestMirrorPolicies(); len(got) != 0 {
+		t.Fatalf("first rule mirrors = %v, want none", got)
+	}
+	mirrors := routes[1].GetRoute().GetRequestMirrorPolicies()
+	if len(mirrors) != 1 {
+		t.Fatalf("mirrors = %d, want 1 (missing Service dropped)", len(mirrors))
+	}
+	if got := mirrors[0].GetCluster(); got != "outbound|9080||reviews-v3.moviereview.svc.cluster.local" {
+		t.Fatalf("mirror cluster = %q", got)
+	}
+	fraction := mirrors[0].GetRuntimeFraction().GetDefaultValue()
+	if fraction.GetNumerator() != 25 || fraction.GetDenominator() != xdstype.FractionalPercent_HUNDRED {
+		t.Fatalf("mirror fraction = %v, want 25/HUNDRED", fraction)
+	}
+}
+
+func TestFractionalPercentKeepsSubPercentShares(t *testing.T) {
+	cases := []struct {
+		numerator, denominator uint32
+		want                   uint32
+		wantDenominator        xdstype.FractionalPercent_DenominatorType
+	}{
+		{numerator: 100, denominator: 100, want: 100, wantDenominator: xdstype.FractionalPercent_HUNDRED},
+		{numerator: 1, denominator: 1000, want: 10, wantDenominator: xdstype.FractionalPercent_TEN_THOUSAND},
+		{numerator: 1, denominator: 200000, want: 5, wantDenominator: xdstype.FractionalPercent_MILLION},
+		{numerator: 1, denominator: 3, want: 333334, wantDenominator: xdstype.FractionalPercent_MILLION},
+		{numerator: 1, denominator: 10000000, want: 1, wantDenominator: xdstype.FractionalPercent_MILLION},
+	}
+	for _, tc := range cases {
+		got := fractionalPercent(tc.numerator, tc.denominator)
+		if got.GetNumerator() != tc.want || got.GetDenominator() != tc.wantDenominator {
+			t.Errorf("fractionalPercent(%d, %d) = %v, want %d/%v", tc.numerator, tc.denominator, got, tc.want, tc.wantDenominator)
+		}
+	}
+}
+
+func TestGatewayAPIPrefixRewriteKeepsOneSeparator(t *testing.T) {
+	cases := []struct {
+		prefix, replacement, path, want string
+	}{
+		{prefix: "/foo", replacement: "/", path: "/foo/bar", want: "/bar"},
+		{prefix: "/foo", replacement: "/", path: "/foo", want: "/"},
+		{prefix: "/foo/", replacement: "/xyz", path: "/foo/bar", want: "/xyz/bar"},
+		{prefix: "/foo", replacement: "/xyz/", path: "/foo/bar", want: "/xyz/bar"},
+		{prefix: "/", replacement: "/xyz", path: "/bar", want: "/xyz/bar"},
+		{prefix: "/a.b", replacement: "/", path: "/a.b/c", want: "/c"},
+	}
+	for _, tc := range cases {
+		rewrite := gatewayAPIPrefixRewrite(tc.prefix, tc.replacement)
+		if rewrite == nil {
+			t.Fatalf("gatewayAPIPrefixRewrite(%q, %q) = nil, want regex rewrite", tc.prefix, tc.replacement)
+		}
+		pattern := regexp.MustCompile(rewrite.GetPattern().GetRegex())
+		substitution := regexp.MustCompile(`\\(\d)`).ReplaceAllString(rewrite.GetSubstitution(), `$${$1}`)
+		if got := pattern.ReplaceAllString(tc.path, substitution); got != tc.want {
+			t.Errorf("rewrite %q with %q->%q = %q, want %q", tc.path, tc.prefix, tc.replacement, got, tc.want)
+		}
+	}
+	if got := gatewayAPIPrefixRewrite("/foo", "/xyz"); got != nil {
+		t.Fatalf("literal prefix rewrite became regex %v", got)
+	}
+}
+
+func TestBuildHTTPRouteAppliesHeaderAndURLRewriteFilters(t *testing.T) {
+	routeConfig := newServiceAttachedHTTPRouteConfig("reviews-tenant", "moviereview", "reviews", 9080)
+	spec := routeConfig.Spec.(*gatewayv1.HTTPRouteSpec)
+	spec.Rules[1].Filters = []gatewayv1.HTTPRouteFilter{
+		{
+			Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set:    []gatewayv1.HTTPHeader{{Name: "X-Tenant", Value: "gold"}},
+				Add:    []gatewayv1.HTTPHeader{{Name: "x-trace", Value: "on"}},
+				Remove: []string{"x-debug"},
+			},
+		},
+		{
+			Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set: []gatewayv1.HTTPHeader{{Name: "x-served-by", Value: "reviews"}},
+			},
+		},
+		{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{
+					Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: ptrTo("/v2"),
+				},
+			},
+		},
+	}
+	push := newRDSTestPushContext(t, []config.Config{routeConfig}, []*model.Service{
+		newRDSTestService("reviews", "moviereview", "reviews.moviereview.svc.cluster.local", 9080),
+		newRDSTestService("reviews-v1", "moviereview", "reviews-v1.moviereview.svc.cluster.local", 9080),
+		newRDSTestService("reviews-v2", "moviereview", "reviews-v2.moviereview.svc.cluster.local", 9080),
+	})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "moviepage.moviereview", Type: model.Inherent},
+		push,
+		"outbound|9080||reviews.moviereview.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	routes := rc.VirtualHosts[0].Routes
+	if got := routes[0].GetRequestHeadersToAdd(); len(got) != 0 {
+		t.Fatalf("first rule headers = %v, want none", got)
+	}
+	r := routes[1]
+	headers := r.GetRequestHeadersToAdd()
+	if len(headers) != 2 {
+		t.Fatalf("request headers = %d, want 2", len(headers))
+	}
+	if got := headers[0].GetHeader().GetKey(); got != "x-tenant" {
+		t.Fatalf("set header key = %q, want x-tenant", got)
+	}
+	if got := headers[0].GetAppendAction(); got != core.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD {
+		t.Fatalf("set header append action = %v", got)
+	}
+	if got := headers[1].GetAppendAction(); got != core.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD {
+		t.Fatalf("add header append action = %v", got)
+	}
+	if got := r.GetRequestHeadersToRemove(); len(got) != 1 || got[0] != "x-debug" {
+		t.Fatalf("request headers to remove = %v", got)
+	}
+	if got := r.GetResponseHeadersToAdd(); len(got) != 1 || got[0].GetHeader().GetValue() != "reviews" {
+		t.Fatalf("response headers = %v", got)
+	}
+	if got := r.GetRoute().GetPrefixRewrite(); got != "/v2" {
+		t.Fatalf("prefix rewrite = %q, want /v2", got)
+	}
+}
+
+func TestBuildHTTPRouteTranslatesRequestRedirect(t *testing.T) {
+	routeConfig := newServiceAttachedHTTPRouteConfig("reviews-redirect", "moviereview", "reviews", 9080)
+	spec := routeConfig.Spec.(*gatewayv1.HTTPRouteSpec)
+	spec.Rules[1].BackendRefs = nil
+	spec.Rules[1].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+			Hostname:   ptrTo(gatewayv1.PreciseHostname("reviews-v2.moviereview.svc.cluster.local")),
+			StatusCode: ptrTo(301),
+			Path: &gatewayv1.HTTPPathModifier{
+				Type:            gatewayv1.FullPathHTTPPathModifier,
+				ReplaceFullPath: ptrTo("/v2/reviews"),
+			},
+		},
+	}}
+	push := newRDSTestPushContext(t, []config.Config{routeConfig}, []*model.Service{
+		newRDSTestService("reviews", "moviereview", "reviews.moviereview.svc.cluster.local", 9080),
+		newRDSTestService("reviews-v2", "moviereview", "reviews-v2.moviereview.svc.cluster.local", 9080),
+	})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "moviepage.moviereview", Type: model.Inherent},
+		push,
+		"outbound|9080||reviews.moviereview.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	routes := rc.VirtualHosts[0].Routes
+	if len(routes) != 2 {
+		t.Fatalf("routes = %d, want 2", len(routes))
+	}
+	redirect := routes[1].GetRedirect()
+	if redirect == nil {
+		t.Fatalf("second route action = %T, want redirect", routes[1].GetAction())
+	}
+	if got := redirect.GetHostRedirect(); got != "reviews-v2.moviereview.svc.cluster.local" {
+		t.Fatalf("host redirect = %q", got)
+	}
+	if got := redirect.GetPathRedirect(); got != "/v2/reviews" {
+		t.Fatalf("path redirect = %q", got)
+	}
+	if got := redirect.GetResponseCode(); got != route.RedirectAction_MOVED_PERMANENTLY {
+		t.Fatalf("response code = %v", got)
+	}
+}
+
+func TestBuildHTTPRouteTranslatesServiceAttachedGRPCRoute(t *testing.T) {
+	kind := gatewayv1.Kind("Service")
+	port := gatewayv1.PortNumber(50051)
+	headerRegex := gatewayv1.GRPCHeaderMatchRegularExpression
+	routeConfig := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.GRPCRoute,
+			Name:             "greeter-canary",
+			Namespace:        "app",
+			Domain:           "cluster.local",
+		},
+		Spec: &gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Kind: &kind, Name: "greeter", Port: &port}},
+			},
+			Rules: []gatewayv1.GRPCRouteRule{{
+				Matches: []gatewayv1.GRPCRouteMatch{
+					{
+						Method: &gatewayv1.GRPCMethodMatch{
+							Service: ptrTo("org.apache.dubbo.Greeter"),
+							Method:  ptrTo("SayHello"),
+						},
+					},
+					{
+						Method: &gatewayv1.GRPCMethodMatch{Service: ptrTo("org.apache.dubbo.Greeter")},
+						Headers: []gatewayv1.GRPCHeaderMatch{{
+							Type:  &headerRegex,
+							Name:  "x-tenant",
+							Value: "gold-.*",
+						}},
+					},
+				},
+				Filters: []gatewayv1.GRPCRouteFilter{{
+					Type: gatewayv1.GRPCRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Set: []gatewayv1.HTTPHeader{{Name: "x-route", Value: "canary"}},
+					},
+				}},
+				BackendRefs: []gatewayv1.GRPCBackendRef{
+					{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter-v1", Port: &port},
+						Weight:                 ptrTo(int32(90)),
+					}},
+					{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter-v2", Port: &port},
+						Weight:                 ptrTo(int32(10)),
+					}},
+				},
+			}},
+		},
+	}
+	push := newRDSTestPushContext(t, []config.Config{routeConfig}, []*model.Service{
+		newRDSTestService("greeter", "app", "greeter.app.svc.cluster.local", 50051),
+		newRDSTestService("greeter-v1", "app", "greeter-v1.app.svc.cluster.local", 50051),
+		newRDSTestService("greeter-v2", "app", "greeter-v2.app.svc.cluster.local", 50051),
+	})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "consumer.app", Type: model.Inherent},
+		push,
+		"outbound|50051||greeter.app.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	routes := rc.VirtualHosts[0].Routes
+	if len(routes) != 2 {
+		t.Fatalf("routes = %d, want 2", len(routes))
+	}
+	if got := routes[0].GetMatch().GetPath(); got != "/org.apache.dubbo.Greeter/SayHello" {
+		t.Fatalf("first match path = %q", got)
+	}
+	if got := routes[1].GetMatch().GetPrefix(); got != "/org.apache.dubbo.Greeter/" {
+		t.Fatalf("second match prefix = %q", got)
+	}
+	if got := routes[1].GetMatch().GetHeaders(); len(got) != 1 || got[0].GetSafeRegexMatch().GetRegex() != "gold-.*" {
+		t.Fatalf("second match headers = %v", got)
+	}
+	want := map[string]uint32{
+		"outbound|50051||greeter-v1.app.svc.cluster.local": 90,
+		"outbound|50051||greeter-v2.app.svc.cluster.local": 10,
+	}
+	if got := weightedClustersByName(t, routes[0]); !reflect.DeepEqual(got, want) {
+		t.Fatalf("weighted clusters = %v, want %v", got, want)
+	}
+	if got := routes[0].GetRequestHeadersToAdd(); len(got) != 1 || got[0].GetHeader().GetKey() != "x-route" {
+		t.Fatalf("request headers = %v", got)
+	}
+}
+
+func TestBuildHTTPRouteAddsDubboInterfaceSubsetRoutes(t *testing.T) {
+	service := newRDSTestService("greeter", "app", "greeter.app.svc.cluster.local", 50051)
+	service.Attributes.DubboInterfaces = []model.DubboInterface{
+		{Name: "org.example.Greeter", Version: "1.0.0"},
+		{Name: "org.example.Greeter", Group: "gray", Version: "2.0.0"},
+		{Name: "org.example.Stock"},
+	}
+	push := newRDSTestPushContext(t, nil, []*model.Service{service})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "consumer.app", Type: model.Inherent},
+		push,
+		"outbound|50051||greeter.app.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	routes := rc.VirtualHosts[0].Routes
+	if len(routes) != 3 {
+		t.Fatalf("routes = %d, want two subset routes plus default", len(routes))
+	}
+	gray := routes[0]
+	if got := gray.GetMatch().GetPrefix(); got != "/org.example.Greeter/" {
+		t.Fatalf("first route prefix = %q", got)
+	}
+	if headers := gray.GetMatch().GetHeaders(); len(headers) != 2 ||
+		headers[0].GetName() != model.DubboVersionHeader || headers[0].GetExactMatch() != "2.0.0" ||
+		headers[1].GetName() != model.DubboGroupHeader || headers[1].GetExactMatch() != "gray" {
+		t.Fatalf("first route headers = %v", headers)
+	}
+	if got := gray.GetRoute().GetCluster(); got != "outbound|50051|dubbo:gray:2.0.0|greeter.app.svc.cluster.local" {
+		t.Fatalf("first route cluster = %q", got)
+	}
+	if got := routes[1].GetRoute().GetCluster(); got != "outbound|50051|dubbo::1.0.0|greeter.app.svc.cluster.local" {
+		t.Fatalf("second route cluster = %q", got)
+	}
+	if got := routes[2].GetRoute().GetCluster(); got != "outbound|50051||greeter.app.svc.cluster.local" {
+		t.Fatalf("default route cluster = %q", got)
+	}
+}
+
+func TestBuildHTTPRouteSelectsDubboSubsetFromVersionMatch(t *testing.T) {
+	kind := gatewayv1.Kind("Service")
+	port := gatewayv1.PortNumber(50051)
+	routeConfig := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.GRPCRoute,
+			Name:             "greeter-v2",
+			Namespace:        "app",
+			Domain:           "cluster.local",
+		},
+		Spec: &gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Kind: &kind, Name: "greeter", Port: &port}},
+			},
+			Rules: []gatewayv1.GRPCRouteRule{{
+				Matches: []gatewayv1.GRPCRouteMatch{{
+					Method:  &gatewayv1.GRPCMethodMatch{Service: ptrTo("org.example.Greeter")},
+					Headers: []gatewayv1.GRPCHeaderMatch{{Name: "Tri-Service-Version", Value: "2.0.0"}},
+				}},
+				BackendRefs: []gatewayv1.GRPCBackendRef{
+					{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter", Port: &port},
+					}},
+					{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter-legacy", Port: &port},
+					}},
+				},
+			}},
+		},
+	}
+	greeter := newRDSTestService("greeter", "app", "greeter.app.svc.cluster.local", 50051)
+	greeter.Attributes.DubboInterfaces = []model.DubboInterface{{Name: "org.example.Greeter", Version: "2.0.0"}}
+	push := newRDSTestPushContext(t, []config.Config{routeConfig}, []*model.Service{
+		greeter,
+		newRDSTestService("greeter-legacy", "app", "greeter-legacy.app.svc.cluster.local", 50051),
+	})
+
+	rc := buildHTTPRoute(
+		&model.Proxy{ID: "consumer.app", Type: model.Inherent},
+		push,
+		"outbound|50051||greeter.app.svc.cluster.local",
+	)
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	want := map[string]uint32{
+		"outbound|50051|dubbo::2.0.0|greeter.app.svc.cluster.local": 1,
+		"outbound|50051||greeter-legacy.app.svc.cluster.local":      1,
+	}
+	if got := weightedClustersByName(t, rc.VirtualHosts[0].Routes[0]); !reflect.DeepEqual(got, want) {
+		t.Fatalf("weighted clusters = %v, want %v", got, want)
+	}
+}
+
 func TestGatewayRDSRoutesActivationAuthorityToOriginalCluster(t *testing.T) {
 	target := newRDSTestService("payment", "app", "payment.app.svc.cluster.local", 8080)
 	activator := newRDSTestService(
@@ -356,6 +792,75 @@ func TestGatewayInboundTargetPortIncludesActivationRoutes(t *testing.T) {
 	t.Fatalf("activation virtual host not found on Gateway targetPort: %v", rc.GetVirtualHosts())
 }
 
+func TestGatewayInboundRoutesGatewayGRPCRouteOnJWTClaim(t *testing.T) {
+	port := gatewayv1.PortNumber(50051)
+	routeConfig := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.GRPCRoute,
+			Name:             "greeter-gold",
+			Namespace:        "app",
+			Domain:           "cluster.local",
+		},
+		Spec: &gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "dxgate-gateway"}},
+			},
+			Rules: []gatewayv1.GRPCRouteRule{{
+				Matches: []gatewayv1.GRPCRouteMatch{{
+					Method:  &gatewayv1.GRPCMethodMatch{Service: ptrTo("org.apache.dubbo.Greeter")},
+					Headers: []gatewayv1.GRPCHeaderMatch{{Name: "x-jwt-claim-planTier", Value: "gold"}},
+				}},
+				BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: "greeter", Port: &port},
+				}}},
+			}},
+		},
+	}
+	gatewayService := newRDSTestService("dxgate-gateway", "app", "dxgate-gateway.app.svc.cluster.local", 80)
+	gatewayService.Attributes.Labels = map[string]string{
+		"gateway.networking.k8s.io/gateway-name": "dxgate-gateway",
+	}
+	push := newRDSTestPushContext(t, []config.Config{routeConfig}, []*model.Service{
+		gatewayService,
+		newRDSTestService("greeter", "app", "greeter.app.svc.cluster.local", 50051),
+	})
+	if got := push.RoutedJWTClaims(); !reflect.DeepEqual(got, []string{"planTier"}) {
+		t.Fatalf("routed claims = %v, want planTier", got)
+	}
+	proxy := &model.Proxy{
+		ID:              "dxgate-gateway.app",
+		Type:            model.Router,
+		ConfigNamespace: "app",
+		ServiceTargets: []model.ServiceTarget{{
+			Service: gatewayService,
+			Port: model.ServiceInstancePort{
+				ServicePort: gatewayService.Ports[0],
+				TargetPort:  15080,
+			},
+		}},
+	}
+
+	rc := buildHTTPRoute(proxy, push, "15080")
+	if rc == nil {
+		t.Fatal("buildHTTPRoute() returned nil")
+	}
+	routes := rc.GetVirtualHosts()[0].GetRoutes()
+	if len(routes) != 1 {
+		t.Fatalf("routes = %v, want the GRPCRoute rule", routes)
+	}
+	if got := routes[0].GetMatch().GetPrefix(); got != "/org.apache.dubbo.Greeter/" {
+		t.Fatalf("match prefix = %q", got)
+	}
+	if headers := routes[0].GetMatch().GetHeaders(); len(headers) != 1 ||
+		headers[0].GetName() != "x-jwt-claim-plantier" || headers[0].GetExactMatch() != "gold" {
+		t.Fatalf("match headers = %v, want the claim route header", headers)
+	}
+	want := map[string]uint32{"outbound|50051||greeter.app.svc.cluster.local": 1}
+	if got := weightedClustersByName(t, routes[0]); !reflect.DeepEqual(got, want) {
+		t.Fatalf("weighted clusters = %v, want %v", got, want)
+	}
+}
+
 func TestBuildAgentConfigCompilesDxgateServiceHTTPRoute(t *testing.T) {
 	routeConfig := newDxgateHTTPRouteConfig("anthropic", "app", "/anthropic", "/v1/chat/completions")
 	serviceConfig := config.Config{
@@ -460,6 +965,11 @@ func TestBuildAgentConfigExpandsMCPFederationTargets(t *testing.T) {
 
 func newRDSTestPushContext(t *testing.T, configs []config.Config, services []*model.Service) *model.PushContext {
 	t.Helper()
+	return newTestPushContextWithMesh(t, mesh.DefaultMeshConfig(), configs, services)
+}
+
+func newTestPushContextWithMesh(t *testing.T, meshConfig *meshv1alpha1.MeshConfig, configs []config.Config, services []*model.Service) *model.PushContext {
+	t.Helper()
 
 	store := memory.Make(collections.DubboGatewayAPI())
 	for _, cfg := range configs {
@@ -472,7 +982,7 @@ func newRDSTestPushContext(t *testing.T, configs []config.Config, services []*mo
 	env.ConfigStore = store
 	env.ServiceDiscovery = staticServiceDiscovery{services: services}
 	env.Watcher = meshwatcher.ConfigAdapter(krt.NewStatic(&meshwatcher.MeshConfigResource{
-		MeshConfig: mesh.DefaultMeshConfig(),
+		MeshConfig: meshConfig,
 	}, true))
 	env.Init()
 
@@ -0,0 +1,193 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"regexp"
+	"strings"
+
+	core "github.com/kdubbo/xds-api/core/v1"
+	route "github.com/kdubbo/xds-api/route/v1"
+	matcher "github.com/kdubbo/xds-api/type/matcher/v1"
+	"google.golang.org/protobuf/proto"
+	sigsk8siogatewayapiapisv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// applyGatewayAPIHeaderFilters copies RequestHeaderModifier and
+// ResponseHeaderModifier filters onto the route. Set overwrites, Add appends.
+func applyGatewayAPIHeaderFilters(r *route.Route, filters []sigsk8siogatewayapiapisv1.HTTPRouteFilter) {
+	for _, filter := range filters {
+		switch filter.Type {
+		case sigsk8siogatewayapiapisv1.HTTPRouteFilterRequestHeaderModifier:
+			if filter.RequestHeaderModifier == nil {
+				continue
+			}
+			r.RequestHeadersToAdd = append(r.RequestHeadersToAdd, headerValueOptions(filter.RequestHeaderModifier)...)
+			r.RequestHeadersToRemove = append(r.RequestHeadersToRemove, filter.RequestHeaderModifier.Remove...)
+		case sigsk8siogatewayapiapisv1.HTTPRouteFilterResponseHeaderModifier:
+			if filter.ResponseHeaderModifier == nil {
+				continue
+			}
+			r.ResponseHeadersToAdd = append(r.ResponseHeadersToAdd, headerValueOptions(filter.ResponseHeaderModifier)...)
+			r.ResponseHeadersToRemove = append(r.ResponseHeadersToRemove, filter.ResponseHeaderModifier.Remove...)
+		}
+	}
+}
+
+func headerValueOptions(modifier *sigsk8siogatewayapiapisv1.HTTPHeaderFilter) []*core.HeaderValueOption {
+	out := make([]*core.HeaderValueOption, 0, len(modifier.Set)+len(modifier.Add))
+	for _, header := range modifier.Set {
+		out = append(out, &core.HeaderValueOption{
+			Header:       &core.HeaderValue{Key: strings.ToLower(string(header.Name)), Value: header.Value},
+			AppendAction: core.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+		})
+	}
+	for _, header := range modifier.Add {
+		out = append(out, &core.HeaderValueOption{
+			Header:       &core.HeaderValue{Key: strings.ToLower(string(header.Name)), Value: header.Value},
+			AppendAction: core.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
+		})
+	}
+	return out
+}
+
+// applyGatewayAPIURLRewrite translates the URLRewrite filter into the route
+// action's prefix, full-path and authority rewrites.
+func applyGatewayAPIURLRewrite(action *route.RouteAction, filters []sigsk8siogatewayapiapisv1.HTTPRouteFilter) {
+	for _, filter := range filters {
+		if filter.Type != sigsk8siogatewayapiapisv1.HTTPRouteFilterURLRewrite || filter.URLRewrite == nil {
+			continue
+		}
+		if hostname := filter.URLRewrite.Hostname; hostname != nil && *hostname != "" {
+			action.HostRewriteSpecifier = &route.RouteAction_HostRewriteLiteral{HostRewriteLiteral: string(*hostname)}
+		}
+		path := filter.URLRewrite.Path
+		if path == nil {
+			continue
+		}
+		switch path.Type {
+		case sigsk8siogatewayapiapisv1.PrefixMatchHTTPPathModifier:
+			if path.ReplacePrefixMatch != nil {
+				action.PrefixRewrite = *path.ReplacePrefixMatch
+			}
+		case sigsk8siogatewayapiapisv1.FullPathHTTPPathModifier:
+			if path.ReplaceFullPath != nil {
+				action.RegexRewrite = &matcher.RegexMatchAndSubstitute{
+					Pattern:      &matcher.RegexMatcher{Regex: "^/.*$"},
+					Substitution: *path.ReplaceFullPath,
+				}
+			}
+		}
+	}
+}
+
+// routeActionForMatch returns the action for one match of a rule, binding a
+// prefix rewrite to that match's own prefix where a literal rewrite would be
+// wrong.
+func routeActionForMatch(action *route.RouteAction, match *route.RouteMatch) *route.RouteAction {
+	regex := gatewayAPIPrefixRewrite(match.GetPrefix(), action.GetPrefixRewrite())
+	if regex == nil {
+		return action
+	}
+	out := proto.Clone(action).(*route.RouteAction)
+	out.PrefixRewrite = ""
+	out.RegexRewrite = regex
+	return out
+}
+
+// redirectActionForMatch is routeActionForMatch for redirects.
+func redirectActionForMatch(redirect *route.RedirectAction, match *route.RouteMatch) *route.RedirectAction {
+	regex := gatewayAPIPrefixRewrite(match.GetPrefix(), redirect.GetPrefixRewrite())
+	if regex == nil {
+		return redirect
+	}
+	out := proto.Clone(redirect).(*route.RedirectAction)
+	out.PathRewriteSpecifier = &route.RedirectAction_RegexRewrite{RegexRewrite: regex}
+	return out
+}
+
+// gatewayAPIPrefixRewrite returns the regex rewrite for ReplacePrefixMatch, or
+// nil when replacing the prefix literally already matches the Gateway API.
+// Literal replacement breaks when either side ends in a slash: rewriting /foo
+// to / would turn /foo/bar into //bar. The Gateway API matches prefixes by
+// path element, so the regex consumes the separator after the prefix and
+// keeps exactly one between the replacement and the rest of the path.
+func gatewayAPIPrefixRewrite(matchPrefix, replacement string) *matcher.RegexMatchAndSubstitute {
+	if matchPrefix == "" || replacement == "" {
+		return nil
+	}
+	if !strings.HasSuffix(matchPrefix, "/") && !strings.HasSuffix(replacement, "/") {
+		return nil
+	}
+	substitution := replacement + `\1\2`
+	if strings.HasSuffix(replacement, "/") {
+		substitution = replacement + `\2`
+	}
+	return &matcher.RegexMatchAndSubstitute{
+		Pattern:      &matcher.RegexMatcher{Regex: "^" + regexp.QuoteMeta(strings.TrimSuffix(matchPrefix, "/")) + `(/?)(.*)`},
+		Substitution: substitution,
+	}
+}
+
+// gatewayAPIRedirectAction returns the redirect for a RequestRedirect filter.
+// A redirecting rule answers the caller directly and never reaches a backend.
+func gatewayAPIRedirectAction(filters []sigsk8siogatewayapiapisv1.HTTPRouteFilter) *route.RedirectAction {
+	for _, filter := range filters {
+		if filter.Type != sigsk8siogatewayapiapisv1.HTTPRouteFilterRequestRedirect || filter.RequestRedirect == nil {
+			continue
+		}
+		in := filter.RequestRedirect
+		redirect := &route.RedirectAction{
+			ResponseCode: route.RedirectAction_FOUND,
+		}
+		if in.Scheme != nil {
+			redirect.SchemeRewriteSpecifier = &route.RedirectAction_SchemeRedirect{SchemeRedirect: *in.Scheme}
+		}
+		if in.Hostname != nil {
+			redirect.HostRedirect = string(*in.Hostname)
+		}
+		if in.Port != nil {
+			redirect.PortRedirect = uint32(*in.Port)
+		}
+		if in.StatusCode != nil {
+			switch *in.StatusCode {
+			case 301:
+				redirect.ResponseCode = route.RedirectAction_MOVED_PERMANENTLY
+			case 303:
+				redirect.ResponseCode = route.RedirectAction_SEE_OTHER
+			case 307:
+				redirect.ResponseCode = route.RedirectAction_TEMPORARY_REDIRECT
+			case 308:
+				redirect.ResponseCode = route.RedirectAction_PERMANENT_REDIRECT
+			}
+		}
+		if in.Path != nil {
+			switch in.Path.Type {
+			case sigsk8siogatewayapiapisv1.FullPathHTTPPathModifier:
+				if in.Path.ReplaceFullPath != nil {
+					redirect.PathRewriteSpecifier = &route.RedirectAction_PathRedirect{PathRedirect: *in.Path.ReplaceFullPath}
+				}
+			case sigsk8siogatewayapiapisv1.PrefixMatchHTTPPathModifier:
+				if in.Path.ReplacePrefixMatch != nil {
+					redirect.PathRewriteSpecifier = &route.RedirectAction_PrefixRewrite{PrefixRewrite: *in.Path.ReplacePrefixMatch}
+				}
+			}
+		}
+		return redirect
+	}
+	return nil
+}
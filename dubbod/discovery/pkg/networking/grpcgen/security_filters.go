@@ -20,40 +20,57 @@ import (
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/util/protoconv"
 	"github.com/apache/dubbo-kubernetes/pkg/config"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	"github.com/apache/dubbo-kubernetes/pkg/wellknown"
+	"github.com/apache/dubbo-kubernetes/pkg/xds/filterpb"
 	security "github.com/kdubbo/api/security/v1alpha3"
 	jwtv1 "github.com/kdubbo/xds-api/extensions/filters/v1/http/jwt_authn"
 	rbacv1 "github.com/kdubbo/xds-api/extensions/filters/v1/http/rbac"
 	routerv1 "github.com/kdubbo/xds-api/extensions/filters/v1/http/router"
 	hcmv1 "github.com/kdubbo/xds-api/extensions/filters/v1/network/http_connection_manager"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
-func buildInboundHTTPFilters(push *model.PushContext, serviceTarget model.ServiceTarget) []*hcmv1.HttpFilter {
+// buildInboundHTTPFilters builds the inbound filter chain. routedClaims are
+// the JWT claims the listener's routes match on; only the gateway listener
+// passes them, see matchesJWTClaim.
+func buildInboundHTTPFilters(push *model.PushContext, serviceTarget model.ServiceTarget, routedClaims []string) []*hcmv1.HttpFilter {
 	filters := []*hcmv1.HttpFilter{}
 	namespace := serviceTargetNamespace(serviceTarget)
 	workloadLabels := workloadLabelsForServiceTarget(serviceTarget)
 
-	if jwt := buildJWTAuthenticationFilter(push.RequestAuthenticationsForWorkload(namespace, workloadLabels)); jwt != nil {
+	if jwt := buildJWTAuthenticationFilter(push.RequestAuthenticationsForWorkload(namespace, workloadLabels), routedClaims); jwt != nil {
 		filters = append(filters, jwt)
 	}
-	filters = append(filters, buildAuthorizationFilters(push.AuthorizationPoliciesForWorkload(namespace, workloadLabels))...)
+	filters = append(filters, buildAuthorizationFilters(push, push.AuthorizationPoliciesForWorkload(namespace, workloadLabels))...)
+	if rateLimit := buildLocalRateLimitFilter(push, serviceTarget); rateLimit != nil {
+		filters = append(filters, rateLimit)
+	}
 	filters = append(filters, routerHTTPFilter())
 	return filters
 }
 
-func buildJWTAuthenticationFilter(configs []config.Config) *hcmv1.HttpFilter {
+// buildJWTAuthenticationFilter builds one JWT provider per rule. Every
+// provider also copies routedClaims into their claim route headers, so the
+// RDS matches on those headers see verified claims only.
+func buildJWTAuthenticationFilter(configs []config.Config, routedClaims []string) *hcmv1.HttpFilter {
 	providers := []*jwtv1.JwtProvider{}
 	for _, cfg := range configs {
 		spec, ok := cfg.Spec.(*security.RequestAuthentication)
 		if !ok || spec == nil {
 			continue
 		}
+		output, err := securityconfig.JWTOutputFromAnnotations(cfg.Annotations)
+		if err != nil {
+			log.Warnf("RequestAuthentication %s/%s: ignoring JWT output options: %v", cfg.Namespace, cfg.Name, err)
+			output = securityconfig.JWTOutput{}
+		}
 		for _, rule := range spec.GetJwtRules() {
 			if rule == nil || rule.GetIssuer() == "" {
 				continue
 			}
-			providers = append(providers, jwtProviderFromRule(rule))
+			providers = append(providers, jwtProviderFromRule(rule, output, routedClaims))
 		}
 	}
 	if len(providers) == 0 {
@@ -65,7 +82,7 @@ func buildJWTAuthenticationFilter(configs []config.Config) *hcmv1.HttpFilter {
 	})
 }
 
-func jwtProviderFromRule(rule *security.JWTRule) *jwtv1.JwtProvider {
+func jwtProviderFromRule(rule *security.JWTRule, output securityconfig.JWTOutput, routedClaims []string) *jwtv1.JwtProvider {
 	headers := make([]*jwtv1.JwtHeader, 0, len(rule.GetFromHeaders()))
 	for _, header := range rule.GetFromHeaders() {
 		if header == nil || header.GetName() == "" {
@@ -80,13 +97,39 @@ func jwtProviderFromRule(rule *security.JWTRule) *jwtv1.JwtProvider {
 		headers = append(headers, &jwtv1.JwtHeader{Name: "authorization", Prefix: "Bearer "})
 	}
 	return &jwtv1.JwtProvider{
-		Issuer:      rule.GetIssuer(),
-		Audiences:   append([]string(nil), rule.GetAudiences()...),
-		JwksUri:     rule.GetJwksUri(),
-		Jwks:        rule.GetJwks(),
-		FromHeaders: headers,
-		FromParams:  append([]string(nil), rule.GetFromParams()...),
+		Issuer:               rule.GetIssuer(),
+		Audiences:            append([]string(nil), rule.GetAudiences()...),
+		JwksUri:              rule.GetJwksUri(),
+		Jwks:                 rule.GetJwks(),
+		FromHeaders:          headers,
+		FromParams:           append([]string(nil), rule.GetFromParams()...),
+		ClaimToHeaders:       jwtClaimToHeaders(output.ClaimToHeaders, routedClaims),
+		Forward:              output.ForwardOriginalToken,
+		ForwardPayloadHeader: output.PayloadHeader,
+	}
+}
+
+// routeHeaderName spells a claim route header the way JWT providers write
+// it, since claims keep their case in routes but headers are lowercase.
+func routeHeaderName(name string) string {
+	if claim, ok := securityconfig.ClaimFromRouteHeader(name); ok {
+		return securityconfig.ClaimRouteHeader(claim)
 	}
+	return name
+}
+
+func jwtClaimToHeaders(mappings []securityconfig.ClaimToHeader, routedClaims []string) []*jwtv1.JwtClaimToHeader {
+	if len(mappings) == 0 && len(routedClaims) == 0 {
+		return nil
+	}
+	out := make([]*jwtv1.JwtClaimToHeader, 0, len(mappings)+len(routedClaims))
+	for _, mapping := range mappings {
+		out = append(out, &jwtv1.JwtClaimToHeader{HeaderName: mapping.Header, ClaimName: mapping.Claim})
+	}
+	for _, claim := range routedClaims {
+		out = append(out, &jwtv1.JwtClaimToHeader{HeaderName: securityconfig.ClaimRouteHeader(claim), ClaimName: claim})
+	}
+	return out
 }
 
 // buildAuthorizationFilters translates AuthorizationPolicies into RBAC filters.
@@ -94,8 +137,15 @@ func jwtProviderFromRule(rule *security.JWTRule) *jwtv1.JwtProvider {
 // rejected if it matches any DENY rule, and — when at least one ALLOW policy
 // exists — rejected unless it matches an ALLOW rule. Emitting them as two
 // filters (DENY first) preserves those semantics; folding both actions into a
-// single filter would turn every ALLOW rule into a DENY rule.
-func buildAuthorizationFilters(configs []config.Config) []*hcmv1.HttpFilter {
+// single filter would turn every ALLOW rule into a DENY rule. CUSTOM policies
+// run ahead of both.
+func buildAuthorizationFilters(push *model.PushContext, configs []config.Config) []*hcmv1.HttpFilter {
+	custom, configs := model.SplitCustomAuthorizationPolicies(configs)
+	filters := []*hcmv1.HttpFilter{}
+	if extAuthz := buildExtAuthzFilter(push, custom); extAuthz != nil {
+		filters = append(filters, extAuthz)
+	}
+
 	denyRules := []*rbacv1.Rule{}
 	allowRules := []*rbacv1.Rule{}
 	hasAllowPolicy := false
@@ -106,7 +156,7 @@ func buildAuthorizationFilters(configs []config.Config) []*hcmv1.HttpFilter {
 		}
 		rules := make([]*rbacv1.Rule, 0, len(spec.GetRules()))
 		for _, rule := range spec.GetRules() {
-			rules = append(rules, authorizationRuleFromAPI(rule))
+			rules = append(rules, authorizationRuleFromAPI(push, rule))
 		}
 		if spec.GetAction() == security.AuthorizationPolicy_DENY {
 			denyRules = append(denyRules, rules...)
@@ -117,7 +167,6 @@ func buildAuthorizationFilters(configs []config.Config) []*hcmv1.HttpFilter {
 			allowRules = append(allowRules, rules...)
 		}
 	}
-	filters := []*hcmv1.HttpFilter{}
 	if len(denyRules) > 0 {
 		filters = append(filters, typedHTTPFilter(wellknown.HTTPRoleBasedAccessControl, &rbacv1.RBAC{
 			Action: rbacv1.RBAC_DENY,
@@ -133,7 +182,57 @@ func buildAuthorizationFilters(configs []config.Config) []*hcmv1.HttpFilter {
 	return filters
 }
 
-func authorizationRuleFromAPI(rule *security.Rule) *rbacv1.Rule {
+// buildExtAuthzFilter sends the requests matched by CUSTOM policies to their
+// external authorization provider. A provider that is missing, ambiguous or
+// cannot be encoded turns the policies into DENY rules: skipping the check
+// would admit exactly the requests the policies mean to guard.
+func buildExtAuthzFilter(push *model.PushContext, custom []config.Config) *hcmv1.HttpFilter {
+	if len(custom) == 0 {
+		return nil
+	}
+	rules := []*rbacv1.Rule{}
+	for _, cfg := range custom {
+		spec, ok := cfg.Spec.(*security.AuthorizationPolicy)
+		if !ok || spec == nil {
+			continue
+		}
+		for _, rule := range spec.GetRules() {
+			rules = append(rules, authorizationRuleFromAPI(push, rule))
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	provider, err := push.ExtAuthzProviderForPolicies(custom)
+	if err == nil {
+		return typedHTTPFilter(wellknown.HTTPExternalAuthorization, extAuthzFilterConfig(provider, rules))
+	}
+	log.Warnf("denying requests matched by %d CUSTOM authorization policies: %v", len(custom), err)
+	return typedHTTPFilter(wellknown.HTTPRoleBasedAccessControl, &rbacv1.RBAC{
+		Action: rbacv1.RBAC_DENY,
+		Rules:  rules,
+	})
+}
+
+// extAuthzFilterConfig builds the ext_authz filter config. Requests matching
+// one of the rules are checked.
+func extAuthzFilterConfig(provider model.ExtAuthzProvider, rules []*rbacv1.Rule) *filterpb.ExtAuthz {
+	return &filterpb.ExtAuthz{
+		Provider:                  provider.Name,
+		Cluster:                   provider.ClusterName(),
+		Authority:                 provider.Service,
+		PathPrefix:                provider.PathPrefix,
+		Timeout:                   durationpb.New(provider.CheckTimeout()),
+		FailureModeAllow:          provider.FailOpen,
+		StatusOnError:             provider.ErrorStatus(),
+		AllowedHeaders:            provider.IncludeRequestHeadersInCheck,
+		HeadersToUpstreamOnAllow:  provider.HeadersToUpstreamOnAllow,
+		HeadersToDownstreamOnDeny: provider.HeadersToDownstreamOnDeny,
+		Matcher:                   protoconv.MessageToAny(&rbacv1.RBAC{Rules: rules}),
+	}
+}
+
+func authorizationRuleFromAPI(push *model.PushContext, rule *security.Rule) *rbacv1.Rule {
 	if rule == nil {
 		return &rbacv1.Rule{}
 	}
@@ -145,7 +244,7 @@ func authorizationRuleFromAPI(rule *security.Rule) *rbacv1.Rule {
 		}
 		sources = append(sources, &rbacv1.Source{
 			RequestPrincipals: append([]string(nil), from.GetSource().GetRequestPrincipals()...),
-			Principals:        append([]string(nil), from.GetSource().GetPrincipals()...),
+			Principals:        append([]string(nil), push.ExpandPrincipals(from.GetSource().GetPrincipals())...),
 		})
 	}
 	when := make([]*rbacv1.Condition, 0, len(rule.GetWhen()))
@@ -153,10 +252,14 @@ func authorizationRuleFromAPI(rule *security.Rule) *rbacv1.Rule {
 		if condition == nil {
 			continue
 		}
+		// Dubbo RPC conditions become path and header conditions on the
+		// Triple request.
+		key, values := securityconfig.TripleCondition(condition.GetKey(), condition.GetValues())
+		_, notValues := securityconfig.TripleCondition(condition.GetKey(), condition.GetNotValues())
 		when = append(when, &rbacv1.Condition{
-			Key:       condition.GetKey(),
-			Values:    append([]string(nil), condition.GetValues()...),
-			NotValues: append([]string(nil), condition.GetNotValues()...),
+			Key:       key,
+			Values:    append([]string(nil), values...),
+			NotValues: append([]string(nil), notValues...),
 		})
 	}
 	return &rbacv1.Rule{Sources: sources, When: when}
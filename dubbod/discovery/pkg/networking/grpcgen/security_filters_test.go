@@ -17,16 +17,26 @@
 package grpcgen
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
 	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/config/mesh"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	"github.com/apache/dubbo-kubernetes/pkg/wellknown"
+	"github.com/apache/dubbo-kubernetes/pkg/xds/filterpb"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+	networking "github.com/kdubbo/api/networking/v1alpha3"
 	security "github.com/kdubbo/api/security/v1alpha3"
 	typev1alpha3 "github.com/kdubbo/api/type/v1alpha3"
 	jwtv1 "github.com/kdubbo/xds-api/extensions/filters/v1/http/jwt_authn"
 	rbacv1 "github.com/kdubbo/xds-api/extensions/filters/v1/http/rbac"
+	"google.golang.org/protobuf/types/known/durationpb"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func TestBuildInboundHTTPFiltersAddsJWTAndAuthorizationBeforeRouter(t *testing.T) {
@@ -45,7 +55,7 @@ func TestBuildInboundHTTPFiltersAddsJWTAndAuthorizationBeforeRouter(t *testing.T
 		},
 	}
 
-	filters := buildInboundHTTPFilters(push, serviceTarget)
+	filters := buildInboundHTTPFilters(push, serviceTarget, nil)
 	if len(filters) != 3 {
 		t.Fatalf("filters = %d, want jwt, rbac, router", len(filters))
 	}
@@ -99,7 +109,7 @@ func TestBuildInboundHTTPFiltersAddsJWTAndAuthorizationBeforeRouter(t *testing.T
 }
 
 func TestAuthorizationRuleProjectsWorkloadPrincipal(t *testing.T) {
-	rule := authorizationRuleFromAPI(&security.Rule{
+	rule := authorizationRuleFromAPI(nil, &security.Rule{
 		From: []*security.From{{Source: &security.Source{
 			Principals: []string{"cluster.local/ns/orders/sa/client"},
 		}}},
@@ -109,6 +119,37 @@ func TestAuthorizationRuleProjectsWorkloadPrincipal(t *testing.T) {
 	}
 }
 
+func TestAuthorizationRuleCompilesDubboRPCConditionsForTriple(t *testing.T) {
+	rule := authorizationRuleFromAPI(nil, &security.Rule{
+		When: []*security.Condition{
+			{Key: "dubbo.interface", Values: []string{"org.apache.dubbo.Greeter"}},
+			{Key: "dubbo.method", NotValues: []string{"delete"}},
+			{Key: "dubbo.group", Values: []string{"gray"}},
+			{Key: "dubbo.version", Values: []string{"1.0.0"}},
+		},
+	})
+	want := []struct {
+		key       string
+		values    []string
+		notValues []string
+	}{
+		{"request.url_path", []string{"/org.apache.dubbo.Greeter/*"}, nil},
+		{"request.url_path", nil, []string{"*/delete"}},
+		{"request.headers[tri-service-group]", []string{"gray"}, nil},
+		{"request.headers[tri-service-version]", []string{"1.0.0"}, nil},
+	}
+	if len(rule.GetWhen()) != len(want) {
+		t.Fatalf("conditions = %v, want %d", rule.GetWhen(), len(want))
+	}
+	for i, w := range want {
+		got := rule.GetWhen()[i]
+		if got.GetKey() != w.key || strings.Join(got.GetValues(), ",") != strings.Join(w.values, ",") ||
+			strings.Join(got.GetNotValues(), ",") != strings.Join(w.notValues, ",") {
+			t.Fatalf("condition[%d] = %v, want %+v", i, got, w)
+		}
+	}
+}
+
 func newRequestAuthenticationConfig() config.Config {
 	return config.Config{
 		Meta: config.Meta{
@@ -165,7 +206,7 @@ func TestBuildAuthorizationFiltersSplitsDenyAndAllow(t *testing.T) {
 	}
 	allow := newAuthorizationPolicyConfig()
 
-	filters := buildAuthorizationFilters([]config.Config{deny, allow})
+	filters := buildAuthorizationFilters(nil, []config.Config{deny, allow})
 	if len(filters) != 2 {
 		t.Fatalf("filters = %d, want deny + allow", len(filters))
 	}
@@ -200,7 +241,7 @@ func TestBuildAuthorizationFiltersEmptyAllowPolicyDeniesAll(t *testing.T) {
 		Meta: config.Meta{GroupVersionKind: gvk.AuthorizationPolicy, Name: "deny-all", Namespace: "foo"},
 		Spec: &security.AuthorizationPolicy{Action: security.AuthorizationPolicy_ALLOW},
 	}
-	filters := buildAuthorizationFilters([]config.Config{allow})
+	filters := buildAuthorizationFilters(nil, []config.Config{allow})
 	if len(filters) != 1 {
 		t.Fatalf("filters = %d, want 1", len(filters))
 	}
@@ -215,3 +256,189 @@ func TestBuildAuthorizationFiltersEmptyAllowPolicyDeniesAll(t *testing.T) {
 		t.Fatalf("rules = %d, want 0", len(rbacConfig.GetRules()))
 	}
 }
+
+func newCustomAuthorizationPolicyConfig(provider string) config.Config {
+	return config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.AuthorizationPolicy,
+			Name:             "opa-check",
+			Namespace:        "foo",
+		},
+		Spec: &security.AuthorizationPolicy{
+			Selector: &typev1alpha3.WorkloadSelector{MatchLabels: map[string]string{"app": "httpbin"}},
+			Action:   security.AuthorizationPolicy_CUSTOM,
+			ActionDetail: &security.AuthorizationPolicy_Provider{
+				Provider: &security.AuthorizationPolicy_ExtensionProvider{Name: provider},
+			},
+			Rules: []*security.Rule{{
+				From: []*security.From{{
+					Source: &security.Source{Principals: []string{"cluster.local/ns/bar/sa/client"}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestBuildAuthorizationFiltersCustomActionRunsExtAuthzFirst(t *testing.T) {
+	meshConfig := mesh.DefaultMeshConfig()
+	meshConfig.ExtensionProviders = []*meshv1alpha1.MeshConfig_ExtensionProvider{{
+		Name: "opa",
+		Provider: &meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExtAuthzHttp{
+			EnvoyExtAuthzHttp: &meshv1alpha1.MeshConfig_ExtensionProvider_EnvoyExternalAuthorizationHttpProvider{
+				Service:                      "opa.policy.svc.cluster.local",
+				Port:                         9191,
+				Timeout:                      durationpb.New(250 * time.Millisecond),
+				FailOpen:                     true,
+				IncludeRequestHeadersInCheck: []string{"X-Tenant"},
+				HeadersToUpstreamOnAllow:     []string{"x-user"},
+			},
+		},
+	}}
+	push := newTestPushContextWithMesh(t, meshConfig, nil, nil)
+
+	filters := buildAuthorizationFilters(push, []config.Config{newCustomAuthorizationPolicyConfig("opa"), newAuthorizationPolicyConfig()})
+	if len(filters) != 2 {
+		t.Fatalf("filters = %d, want ext_authz + allow", len(filters))
+	}
+	if filters[0].GetName() != wellknown.HTTPExternalAuthorization {
+		t.Fatalf("first filter = %s, want %s", filters[0].GetName(), wellknown.HTTPExternalAuthorization)
+	}
+	if filters[0].GetIsOptional() {
+		t.Fatal("ext_authz filter is optional, want it required")
+	}
+	extAuthz := &filterpb.ExtAuthz{}
+	if err := filters[0].GetTypedConfig().UnmarshalTo(extAuthz); err != nil {
+		t.Fatalf("unmarshal ext_authz filter: %v", err)
+	}
+	if got := extAuthz.GetCluster(); got != "outbound|9191||opa.policy.svc.cluster.local" {
+		t.Fatalf("cluster = %q", got)
+	}
+	if got := extAuthz.GetTimeout().AsDuration(); got != 250*time.Millisecond {
+		t.Fatalf("timeout = %v, want 250ms", got)
+	}
+	if !extAuthz.GetFailureModeAllow() {
+		t.Fatalf("failureModeAllow = false, want true")
+	}
+	if got := extAuthz.GetAllowedHeaders(); len(got) != 1 || got[0] != "x-tenant" {
+		t.Fatalf("allowedHeaders = %v, want [x-tenant]", got)
+	}
+	matcher := &rbacv1.RBAC{}
+	if err := extAuthz.GetMatcher().UnmarshalTo(matcher); err != nil {
+		t.Fatalf("unmarshal matcher: %v", err)
+	}
+	if got := len(matcher.GetRules()); got != 1 {
+		t.Fatalf("rules = %d, want 1", got)
+	}
+	if filters[1].GetName() != wellknown.HTTPRoleBasedAccessControl {
+		t.Fatalf("second filter = %s, want rbac", filters[1].GetName())
+	}
+}
+
+func TestBuildAuthorizationFiltersCustomActionWithoutProviderFailsClosed(t *testing.T) {
+	filters := buildAuthorizationFilters(nil, []config.Config{newCustomAuthorizationPolicyConfig("missing")})
+	if len(filters) != 1 {
+		t.Fatalf("filters = %d, want 1", len(filters))
+	}
+	rbacConfig := &rbacv1.RBAC{}
+	if err := filters[0].GetTypedConfig().UnmarshalTo(rbacConfig); err != nil {
+		t.Fatalf("unmarshal filter: %v", err)
+	}
+	if rbacConfig.GetAction() != rbacv1.RBAC_DENY || len(rbacConfig.GetRules()) != 1 {
+		t.Fatalf("filter = %v, want DENY with the CUSTOM rule", rbacConfig)
+	}
+}
+
+func TestBuildJWTAuthenticationFilterCopiesClaimsToHeaders(t *testing.T) {
+	requestAuthn := newRequestAuthenticationConfig()
+	requestAuthn.Annotations = map[string]string{
+		securityconfig.JWTClaimToHeadersAnnotation:       "x-tenant=tenant",
+		securityconfig.JWTForwardOriginalTokenAnnotation: "true",
+		securityconfig.JWTPayloadHeaderAnnotation:        "x-jwt-payload",
+	}
+
+	filter := buildJWTAuthenticationFilter([]config.Config{requestAuthn}, []string{"planTier"})
+	jwtConfig := &jwtv1.JwtAuthentication{}
+	if err := filter.GetTypedConfig().UnmarshalTo(jwtConfig); err != nil {
+		t.Fatalf("unmarshal jwt filter: %v", err)
+	}
+	provider := jwtConfig.GetProviders()[0]
+	if !provider.GetForward() || provider.GetForwardPayloadHeader() != "x-jwt-payload" {
+		t.Fatalf("forward = %v, payload header = %q", provider.GetForward(), provider.GetForwardPayloadHeader())
+	}
+	got := map[string]string{}
+	for _, mapping := range provider.GetClaimToHeaders() {
+		got[mapping.GetHeaderName()] = mapping.GetClaimName()
+	}
+	want := map[string]string{"x-tenant": "tenant", "x-jwt-claim-plantier": "planTier"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("claim to headers = %v, want %v", got, want)
+	}
+}
+
+func TestBuildRouteMatchesDropsJWTClaimMatches(t *testing.T) {
+	httpRoute := newServiceAttachedHTTPRouteConfig("reviews", "foo", "reviews", 9080)
+	matches := httpRoute.Spec.(*gatewayv1.HTTPRouteSpec).Rules[0].Matches
+	matches[0].Headers[0].Name = "X-JWT-Claim-planTier"
+	if got := buildRouteMatchesFromHTTPRouteMatches(matches, false); len(got) != 0 {
+		t.Fatalf("route matches = %v, want the claim match dropped", got)
+	}
+	if got := buildRouteMatchesFromHTTPRouteMatches(matches, true); len(got) != 1 || got[0].GetHeaders()[0].GetName() != "x-jwt-claim-plantier" {
+		t.Fatalf("gateway route matches = %v, want the claim route header", got)
+	}
+
+	grpcMatches := []gatewayv1.GRPCRouteMatch{
+		{Headers: []gatewayv1.GRPCHeaderMatch{{Name: "x-jwt-claim-plan", Value: "gold"}}},
+		{Headers: []gatewayv1.GRPCHeaderMatch{{Name: "x-plan", Value: "gold"}}},
+	}
+	got := buildRouteMatchesFromGRPCRouteMatches(grpcMatches, false)
+	if len(got) != 1 || got[0].GetHeaders()[0].GetName() != "x-plan" {
+		t.Fatalf("route matches = %v, want only the plain header match", got)
+	}
+	if got := buildRouteMatchesFromGRPCRouteMatches(grpcMatches, true); len(got) != 2 {
+		t.Fatalf("gateway route matches = %v, want both matches", got)
+	}
+}
+
+func TestBuildInboundHTTPFiltersAddsLocalRateLimitBeforeRouter(t *testing.T) {
+	push := newTestPushContextWithMesh(t, mesh.DefaultMeshConfig(), []config.Config{{
+		Meta: config.Meta{GroupVersionKind: gvk.RateLimitPolicy, Name: "httpbin-limit", Namespace: "foo"},
+		Spec: &networking.RateLimitPolicy{
+			TargetRefs: []*networking.PolicyTargetReference{{Kind: "Service", Name: "httpbin", SectionName: "grpc"}},
+			TokenBucket: &networking.TokenBucket{
+				MaxTokens:     20,
+				TokensPerFill: 5,
+				FillInterval:  durationpb.New(500 * time.Millisecond),
+			},
+			Key:    networking.RateLimitPolicy_HEADER,
+			Header: "x-tenant",
+		},
+	}}, nil)
+
+	serviceTarget := func(port string) model.ServiceTarget {
+		return model.ServiceTarget{
+			Service: &model.Service{Attributes: model.ServiceAttributes{Name: "httpbin", Namespace: "foo"}},
+			Port:    model.ServiceInstancePort{ServicePort: &model.Port{Name: port, Port: 8000}},
+		}
+	}
+	filters := buildInboundHTTPFilters(push, serviceTarget("grpc"), nil)
+	if len(filters) != 2 || filters[0].GetName() != wellknown.HTTPLocalRateLimit || filters[1].GetName() != wellknown.HTTPRouter {
+		t.Fatalf("filters = %v, want local rate limit, router", filters)
+	}
+	if !filters[0].GetIsOptional() {
+		t.Fatal("rate limit filter is required, want it optional")
+	}
+	cfg := &filterpb.LocalRateLimit{}
+	if err := filters[0].GetTypedConfig().UnmarshalTo(cfg); err != nil {
+		t.Fatalf("unmarshal rate limit filter: %v", err)
+	}
+	if cfg.GetMaxTokens() != 20 || cfg.GetTokensPerFill() != 5 || cfg.GetFillInterval().AsDuration() != 500*time.Millisecond {
+		t.Fatalf("token bucket = %d+%d per %v", cfg.GetMaxTokens(), cfg.GetTokensPerFill(), cfg.GetFillInterval().AsDuration())
+	}
+	if cfg.GetKey() != filterpb.LocalRateLimit_HEADER || cfg.GetHeader() != "x-tenant" || cfg.GetPolicy() != "foo/httpbin-limit" {
+		t.Fatalf("bucket key = %v %q of %s", cfg.GetKey(), cfg.GetHeader(), cfg.GetPolicy())
+	}
+
+	if filters := buildInboundHTTPFilters(push, serviceTarget("http"), nil); len(filters) != 1 {
+		t.Fatalf("filters for an untargeted port = %v, want router only", filters)
+	}
+}
@@ -0,0 +1,136 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/status"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
+	"github.com/apache/dubbo-kubernetes/pkg/log"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var logger = log.RegisterScope("ratelimit", "Rate limit policies")
+
+// Controller writes the status of each RateLimitPolicy: whether it applies
+// to its targets and whether the workloads behind them enforce it.
+type Controller struct {
+	policies kclient.Client[*clientnetworking.RateLimitPolicy]
+	services kclient.Client[*corev1.Service]
+	pods     kclient.Client[*corev1.Pod]
+	queue    controllers.Queue
+}
+
+// NewController creates the status controller.
+func NewController(client kube.Client) *Controller {
+	c := &Controller{
+		policies: kclient.New[*clientnetworking.RateLimitPolicy](client),
+		services: kclient.New[*corev1.Service](client),
+		pods:     kclient.New[*corev1.Pod](client),
+	}
+	c.queue = controllers.NewQueue("rate limit policy",
+		controllers.WithReconciler(c.Reconcile),
+		controllers.WithMaxAttempts(5))
+	// Policies of a namespace compete for the same ports, so a change to one
+	// can move the others between Conflicted and Accepted.
+	c.policies.AddEventHandler(controllers.EventHandler[*clientnetworking.RateLimitPolicy]{
+		AddFunc: func(policy *clientnetworking.RateLimitPolicy) {
+			c.enqueueNamespace(policy.Namespace)
+		},
+		UpdateFunc: func(oldPolicy, newPolicy *clientnetworking.RateLimitPolicy) {
+			// Status writes do not bump the generation; skip them.
+			if oldPolicy.GetGeneration() != newPolicy.GetGeneration() {
+				c.enqueueNamespace(newPolicy.Namespace)
+			}
+		},
+		DeleteFunc: func(policy *clientnetworking.RateLimitPolicy) {
+			c.enqueueNamespace(policy.Namespace)
+		},
+	})
+	// The status depends on the pods behind each target.
+	c.services.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) {
+		c.enqueueNamespace(o.GetNamespace())
+	}))
+	c.pods.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) {
+		c.enqueueNamespace(o.GetNamespace())
+	}))
+	return c
+}
+
+// Run waits for the informers to sync and writes status until stop is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	kube.WaitForCacheSync("rate limit controller", stop, c.policies.HasSynced, c.services.HasSynced, c.pods.HasSynced)
+	c.queue.Run(stop)
+	controllers.ShutdownAll(c.policies, c.services, c.pods)
+}
+
+func (c *Controller) enqueueNamespace(namespace string) {
+	for _, policy := range c.policies.List(namespace, klabels.Everything()) {
+		c.queue.AddObject(policy)
+	}
+}
+
+// Reconcile recomputes the conditions of one policy and writes them when
+// they changed.
+func (c *Controller) Reconcile(key types.NamespacedName) error {
+	policy := c.policies.Get(key.Name, key.Namespace)
+	if policy == nil {
+		return nil
+	}
+
+	conditions := Conditions(policy, c.index(policy.Namespace), c)
+	if status.SameConditions(policy.Status.GetConditions(), conditions) {
+		return nil
+	}
+
+	updated := policy.DeepCopy()
+	updated.Status.Conditions = conditions
+	if _, err := c.policies.UpdateStatus(updated); err != nil {
+		return err
+	}
+	for _, condition := range conditions {
+		logger.Debugf("updated %s/%s: %s=%s(%s)", key.Namespace, key.Name,
+			condition.GetType(), condition.GetStatus(), condition.GetReason())
+	}
+	return nil
+}
+
+// index builds the same port index the push context builds. Policies only
+// target Services of their own namespace, so the namespace is enough.
+func (c *Controller) index(namespace string) map[string]model.RateLimitSettings {
+	var configs []config.Config
+	for _, policy := range c.policies.List(namespace, klabels.Everything()) {
+		configs = append(configs, PolicyConfig(policy))
+	}
+	return model.IndexRateLimitPolicies(configs)
+}
+
+// Service implements Lister.
+func (c *Controller) Service(namespace, name string) *corev1.Service {
+	return c.services.Get(name, namespace)
+}
+
+// Pods implements Lister.
+func (c *Controller) Pods(namespace string, selector map[string]string) []*corev1.Pod {
+	return c.pods.List(namespace, klabels.SelectorFromSet(selector))
+}
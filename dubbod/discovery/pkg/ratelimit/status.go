@@ -0,0 +1,200 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/config/schema/gvk"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
+	metav1alpha1 "github.com/kdubbo/api/meta/v1alpha1"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConditionAccepted reports whether the policy describes a token bucket
+	// that applies to at least one of its targets.
+	ConditionAccepted = "Accepted"
+	// ConditionEnforced reports whether workloads behind the targets limit
+	// their inbound requests. It is only set on accepted policies.
+	ConditionEnforced = "Enforced"
+)
+
+const (
+	reasonAccepted          = "Accepted"
+	reasonInvalid           = "Invalid"
+	reasonConflicted        = "Conflicted"
+	reasonEnforced          = "Enforced"
+	reasonPartiallyEnforced = "PartiallyEnforced"
+	reasonNoDataPlane       = "NoDataPlane"
+	reasonNoWorkloads       = "NoWorkloads"
+	reasonTargetNotFound    = "TargetNotFound"
+)
+
+// targetState is how far a single targetRef got towards being enforced.
+// Later states are closer to a working limit.
+type targetState int
+
+const (
+	targetConflicted targetState = iota
+	targetNotFound
+	targetNoWorkloads
+	targetNoDataPlane
+	targetPartiallyEnforced
+	targetEnforced
+)
+
+var targetReasons = map[targetState]string{
+	targetNotFound:    reasonTargetNotFound,
+	targetNoWorkloads: reasonNoWorkloads,
+	targetNoDataPlane: reasonNoDataPlane,
+}
+
+// Lister resolves the Services a policy targets and the pods behind them.
+type Lister interface {
+	Service(namespace, name string) *corev1.Service
+	Pods(namespace string, selector map[string]string) []*corev1.Pod
+}
+
+// PolicyConfig converts a RateLimitPolicy object into the config the push
+// context indexes, so the status sees the same winner per port as the
+// workloads do.
+func PolicyConfig(policy *clientnetworking.RateLimitPolicy) config.Config {
+	return config.Config{
+		Meta: config.Meta{
+			GroupVersionKind:  gvk.RateLimitPolicy,
+			Name:              policy.Name,
+			Namespace:         policy.Namespace,
+			CreationTimestamp: policy.CreationTimestamp.Time,
+		},
+		Spec: &policy.Spec,
+	}
+}
+
+// Conditions evaluates where a policy is enforced. index holds the policies
+// of the mesh, see model.IndexRateLimitPolicies; a port limited by an older
+// policy does not count for this one. A target is only fully enforced when
+// every pod behind it runs a Dubbo data plane, since pods without one are not
+// limited.
+func Conditions(policy *clientnetworking.RateLimitPolicy, index map[string]model.RateLimitSettings, lister Lister) []*metav1alpha1.DubboCondition {
+	accepted := &metav1alpha1.DubboCondition{
+		Type:               ConditionAccepted,
+		Status:             "True",
+		Reason:             reasonAccepted,
+		ObservedGeneration: policy.GetGeneration(),
+	}
+	if _, ok := model.RateLimitSettingsFromPolicy(PolicyConfig(policy)); !ok {
+		accepted.Status = "False"
+		accepted.Reason = reasonInvalid
+		return []*metav1alpha1.DubboCondition{accepted}
+	}
+
+	best, enforced, targets := targetConflicted, 0, 0
+	for _, target := range policy.Spec.GetTargetRefs() {
+		state := evaluateTarget(policy, target.GetName(), target.GetSectionName(), index, lister)
+		targets++
+		best = max(best, state)
+		if state == targetEnforced {
+			enforced++
+		}
+	}
+	if best == targetConflicted {
+		accepted.Status = "False"
+		accepted.Reason = reasonConflicted
+		return []*metav1alpha1.DubboCondition{accepted}
+	}
+
+	condition := &metav1alpha1.DubboCondition{
+		Type:               ConditionEnforced,
+		Status:             "True",
+		ObservedGeneration: policy.GetGeneration(),
+	}
+	switch {
+	case enforced == targets:
+		condition.Reason = reasonEnforced
+	case best >= targetPartiallyEnforced:
+		condition.Reason = reasonPartiallyEnforced
+	default:
+		condition.Status = "False"
+		condition.Reason = targetReasons[best]
+	}
+	return []*metav1alpha1.DubboCondition{accepted, condition}
+}
+
+func evaluateTarget(policy *clientnetworking.RateLimitPolicy, service, sectionName string, index map[string]model.RateLimitSettings,
+	lister Lister,
+) targetState {
+	svc := lister.Service(policy.Namespace, service)
+	if svc == nil {
+		return targetNotFound
+	}
+	ports, shadowed := 0, 0
+	for _, port := range svc.Spec.Ports {
+		if sectionName != "" && port.Name != sectionName {
+			continue
+		}
+		ports++
+		if owner, found := model.RateLimitPolicyFor(index, policy.Namespace, service, port.Name); found && owner.Name != policy.Name {
+			shadowed++
+		}
+	}
+	switch {
+	case ports == 0:
+		return targetNotFound
+	case shadowed == ports:
+		return targetConflicted
+	case len(svc.Spec.Selector) == 0:
+		// Only workloads behind a selector enforce the limit.
+		return targetNoWorkloads
+	}
+
+	pods := lister.Pods(svc.Namespace, svc.Spec.Selector)
+	enforcing := 0
+	for _, pod := range pods {
+		if hasDubboDataPlane(pod) {
+			enforcing++
+		}
+	}
+	switch {
+	case len(pods) == 0:
+		return targetNoWorkloads
+	case enforcing == 0:
+		return targetNoDataPlane
+	case enforcing < len(pods) || shadowed > 0:
+		return targetPartiallyEnforced
+	}
+	return targetEnforced
+}
+
+// hasDubboDataPlane reports whether a pod runs the inherent gRPC data plane,
+// which enforces the inbound filters and runtime config of the policy.
+func hasDubboDataPlane(pod *corev1.Pod) bool {
+	for _, template := range strings.Split(pod.Annotations[inject.InherentInjectTemplatesAnnoName], ",") {
+		if strings.TrimSpace(template) == inject.InherentGRPCTemplateName {
+			return true
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == inject.InherentXDSVolumeName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/config"
+	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
+	metav1alpha1 "github.com/kdubbo/api/meta/v1alpha1"
+	networking "github.com/kdubbo/api/networking/v1alpha3"
+	clientnetworking "github.com/kdubbo/client-go/pkg/apis/networking/v1alpha3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type fakeLister struct {
+	services []*corev1.Service
+	pods     []*corev1.Pod
+}
+
+func (l fakeLister) Service(namespace, name string) *corev1.Service {
+	for _, svc := range l.services {
+		if svc.Namespace == namespace && svc.Name == name {
+			return svc
+		}
+	}
+	return nil
+}
+
+func (l fakeLister) Pods(namespace string, selector map[string]string) []*corev1.Pod {
+	var out []*corev1.Pod
+	for _, pod := range l.pods {
+		if pod.Namespace == namespace && labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels)) {
+			out = append(out, pod)
+		}
+	}
+	return out
+}
+
+func rateLimitPolicy(name string, created time.Time, targets ...*networking.PolicyTargetReference) *clientnetworking.RateLimitPolicy {
+	return &clientnetworking.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", Generation: 2, CreationTimestamp: metav1.NewTime(created)},
+		Spec: networking.RateLimitPolicy{
+			TargetRefs:  targets,
+			TokenBucket: &networking.TokenBucket{MaxTokens: 100, FillInterval: durationpb.New(time.Second)},
+		},
+	}
+}
+
+func index(policies ...*clientnetworking.RateLimitPolicy) map[string]model.RateLimitSettings {
+	var configs []config.Config
+	for _, policy := range policies {
+		configs = append(configs, PolicyConfig(policy))
+	}
+	return model.IndexRateLimitPolicies(configs)
+}
+
+func assertConditions(t *testing.T, got []*metav1alpha1.DubboCondition, want ...string) {
+	t.Helper()
+	if len(got) != len(want)/2 {
+		t.Fatalf("conditions = %v, want %v", got, want)
+	}
+	for i, condition := range got {
+		if condition.GetStatus() != want[2*i] || condition.GetReason() != want[2*i+1] {
+			t.Fatalf("%s = %s(%s), want %s(%s)", condition.GetType(), condition.GetStatus(), condition.GetReason(), want[2*i], want[2*i+1])
+		}
+		if condition.GetObservedGeneration() != 2 {
+			t.Fatalf("%s observed generation = %d, want 2", condition.GetType(), condition.GetObservedGeneration())
+		}
+	}
+}
+
+func TestConditionsReportWherePolicyIsEnforced(t *testing.T) {
+	pod := func(name string, injected bool) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", Labels: map[string]string{"app": "orders"}}}
+		if injected {
+			p.Annotations = map[string]string{inject.InherentInjectTemplatesAnnoName: inject.InherentGRPCTemplateName}
+		}
+		return p
+	}
+	orders := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "app"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "orders"},
+			Ports:    []corev1.ServicePort{{Name: "grpc", Port: 50051}, {Name: "http", Port: 8080}},
+		},
+	}
+	now := time.Now()
+	older := rateLimitPolicy("older", now.Add(-time.Hour), &networking.PolicyTargetReference{Kind: "Service", Name: "orders", SectionName: "http"})
+	policy := rateLimitPolicy("orders", now, &networking.PolicyTargetReference{Kind: "Service", Name: "orders"})
+	idx := index(older, policy)
+
+	for name, test := range map[string]struct {
+		policy *clientnetworking.RateLimitPolicy
+		lister fakeLister
+		want   []string
+	}{
+		"every pod enforces": {
+			policy: older,
+			lister: fakeLister{services: []*corev1.Service{orders}, pods: []*corev1.Pod{pod("orders-1", true), pod("orders-2", true)}},
+			want:   []string{"True", reasonAccepted, "True", reasonEnforced},
+		},
+		"port limited by an older policy": {
+			policy: policy,
+			lister: fakeLister{services: []*corev1.Service{orders}, pods: []*corev1.Pod{pod("orders-1", true)}},
+			want:   []string{"True", reasonAccepted, "True", reasonPartiallyEnforced},
+		},
+		"pod without data plane": {
+			policy: older,
+			lister: fakeLister{services: []*corev1.Service{orders}, pods: []*corev1.Pod{pod("orders-1", true), pod("legacy", false)}},
+			want:   []string{"True", reasonAccepted, "True", reasonPartiallyEnforced},
+		},
+		"no data plane": {
+			policy: older,
+			lister: fakeLister{services: []*corev1.Service{orders}, pods: []*corev1.Pod{pod("legacy", false)}},
+			want:   []string{"True", reasonAccepted, "False", reasonNoDataPlane},
+		},
+		"no pods": {
+			policy: older,
+			lister: fakeLister{services: []*corev1.Service{orders}},
+			want:   []string{"True", reasonAccepted, "False", reasonNoWorkloads},
+		},
+		"missing service": {
+			policy: older,
+			lister: fakeLister{},
+			want:   []string{"True", reasonAccepted, "False", reasonTargetNotFound},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertConditions(t, Conditions(test.policy, idx, test.lister), test.want...)
+		})
+	}
+}
+
+func TestConditionsRejectConflictedPolicy(t *testing.T) {
+	orders := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "app"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "grpc", Port: 50051}}},
+	}
+	now := time.Now()
+	older := rateLimitPolicy("older", now.Add(-time.Hour), &networking.PolicyTargetReference{Kind: "Service", Name: "orders"})
+	newer := rateLimitPolicy("newer", now, &networking.PolicyTargetReference{Kind: "Service", Name: "orders"})
+
+	conditions := Conditions(newer, index(older, newer), fakeLister{services: []*corev1.Service{orders}})
+	assertConditions(t, conditions, "False", reasonConflicted)
+}
+
+func TestConditionsRejectInvalidPolicy(t *testing.T) {
+	policy := rateLimitPolicy("orders", time.Now(), &networking.PolicyTargetReference{Kind: "Service", Name: "orders"})
+	policy.Spec.TokenBucket.MaxTokens = 0
+	assertConditions(t, Conditions(policy, nil, fakeLister{}), "False", reasonInvalid)
+}
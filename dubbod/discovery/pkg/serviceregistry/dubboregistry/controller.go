@@ -0,0 +1,269 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dubboregistry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/provider"
+	"github.com/apache/dubbo-kubernetes/pkg/cluster"
+	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
+	"github.com/apache/dubbo-kubernetes/pkg/config/host"
+	"github.com/apache/dubbo-kubernetes/pkg/config/labels"
+	dubbolog "github.com/apache/dubbo-kubernetes/pkg/log"
+	"github.com/apache/dubbo-kubernetes/pkg/slices"
+	"go.uber.org/atomic"
+)
+
+var log = dubbolog.RegisterScope("dubboregistry", "Dubbo registry adapters")
+
+// DefaultDomainSuffix is appended to the lower-cased interface name to form
+// the mesh hostname of a legacy Dubbo service.
+const DefaultDomainSuffix = "dubbo"
+
+// Source watches one external Dubbo registry. Run blocks until stop closes
+// and reports the complete provider set after every change.
+type Source interface {
+	Run(stop <-chan struct{}, handler Handler)
+}
+
+// Handler receives what a Source observes.
+type Handler interface {
+	// Update replaces the provider set.
+	Update(urls []*ProviderURL)
+	// ListFailed reports a registry that could not be read. The previous
+	// provider set stays in place.
+	ListFailed(err error)
+}
+
+type Options struct {
+	Provider  provider.ID
+	ClusterID cluster.ID
+	// Namespace is the mesh namespace the converted services live in.
+	Namespace    string
+	DomainSuffix string
+	XDSUpdater   model.XDSUpdater
+}
+
+// Controller turns the providers of an external Dubbo registry into mesh
+// services, one per interface, so mesh clients can reach applications that
+// have not been migrated to Kubernetes yet.
+type Controller struct {
+	opts   Options
+	source Source
+
+	mu        sync.RWMutex
+	services  map[host.Name]*model.Service
+	endpoints map[host.Name][]*model.DubboEndpoint
+	synced    atomic.Bool
+}
+
+func NewController(opts Options, source Source) *Controller {
+	if opts.DomainSuffix == "" {
+		opts.DomainSuffix = DefaultDomainSuffix
+	}
+	return &Controller{
+		opts:      opts,
+		source:    source,
+		services:  map[host.Name]*model.Service{},
+		endpoints: map[host.Name][]*model.DubboEndpoint{},
+	}
+}
+
+// ServiceHostname returns the mesh hostname of a Dubbo interface.
+func ServiceHostname(iface, domainSuffix string) host.Name {
+	return host.Name(strings.ToLower(iface) + "." + domainSuffix)
+}
+
+// Update replaces the provider set and pushes the difference to xDS.
+func (c *Controller) Update(urls []*ProviderURL) {
+	byHost := map[host.Name][]*ProviderURL{}
+	for _, u := range urls {
+		if u == nil || u.Disabled {
+			continue
+		}
+		hostname := ServiceHostname(u.Interface, c.opts.DomainSuffix)
+		byHost[hostname] = append(byHost[hostname], u)
+	}
+
+	c.mu.Lock()
+	previous := c.services
+	previousEndpoints := c.endpoints
+	services := make(map[host.Name]*model.Service, len(byHost))
+	endpoints := make(map[host.Name][]*model.DubboEndpoint, len(byHost))
+	for hostname, providers := range byHost {
+		svc := c.convertService(hostname, providers)
+		if old := previous[hostname]; old != nil {
+			svc.CreationTime = old.CreationTime
+		}
+		services[hostname] = svc
+		endpoints[hostname] = c.convertEndpoints(providers)
+	}
+	c.services = services
+	c.endpoints = endpoints
+	c.mu.Unlock()
+	c.synced.Store(true)
+
+	if c.opts.XDSUpdater == nil {
+		return
+	}
+	shard := model.ShardKeyFromRegistry(c)
+	namespace := c.opts.Namespace
+	for hostname, svc := range services {
+		oldService := previous[hostname]
+		switch {
+		case oldService == nil:
+			c.opts.XDSUpdater.ServiceUpdate(shard, string(hostname), namespace, model.EventAdd)
+		case !oldService.Equals(svc):
+			c.opts.XDSUpdater.ServiceUpdate(shard, string(hostname), namespace, model.EventUpdate)
+		}
+		if oldService == nil || !slices.EqualFunc(previousEndpoints[hostname], endpoints[hostname], func(a, b *model.DubboEndpoint) bool {
+			return a.Equals(b)
+		}) {
+			c.opts.XDSUpdater.EDSUpdate(shard, string(hostname), namespace, endpoints[hostname])
+		}
+	}
+	for hostname := range previous {
+		if _, found := services[hostname]; found {
+			continue
+		}
+		c.opts.XDSUpdater.ServiceUpdate(shard, string(hostname), namespace, model.EventDelete)
+		c.opts.XDSUpdater.EDSUpdate(shard, string(hostname), namespace, nil)
+	}
+}
+
+func (c *Controller) convertService(hostname host.Name, providers []*ProviderURL) *model.Service {
+	ports := model.PortList{}
+	seen := map[string]struct{}{}
+	interfaces := make([]model.DubboInterface, 0, len(providers))
+	for _, u := range providers {
+		interfaces = append(interfaces, model.DubboInterface{Name: u.Interface, Group: u.Group, Version: u.Version})
+		name := portName(u)
+		if _, found := seen[name]; found {
+			continue
+		}
+		seen[name] = struct{}{}
+		ports = append(ports, &model.Port{Name: name, Port: int(u.Port), Protocol: PortProtocol(u.Protocol)})
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Name < ports[j].Name
+	})
+	svc := &model.Service{
+		Hostname: hostname,
+		Ports:    ports,
+		ClusterVIPs: model.AddressMap{Addresses: map[cluster.ID][]string{
+			c.opts.ClusterID: {constants.UnspecifiedIP},
+		}},
+		CreationTime:   time.Now(),
+		DefaultAddress: constants.UnspecifiedIP,
+		Resolution:     model.ClientSideLB,
+		Attributes: model.ServiceAttributes{
+			Name:            strings.TrimSuffix(string(hostname), "."+c.opts.DomainSuffix),
+			Namespace:       c.opts.Namespace,
+			ServiceRegistry: c.opts.Provider,
+			Labels:          map[string]string{InterfaceLabel: providers[0].Interface},
+			DubboInterfaces: model.SortDubboInterfaces(interfaces),
+		},
+	}
+	return svc
+}
+
+func (c *Controller) convertEndpoints(providers []*ProviderURL) []*model.DubboEndpoint {
+	out := make([]*model.DubboEndpoint, 0, len(providers))
+	for _, u := range providers {
+		epLabels := labels.Instance{InterfaceLabel: u.Interface}
+		if u.Version != "" {
+			epLabels[VersionLabel] = u.Version
+		}
+		if u.Group != "" {
+			epLabels[GroupLabel] = u.Group
+		}
+		workload := u.Application
+		if u.Application != "" {
+			epLabels[ApplicationLabel] = u.Application
+		} else {
+			workload = u.Address
+		}
+		out = append(out, &model.DubboEndpoint{
+			Addresses:       []string{u.Address},
+			ServicePortName: portName(u),
+			Labels:          epLabels,
+			LbWeight:        u.Weight,
+			HealthStatus:    model.Healthy,
+			EndpointPort:    u.Port,
+			WorkloadName:    workload,
+			Namespace:       c.opts.Namespace,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].FirstAddressOrNil() != out[j].FirstAddressOrNil() {
+			return out[i].FirstAddressOrNil() < out[j].FirstAddressOrNil()
+		}
+		return out[i].EndpointPort < out[j].EndpointPort
+	})
+	return out
+}
+
+func portName(u *ProviderURL) string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(u.Protocol), u.Port)
+}
+
+func (c *Controller) Services() []*model.Service {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]*model.Service, 0, len(c.services))
+	for _, svc := range c.services {
+		out = append(out, svc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Hostname < out[j].Hostname })
+	return out
+}
+
+// ListFailed keeps the last known providers. A failed list does not count as
+// synced: until one succeeds the controller knows nothing about the registry,
+// and reporting that as synced would push an empty provider set as if it were
+// the registry's contents.
+func (c *Controller) ListFailed(err error) {
+	log.Warnf("%s registry list failed, keeping %d known services: %v", c.opts.Provider, len(c.Services()), err)
+}
+
+func (c *Controller) GetService(hostname host.Name) *model.Service {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services[hostname]
+}
+
+// GetProxyServiceTargets returns nothing: providers in an external registry
+// are not mesh proxies.
+func (c *Controller) GetProxyServiceTargets(*model.Proxy) []model.ServiceTarget { return nil }
+
+func (c *Controller) Provider() provider.ID { return c.opts.Provider }
+func (c *Controller) Cluster() cluster.ID   { return c.opts.ClusterID }
+func (c *Controller) HasSynced() bool       { return c.synced.Load() }
+
+func (c *Controller) Run(stop <-chan struct{}) {
+	log.Infof("starting %s registry adapter", c.opts.Provider)
+	c.source.Run(stop, c)
+}
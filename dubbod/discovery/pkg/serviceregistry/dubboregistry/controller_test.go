@@ -0,0 +1,170 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dubboregistry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/provider"
+	"github.com/apache/dubbo-kubernetes/pkg/cluster"
+	"github.com/apache/dubbo-kubernetes/pkg/config/protocol"
+)
+
+func TestParseProviderURL(t *testing.T) {
+	u, err := ParseProviderURL("tri://10.0.0.1:50051/org.example.Greeter?application=greeter&group=gray&side=provider&version=1.0.0&weight=100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProviderURL{
+		Protocol: "tri", Address: "10.0.0.1", Port: 50051, Interface: "org.example.Greeter",
+		Version: "1.0.0", Group: "gray", Application: "greeter", Weight: 100,
+	}
+	if *u != want {
+		t.Fatalf("got %+v, want %+v", *u, want)
+	}
+
+	u, err = ParseProviderURL("dubbo://10.0.0.2:20880/com.foo.Bar?interface=org.example.Greeter&disabled=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Interface != "org.example.Greeter" || !u.Disabled || u.Weight != 1 {
+		t.Fatalf("unexpected url: %+v", *u)
+	}
+
+	for _, raw := range []string{
+		"tri://10.0.0.1/org.example.Greeter",
+		"tri://10.0.0.1:0/org.example.Greeter",
+		"tri://10.0.0.1:50051/",
+		"consumer://10.0.0.1:50051/org.example.Greeter?side=consumer",
+	} {
+		if _, err := ParseProviderURL(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestPortProtocol(t *testing.T) {
+	cases := map[string]protocol.Instance{
+		"tri":   protocol.GRPC,
+		"grpc":  protocol.GRPC,
+		"rest":  protocol.HTTP,
+		"dubbo": protocol.TCP,
+	}
+	for name, want := range cases {
+		if got := PortProtocol(name); got != want {
+			t.Errorf("PortProtocol(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestControllerUpdate(t *testing.T) {
+	updater := &fakeXDSUpdater{}
+	c := NewController(Options{
+		Provider:   provider.Zookeeper,
+		ClusterID:  cluster.ID("legacy"),
+		Namespace:  "dubbo-legacy",
+		XDSUpdater: updater,
+	}, nil)
+	if c.HasSynced() {
+		t.Fatal("controller synced before the first list")
+	}
+	c.ListFailed(errors.New("connection refused"))
+	if c.HasSynced() {
+		t.Fatal("controller synced after a failed list")
+	}
+
+	c.Update([]*ProviderURL{
+		{Protocol: "tri", Address: "10.0.0.2", Port: 50051, Interface: "org.example.Greeter", Version: "2.0.0", Weight: 1},
+		{Protocol: "tri", Address: "10.0.0.1", Port: 50051, Interface: "org.example.Greeter", Version: "1.0.0", Group: "gray", Application: "greeter", Weight: 100},
+		{Protocol: "dubbo", Address: "10.0.0.3", Port: 20880, Interface: "org.example.Stock", Disabled: true},
+	})
+	if !c.HasSynced() {
+		t.Fatal("controller not synced after update")
+	}
+	if got := len(c.Services()); got != 1 {
+		t.Fatalf("expected only the enabled interface, got %d services", got)
+	}
+	svc := c.GetService("org.example.greeter.dubbo")
+	if svc == nil {
+		t.Fatal("greeter service was not created")
+	}
+	if svc.Attributes.Namespace != "dubbo-legacy" || svc.Attributes.ServiceRegistry != provider.Zookeeper {
+		t.Fatalf("unexpected service attributes: %+v", svc.Attributes)
+	}
+	if len(svc.Ports) != 1 || svc.Ports[0].Name != "tri-50051" || svc.Ports[0].Protocol != protocol.GRPC {
+		t.Fatalf("unexpected ports: %v", svc.Ports)
+	}
+	if got := svc.Attributes.DubboInterfaces; len(got) != 2 || got[0].ServiceKey() != "gray/org.example.Greeter:1.0.0" {
+		t.Fatalf("unexpected dubbo interfaces: %+v", got)
+	}
+	if len(updater.serviceEvents) != 1 || updater.serviceEvents[0] != model.EventAdd {
+		t.Fatalf("unexpected service events: %v", updater.serviceEvents)
+	}
+	eds := updater.lastEDS(t)
+	if len(eds) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(eds))
+	}
+	first := eds[0]
+	if first.FirstAddressOrNil() != "10.0.0.1" || first.LbWeight != 100 || first.WorkloadName != "greeter" {
+		t.Fatalf("unexpected endpoint: %+v", first)
+	}
+	if first.Labels[VersionLabel] != "1.0.0" || first.Labels[GroupLabel] != "gray" || first.Labels[InterfaceLabel] != "org.example.Greeter" {
+		t.Fatalf("unexpected endpoint labels: %v", first.Labels)
+	}
+
+	// The same set again must not push anything.
+	c.Update([]*ProviderURL{
+		{Protocol: "tri", Address: "10.0.0.1", Port: 50051, Interface: "org.example.Greeter", Version: "1.0.0", Group: "gray", Application: "greeter", Weight: 100},
+		{Protocol: "tri", Address: "10.0.0.2", Port: 50051, Interface: "org.example.Greeter", Version: "2.0.0", Weight: 1},
+	})
+	if len(updater.serviceEvents) != 1 || len(updater.edsEvents) != 1 {
+		t.Fatalf("unchanged providers pushed: %d service, %d eds events", len(updater.serviceEvents), len(updater.edsEvents))
+	}
+
+	c.Update(nil)
+	if c.GetService("org.example.greeter.dubbo") != nil {
+		t.Fatal("service not removed")
+	}
+	if updater.serviceEvents[len(updater.serviceEvents)-1] != model.EventDelete || len(updater.lastEDS(t)) != 0 {
+		t.Fatal("removal was not pushed")
+	}
+}
+
+type fakeXDSUpdater struct {
+	serviceEvents []model.Event
+	edsEvents     [][]*model.DubboEndpoint
+}
+
+func (*fakeXDSUpdater) ConfigUpdate(*model.PushRequest) {}
+func (f *fakeXDSUpdater) ServiceUpdate(_ model.ShardKey, _, _ string, event model.Event) {
+	f.serviceEvents = append(f.serviceEvents, event)
+}
+func (f *fakeXDSUpdater) EDSUpdate(_ model.ShardKey, _, _ string, endpoints []*model.DubboEndpoint) {
+	f.edsEvents = append(f.edsEvents, endpoints)
+}
+func (*fakeXDSUpdater) EDSCacheUpdate(model.ShardKey, string, string, []*model.DubboEndpoint) {}
+func (*fakeXDSUpdater) ProxyUpdate(cluster.ID, string)                                        {}
+
+func (f *fakeXDSUpdater) lastEDS(t *testing.T) []*model.DubboEndpoint {
+	t.Helper()
+	if len(f.edsEvents) == 0 {
+		t.Fatal("no EDS update received")
+	}
+	return f.edsEvents[len(f.edsEvents)-1]
+}
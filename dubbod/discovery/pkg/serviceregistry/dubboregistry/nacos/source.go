@@ -0,0 +1,298 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/dubboregistry"
+	"github.com/apache/dubbo-kubernetes/pkg/backoff"
+	dubbolog "github.com/apache/dubbo-kubernetes/pkg/log"
+)
+
+var log = dubbolog.RegisterScope("nacos", "Nacos Dubbo registry adapter")
+
+const (
+	// providerPrefix marks the interface-level services Dubbo registers, named
+	// providers:<interface>:<version>:<group>.
+	providerPrefix = "providers:"
+	pageSize       = 500
+	// maxConcurrentLookups bounds the instance lists fetched at once during
+	// a poll.
+	maxConcurrentLookups = 8
+)
+
+type Options struct {
+	// Address is the Nacos server base URL, e.g. http://nacos:8848.
+	Address     string
+	NamespaceID string
+	// GroupName defaults to DEFAULT_GROUP.
+	GroupName    string
+	AccessToken  string
+	PollInterval time.Duration
+	// MaxRetryInterval caps the backoff after failed polls, which starts at
+	// PollInterval and doubles with every further failure.
+	MaxRetryInterval time.Duration
+	HTTPClient       *http.Client
+}
+
+// Source polls the Nacos naming open API for Dubbo providers. Only healthy,
+// enabled instances are returned. Failed polls back off so an unreachable
+// server is not hammered every PollInterval.
+type Source struct {
+	opts Options
+
+	mu sync.Mutex
+	// known holds the last instances listed for each service, served again
+	// when listing that service alone fails.
+	known map[string][]instance
+}
+
+var _ dubboregistry.Source = &Source{}
+
+func NewSource(opts Options) *Source {
+	opts.Address = strings.TrimRight(opts.Address, "/")
+	if opts.GroupName == "" {
+		opts.GroupName = "DEFAULT_GROUP"
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 10 * time.Second
+	}
+	if opts.MaxRetryInterval < opts.PollInterval {
+		opts.MaxRetryInterval = max(opts.PollInterval, 2*time.Minute)
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Source{opts: opts, known: map[string][]instance{}}
+}
+
+func (s *Source) Run(stop <-chan struct{}, handler dubboregistry.Handler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	retry := backoff.NewExponentialBackOff(backoff.Option{
+		InitialInterval: s.opts.PollInterval,
+		MaxInterval:     s.opts.MaxRetryInterval,
+	})
+	for {
+		urls, err := s.List(ctx)
+		wait := s.opts.PollInterval
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			handler.ListFailed(err)
+			wait = retry.NextBackOff()
+		} else {
+			retry.Reset()
+			handler.Update(urls)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// List returns every provider currently registered in Nacos. Only a failure
+// to list the services fails the poll; a service whose instances cannot be
+// listed keeps the instances it had on the previous poll, or is left out if
+// it has none yet, so one broken service does not hide all the others.
+func (s *Source) List(ctx context.Context) ([]*dubboregistry.ProviderURL, error) {
+	services, err := s.listServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instances := make([][]instance, len(services))
+	failed := make([]error, len(services))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+	for i, service := range services {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			instances[i], failed[i] = s.listInstances(ctx, service)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	known := make(map[string][]instance, len(services))
+	for i, service := range services {
+		if failed[i] != nil {
+			last, ok := s.known[service]
+			if ok {
+				log.Warnf("listing instances of %s failed, keeping the last known ones: %v", service, failed[i])
+				known[service] = last
+			} else {
+				log.Warnf("listing instances of %s failed, skipping it: %v", service, failed[i])
+			}
+			continue
+		}
+		known[service] = instances[i]
+	}
+	s.known = known
+	s.mu.Unlock()
+
+	var out []*dubboregistry.ProviderURL
+	for _, service := range services {
+		for _, instance := range known[service] {
+			if provider := instance.providerURL(service); provider != nil {
+				out = append(out, provider)
+			}
+		}
+	}
+	return out, nil
+}
+
+type serviceList struct {
+	Count int      `json:"count"`
+	Doms  []string `json:"doms"`
+}
+
+func (s *Source) listServices(ctx context.Context) ([]string, error) {
+	var services []string
+	for page := 1; ; page++ {
+		var resp serviceList
+		if err := s.get(ctx, "/nacos/v1/ns/service/list", url.Values{
+			"pageNo":   {strconv.Itoa(page)},
+			"pageSize": {strconv.Itoa(pageSize)},
+		}, &resp); err != nil {
+			return nil, err
+		}
+		for _, name := range resp.Doms {
+			if strings.HasPrefix(name, providerPrefix) {
+				services = append(services, name)
+			}
+		}
+		if len(resp.Doms) < pageSize || page*pageSize >= resp.Count {
+			break
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+type instanceList struct {
+	Hosts []instance `json:"hosts"`
+}
+
+type instance struct {
+	IP       string            `json:"ip"`
+	Port     uint32            `json:"port"`
+	Weight   float64           `json:"weight"`
+	Healthy  bool              `json:"healthy"`
+	Enabled  bool              `json:"enabled"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (s *Source) listInstances(ctx context.Context, service string) ([]instance, error) {
+	var resp instanceList
+	if err := s.get(ctx, "/nacos/v1/ns/instance/list", url.Values{
+		"serviceName": {service},
+		"healthyOnly": {"true"},
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Hosts, nil
+}
+
+func (s *Source) get(ctx context.Context, path string, query url.Values, out any) error {
+	if s.opts.NamespaceID != "" {
+		query.Set("namespaceId", s.opts.NamespaceID)
+	}
+	query.Set("groupName", s.opts.GroupName)
+	if s.opts.AccessToken != "" {
+		query.Set("accessToken", s.opts.AccessToken)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.opts.Address+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nacos %s returned %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// providerURL converts an instance of providers:<interface>:<version>:<group>.
+// Instance metadata written by Dubbo wins over the service name.
+func (i instance) providerURL(service string) *dubboregistry.ProviderURL {
+	if !i.Healthy || !i.Enabled || i.IP == "" || i.Port == 0 {
+		return nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(service, providerPrefix), ":", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	provider := &dubboregistry.ProviderURL{
+		Protocol:    metadataOr(i.Metadata, "protocol", "dubbo"),
+		Address:     i.IP,
+		Port:        i.Port,
+		Interface:   metadataOr(i.Metadata, "interface", parts[0]),
+		Version:     metadataOr(i.Metadata, "version", parts[1]),
+		Group:       metadataOr(i.Metadata, "group", parts[2]),
+		Application: i.Metadata["application"],
+		Weight:      1,
+	}
+	if provider.Interface == "" {
+		return nil
+	}
+	if weight, err := strconv.ParseUint(i.Metadata["weight"], 10, 32); err == nil && weight > 0 {
+		provider.Weight = uint32(weight)
+	} else if i.Weight >= 1 {
+		provider.Weight = uint32(math.Round(i.Weight))
+	}
+	return provider
+}
+
+func metadataOr(metadata map[string]string, key, fallback string) string {
+	if value := metadata[key]; value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,144 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSourceListsProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("namespaceId") != "legacy" || query.Get("groupName") != "DEFAULT_GROUP" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/nacos/v1/ns/service/list":
+			_ = json.NewEncoder(w).Encode(serviceList{Count: 3, Doms: []string{
+				"providers:org.example.Greeter:1.0.0:gray",
+				"consumers:org.example.Greeter::",
+				"greeter-app",
+			}})
+		case "/nacos/v1/ns/instance/list":
+			if query.Get("serviceName") != "providers:org.example.Greeter:1.0.0:gray" {
+				t.Errorf("unexpected service %q", query.Get("serviceName"))
+			}
+			_ = json.NewEncoder(w).Encode(instanceList{Hosts: []instance{
+				{IP: "10.0.0.1", Port: 50051, Weight: 1, Healthy: true, Enabled: true, Metadata: map[string]string{
+					"protocol": "tri", "application": "greeter", "weight": "100",
+				}},
+				{IP: "10.0.0.2", Port: 50051, Weight: 3, Healthy: true, Enabled: true},
+				{IP: "10.0.0.3", Port: 50051, Weight: 1, Healthy: true, Enabled: false},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	urls, err := NewSource(Options{Address: server.URL + "/", NamespaceID: "legacy"}).List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(urls))
+	}
+	first := urls[0]
+	if first.Interface != "org.example.Greeter" || first.Version != "1.0.0" || first.Group != "gray" {
+		t.Fatalf("service name not applied: %+v", *first)
+	}
+	if first.Protocol != "tri" || first.Application != "greeter" || first.Weight != 100 {
+		t.Fatalf("metadata not applied: %+v", *first)
+	}
+	if second := urls[1]; second.Protocol != "dubbo" || second.Weight != 3 {
+		t.Fatalf("unexpected defaults: %+v", *second)
+	}
+}
+
+func TestSourceReportsServerErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if _, err := NewSource(Options{Address: server.URL}).List(context.Background()); err == nil {
+		t.Fatal("expected an error from a failing server")
+	}
+}
+
+func TestSourceIsolatesFailingServices(t *testing.T) {
+	var stockDown atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v1/ns/service/list":
+			_ = json.NewEncoder(w).Encode(serviceList{Count: 3, Doms: []string{
+				"providers:org.example.Broken::",
+				"providers:org.example.Greeter::",
+				"providers:org.example.Stock::",
+			}})
+		case "/nacos/v1/ns/instance/list":
+			switch r.URL.Query().Get("serviceName") {
+			case "providers:org.example.Broken::":
+				http.Error(w, "boom", http.StatusInternalServerError)
+			case "providers:org.example.Stock::":
+				if stockDown.Load() {
+					http.Error(w, "boom", http.StatusInternalServerError)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(instanceList{Hosts: []instance{
+					{IP: "10.0.0.2", Port: 50051, Healthy: true, Enabled: true},
+				}})
+			default:
+				_ = json.NewEncoder(w).Encode(instanceList{Hosts: []instance{
+					{IP: "10.0.0.1", Port: 50051, Healthy: true, Enabled: true},
+				}})
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	source := NewSource(Options{Address: server.URL})
+
+	interfaces := func() []string {
+		t.Helper()
+		urls, err := source.List(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, u := range urls {
+			out = append(out, u.Interface)
+		}
+		return out
+	}
+
+	// A service that never listed is left out, the rest are published.
+	if got := interfaces(); len(got) != 2 || got[0] != "org.example.Greeter" || got[1] != "org.example.Stock" {
+		t.Fatalf("first poll = %v", got)
+	}
+	// A service that listed before keeps its last known instances.
+	stockDown.Store(true)
+	if got := interfaces(); len(got) != 2 || got[1] != "org.example.Stock" {
+		t.Fatalf("poll with Stock failing = %v", got)
+	}
+}
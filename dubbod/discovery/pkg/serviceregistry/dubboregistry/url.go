@@ -0,0 +1,108 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dubboregistry
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	"github.com/apache/dubbo-kubernetes/pkg/config/protocol"
+)
+
+// Labels carried by endpoints converted from a Dubbo registry, so routing and
+// authorization can tell providers of the same interface apart.
+const (
+	InterfaceLabel   = model.DubboInterfaceLabel
+	VersionLabel     = model.DubboVersionLabel
+	GroupLabel       = model.DubboGroupLabel
+	ApplicationLabel = "app"
+)
+
+// ProviderURL is one provider registration, e.g.
+// tri://10.0.0.1:50051/org.example.Greeter?version=1.0.0&group=gray&weight=100.
+type ProviderURL struct {
+	Protocol    string
+	Address     string
+	Port        uint32
+	Interface   string
+	Version     string
+	Group       string
+	Application string
+	Weight      uint32
+	// Disabled is set by dubbo-admin when a provider is taken out of service.
+	Disabled bool
+}
+
+// ParseProviderURL parses a provider URL as registered by Dubbo. ZooKeeper
+// node names carry the URL escaped once more; callers unescape it first.
+func ParseProviderURL(raw string) (*ProviderURL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider url %q: %v", raw, err)
+	}
+	host, portText, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("provider url %q has no host:port", raw)
+	}
+	port, err := strconv.ParseUint(portText, 10, 32)
+	if err != nil || port == 0 || port > 65535 {
+		return nil, fmt.Errorf("provider url %q has invalid port %q", raw, portText)
+	}
+	query := u.Query()
+	iface := query.Get("interface")
+	if iface == "" {
+		iface = strings.TrimPrefix(u.Path, "/")
+	}
+	if iface == "" {
+		return nil, fmt.Errorf("provider url %q has no interface", raw)
+	}
+	if side := query.Get("side"); side != "" && side != "provider" {
+		return nil, fmt.Errorf("url %q is not a provider registration", raw)
+	}
+	out := &ProviderURL{
+		Protocol:    u.Scheme,
+		Address:     host,
+		Port:        uint32(port),
+		Interface:   iface,
+		Version:     query.Get("version"),
+		Group:       query.Get("group"),
+		Application: query.Get("application"),
+		Weight:      1,
+		Disabled:    query.Get("disabled") == "true" || query.Get("enabled") == "false",
+	}
+	if weight, err := strconv.ParseUint(query.Get("weight"), 10, 32); err == nil && weight > 0 {
+		out.Weight = uint32(weight)
+	}
+	return out, nil
+}
+
+// PortProtocol maps a Dubbo protocol name to the mesh protocol of its port.
+// Triple and gRPC are HTTP/2 gRPC on the wire; the Dubbo2 protocol is opaque.
+func PortProtocol(name string) protocol.Instance {
+	switch strings.ToLower(name) {
+	case "tri", "triple", "grpc":
+		return protocol.GRPC
+	case "rest", "http":
+		return protocol.HTTP
+	default:
+		return protocol.TCP
+	}
+}
@@ -0,0 +1,482 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeper
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// The registry adapter only ever lists children with a watch, so this is a
+// deliberately small client for that part of the ZooKeeper protocol: session
+// handshake, getChildren, watch notifications and pings.
+
+var (
+	// ErrNoNode is returned for a path that does not exist.
+	ErrNoNode = errors.New("zookeeper: node does not exist")
+	// ErrConnectionClosed is returned for requests cut off by a lost session.
+	ErrConnectionClosed = errors.New("zookeeper: connection closed")
+)
+
+const (
+	opGetChildren = 8
+	opPing        = 11
+	opClose       = -11
+
+	xidWatchEvent = -1
+	xidPing       = -2
+
+	errCodeNoNode = -101
+
+	maxFrameSize = 16 << 20
+
+	// DefaultSessionTimeout is requested when NewConn is given none.
+	DefaultSessionTimeout = 30 * time.Second
+)
+
+// Client is the part of ZooKeeper the Source needs.
+type Client interface {
+	// ChildrenW lists the children of path. The returned channel is closed
+	// once the children change or the session is lost.
+	ChildrenW(path string) ([]string, <-chan struct{}, error)
+	Close()
+}
+
+// Conn is a Client that dials one of servers on first use and again after a
+// lost session.
+type Conn struct {
+	servers        []string
+	sessionTimeout time.Duration
+
+	mu      sync.Mutex
+	session *session
+}
+
+var _ Client = &Conn{}
+
+func NewConn(servers []string, sessionTimeout time.Duration) *Conn {
+	if sessionTimeout <= 0 {
+		sessionTimeout = DefaultSessionTimeout
+	}
+	return &Conn{servers: servers, sessionTimeout: sessionTimeout}
+}
+
+func (c *Conn) ChildrenW(path string) ([]string, <-chan struct{}, error) {
+	s, err := c.current()
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.childrenW(path)
+}
+
+func (c *Conn) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session != nil {
+		c.session.close()
+		c.session = nil
+	}
+}
+
+func (c *Conn) current() (*session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session != nil && !c.session.isDead() {
+		return c.session, nil
+	}
+	var lastErr error
+	for _, i := range rand.Perm(len(c.servers)) {
+		s, err := dialSession(c.servers[i], c.sessionTimeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		c.session = s
+		return s, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("zookeeper: no servers configured")
+	}
+	return nil, lastErr
+}
+
+type response struct {
+	err  int32
+	body []byte
+}
+
+type session struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	xid     int32
+	pending map[int32]chan response
+	watches map[string][]chan struct{}
+	dead    chan struct{}
+}
+
+func dialSession(server string, timeout time.Duration) (*session, error) {
+	conn, err := net.DialTimeout("tcp", server, timeout)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{
+		conn:    conn,
+		pending: map[int32]chan response{},
+		watches: map[string][]chan struct{}{},
+		dead:    make(chan struct{}),
+	}
+	reader := bufio.NewReader(conn)
+	negotiated, err := s.handshake(reader, timeout)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	// Pings go out every third of the negotiated timeout and the server
+	// answers each one, so a read that waits two thirds of it means the
+	// server or the path to it is gone even if TCP never notices.
+	go s.readLoop(reader, negotiated*2/3)
+	go s.pingLoop(negotiated / 3)
+	return s, nil
+}
+
+// handshake opens a new session and returns the timeout the server
+// negotiated for it.
+func (s *session) handshake(reader *bufio.Reader, timeout time.Duration) (time.Duration, error) {
+	var req encoder
+	req.int32(0) // protocol version
+	req.int64(0) // last zxid seen
+	req.int32(int32(timeout / time.Millisecond))
+	req.int64(0) // new session
+	req.buffer(make([]byte, 16))
+	_ = s.conn.SetDeadline(time.Now().Add(timeout))
+	if err := writeFrame(s.conn, req.bytes()); err != nil {
+		return 0, err
+	}
+	frame, err := readFrame(reader)
+	if err != nil {
+		return 0, err
+	}
+	_ = s.conn.SetDeadline(time.Time{})
+	d := decoder{buf: frame}
+	d.int32() // protocol version
+	negotiated := d.int32()
+	if d.err != nil {
+		return 0, d.err
+	}
+	if negotiated <= 0 {
+		return 0, fmt.Errorf("zookeeper: session rejected by server")
+	}
+	return time.Duration(negotiated) * time.Millisecond, nil
+}
+
+func (s *session) childrenW(path string) ([]string, <-chan struct{}, error) {
+	// Register the watch before sending so a change that races the reply
+	// still closes it.
+	watch := make(chan struct{})
+	s.mu.Lock()
+	s.watches[path] = append(s.watches[path], watch)
+	s.mu.Unlock()
+
+	var body encoder
+	body.string(path)
+	body.bool(true)
+	resp, err := s.call(opGetChildren, body.bytes())
+	if err == nil && resp.err != 0 {
+		err = codeError(resp.err)
+	}
+	if err != nil {
+		s.dropWatch(path, watch)
+		return nil, nil, err
+	}
+	d := decoder{buf: resp.body}
+	children := d.strings()
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return children, watch, nil
+}
+
+func (s *session) call(op int32, body []byte) (response, error) {
+	s.mu.Lock()
+	s.xid++
+	xid := s.xid
+	ch := make(chan response, 1)
+	s.pending[xid] = ch
+	s.mu.Unlock()
+
+	var req encoder
+	req.int32(xid)
+	req.int32(op)
+	req.raw(body)
+	if err := s.write(req.bytes()); err != nil {
+		s.fail()
+		return response{}, err
+	}
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-s.dead:
+		return response{}, ErrConnectionClosed
+	}
+}
+
+func (s *session) write(frame []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return writeFrame(s.conn, frame)
+}
+
+func (s *session) readLoop(reader *bufio.Reader, readTimeout time.Duration) {
+	defer s.fail()
+	for {
+		_ = s.conn.SetReadDeadline(time.Now().Add(readTimeout))
+		frame, err := readFrame(reader)
+		if err != nil {
+			return
+		}
+		d := decoder{buf: frame}
+		xid := d.int32()
+		d.int64() // zxid
+		code := d.int32()
+		if d.err != nil {
+			return
+		}
+		switch xid {
+		case xidPing:
+			continue
+		case xidWatchEvent:
+			d.int32() // event type
+			d.int32() // keeper state
+			s.fire(d.string())
+			continue
+		}
+		s.mu.Lock()
+		ch := s.pending[xid]
+		delete(s.pending, xid)
+		s.mu.Unlock()
+		if ch != nil {
+			ch <- response{err: code, body: d.rest()}
+		}
+	}
+}
+
+func (s *session) pingLoop(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.dead:
+			return
+		case <-ticker.C:
+			var req encoder
+			req.int32(xidPing)
+			req.int32(opPing)
+			if err := s.write(req.bytes()); err != nil {
+				s.fail()
+				return
+			}
+		}
+	}
+}
+
+// fire closes every child watch on path. ZooKeeper watches are one-shot, so
+// callers list again to re-arm them.
+func (s *session) fire(path string) {
+	s.mu.Lock()
+	watches := s.watches[path]
+	delete(s.watches, path)
+	s.mu.Unlock()
+	for _, watch := range watches {
+		close(watch)
+	}
+}
+
+func (s *session) dropWatch(path string, watch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watches := s.watches[path]
+	for i, w := range watches {
+		if w == watch {
+			s.watches[path] = append(watches[:i], watches[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *session) isDead() bool {
+	select {
+	case <-s.dead:
+		return true
+	default:
+		return false
+	}
+}
+
+// fail tears the session down once. Every outstanding watch fires so callers
+// relist and pick up a fresh session.
+func (s *session) fail() {
+	s.mu.Lock()
+	if s.isDead() {
+		s.mu.Unlock()
+		return
+	}
+	close(s.dead)
+	watches := s.watches
+	s.watches = map[string][]chan struct{}{}
+	s.mu.Unlock()
+	_ = s.conn.Close()
+	for _, list := range watches {
+		for _, watch := range list {
+			close(watch)
+		}
+	}
+}
+
+func (s *session) close() {
+	if !s.isDead() {
+		var req encoder
+		req.int32(0)
+		req.int32(opClose)
+		_ = s.write(req.bytes())
+	}
+	s.fail()
+}
+
+func codeError(code int32) error {
+	if code == errCodeNoNode {
+		return ErrNoNode
+	}
+	return fmt.Errorf("zookeeper: server error %d", code)
+}
+
+func writeFrame(w io.Writer, payload []byte) error {
+	frame := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[4:], payload)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("zookeeper: frame of %d bytes exceeds limit", n)
+	}
+	frame := make([]byte, n)
+	_, err := io.ReadFull(r, frame)
+	return frame, err
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *encoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+func (e *encoder) raw(b []byte)  { e.buf = append(e.buf, b...) }
+func (e *encoder) bytes() []byte { return e.buf }
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *encoder) buffer(b []byte) {
+	e.int32(int32(len(b)))
+	e.raw(b)
+}
+
+func (e *encoder) string(v string) { e.buffer([]byte(v)) }
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	out := d.buf[:n]
+	d.buf = d.buf[n:]
+	return out
+}
+
+func (d *decoder) int32() int32 {
+	b := d.take(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *decoder) int64() int64 {
+	b := d.take(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *decoder) string() string {
+	n := d.int32()
+	if n <= 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+func (d *decoder) strings() []string {
+	n := d.int32()
+	if n <= 0 {
+		return nil
+	}
+	out := make([]string, 0, n)
+	for i := int32(0); i < n && d.err == nil; i++ {
+		out = append(out, d.string())
+	}
+	return out
+}
+
+func (d *decoder) rest() []byte {
+	out := d.buf
+	d.buf = nil
+	return out
+}
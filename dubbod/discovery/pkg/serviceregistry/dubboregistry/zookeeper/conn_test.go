@@ -0,0 +1,351 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConnListsChildrenAndFollowsWatches(t *testing.T) {
+	server := newFakeServer(t)
+	server.set("/dubbo", "org.example.Greeter", "org.example.Stock")
+
+	conn := NewConn([]string{server.addr()}, 3*time.Second)
+	defer conn.Close()
+
+	children, watch, err := conn.ChildrenW("/dubbo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(children, []string{"org.example.Greeter", "org.example.Stock"}) {
+		t.Fatalf("children = %v", children)
+	}
+	if got := server.requestedTimeout(); got != 3000 {
+		t.Fatalf("requested session timeout = %dms, want 3000ms", got)
+	}
+
+	server.set("/dubbo", "org.example.Greeter")
+	waitClosed(t, watch, "child watch")
+	children, _, err = conn.ChildrenW("/dubbo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(children, []string{"org.example.Greeter"}) {
+		t.Fatalf("children after change = %v", children)
+	}
+}
+
+func TestConnWritesGetChildrenRequest(t *testing.T) {
+	server := newFakeServer(t)
+	server.set("/dubbo")
+
+	conn := NewConn([]string{server.addr()}, 3*time.Second)
+	defer conn.Close()
+	if _, _, err := conn.ChildrenW("/dubbo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// xid 1, getChildren2 opcode 8, path "/dubbo", watch true.
+	want := []byte{
+		0, 0, 0, 1,
+		0, 0, 0, 8,
+		0, 0, 0, 6, '/', 'd', 'u', 'b', 'b', 'o',
+		1,
+	}
+	if got := server.firstRequest(opGetChildren); !bytes.Equal(got, want) {
+		t.Fatalf("getChildren request = %x, want %x", got, want)
+	}
+}
+
+func TestConnReportsMissingNode(t *testing.T) {
+	server := newFakeServer(t)
+	conn := NewConn([]string{server.addr()}, 3*time.Second)
+	defer conn.Close()
+
+	if _, _, err := conn.ChildrenW("/dubbo"); !errors.Is(err, ErrNoNode) {
+		t.Fatalf("err = %v, want ErrNoNode", err)
+	}
+}
+
+func TestConnRedialsAfterLostSession(t *testing.T) {
+	server := newFakeServer(t)
+	server.set("/dubbo", "org.example.Greeter")
+	conn := NewConn([]string{server.addr()}, 3*time.Second)
+	defer conn.Close()
+
+	_, watch, err := conn.ChildrenW("/dubbo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.dropConnections()
+	waitClosed(t, watch, "watch of the lost session")
+
+	children, _, err := conn.ChildrenW("/dubbo")
+	if err != nil {
+		t.Fatalf("list after reconnect: %v", err)
+	}
+	if !slices.Equal(children, []string{"org.example.Greeter"}) {
+		t.Fatalf("children after reconnect = %v", children)
+	}
+	if got := server.sessions(); got != 2 {
+		t.Fatalf("sessions = %d, want 2", got)
+	}
+}
+
+func TestConnRedialsAfterServerStopsAnswering(t *testing.T) {
+	server := newFakeServer(t)
+	server.set("/dubbo", "org.example.Greeter")
+	conn := NewConn([]string{server.addr()}, 300*time.Millisecond)
+	defer conn.Close()
+
+	_, watch, err := conn.ChildrenW("/dubbo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The connection stays open but nothing comes back, not even pings: a
+	// half-open link that only the read deadline can detect.
+	server.mute(true)
+	waitClosed(t, watch, "watch of the unresponsive session")
+
+	server.mute(false)
+	children, _, err := conn.ChildrenW("/dubbo")
+	if err != nil {
+		t.Fatalf("list after reconnect: %v", err)
+	}
+	if !slices.Equal(children, []string{"org.example.Greeter"}) {
+		t.Fatalf("children after reconnect = %v", children)
+	}
+	if got := server.sessions(); got != 2 {
+		t.Fatalf("sessions = %d, want 2", got)
+	}
+}
+
+func TestDecoderRejectsTruncatedFrames(t *testing.T) {
+	d := decoder{buf: []byte{0, 0, 0, 5, 'a'}}
+	if got := d.string(); got != "" || !errors.Is(d.err, io.ErrUnexpectedEOF) {
+		t.Fatalf("string() = %q, err %v; want truncation error", got, d.err)
+	}
+
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], maxFrameSize+1)
+	if _, err := readFrame(bytes.NewReader(size[:])); err == nil {
+		t.Fatal("oversized frame accepted")
+	}
+}
+
+func waitClosed(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s did not fire", what)
+	}
+}
+
+// fakeServer answers the handshake, getChildren, ping and close requests
+// Conn sends. Replies are encoded by hand from the ZooKeeper jute records
+// rather than with the client's own encoder, so the two check each other.
+type fakeServer struct {
+	t        *testing.T
+	listener net.Listener
+
+	mu       sync.Mutex
+	children map[string][]string
+	watches  map[string][]*fakeServerConn
+	conns    []*fakeServerConn
+	timeout  int32
+	requests [][]byte
+	muted    bool
+}
+
+type fakeServerConn struct {
+	net.Conn
+	writeMu sync.Mutex
+}
+
+func (c *fakeServerConn) send(parts ...[]byte) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	payload := bytes.Join(parts, nil)
+	_, _ = c.Write(binary.BigEndian.AppendUint32(nil, uint32(len(payload))))
+	_, _ = c.Write(payload)
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{
+		t:        t,
+		listener: listener,
+		children: map[string][]string{},
+		watches:  map[string][]*fakeServerConn{},
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+		s.dropConnections()
+	})
+	go s.accept()
+	return s
+}
+
+func (s *fakeServer) addr() string { return s.listener.Addr().String() }
+
+func (s *fakeServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &fakeServerConn{Conn: conn}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+func (s *fakeServer) serve(c *fakeServerConn) {
+	defer c.Close()
+	// ConnectRequest: protocolVersion, lastZxidSeen, timeOut, sessionId, passwd.
+	frame, err := readFrame(c)
+	if err != nil || len(frame) < 20 {
+		return
+	}
+	timeout := int32(binary.BigEndian.Uint32(frame[12:16]))
+	s.mu.Lock()
+	s.timeout = timeout
+	s.mu.Unlock()
+	// ConnectResponse: protocolVersion, timeOut, sessionId, passwd.
+	c.send(be32(0), be32(timeout), be64(1), be32(16), make([]byte, 16))
+
+	for {
+		frame, err := readFrame(c)
+		if err != nil || len(frame) < 8 {
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, frame)
+		muted := s.muted
+		s.mu.Unlock()
+		if muted {
+			continue
+		}
+		xid, op := int32(binary.BigEndian.Uint32(frame)), int32(binary.BigEndian.Uint32(frame[4:]))
+		switch op {
+		case opPing:
+			c.send(replyHeader(xidPing, 0))
+		case opClose:
+			c.send(replyHeader(xid, 0))
+			return
+		case opGetChildren:
+			n := binary.BigEndian.Uint32(frame[8:])
+			path := string(frame[12 : 12+n])
+			watch := frame[12+n] == 1
+			s.mu.Lock()
+			children, found := s.children[path]
+			if found && watch {
+				s.watches[path] = append(s.watches[path], c)
+			}
+			s.mu.Unlock()
+			if !found {
+				c.send(replyHeader(xid, errCodeNoNode))
+				continue
+			}
+			parts := [][]byte{replyHeader(xid, 0), be32(int32(len(children)))}
+			for _, child := range children {
+				parts = append(parts, be32(int32(len(child))), []byte(child))
+			}
+			c.send(parts...)
+		default:
+			s.t.Errorf("unexpected opcode %d", op)
+			return
+		}
+	}
+}
+
+// set replaces the children of path and notifies its watchers with a
+// NodeChildrenChanged event.
+func (s *fakeServer) set(path string, children ...string) {
+	s.mu.Lock()
+	s.children[path] = children
+	watchers := s.watches[path]
+	delete(s.watches, path)
+	s.mu.Unlock()
+	for _, c := range watchers {
+		// WatcherEvent: type NodeChildrenChanged (4), state SyncConnected (3), path.
+		c.send(replyHeader(xidWatchEvent, 0), be32(4), be32(3), be32(int32(len(path))), []byte(path))
+	}
+}
+
+func (s *fakeServer) dropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.watches = map[string][]*fakeServerConn{}
+	s.mu.Unlock()
+	for _, c := range conns {
+		_ = c.Close()
+	}
+}
+
+// mute makes the server read requests without answering any of them, while
+// keeping the existing connections open.
+func (s *fakeServer) mute(muted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.muted = muted
+}
+
+func (s *fakeServer) sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+func (s *fakeServer) requestedTimeout() int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timeout
+}
+
+func (s *fakeServer) firstRequest(op int32) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, frame := range s.requests {
+		if int32(binary.BigEndian.Uint32(frame[4:])) == op {
+			return frame
+		}
+	}
+	return nil
+}
+
+// replyHeader is the ReplyHeader record: xid, zxid, err.
+func replyHeader(xid, code int32) []byte {
+	return bytes.Join([][]byte{be32(xid), be64(0), be32(code)}, nil)
+}
+
+func be32(v int32) []byte { return binary.BigEndian.AppendUint32(nil, uint32(v)) }
+func be64(v int64) []byte { return binary.BigEndian.AppendUint64(nil, uint64(v)) }
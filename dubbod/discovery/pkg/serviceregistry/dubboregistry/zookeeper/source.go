@@ -0,0 +1,161 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeper
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/dubboregistry"
+	"github.com/apache/dubbo-kubernetes/pkg/backoff"
+	dubbolog "github.com/apache/dubbo-kubernetes/pkg/log"
+)
+
+var log = dubbolog.RegisterScope("zookeeper", "ZooKeeper Dubbo registry adapter")
+
+// DefaultRoot is the node Dubbo registers interfaces under.
+const DefaultRoot = "/dubbo"
+
+type Options struct {
+	// Root defaults to DefaultRoot.
+	Root string
+	// RetryInterval is the wait after the first failed list. It doubles
+	// with every further failure up to MaxRetryInterval.
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+	// ResyncInterval relists even without a watch firing. A providers node
+	// created after its interface node is only seen this way, because a
+	// child watch cannot be set on a node that does not exist yet.
+	ResyncInterval time.Duration
+}
+
+// Source lists /<root>/<interface>/providers and keeps watches on the root
+// and every providers node.
+type Source struct {
+	client Client
+	opts   Options
+}
+
+var _ dubboregistry.Source = &Source{}
+
+func NewSource(client Client, opts Options) *Source {
+	if opts.Root == "" {
+		opts.Root = DefaultRoot
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 5 * time.Second
+	}
+	if opts.MaxRetryInterval < opts.RetryInterval {
+		opts.MaxRetryInterval = max(opts.RetryInterval, 2*time.Minute)
+	}
+	if opts.ResyncInterval <= 0 {
+		opts.ResyncInterval = time.Minute
+	}
+	return &Source{client: client, opts: opts}
+}
+
+func (s *Source) Run(stop <-chan struct{}, handler dubboregistry.Handler) {
+	defer s.client.Close()
+	retry := backoff.NewExponentialBackOff(backoff.Option{
+		InitialInterval: s.opts.RetryInterval,
+		MaxInterval:     s.opts.MaxRetryInterval,
+	})
+	for {
+		urls, watches, err := s.list()
+		wait := s.opts.ResyncInterval
+		if err != nil {
+			handler.ListFailed(err)
+			wait = retry.NextBackOff()
+		} else {
+			retry.Reset()
+			handler.Update(urls)
+		}
+		if !waitForChange(stop, watches, wait) {
+			return
+		}
+	}
+}
+
+func (s *Source) list() ([]*dubboregistry.ProviderURL, []<-chan struct{}, error) {
+	interfaces, rootWatch, err := s.client.ChildrenW(s.opts.Root)
+	if errors.Is(err, ErrNoNode) {
+		// Nothing registered yet.
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	watches := []<-chan struct{}{rootWatch}
+	var urls []*dubboregistry.ProviderURL
+	for _, iface := range interfaces {
+		providersPath := path.Join(s.opts.Root, iface, "providers")
+		children, watch, err := s.client.ChildrenW(providersPath)
+		if errors.Is(err, ErrNoNode) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		watches = append(watches, watch)
+		for _, child := range children {
+			raw, err := url.QueryUnescape(child)
+			if err != nil {
+				log.Debugf("skipping undecodable provider %q under %s: %v", child, providersPath, err)
+				continue
+			}
+			provider, err := dubboregistry.ParseProviderURL(raw)
+			if err != nil {
+				log.Debugf("skipping provider under %s: %v", providersPath, err)
+				continue
+			}
+			urls = append(urls, provider)
+		}
+	}
+	return urls, watches, nil
+}
+
+// waitForChange blocks until a watch fires or the timeout passes. It
+// returns false once stop is closed.
+func waitForChange(stop <-chan struct{}, watches []<-chan struct{}, timeout time.Duration) bool {
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+	for _, watch := range watches {
+		go func(watch <-chan struct{}) {
+			select {
+			case <-watch:
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			case <-done:
+			}
+		}(watch)
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stop:
+		return false
+	case <-changed:
+		return true
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,139 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeper
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/serviceregistry/dubboregistry"
+)
+
+func TestSourceListsProvidersAndFollowsWatches(t *testing.T) {
+	client := newFakeClient()
+	client.set("/dubbo", "org.example.Greeter", "org.example.Empty")
+	client.set("/dubbo/org.example.Greeter/providers",
+		url.QueryEscape("tri://10.0.0.1:50051/org.example.Greeter?version=1.0.0&side=provider"),
+		url.QueryEscape("consumer://10.0.0.9:0/org.example.Greeter?side=consumer"),
+	)
+
+	handler := &fakeHandler{updates: make(chan []*dubboregistry.ProviderURL, 10)}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		NewSource(client, Options{ResyncInterval: time.Hour}).Run(stop, handler)
+		close(done)
+	}()
+
+	urls := handler.next(t)
+	if len(urls) != 1 || urls[0].Address != "10.0.0.1" || urls[0].Version != "1.0.0" {
+		t.Fatalf("unexpected providers: %+v", urls)
+	}
+
+	client.set("/dubbo/org.example.Greeter/providers",
+		url.QueryEscape("tri://10.0.0.1:50051/org.example.Greeter?version=1.0.0"),
+		url.QueryEscape("tri://10.0.0.2:50051/org.example.Greeter?version=2.0.0"),
+	)
+	if urls := handler.next(t); len(urls) != 2 {
+		t.Fatalf("expected the watch to trigger a relist with 2 providers, got %d", len(urls))
+	}
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("source did not stop")
+	}
+	if !client.isClosed() {
+		t.Fatal("client not closed on stop")
+	}
+}
+
+func TestSourceWithoutRootNode(t *testing.T) {
+	urls, watches, err := NewSource(newFakeClient(), Options{}).list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 0 || len(watches) != 0 {
+		t.Fatalf("expected an empty registry, got %d providers and %d watches", len(urls), len(watches))
+	}
+}
+
+type fakeClient struct {
+	mu       sync.Mutex
+	children map[string][]string
+	watches  map[string][]chan struct{}
+	closed   bool
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{children: map[string][]string{}, watches: map[string][]chan struct{}{}}
+}
+
+func (f *fakeClient) set(path string, children ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.children[path] = children
+	for _, watch := range f.watches[path] {
+		close(watch)
+	}
+	delete(f.watches, path)
+}
+
+func (f *fakeClient) ChildrenW(path string) ([]string, <-chan struct{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	children, found := f.children[path]
+	if !found {
+		return nil, nil, ErrNoNode
+	}
+	watch := make(chan struct{})
+	f.watches[path] = append(f.watches[path], watch)
+	return append([]string(nil), children...), watch, nil
+}
+
+func (f *fakeClient) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+func (f *fakeClient) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+type fakeHandler struct {
+	updates chan []*dubboregistry.ProviderURL
+}
+
+func (f *fakeHandler) Update(urls []*dubboregistry.ProviderURL) { f.updates <- urls }
+func (f *fakeHandler) ListFailed(error)                         {}
+
+func (f *fakeHandler) next(t *testing.T) []*dubboregistry.ProviderURL {
+	t.Helper()
+	select {
+	case urls := <-f.updates:
+		return urls
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update received")
+		return nil
+	}
+}
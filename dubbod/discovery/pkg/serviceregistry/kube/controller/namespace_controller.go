@@ -31,6 +31,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/kube/controllers"
 	"github.com/apache/dubbo-kubernetes/pkg/kube/inject"
 	"github.com/apache/dubbo-kubernetes/pkg/kube/kclient"
+	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
 	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
 )
 
@@ -153,6 +154,31 @@ func (nc *NamespaceController) startCaBundleWatcher(stop <-chan struct{}) {
 	}
 }
 
+// rootCertData is the content of the root-cert configmap. The roots of
+// federated trust domains are written as a SPIFFE bundle map, keeping each
+// bound to its own trust domain, only once federation is configured, and
+// emptied rather than left stale when it is removed.
+func (nc *NamespaceController) rootCertData(ns string) map[string]string {
+	data := map[string]string{
+		constants.CACertNamespaceConfigMapDataName: string(nc.caBundleWatcher.GetCABundle()),
+	}
+	if federated := nc.caBundleWatcher.GetFederatedCABundles(); len(federated) > 0 {
+		bundleMap, err := spiffe.EncodeBundleMap(federated)
+		if err != nil {
+			log.Errorf("failed to encode federated trust bundles: %v", err)
+		} else {
+			data[constants.FederatedBundleMapNamespaceConfigMapDataName] = string(bundleMap)
+			return data
+		}
+	}
+	if cm := nc.configmaps.Get(CACertNamespaceConfigMap, ns); cm != nil {
+		if _, found := cm.Data[constants.FederatedBundleMapNamespaceConfigMapDataName]; found {
+			data[constants.FederatedBundleMapNamespaceConfigMapDataName] = ""
+		}
+	}
+	return data
+}
+
 // reconcileCACertAndCRL will reconcile the ca root cert and crl configmap for the specified namespace
 // If the configmap is not found, it will be created.
 // If the namespace is filtered out by discovery selector, the configmap will be deleted.
@@ -165,15 +191,14 @@ func (nc *NamespaceController) reconcileCACertAndCRL(o types.NamespacedName) err
 
 	errs := []error{
 		// upsert root-cert configmap
-		k8s.InsertDataToConfigMap(
+		k8s.InsertDataMapToConfigMap(
 			nc.configmaps,
 			metav1.ObjectMeta{
 				Name:      CACertNamespaceConfigMap,
 				Namespace: ns,
 				Labels:    configMapLabel,
 			},
-			constants.CACertNamespaceConfigMapDataName,
-			nc.caBundleWatcher.GetCABundle(),
+			nc.rootCertData(ns),
 		),
 	}
 
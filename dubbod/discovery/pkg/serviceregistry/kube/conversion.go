@@ -22,11 +22,14 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/cluster"
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
 	"github.com/apache/dubbo-kubernetes/pkg/config/host"
+	dubbolog "github.com/apache/dubbo-kubernetes/pkg/log"
 	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
 	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 )
 
+var log = dubbolog.RegisterScope("controller", "kube controller debugging")
+
 func ServiceHostname(name, namespace, domainSuffix string) host.Name {
 	return host.Name(name + "." + namespace + "." + "svc" + "." + domainSuffix) // Format: "%s.%s.svc.%s"
 }
@@ -78,6 +81,24 @@ func ConvertService(svc corev1.Service, domainSuffix string, clusterID cluster.I
 	dubboService.Attributes.Type = string(svc.Spec.Type)
 	dubboService.Attributes.ExternalName = externalName
 	dubboService.Attributes.PublishNotReadyAddresses = svc.Spec.PublishNotReadyAddresses
+
+	loadBalancer, err := model.LoadBalancerSettingsFromAnnotations(svc.Annotations)
+	if err != nil {
+		log.Warnf("ignoring load balancer annotations on service %s/%s: %v", svc.Namespace, svc.Name, err)
+	}
+	dubboService.Attributes.LoadBalancer = loadBalancer
+
+	localityLoadBalancer, err := model.LocalityLbSettingFromAnnotations(svc.Annotations)
+	if err != nil {
+		log.Warnf("ignoring locality load balancer annotation on service %s/%s: %v", svc.Namespace, svc.Name, err)
+	}
+	dubboService.Attributes.LocalityLoadBalancer = localityLoadBalancer
+
+	dubboInterfaces, err := model.DubboInterfacesFromAnnotations(svc.Annotations)
+	if err != nil {
+		log.Warnf("ignoring dubbo interfaces annotation on service %s/%s: %v", svc.Namespace, svc.Name, err)
+	}
+	dubboService.Attributes.DubboInterfaces = dubboInterfaces
 	return dubboService
 }
 
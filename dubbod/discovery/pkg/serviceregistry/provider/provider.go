@@ -24,6 +24,10 @@ const (
 	Kubernetes ID = "Kubernetes"
 	// External is a service registry backed by declarative ServiceEntry resources.
 	External ID = "External"
+	// Zookeeper is a service registry backed by Dubbo provider URLs in ZooKeeper.
+	Zookeeper ID = "Zookeeper"
+	// Nacos is a service registry backed by Dubbo providers registered in Nacos.
+	Nacos ID = "Nacos"
 )
 
 func (id ID) String() string {
@@ -0,0 +1,40 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	metav1alpha1 "github.com/kdubbo/api/meta/v1alpha1"
+)
+
+// SameConditions reports whether two condition sets carry the same information,
+// so an unchanged policy is not written back on every resync. Status writes are
+// not free: a resync storm across every policy is a self-inflicted load spike
+// on the API server.
+func SameConditions(a, b []*metav1alpha1.DubboCondition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].GetType() != b[i].GetType() ||
+			a[i].GetStatus() != b[i].GetStatus() ||
+			a[i].GetReason() != b[i].GetReason() ||
+			a[i].GetObservedGeneration() != b[i].GetObservedGeneration() {
+			return false
+		}
+	}
+	return true
+}
@@ -21,10 +21,14 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	v1 "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/xds/v1"
 	"github.com/apache/dubbo-kubernetes/pkg/config/schema/collections"
+	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // SyncStatus reports the xDS synchronization state of a single connected proxy.
@@ -48,6 +52,25 @@ type WatchedTypeStatus struct {
 	LastError    string    `json:"last_error,omitempty"`
 }
 
+// ProxyConfigDump holds the xDS resources dubbod generates for one connected
+// proxy right now, as protojson. It is what the proxy would receive on a
+// forced full push, which is not necessarily what it has acknowledged.
+type ProxyConfigDump struct {
+	ProxyID   string            `json:"proxy"`
+	Listeners []json.RawMessage `json:"listeners,omitempty"`
+	Routes    []json.RawMessage `json:"routes,omitempty"`
+	Clusters  []json.RawMessage `json:"clusters,omitempty"`
+	Endpoints []json.RawMessage `json:"endpoints,omitempty"`
+}
+
+// configDumpTypes maps the ?type= values of /debug/config_dump to xDS types.
+var configDumpTypes = map[string]string{
+	"listeners": v1.ListenerType,
+	"routes":    v1.RouteType,
+	"clusters":  v1.ClusterType,
+	"endpoints": v1.EndpointType,
+}
+
 type debugHandler struct {
 	path string
 	help string
@@ -66,6 +89,7 @@ func (s *DiscoveryServer) AppendDebugHandlers(mux *http.ServeMux) {
 	register("/debug/configz", "Configuration resources known to this dubbod instance", s.configz)
 	register("/debug/registryz", "Services in the service registry", s.registryz)
 	register("/debug/endpointz", "Endpoints published through xDS", s.endpointz)
+	register("/debug/config_dump", "xDS resources generated for a connected proxy (?proxyID=<id>&type=listeners|routes|clusters|endpoints)", s.configDump)
 
 	mux.HandleFunc("/debug", func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -226,6 +250,121 @@ func (s *DiscoveryServer) endpointz(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, result)
 }
 
+func (s *DiscoveryServer) configDump(w http.ResponseWriter, req *http.Request) {
+	proxyID := req.URL.Query().Get("proxyID")
+	if proxyID == "" {
+		http.Error(w, "proxyID is required", http.StatusBadRequest)
+		return
+	}
+	types := sets.New[string]()
+	if raw := req.URL.Query().Get("type"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			if _, ok := configDumpTypes[t]; !ok {
+				http.Error(w, fmt.Sprintf("unknown type %q, want listeners, routes, clusters or endpoints", t), http.StatusBadRequest)
+				return
+			}
+			types.Insert(t)
+		}
+	} else {
+		for t := range configDumpTypes {
+			types.Insert(t)
+		}
+	}
+	con := s.connectionForProxy(proxyID)
+	if con == nil {
+		http.Error(w, fmt.Sprintf("proxy %q is not connected to this dubbod instance", proxyID), http.StatusNotFound)
+		return
+	}
+	dump, err := s.ConfigDump(con, types)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, dump)
+}
+
+func (s *DiscoveryServer) connectionForProxy(proxyID string) *Connection {
+	for _, con := range s.AllClients() {
+		if proxy := con.Proxy(); proxy != nil && proxy.ID == proxyID {
+			return con
+		}
+	}
+	return nil
+}
+
+// ConfigDump runs the connection's generators for the requested types, using
+// the names the proxy currently watches. Listeners and clusters fall back to a
+// wildcard request when the proxy has not subscribed to them.
+func (s *DiscoveryServer) ConfigDump(con *Connection, types sets.String) (*ProxyConfigDump, error) {
+	proxy := con.Proxy()
+	dump := &ProxyConfigDump{ProxyID: proxy.ID}
+	req := &model.PushRequest{
+		Full:   true,
+		Forced: true,
+		Push:   s.globalPushContext(),
+		Reason: model.NewReasonStats(model.ProxyRequest),
+		Start:  time.Now(),
+	}
+	for _, name := range sets.SortedList(types) {
+		typeURL := configDumpTypes[name]
+		watched := &model.WatchedResource{TypeUrl: typeURL, ResourceNames: sets.New[string]()}
+		proxy.RLock()
+		wr := proxy.WatchedResources[typeURL]
+		if wr != nil {
+			watched.ResourceNames = wr.ResourceNames.Copy()
+		}
+		proxy.RUnlock()
+		if wr == nil && (typeURL == v1.RouteType || typeURL == v1.EndpointType) {
+			// Routes and endpoints are never wildcard; nothing watched means nothing sent.
+			continue
+		}
+		gen := s.findGenerator(typeURL, con)
+		if gen == nil {
+			continue
+		}
+		resources, _, err := gen.Generate(proxy, watched, req)
+		if err != nil {
+			return nil, fmt.Errorf("generate %s: %v", name, err)
+		}
+		out, err := marshalConfigDumpResources(resources)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %v", name, err)
+		}
+		switch typeURL {
+		case v1.ListenerType:
+			dump.Listeners = out
+		case v1.RouteType:
+			dump.Routes = out
+		case v1.ClusterType:
+			dump.Clusters = out
+		case v1.EndpointType:
+			dump.Endpoints = out
+		}
+	}
+	return dump, nil
+}
+
+func marshalConfigDumpResources(resources model.Resources) ([]json.RawMessage, error) {
+	sort.Slice(resources, func(i, j int) bool { return resources[i].GetName() < resources[j].GetName() })
+	out := make([]json.RawMessage, 0, len(resources))
+	for _, r := range resources {
+		if r == nil || r.Resource == nil {
+			continue
+		}
+		msg, err := r.Resource.UnmarshalNew()
+		if err != nil {
+			return nil, err
+		}
+		raw, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, raw)
+	}
+	return out, nil
+}
+
 func endpointHealthString(status model.HealthStatus) string {
 	switch status {
 	case model.Healthy:
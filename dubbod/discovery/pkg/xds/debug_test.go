@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	v1 "github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/xds/v1"
 	"github.com/apache/dubbo-kubernetes/pkg/config/host"
 	"github.com/apache/dubbo-kubernetes/pkg/config/mesh"
 	"github.com/apache/dubbo-kubernetes/pkg/config/mesh/meshwatcher"
@@ -80,6 +81,48 @@ func TestEndpointzReportsVMEndpointState(t *testing.T) {
 	}
 }
 
+func TestConfigDumpGeneratesWatchedClustersForProxy(t *testing.T) {
+	server, con, _ := newDeltaXDSTestServer(filteredDeltaGenerator{})
+	server.adsClients[con.ID()] = con
+	con.proxy.NewWatchedResource(v1.ClusterType, []string{"outbound|80||b.app.svc.cluster.local", "outbound|80||a.app.svc.cluster.local"})
+
+	recorder := httptest.NewRecorder()
+	server.configDump(recorder, httptest.NewRequest("GET", "/debug/config_dump?proxyID=pod-1&type=clusters", nil))
+	if recorder.Code != 200 {
+		t.Fatalf("status code = %d, want 200: %s", recorder.Code, recorder.Body.String())
+	}
+	var dump struct {
+		ProxyID   string           `json:"proxy"`
+		Clusters  []map[string]any `json:"clusters"`
+		Listeners []map[string]any `json:"listeners"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &dump); err != nil {
+		t.Fatalf("decode config_dump: %v", err)
+	}
+	if dump.ProxyID != "pod-1" || len(dump.Listeners) != 0 {
+		t.Fatalf("unexpected dump: %+v", dump)
+	}
+	if len(dump.Clusters) != 2 || dump.Clusters[0]["name"] != "outbound|80||a.app.svc.cluster.local" {
+		t.Fatalf("clusters = %v, want both watched clusters sorted by name", dump.Clusters)
+	}
+}
+
+func TestConfigDumpRejectsUnknownProxyAndType(t *testing.T) {
+	server, con, _ := newDeltaXDSTestServer(filteredDeltaGenerator{})
+	server.adsClients[con.ID()] = con
+	for url, want := range map[string]int{
+		"/debug/config_dump":                          400,
+		"/debug/config_dump?proxyID=pod-1&type=peers": 400,
+		"/debug/config_dump?proxyID=missing":          404,
+	} {
+		recorder := httptest.NewRecorder()
+		server.configDump(recorder, httptest.NewRequest("GET", url, nil))
+		if recorder.Code != want {
+			t.Fatalf("%s status code = %d, want %d", url, recorder.Code, want)
+		}
+	}
+}
+
 type debugServiceDiscovery struct {
 	service *model.Service
 }
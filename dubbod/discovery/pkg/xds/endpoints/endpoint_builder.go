@@ -47,26 +47,36 @@ type EndpointBuilder struct {
 	hostname    host.Name
 	port        int
 	service     *model.Service
+
+	// dubboSubset narrows the endpoints to one Dubbo group and version when
+	// the cluster name carries a subset built by model.DubboSubsetName.
+	dubboSubset  bool
+	dubboGroup   string
+	dubboVersion string
 }
 
 var _ model.XdsCacheEntry = &EndpointBuilder{}
 
 // NewEndpointBuilder creates a new EndpointBuilder
 func NewEndpointBuilder(clusterName string, proxy *model.Proxy, push *model.PushContext) *EndpointBuilder {
-	_, _, hostname, port := model.ParseSubsetKey(clusterName)
+	_, subset, hostname, port := model.ParseSubsetKey(clusterName)
 	if hostname == "" || port == 0 {
 		return nil
 	}
 
 	svc := push.ServiceForHostname(proxy, hostname)
+	group, version, dubboSubset := model.ParseDubboSubsetName(subset)
 
 	return &EndpointBuilder{
-		clusterName: clusterName,
-		proxy:       proxy,
-		push:        push,
-		hostname:    hostname,
-		port:        port,
-		service:     svc,
+		clusterName:  clusterName,
+		proxy:        proxy,
+		push:         push,
+		hostname:     hostname,
+		port:         port,
+		service:      svc,
+		dubboSubset:  dubboSubset,
+		dubboGroup:   group,
+		dubboVersion: version,
 	}
 }
 
@@ -93,8 +103,12 @@ func (b *EndpointBuilder) rewriteColdServiceToActivator(
 ) *endpoint.ClusterLoadAssignment {
 	if b == nil || b.proxy == nil || !b.proxy.IsInherentGrpc() || b.proxy.IsRouter() ||
 		b.push == nil || b.service == nil || b.service.Attributes.Name == model.ActivationGatewayServiceName ||
-		!b.push.ServiceActivationEnabled(b.service.Attributes.Namespace, b.service.Attributes.Name) ||
-		hasLbEndpoints(assignment) {
+		!b.push.ServiceActivationEnabled(b.service.Attributes.Namespace, b.service.Attributes.Name) {
+		return assignment
+	}
+	// An idle Service is drained through the Activator before it is scaled to
+	// zero, so its callers are rerouted while it still has endpoints.
+	if hasLbEndpoints(assignment) && !b.push.ServiceActivationDraining(b.service.Attributes.Namespace, b.service.Attributes.Name) {
 		return assignment
 	}
 
@@ -194,17 +208,20 @@ func (b *EndpointBuilder) BuildClusterLoadAssignmentWithGateways(endpointIndex *
 
 	// Filter and convert endpoints
 	type localityEndpoints struct {
-		locality  *core.Locality
-		endpoints []*endpoint.LbEndpoint
-		weight    uint32
+		locality   *core.Locality
+		endpoints  []*endpoint.LbEndpoint
+		weight     uint32
+		viaGateway map[string]*endpoint.LbEndpoint
 	}
 	localities := make(map[string]*localityEndpoints)
+	localityLbSetting := b.localityLbSetting()
 	var lbEndpoints []*endpoint.LbEndpoint
 	var filteredCount int
 	var totalEndpoints int
 	var portNameMismatchCount int
 	var unhealthyCount int
 	var buildFailedCount int
+	var subsetMismatchCount int
 
 	allServicePortNames := make(map[string]int)
 	for _, eps := range shards.Shards {
@@ -242,6 +259,12 @@ func (b *EndpointBuilder) BuildClusterLoadAssignmentWithGateways(endpointIndex *
 				}
 			}
 
+			if b.dubboSubset && !model.DubboSubsetMatches(ep.Labels, b.dubboGroup, b.dubboVersion) {
+				subsetMismatchCount++
+				filteredCount++
+				continue
+			}
+
 			lbEp := b.buildLbEndpointForCluster(ep, shard.Cluster, gateways)
 			if lbEp == nil {
 				buildFailedCount++
@@ -249,14 +272,29 @@ func (b *EndpointBuilder) BuildClusterLoadAssignmentWithGateways(endpointIndex *
 				log.Debugf("buildLbEndpoint returned nil for endpoint %s", ep.FirstAddressOrNil())
 				continue
 			}
-			lbEndpoints = append(lbEndpoints, lbEp)
 			group := localities[ep.Locality]
 			if group == nil {
-				group = &localityEndpoints{locality: parseLocality(ep.Locality)}
+				group = &localityEndpoints{locality: parseLocality(ep.Locality), viaGateway: map[string]*endpoint.LbEndpoint{}}
 				localities[ep.Locality] = group
 			}
-			group.endpoints = append(group.endpoints, lbEp)
 			group.weight += lbEp.GetLoadBalancingWeight().GetValue()
+			// Remote endpoints behind one east-west gateway share its address.
+			// When locality load balancing weighs localities, fold them into
+			// one endpoint per locality so the gateway carries the locality's
+			// full weight instead of duplicate addresses.
+			if gateway, ok := b.eastWestGatewayForCluster(shard.Cluster, gateways); ok && localityLbSetting.IsEnabled() {
+				key := gateway.Endpoint()
+				if existing := group.viaGateway[key]; existing != nil {
+					existing.LoadBalancingWeight = wrapperspb.UInt32(existing.GetLoadBalancingWeight().GetValue() + lbEp.GetLoadBalancingWeight().GetValue())
+					if lbEp.HealthStatus == core.HealthStatus_HEALTHY {
+						existing.HealthStatus = core.HealthStatus_HEALTHY
+					}
+					continue
+				}
+				group.viaGateway[key] = lbEp
+			}
+			lbEndpoints = append(lbEndpoints, lbEp)
+			group.endpoints = append(group.endpoints, lbEp)
 		}
 	}
 
@@ -267,8 +305,8 @@ func (b *EndpointBuilder) BuildClusterLoadAssignmentWithGateways(endpointIndex *
 		if totalEndpoints > 0 {
 			logLevel = log.Warnf // If endpoints exist but were filtered, this is a warning
 		}
-		logLevel("no endpoints found for cluster %s (hostname=%s, port=%d, svcPort.Name='%s', svcPort.Port=%d, totalEndpoints=%d, filteredCount=%d, portNameMismatch=%d, unhealthy=%d, buildFailed=%d)",
-			b.clusterName, b.hostname, b.port, svcPort.Name, svcPort.Port, totalEndpoints, filteredCount, portNameMismatchCount, unhealthyCount, buildFailedCount)
+		logLevel("no endpoints found for cluster %s (hostname=%s, port=%d, svcPort.Name='%s', svcPort.Port=%d, totalEndpoints=%d, filteredCount=%d, portNameMismatch=%d, subsetMismatch=%d, unhealthy=%d, buildFailed=%d)",
+			b.clusterName, b.hostname, b.port, svcPort.Name, svcPort.Port, totalEndpoints, filteredCount, portNameMismatchCount, subsetMismatchCount, unhealthyCount, buildFailedCount)
 		return buildEmptyClusterLoadAssignment(b.clusterName)
 	}
 
@@ -305,7 +343,7 @@ func (b *EndpointBuilder) BuildClusterLoadAssignmentWithGateways(endpointIndex *
 
 	return &endpoint.ClusterLoadAssignment{
 		ClusterName: b.clusterName,
-		Endpoints:   localityLbEndpoints,
+		Endpoints:   applyLocalityLoadBalancing(localityLbSetting, b.proxy.Locality(), localityLbEndpoints),
 	}
 }
 
@@ -450,7 +488,14 @@ func (b *EndpointBuilder) Cacheable() bool {
 func (b *EndpointBuilder) Key() any {
 	// EDS cache expects uint64 key, not string
 	// Hash the cluster name to uint64 to match the cache type
-	return xxhash.Sum64String(b.clusterName + "|" + string(b.proxyClusterID()) + "|" + features.EastWestGatewayRegistry)
+	key := b.clusterName + "|" + string(b.proxyClusterID()) + "|" + features.EastWestGatewayRegistry
+	// With locality load balancing, priorities and weights depend on where
+	// the caller sits, so proxies in different localities cannot share an
+	// entry.
+	if b.localityLbSetting().IsEnabled() {
+		key += "|" + b.proxy.Locality()
+	}
+	return xxhash.Sum64String(key)
 }
 
 func (b *EndpointBuilder) proxyClusterID() cluster.ID {
@@ -16,6 +16,7 @@
 package endpoints
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/config/memory"
@@ -32,7 +33,9 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/kube/multicluster"
 	networking "github.com/kdubbo/api/networking/v1alpha3"
 	security "github.com/kdubbo/api/security/v1alpha3"
+	core "github.com/kdubbo/xds-api/core/v1"
 	endpoint "github.com/kdubbo/xds-api/endpoint/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestBuildClusterLoadAssignmentKeepsAppPortWithoutDUBBOMutual(t *testing.T) {
@@ -137,6 +140,52 @@ func TestColdActivationRewritesInherentEDSAndSwitchesBack(t *testing.T) {
 	}
 }
 
+func TestIdleActivationDrainsThroughTheActivatorWhileServing(t *testing.T) {
+	targetHost := host.Name("payment.app.svc.cluster.local")
+	activatorHost := host.Name("dxgate-gateway.app.svc.cluster.local")
+	target := newEndpointTestService("payment", "app", string(targetHost), 8080)
+	activator := newEndpointTestService(model.ActivationGatewayServiceName, "app", string(activatorHost), 80)
+	env := newEndpointTestEnvironment(t, []config.Config{{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.ServiceActivationPolicy,
+			Name:             "payment",
+			Namespace:        "app",
+		},
+		Spec: &networking.ServiceActivationPolicy{
+			TargetRef:              &networking.PolicyTargetReference{Kind: "Service", Name: "payment"},
+			AutoscalerRef:          &networking.AutoscalerReference{Name: "payment"},
+			BackendServiceAccounts: []string{"payment"},
+		},
+	}}, []*model.Service{target, activator})
+	env.ActivationDrains = staticActivationDrains{{Namespace: "app", Name: "payment"}}
+	push := model.NewPushContext()
+	push.InitContext(env, nil, nil)
+
+	index := model.NewEndpointIndex(model.DisabledCache{})
+	for hostname, address := range map[host.Name]string{activatorHost: "10.0.0.9", targetHost: "10.0.0.5"} {
+		index.UpdateServiceEndpoints(model.ShardKey{}, string(hostname), "app", []*model.DubboEndpoint{{
+			Addresses:       []string{address},
+			EndpointPort:    8080,
+			ServicePortName: "http",
+			HealthStatus:    model.Healthy,
+		}}, false)
+	}
+
+	clusterName := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", targetHost, 8080)
+	draining := NewEndpointBuilder(clusterName, newEndpointTestProxy(), push).BuildClusterLoadAssignment(index)
+	if got := firstEndpointAddress(t, draining); got != "10.0.0.9" {
+		t.Fatalf("draining endpoint address = %q, want Activator 10.0.0.9", got)
+	}
+
+	env.ActivationDrains = nil
+	push = model.NewPushContext()
+	push.InitContext(env, nil, nil)
+	restored := NewEndpointBuilder(clusterName, newEndpointTestProxy(), push).BuildClusterLoadAssignment(index)
+	if got := firstEndpointAddress(t, restored); got != "10.0.0.5" {
+		t.Fatalf("restored endpoint address = %q, want backend 10.0.0.5", got)
+	}
+}
+
 func TestColdActivationDoesNotRewriteRouterEDS(t *testing.T) {
 	targetHost := host.Name("payment.app.svc.cluster.local")
 	activatorHost := host.Name("dxgate-gateway.app.svc.cluster.local")
@@ -328,6 +377,190 @@ func TestBuildClusterLoadAssignmentPreservesVMLocalityHealthAndWeight(t *testing
 	}
 }
 
+func TestBuildClusterLoadAssignmentAssignsLocalityFailoverPriorities(t *testing.T) {
+	hostname := host.Name("reviews.bookinfo.svc.cluster.local")
+	svc := newEndpointTestService("reviews", "bookinfo", string(hostname), 9080)
+	svc.Attributes.LocalityLoadBalancer = &model.LocalityLbSetting{
+		Failover: []model.LocalityFailover{
+			{From: "us-east", To: "eu-west"},
+			{From: "us-east", To: "us-west"},
+		},
+	}
+	push := newEndpointTestPushContext(t, nil, []*model.Service{svc})
+	index := model.NewEndpointIndex(model.DisabledCache{})
+	var eps []*model.DubboEndpoint
+	for i, locality := range []string{"us-east/zone-a", "us-east/zone-b", "us-west/zone-a", "eu-west/zone-a", "ap-south/zone-a"} {
+		eps = append(eps, &model.DubboEndpoint{
+			Addresses:       []string{fmt.Sprintf("10.0.0.%d", i+1)},
+			EndpointPort:    9080,
+			ServicePortName: "http",
+			Locality:        locality,
+			HealthStatus:    model.Healthy,
+		})
+	}
+	index.UpdateServiceEndpoints(model.ShardKey{}, string(hostname), "bookinfo", eps, false)
+
+	proxy := newEndpointTestProxy()
+	proxy.XdsNode = &core.Node{Locality: &core.Locality{Region: "us-east", Zone: "zone-a"}}
+	clusterName := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", hostname, 9080)
+	cla := NewEndpointBuilder(clusterName, proxy, push).BuildClusterLoadAssignment(index)
+
+	want := map[string]uint32{
+		"us-east/zone-a":  0,
+		"us-east/zone-b":  1,
+		"eu-west/zone-a":  2,
+		"us-west/zone-a":  3,
+		"ap-south/zone-a": 4,
+	}
+	if len(cla.GetEndpoints()) != len(want) {
+		t.Fatalf("localities = %d, want %d", len(cla.GetEndpoints()), len(want))
+	}
+	for _, group := range cla.GetEndpoints() {
+		name := localityString(group.GetLocality())
+		if got := group.GetPriority(); got != want[name] {
+			t.Errorf("locality %s priority = %d, want %d", name, got, want[name])
+		}
+	}
+}
+
+func TestBuildClusterLoadAssignmentDistributesLocalityWeights(t *testing.T) {
+	hostname := host.Name("reviews.bookinfo.svc.cluster.local")
+	svc := newEndpointTestService("reviews", "bookinfo", string(hostname), 9080)
+	svc.Attributes.LocalityLoadBalancer = &model.LocalityLbSetting{
+		Distribute: []model.LocalityDistribute{{
+			From: "us-east/zone-a/*",
+			To:   map[string]uint32{"us-east/zone-a/*": 80, "us-east/zone-b/*": 20},
+		}},
+	}
+	push := newEndpointTestPushContext(t, nil, []*model.Service{svc})
+	index := model.NewEndpointIndex(model.DisabledCache{})
+	index.UpdateServiceEndpoints(model.ShardKey{}, string(hostname), "bookinfo", []*model.DubboEndpoint{
+		{Addresses: []string{"10.0.0.1"}, EndpointPort: 9080, ServicePortName: "http", Locality: "us-east/zone-a", HealthStatus: model.Healthy},
+		{Addresses: []string{"10.0.0.2"}, EndpointPort: 9080, ServicePortName: "http", Locality: "us-east/zone-b", HealthStatus: model.Healthy},
+		{Addresses: []string{"10.0.0.3"}, EndpointPort: 9080, ServicePortName: "http", Locality: "us-west/zone-a", HealthStatus: model.Healthy},
+	}, false)
+
+	proxy := newEndpointTestProxy()
+	proxy.XdsNode = &core.Node{Locality: &core.Locality{Region: "us-east", Zone: "zone-a"}}
+	clusterName := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", hostname, 9080)
+	cla := NewEndpointBuilder(clusterName, proxy, push).BuildClusterLoadAssignment(index)
+
+	localities := cla.GetEndpoints()
+	if len(localities) != 2 {
+		t.Fatalf("localities = %d, want 2 (us-west receives no traffic)", len(localities))
+	}
+	zoneA, zoneB := localities[0].GetLoadBalancingWeight().GetValue(), localities[1].GetLoadBalancingWeight().GetValue()
+	if zoneA != 4*zoneB {
+		t.Fatalf("locality weights = %d/%d, want 80/20 split", zoneA, zoneB)
+	}
+	for _, group := range localities {
+		if group.GetPriority() != 0 {
+			t.Fatalf("distribute should keep priority 0, got %d for %v", group.GetPriority(), group.GetLocality())
+		}
+	}
+}
+
+func TestBuildClusterLoadAssignmentFoldsRemoteLocalityBehindEastWestGateway(t *testing.T) {
+	hostname := host.Name("nginx.app.svc.cluster.local")
+	svc := newEndpointTestService("nginx", "app", string(hostname), 80)
+	svc.Attributes.LocalityLoadBalancer = &model.LocalityLbSetting{}
+	push := newEndpointTestPushContext(t, nil, []*model.Service{svc})
+	index := model.NewEndpointIndex(model.DisabledCache{})
+	index.UpdateServiceEndpoints(model.ShardKey{Cluster: cluster.ID("primary")}, string(hostname), "app", []*model.DubboEndpoint{
+		{Addresses: []string{"10.0.0.1"}, EndpointPort: 80, ServicePortName: "http", Locality: "us-east/zone-a", HealthStatus: model.Healthy},
+	}, false)
+	index.UpdateServiceEndpoints(model.ShardKey{Cluster: cluster.ID("remote")}, string(hostname), "app", []*model.DubboEndpoint{
+		{Addresses: []string{"192.168.0.1"}, EndpointPort: 80, ServicePortName: "http", Locality: "us-west/zone-a", HealthStatus: model.Healthy},
+		{Addresses: []string{"192.168.0.2"}, EndpointPort: 80, ServicePortName: "http", Locality: "us-west/zone-a", HealthStatus: model.Healthy},
+	}, false)
+
+	proxy := newEndpointTestProxy()
+	proxy.XdsNode = &core.Node{Locality: &core.Locality{Region: "us-east", Zone: "zone-a"}}
+	clusterName := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", hostname, 80)
+	cla := NewEndpointBuilder(clusterName, proxy, push).BuildClusterLoadAssignmentWithGateways(index, map[cluster.ID]multicluster.EastWestGateway{
+		cluster.ID("remote"): {Cluster: cluster.ID("remote"), Address: "192.168.15.155", Port: 15443},
+	})
+
+	localities := cla.GetEndpoints()
+	if len(localities) != 2 {
+		t.Fatalf("localities = %d, want 2", len(localities))
+	}
+	remote := localities[1]
+	if got := localityString(remote.GetLocality()); got != "us-west/zone-a" {
+		t.Fatalf("second locality = %s, want us-west/zone-a", got)
+	}
+	if remote.GetPriority() != 1 {
+		t.Fatalf("remote locality priority = %d, want 1", remote.GetPriority())
+	}
+	if len(remote.GetLbEndpoints()) != 1 || remote.GetLbEndpoints()[0].GetLoadBalancingWeight().GetValue() != 2 {
+		t.Fatalf("remote endpoints = %v, want one gateway endpoint with weight 2", remote.GetLbEndpoints())
+	}
+}
+
+func TestBuildClusterLoadAssignmentKeepsGatewayEndpointsWithoutLocalitySetting(t *testing.T) {
+	hostname := host.Name("nginx.app.svc.cluster.local")
+	svc := newEndpointTestService("nginx", "app", string(hostname), 80)
+	push := newEndpointTestPushContext(t, nil, []*model.Service{svc})
+	index := model.NewEndpointIndex(model.DisabledCache{})
+	index.UpdateServiceEndpoints(model.ShardKey{Cluster: cluster.ID("remote")}, string(hostname), "app", []*model.DubboEndpoint{
+		{Addresses: []string{"192.168.0.1"}, EndpointPort: 80, ServicePortName: "http", Locality: "us-west/zone-a", HealthStatus: model.Healthy},
+		{Addresses: []string{"192.168.0.2"}, EndpointPort: 80, ServicePortName: "http", Locality: "us-west/zone-a", HealthStatus: model.Healthy},
+	}, false)
+
+	proxy := newEndpointTestProxy()
+	proxy.XdsNode = &core.Node{Locality: &core.Locality{Region: "us-east", Zone: "zone-a"}}
+	clusterName := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", hostname, 80)
+	cla := NewEndpointBuilder(clusterName, proxy, push).BuildClusterLoadAssignmentWithGateways(index, map[cluster.ID]multicluster.EastWestGateway{
+		cluster.ID("remote"): {Cluster: cluster.ID("remote"), Address: "192.168.15.155", Port: 15443},
+	})
+
+	localities := cla.GetEndpoints()
+	if len(localities) != 1 || len(localities[0].GetLbEndpoints()) != 2 {
+		t.Fatalf("localities = %v, want both gateway endpoints kept", localities)
+	}
+	if localities[0].GetPriority() != 0 {
+		t.Fatalf("priority = %d, want 0 without a locality setting", localities[0].GetPriority())
+	}
+}
+
+func TestBuildClusterLoadAssignmentFiltersDubboSubset(t *testing.T) {
+	hostname := host.Name("greeter.app.svc.cluster.local")
+	svc := newEndpointTestService("greeter", "app", string(hostname), 50051)
+	push := newEndpointTestPushContext(t, nil, []*model.Service{svc})
+	index := model.NewEndpointIndex(model.DisabledCache{})
+	index.UpdateServiceEndpoints(model.ShardKey{}, string(hostname), "app", []*model.DubboEndpoint{
+		{
+			Addresses:       []string{"10.0.0.1"},
+			EndpointPort:    50051,
+			ServicePortName: "http",
+			Labels:          map[string]string{model.DubboVersionLabel: "1.0.0"},
+			HealthStatus:    model.Healthy,
+		},
+		{
+			Addresses:       []string{"10.0.0.2"},
+			EndpointPort:    50051,
+			ServicePortName: "http",
+			Labels:          map[string]string{model.DubboVersionLabel: "2.0.0", model.DubboGroupLabel: "gray"},
+			HealthStatus:    model.Healthy,
+		},
+	}, false)
+
+	subset := model.BuildSubsetKey(model.TrafficDirectionOutbound, model.DubboSubsetName("gray", "2.0.0"), hostname, 50051)
+	cla := NewEndpointBuilder(subset, newEndpointTestProxy(), push).BuildClusterLoadAssignment(index)
+	if got := firstEndpointAddress(t, cla); got != "10.0.0.2" {
+		t.Fatalf("subset endpoint = %s, want 10.0.0.2", got)
+	}
+	if got := len(cla.GetEndpoints()[0].GetLbEndpoints()); got != 1 {
+		t.Fatalf("subset endpoints = %d, want 1", got)
+	}
+
+	all := model.BuildSubsetKey(model.TrafficDirectionOutbound, "", hostname, 50051)
+	cla = NewEndpointBuilder(all, newEndpointTestProxy(), push).BuildClusterLoadAssignment(index)
+	if got := len(cla.GetEndpoints()[0].GetLbEndpoints()); got != 2 {
+		t.Fatalf("default cluster endpoints = %d, want 2", got)
+	}
+}
+
 func firstEndpointAddress(t *testing.T, cla *endpoint.ClusterLoadAssignment) string {
 	t.Helper()
 	localities := cla.GetEndpoints()
@@ -347,6 +580,14 @@ func firstEndpointPort(t *testing.T, cla *endpoint.ClusterLoadAssignment) uint32
 }
 
 func newEndpointTestPushContext(t *testing.T, configs []config.Config, services []*model.Service) *model.PushContext {
+	t.Helper()
+	env := newEndpointTestEnvironment(t, configs, services)
+	push := model.NewPushContext()
+	push.InitContext(env, nil, nil)
+	return push
+}
+
+func newEndpointTestEnvironment(t *testing.T, configs []config.Config, services []*model.Service) *model.Environment {
 	t.Helper()
 	store := memory.Make(collections.DubboGatewayAPI())
 	for _, cfg := range configs {
@@ -361,9 +602,13 @@ func newEndpointTestPushContext(t *testing.T, configs []config.Config, services
 		MeshConfig: mesh.DefaultMeshConfig(),
 	}, true))
 	env.Init()
-	push := model.NewPushContext()
-	push.InitContext(env, nil, nil)
-	return push
+	return env
+}
+
+type staticActivationDrains []types.NamespacedName
+
+func (d staticActivationDrains) DrainingServices() []types.NamespacedName {
+	return d
 }
 
 func newEndpointTestProxy() *model.Proxy {
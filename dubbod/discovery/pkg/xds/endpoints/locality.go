@@ -0,0 +1,163 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoints
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/discovery/pkg/model"
+	core "github.com/kdubbo/xds-api/core/v1"
+	endpoint "github.com/kdubbo/xds-api/endpoint/v1"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// localityLbSetting returns the service's own locality setting, falling back
+// to MeshConfig localityLbSetting.
+func (b *EndpointBuilder) localityLbSetting() *model.LocalityLbSetting {
+	if b.service != nil && b.service.Attributes.LocalityLoadBalancer != nil {
+		return b.service.Attributes.LocalityLoadBalancer
+	}
+	return b.push.LocalityLbSetting()
+}
+
+// applyLocalityLoadBalancing orders or weighs the localities relative to the
+// proxy. Nothing changes when the setting is off or the proxy did not report
+// a locality, so every locality keeps priority 0.
+func applyLocalityLoadBalancing(
+	setting *model.LocalityLbSetting,
+	proxyLocality string,
+	localities []*endpoint.LocalityLbEndpoints,
+) []*endpoint.LocalityLbEndpoints {
+	if !setting.IsEnabled() || proxyLocality == "" || len(localities) == 0 {
+		return localities
+	}
+	if weights := setting.DistributeFor(proxyLocality); weights != nil {
+		return applyLocalityDistribute(weights, localities)
+	}
+	proxy := parseLocality(proxyLocality)
+	applyLocalityFailover(proxy, setting.FailoverRegions(proxy.GetRegion()), localities)
+	return localities
+}
+
+// applyLocalityFailover assigns EDS priorities: same subzone, same zone, same
+// region, then each failover region in order, then all remaining regions.
+// Priorities are compacted because gRPC and Envoy both require them to be
+// contiguous from 0.
+func applyLocalityFailover(proxy *core.Locality, failover []string, localities []*endpoint.LocalityLbEndpoints) {
+	raw := make([]int, len(localities))
+	used := map[int]struct{}{}
+	for i, group := range localities {
+		raw[i] = localityPriority(proxy, group.GetLocality(), failover)
+		used[raw[i]] = struct{}{}
+	}
+	levels := make([]int, 0, len(used))
+	for level := range used {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+	compact := make(map[int]uint32, len(levels))
+	for i, level := range levels {
+		compact[level] = uint32(i)
+	}
+	for i, group := range localities {
+		group.Priority = compact[raw[i]]
+	}
+}
+
+func localityPriority(proxy, target *core.Locality, failover []string) int {
+	if target.GetRegion() == proxy.GetRegion() {
+		switch {
+		case target.GetZone() != proxy.GetZone():
+			return 2
+		case target.GetSubZone() != proxy.GetSubZone():
+			return 1
+		default:
+			return 0
+		}
+	}
+	for i, region := range failover {
+		if target.GetRegion() == region {
+			return 3 + i
+		}
+	}
+	return 3 + len(failover)
+}
+
+// applyLocalityDistribute keeps every locality in priority 0 and splits each
+// destination pattern's weight across the localities it matches, in
+// proportion to their endpoint weight. Localities no pattern matches receive
+// no traffic and are dropped.
+func applyLocalityDistribute(weights map[string]uint32, localities []*endpoint.LocalityLbEndpoints) []*endpoint.LocalityLbEndpoints {
+	patterns := make([]string, 0, len(weights))
+	for pattern := range weights {
+		patterns = append(patterns, pattern)
+	}
+	// The most specific pattern claims a locality first.
+	sort.Slice(patterns, func(i, j int) bool {
+		si, sj := localityPatternSpecificity(patterns[i]), localityPatternSpecificity(patterns[j])
+		if si != sj {
+			return si > sj
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	matched := make([]string, len(localities))
+	patternWeight := map[string]uint64{}
+	for i, group := range localities {
+		name := localityString(group.GetLocality())
+		for _, pattern := range patterns {
+			if model.LocalityMatch(name, pattern) {
+				matched[i] = pattern
+				patternWeight[pattern] += uint64(group.GetLoadBalancingWeight().GetValue())
+				break
+			}
+		}
+	}
+
+	out := make([]*endpoint.LocalityLbEndpoints, 0, len(localities))
+	for i, group := range localities {
+		pattern := matched[i]
+		if pattern == "" || patternWeight[pattern] == 0 {
+			continue
+		}
+		// Scale by 1000 so small shares split across many localities keep
+		// their ratio after integer division.
+		weight := uint64(weights[pattern]) * 1000 * uint64(group.GetLoadBalancingWeight().GetValue()) / patternWeight[pattern]
+		group.LoadBalancingWeight = wrapperspb.UInt32(uint32(max(weight, 1)))
+		out = append(out, group)
+	}
+	if len(out) == 0 {
+		log.Warnf("locality distribute matched none of %d localities; keeping the default distribution", len(localities))
+		return localities
+	}
+	return out
+}
+
+func localityPatternSpecificity(pattern string) int {
+	specificity := 0
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment != "*" {
+			specificity++
+		}
+	}
+	return specificity
+}
+
+func localityString(locality *core.Locality) string {
+	return strings.TrimRight(locality.GetRegion()+"/"+locality.GetZone()+"/"+locality.GetSubZone(), "/")
+}
@@ -230,6 +230,9 @@ func readSignedCsr(client clientset.Interface, csr string, watchTimeout time.Dur
 	})
 	if l != nil && len(l.Items) > 0 {
 		reqSigned := l.Items[0]
+		if err := csrFailure(&reqSigned); err != nil {
+			return nil, err
+		}
 		if reqSigned.Status.Certificate != nil {
 			return reqSigned.Status.Certificate, nil
 		}
@@ -251,8 +254,17 @@ func readSignedCsr(client clientset.Interface, csr string, watchTimeout time.Dur
 	timer := time.After(watchTimeout)
 	for {
 		select {
-		case r := <-watcher.ResultChan():
-			reqSigned := r.Object.(*cert.CertificateSigningRequest)
+		case r, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil, fmt.Errorf("watch for CSR %v closed before it was signed", csr)
+			}
+			reqSigned, ok := r.Object.(*cert.CertificateSigningRequest)
+			if !ok {
+				continue
+			}
+			if err := csrFailure(reqSigned); err != nil {
+				return nil, err
+			}
 			if reqSigned.Status.Certificate != nil {
 				return reqSigned.Status.Certificate, nil
 			}
@@ -262,6 +274,23 @@ func readSignedCsr(client clientset.Interface, csr string, watchTimeout time.Dur
 	}
 }
 
+// csrFailure reports a CSR the approver denied or the signer failed, so callers
+// do not wait out the whole watch timeout for a certificate that never comes.
+func csrFailure(csr *cert.CertificateSigningRequest) error {
+	for _, c := range csr.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case cert.CertificateDenied:
+			return fmt.Errorf("CSR %v was denied: %s", csr.Name, c.Message)
+		case cert.CertificateFailed:
+			return fmt.Errorf("signer %v failed CSR %v: %s", csr.Spec.SignerName, csr.Name, c.Message)
+		}
+	}
+	return nil
+}
+
 // Clean up the CSR
 func cleanupCSR(client clientset.Interface, csr *cert.CertificateSigningRequest) error {
 	err := client.CertificatesV1().CertificateSigningRequests().Delete(context.TODO(), csr.Name, metav1.DeleteOptions{})
@@ -30,14 +30,18 @@ import (
 var log = dubbolog.RegisterScope("k8sconfig", "k8s config debugging")
 
 func InsertDataToConfigMap(client kclient.Client[*v1.ConfigMap], meta metav1.ObjectMeta, dataKeyName string, data []byte) error {
+	return InsertDataMapToConfigMap(client, meta, map[string]string{dataKeyName: string(data)})
+}
+
+// InsertDataMapToConfigMap is InsertDataToConfigMap for several keys, written
+// in a single create or update.
+func InsertDataMapToConfigMap(client kclient.Client[*v1.ConfigMap], meta metav1.ObjectMeta, data map[string]string) error {
 	configmap := client.Get(meta.Name, meta.Namespace)
 	if configmap == nil {
 		// Create a new ConfigMap.
 		configmap = &v1.ConfigMap{
 			ObjectMeta: meta,
-			Data: map[string]string{
-				dataKeyName: string(data),
-			},
+			Data:       data,
 		}
 		if _, err := client.Create(configmap); err != nil {
 			// Namespace may be deleted between now... and our previous check. Just skip this, we cannot create into deleted ns
@@ -53,7 +57,7 @@ func InsertDataToConfigMap(client kclient.Client[*v1.ConfigMap], meta metav1.Obj
 		}
 	} else {
 		// Otherwise, update the config map if changes are required
-		err := updateDataInConfigMap(client, configmap, dataKeyName, data)
+		err := updateDataInConfigMap(client, configmap, data)
 		if err != nil {
 			return err
 		}
@@ -80,15 +84,12 @@ func insertData(cm *v1.ConfigMap, data map[string]string) bool {
 	return needsUpdate
 }
 
-func updateDataInConfigMap(c kclient.Client[*v1.ConfigMap], cm *v1.ConfigMap, dataKeyName string, data []byte) error {
+func updateDataInConfigMap(c kclient.Client[*v1.ConfigMap], cm *v1.ConfigMap, data map[string]string) error {
 	if cm == nil {
 		return fmt.Errorf("cannot update nil configmap")
 	}
 	newCm := cm.DeepCopy()
-	cmData := map[string]string{
-		dataKeyName: string(data),
-	}
-	if needsUpdate := insertData(newCm, cmData); !needsUpdate {
+	if needsUpdate := insertData(newCm, data); !needsUpdate {
 		log.Debugf("ConfigMap %s/%s is already up to date", cm.Namespace, cm.Name)
 		return nil
 	}
@@ -0,0 +1,97 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenreview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/pkg/security"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// serviceAccountPrefix is the username prefix the API server gives service account tokens.
+	serviceAccountPrefix = "system:serviceaccount:"
+	// Extra keys set on the TokenReview status for tokens bound to a pod.
+	podNameKey = "authentication.kubernetes.io/pod-name"
+	podUIDKey  = "authentication.kubernetes.io/pod-uid"
+)
+
+// ValidateK8sJwt sends the token to the API server for review and returns the
+// service account, and the pod for bound tokens, it was issued to. The token
+// must be valid for at least one of audiences.
+func ValidateK8sJwt(ctx context.Context, client kubernetes.Interface, token string, audiences []string) (security.KubernetesInfo, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: audiences,
+		},
+	}
+	resp, err := client.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return security.KubernetesInfo{}, fmt.Errorf("failed to call the TokenReview API: %v", err)
+	}
+	return getTokenReviewResult(resp, audiences)
+}
+
+func getTokenReviewResult(resp *authenticationv1.TokenReview, audiences []string) (security.KubernetesInfo, error) {
+	if resp.Status.Error != "" {
+		return security.KubernetesInfo{}, fmt.Errorf("the service account authentication returns an error: %v", resp.Status.Error)
+	}
+	if !resp.Status.Authenticated {
+		return security.KubernetesInfo{}, fmt.Errorf("the token is not authenticated")
+	}
+	// The API server echoes the audiences the token is valid for. An API server
+	// that ignores spec.audiences returns none, so the check is repeated here.
+	if len(audiences) > 0 && !hasAudience(resp.Status.Audiences, audiences) {
+		return security.KubernetesInfo{}, fmt.Errorf("the token audiences %v do not match any of %v", resp.Status.Audiences, audiences)
+	}
+	username := resp.Status.User.Username
+	if !strings.HasPrefix(username, serviceAccountPrefix) {
+		return security.KubernetesInfo{}, fmt.Errorf("the token is not a service account token: %q", username)
+	}
+	parts := strings.Split(strings.TrimPrefix(username, serviceAccountPrefix), ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return security.KubernetesInfo{}, fmt.Errorf("invalid service account username %q", username)
+	}
+	info := security.KubernetesInfo{
+		PodNamespace:      parts[0],
+		PodServiceAccount: parts[1],
+	}
+	if name := resp.Status.User.Extra[podNameKey]; len(name) == 1 {
+		info.PodName = name[0]
+	}
+	if uid := resp.Status.User.Extra[podUIDKey]; len(uid) == 1 {
+		info.PodUID = uid[0]
+	}
+	return info, nil
+}
+
+func hasAudience(got, want []string) bool {
+	for _, g := range got {
+		for _, w := range want {
+			if g == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenreview
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func fakeTokenReviewClient(status authenticationv1.TokenReviewStatus) (*fake.Clientset, *authenticationv1.TokenReviewSpec) {
+	client := fake.NewClientset()
+	spec := &authenticationv1.TokenReviewSpec{}
+	client.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		*spec = review.Spec
+		return true, &authenticationv1.TokenReview{Status: status}, nil
+	})
+	return client, spec
+}
+
+func TestValidateK8sJwtMapsBoundServiceAccountToken(t *testing.T) {
+	client, spec := fakeTokenReviewClient(authenticationv1.TokenReviewStatus{
+		Authenticated: true,
+		Audiences:     []string{"dubbo-ca"},
+		User: authenticationv1.UserInfo{
+			Username: "system:serviceaccount:batch:reporter",
+			Extra: map[string]authenticationv1.ExtraValue{
+				podNameKey: {"reporter-28312"},
+				podUIDKey:  {"1234"},
+			},
+		},
+	})
+
+	info, err := ValidateK8sJwt(context.Background(), client, "token", []string{"dubbo-ca", "cluster.local"})
+	if err != nil {
+		t.Fatalf("ValidateK8sJwt() error = %v", err)
+	}
+	if spec.Token != "token" || len(spec.Audiences) != 2 {
+		t.Fatalf("review spec = %+v, want token and both audiences", spec)
+	}
+	if info.PodNamespace != "batch" || info.PodServiceAccount != "reporter" || info.PodName != "reporter-28312" || info.PodUID != "1234" {
+		t.Fatalf("info = %+v", info)
+	}
+}
+
+func TestValidateK8sJwtRejectsInvalidTokens(t *testing.T) {
+	cases := map[string]struct {
+		status authenticationv1.TokenReviewStatus
+		want   string
+	}{
+		"unauthenticated": {
+			status: authenticationv1.TokenReviewStatus{},
+			want:   "not authenticated",
+		},
+		"review error": {
+			status: authenticationv1.TokenReviewStatus{Error: "token expired"},
+			want:   "token expired",
+		},
+		"wrong audience": {
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				Audiences:     []string{"https://kubernetes.default.svc"},
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:batch:reporter"},
+			},
+			want: "audiences",
+		},
+		"user token": {
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				Audiences:     []string{"dubbo-ca"},
+				User:          authenticationv1.UserInfo{Username: "alice"},
+			},
+			want: "not a service account token",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client, _ := fakeTokenReviewClient(tc.status)
+			_, err := ValidateK8sJwt(context.Background(), client, "token", []string{"dubbo-ca"})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("ValidateK8sJwt() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -28,7 +29,9 @@ import (
 	"time"
 
 	"github.com/apache/dubbo-kubernetes/pkg/backoff"
+	"github.com/apache/dubbo-kubernetes/pkg/env"
 	"github.com/apache/dubbo-kubernetes/pkg/file"
+	"github.com/apache/dubbo-kubernetes/pkg/maps"
 	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
 	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
 
@@ -48,6 +51,15 @@ const (
 
 var cacheLog = dubbolog.RegisterScope("cache", "cache debugging")
 
+var federatedTrustBundleFile = env.Register("FEDERATED_TRUST_BUNDLE_FILE", "",
+	"Path of the SPIFFE bundle map with the roots of federated trust domains, usually the "+
+		"federated-bundle-map.json key of the mounted dubbo-ca-root-cert ConfigMap. "+
+		"Used when the agent options do not set one.")
+
+var caCRLFile = env.Register("CA_CRL_FILE", "",
+	"Path of the PEM revocation lists of the mesh CA, usually the ca-crl.pem key of the mounted "+
+		"dubbo-ca-crl ConfigMap. Used when the agent options do not set one.")
+
 var (
 	totalTimeout = time.Second * 10
 )
@@ -70,6 +82,8 @@ type SecretManagerClient struct {
 	fileCerts               map[FileCert]struct{}
 	configTrustBundleMutex  sync.RWMutex
 	configTrustBundle       []byte
+	crlMutex                sync.RWMutex
+	crl                     []byte
 	outputMutex             sync.Mutex
 	generateMutex           sync.Mutex
 	cache                   secretCache
@@ -102,11 +116,88 @@ func NewSecretManagerClient(caClient security.Client, options *security.Options)
 		caRootPath:  options.CARootPath,
 	}
 
+	if options.FederatedTrustBundleFilePath == "" {
+		options.FederatedTrustBundleFilePath = federatedTrustBundleFile.Get()
+	}
+	if options.FederatedTrustBundleFilePath != "" {
+		ret.loadFederatedTrustBundle()
+		ret.addFileWatcher(options.FederatedTrustBundleFilePath, federatedTrustBundleResourceName)
+	}
+	if options.CRLFilePath == "" {
+		options.CRLFilePath = caCRLFile.Get()
+	}
+	if options.CRLFilePath != "" {
+		ret.loadCRL()
+		ret.addFileWatcher(options.CRLFilePath, crlResourceName)
+	}
+
 	go ret.queue.Run(ret.stop)
 	go ret.handleFileWatch()
 	return ret, nil
 }
 
+// federatedTrustBundleResourceName tracks the federated trust bundle file
+// watch. It is never served over SDS; changes are merged into ROOTCA.
+const federatedTrustBundleResourceName = "federated-trust-bundle"
+
+// loadFederatedTrustBundle reads the SPIFFE bundle map of federated trust
+// domains. SDS serves a single validation context, so the roots are flattened
+// into ROOTCA; Envoy still pins the peer SPIFFE ID through the SAN matchers of
+// the cluster, which is what keeps a foreign root from vouching for a mesh
+// identity.
+func (sc *SecretManagerClient) loadFederatedTrustBundle() {
+	path := sc.configOptions.FederatedTrustBundleFilePath
+	var bundle []byte
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			cacheLog.Errorf("failed to read federated trust bundle %s: %v", path, err)
+		}
+	} else if len(data) > 0 {
+		bundles, err := spiffe.DecodeBundleMap(data)
+		if err != nil {
+			cacheLog.Errorf("failed to parse federated trust bundle %s: %v", path, err)
+			return
+		}
+		var roots []*x509.Certificate
+		for _, td := range sets.SortedList(sets.New(maps.Keys(bundles)...)) {
+			roots = append(roots, bundles[td]...)
+		}
+		bundle = spiffe.CertsToPEM(roots)
+	}
+	_ = sc.UpdateConfigTrustBundle(bundle)
+}
+
+// crlResourceName tracks the CA revocation list file watch. It is never served
+// over SDS; the list is attached to ROOTCA.
+const crlResourceName = "ca-crl"
+
+func (sc *SecretManagerClient) loadCRL() {
+	path := sc.configOptions.CRLFilePath
+	crl, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			cacheLog.Errorf("failed to read CA revocation list %s: %v", path, err)
+		}
+		crl = nil
+	}
+	sc.crlMutex.Lock()
+	if bytes.Equal(sc.crl, crl) {
+		sc.crlMutex.Unlock()
+		return
+	}
+	sc.crl = crl
+	sc.crlMutex.Unlock()
+
+	sc.OnSecretUpdate(security.RootCertReqResourceName)
+}
+
+func (sc *SecretManagerClient) getCRL() []byte {
+	sc.crlMutex.RLock()
+	defer sc.crlMutex.RUnlock()
+	return sc.crl
+}
+
 func (s *secretCache) GetRoot() (rootCert []byte) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -154,6 +245,9 @@ func (sc *SecretManagerClient) GenerateSecret(resourceName string) (secret *secu
 		if secret == nil || err != nil {
 			return
 		}
+		if resourceName == security.RootCertReqResourceName {
+			secret.CRL = sc.getCRL()
+		}
 		sc.outputMutex.Lock()
 		defer sc.outputMutex.Unlock()
 		if resourceName == security.RootCertReqResourceName || resourceName == security.WorkloadKeyCertResourceName {
@@ -665,9 +759,24 @@ func (sc *SecretManagerClient) handleFileWatch() {
 			// Trigger callbacks for all resources referencing this file. This is practically always
 			// a single resource.
 			for k := range resources {
-				if k.Filename == event.Name {
-					sc.OnSecretUpdate(k.ResourceName)
+				if k.Filename != event.Name {
+					continue
+				}
+				if k.ResourceName == federatedTrustBundleResourceName {
+					sc.loadFederatedTrustBundle()
+					if isRemove(event) {
+						sc.addFileWatcher(sc.configOptions.FederatedTrustBundleFilePath, federatedTrustBundleResourceName)
+					}
+					continue
+				}
+				if k.ResourceName == crlResourceName {
+					sc.loadCRL()
+					if isRemove(event) {
+						sc.addFileWatcher(sc.configOptions.CRLFilePath, crlResourceName)
+					}
+					continue
 				}
+				sc.OnSecretUpdate(k.ResourceName)
 			}
 		case err, ok := <-sc.certWatcher.Errors:
 			// Channel is closed.
@@ -0,0 +1,167 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pkiutil "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
+	"github.com/apache/dubbo-kubernetes/pkg/file"
+	"github.com/apache/dubbo-kubernetes/pkg/security"
+	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
+)
+
+func TestFederatedTrustBundleFromEnv(t *testing.T) {
+	meshRoot := genRoot(t, "cluster.local")
+	partnerRoot := genRoot(t, "partner.example")
+	rotatedPartnerRoot := genRoot(t, "partner.example")
+
+	bundlePath := filepath.Join(t.TempDir(), "federated-bundle-map.json")
+	writeBundleMap(t, bundlePath, map[string][]byte{"partner.example": partnerRoot})
+	t.Setenv(federatedTrustBundleFile.Name, bundlePath)
+
+	sc, err := NewSecretManagerClient(&fakeCAClient{roots: []string{string(meshRoot)}}, &security.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+	updates := make(chan string, 10)
+	sc.RegisterSecretHandler(func(resourceName string) {
+		updates <- resourceName
+	})
+
+	root := generateRoot(t, sc)
+	if !bytes.Contains(root, bytes.TrimSpace(meshRoot)) || !bytes.Contains(root, bytes.TrimSpace(partnerRoot)) {
+		t.Fatalf("ROOTCA does not carry the mesh and federated roots:\n%s", root)
+	}
+
+	writeBundleMap(t, bundlePath, map[string][]byte{"partner.example": rotatedPartnerRoot})
+	waitForUpdate(t, updates, security.RootCertReqResourceName)
+	root = generateRoot(t, sc)
+	if !bytes.Contains(root, bytes.TrimSpace(rotatedPartnerRoot)) || bytes.Contains(root, bytes.TrimSpace(partnerRoot)) {
+		t.Fatalf("ROOTCA did not pick up the rotated federated root:\n%s", root)
+	}
+}
+
+func TestCRLFromEnv(t *testing.T) {
+	crlPath := filepath.Join(t.TempDir(), "ca-crl.pem")
+	crl := []byte("-----BEGIN X509 CRL-----\nAAAA\n-----END X509 CRL-----\n")
+	if err := file.AtomicWrite(crlPath, crl, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(caCRLFile.Name, crlPath)
+
+	sc, err := NewSecretManagerClient(&fakeCAClient{roots: []string{string(genRoot(t, "cluster.local"))}}, &security.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+	updates := make(chan string, 10)
+	sc.RegisterSecretHandler(func(resourceName string) {
+		updates <- resourceName
+	})
+
+	if got := generateRootCRL(t, sc); !bytes.Equal(got, crl) {
+		t.Fatalf("ROOTCA CRL = %q, want %q", got, crl)
+	}
+
+	updated := []byte("-----BEGIN X509 CRL-----\nBBBB\n-----END X509 CRL-----\n")
+	if err := file.AtomicWrite(crlPath, updated, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitForUpdate(t, updates, security.RootCertReqResourceName)
+	if got := generateRootCRL(t, sc); !bytes.Equal(got, updated) {
+		t.Fatalf("ROOTCA CRL = %q, want the updated %q", got, updated)
+	}
+}
+
+type fakeCAClient struct {
+	roots []string
+}
+
+func (f *fakeCAClient) CSRSign([]byte, int64) ([]string, error) {
+	return nil, os.ErrInvalid
+}
+
+func (f *fakeCAClient) Close() {}
+
+func (f *fakeCAClient) GetRootCertBundle() ([]string, error) {
+	return f.roots, nil
+}
+
+func genRoot(t *testing.T, org string) []byte {
+	t.Helper()
+	cert, _, err := pkiutil.GenCertKeyFromOptions(pkiutil.CertOptions{
+		TTL:          time.Hour,
+		Org:          org,
+		IsCA:         true,
+		IsSelfSigned: true,
+		RSAKeySize:   2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func writeBundleMap(t *testing.T, path string, bundles map[string][]byte) {
+	t.Helper()
+	data, err := spiffe.EncodeBundleMap(bundles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.AtomicWrite(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func generateRoot(t *testing.T, sc *SecretManagerClient) []byte {
+	t.Helper()
+	secret, err := sc.GenerateSecret(security.RootCertReqResourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret.RootCert
+}
+
+func generateRootCRL(t *testing.T, sc *SecretManagerClient) []byte {
+	t.Helper()
+	secret, err := sc.GenerateSecret(security.RootCertReqResourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret.CRL
+}
+
+func waitForUpdate(t *testing.T, updates <-chan string, resourceName string) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case got := <-updates:
+			if got == resourceName {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s update", resourceName)
+		}
+	}
+}
@@ -239,10 +239,14 @@ func toXdsSecret(item *security.SecretItem, requestedName string) *tlsv1.Secret
 	}
 	secret := &tlsv1.Secret{Name: name}
 	if name == security.RootCertReqResourceName {
+		validationContext := &tlsv1.CertificateValidationContext{
+			TrustedCa: inlineBytes(item.RootCert),
+		}
+		if len(item.CRL) > 0 {
+			validationContext.Crl = inlineBytes(item.CRL)
+		}
 		secret.Type = &tlsv1.Secret_ValidationContext{
-			ValidationContext: &tlsv1.CertificateValidationContext{
-				TrustedCa: inlineBytes(item.RootCert),
-			},
+			ValidationContext: validationContext,
 		}
 		return secret
 	}
@@ -100,6 +100,7 @@ func TestGenerateBuildsRootValidationContext(t *testing.T) {
 				security.RootCertReqResourceName: {
 					ResourceName: security.RootCertReqResourceName,
 					RootCert:     []byte("root"),
+					CRL:          []byte("crl"),
 				},
 			},
 		},
@@ -125,6 +126,9 @@ func TestGenerateBuildsRootValidationContext(t *testing.T) {
 	if got := secret.GetValidationContext().GetTrustedCa().GetInlineBytes(); !bytes.Equal(got, []byte("root")) {
 		t.Fatalf("trusted CA = %q, want root", got)
 	}
+	if got := secret.GetValidationContext().GetCrl().GetInlineBytes(); !bytes.Equal(got, []byte("crl")) {
+		t.Fatalf("CRL = %q, want crl", got)
+	}
 }
 
 func TestFetchSecretsReturnsGeneratedResources(t *testing.T) {
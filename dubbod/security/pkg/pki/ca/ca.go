@@ -25,6 +25,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/dubbod/security/cmd"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	caerror "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/error"
@@ -91,6 +92,11 @@ type DubboCA struct {
 	caRSAKeySize    int
 	keyCertBundle   *util.KeyCertBundle
 	rootCertRotator *SelfSignedCARootCertRotator
+	// revocations holds the identities the CA refuses to sign for.
+	revocations atomic.Pointer[RevocationSet]
+	// issued holds the serial numbers signed per identity, listed in the CRL
+	// once the identity is revoked.
+	issued issuedCertificates
 }
 
 type DubboCAOptions struct {
@@ -107,6 +113,9 @@ type DubboCAOptions struct {
 
 	// OnRootCertUpdate is the cb which can only be called by self-signed root cert rotator
 	OnRootCertUpdate func() error
+
+	// RootRotationProgress reports the workloads holding back a staged root rotation.
+	RootRotationProgress RootRotationProgress
 }
 
 func NewDubboCA(opts *DubboCAOptions) (*DubboCA, error) {
@@ -117,7 +126,7 @@ func NewDubboCA(opts *DubboCAOptions) (*DubboCA, error) {
 	}
 
 	if opts.CAType == selfSignedCA && opts.RotatorConfig != nil && opts.RotatorConfig.CheckInterval > time.Duration(0) {
-		ca.rootCertRotator = NewSelfSignedCARootCertRotator(opts.RotatorConfig, ca, opts.OnRootCertUpdate)
+		ca.rootCertRotator = NewSelfSignedCARootCertRotator(opts.RotatorConfig, ca, opts.OnRootCertUpdate, opts.RootRotationProgress)
 	}
 
 	// if CA cert becomes invalid before workload cert it's going to cause workload cert to be invalid too,
@@ -136,7 +145,8 @@ func loadSelfSignedCaSecret(client corev1.CoreV1Interface, namespace string, caC
 	caSecret, err := client.Secrets(namespace).Get(context.TODO(), caCertName, metav1.GetOptions{})
 	if err == nil {
 		pkiCaLog.Infof("Load signing key and cert from existing secret %s/%s", caSecret.Namespace, caSecret.Name)
-		rootCerts, err := util.AppendRootCerts(caSecret.Data[CACertFile], rootCertFile)
+		// While a root rotation is running the secret trusts more than the signing root.
+		rootCerts, err := util.AppendRootCerts(rootBundleFromSecret(caSecret.Data), rootCertFile)
 		if err != nil {
 			return fmt.Errorf("failed to append root certificates (%v)", err)
 		}
@@ -166,6 +176,7 @@ func NewSelfSignedDubboCAOptions(ctx context.Context,
 		RotatorConfig: &SelfSignedCARootCertRotatorConfig{
 			CheckInterval:      rootCertCheckInverval,
 			caCertTTL:          caCertTTL,
+			workloadCertTTL:    defaultCertTTL,
 			retryInterval:      cmd.ReadSigningCertRetryInterval,
 			retryMax:           cmd.ReadSigningCertRetryMax,
 			certInspector:      certutil.NewCertUtil(rootCertGracePeriodPercentile),
@@ -174,6 +185,7 @@ func NewSelfSignedDubboCAOptions(ctx context.Context,
 			org:                org,
 			rootCertFile:       rootCertFile,
 			enableJitter:       enableJitter,
+			caRSAKeySize:       caRSAKeySize,
 			client:             client,
 		},
 	}
@@ -387,6 +399,10 @@ func (ca *DubboCA) sign(csrPEM []byte, subjectIDs []string, requestedLifetime ti
 		return nil, caerror.NewError(caerror.CSRError, err)
 	}
 
+	if err := ca.checkRevokedIdentities(subjectIDs); err != nil {
+		return nil, err
+	}
+
 	lifetime := requestedLifetime
 	// If the requested requestedLifetime is non-positive, apply the default TTL.
 	if requestedLifetime.Seconds() <= 0 {
@@ -403,6 +419,10 @@ func (ca *DubboCA) sign(csrPEM []byte, subjectIDs []string, requestedLifetime ti
 		return nil, caerror.NewError(caerror.CertGenError, err)
 	}
 
+	if leaf, err := x509.ParseCertificate(certBytes); err == nil {
+		ca.issued.record(subjectIDs, leaf.SerialNumber, leaf.NotAfter, time.Now())
+	}
+
 	block := &pem.Block{
 		Type:  "CERTIFICATE",
 		Bytes: certBytes,
@@ -0,0 +1,391 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	caerror "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/error"
+)
+
+const (
+	// RevocationsConfigMap lists the certificates and identities revoked with
+	// dubboctl. dubbod signs a CRL from it.
+	RevocationsConfigMap = "dubbo-ca-revocations"
+	RevocationsKey       = "revocations"
+
+	// CRLValidity is the NextUpdate horizon of a generated CRL. dubbod re-signs
+	// the list well before it runs out.
+	CRLValidity = 24 * time.Hour
+)
+
+// revocationReasons maps the RFC 5280 reason names accepted by the API to
+// their CRL reason codes.
+var revocationReasons = map[string]int{
+	"unspecified":          0,
+	"keyCompromise":        1,
+	"caCompromise":         2,
+	"affiliationChanged":   3,
+	"superseded":           4,
+	"cessationOfOperation": 5,
+}
+
+// Revocation revokes either a single certificate, by serial number, or every
+// certificate of a SPIFFE identity. An identity revocation also stops the CA
+// from signing new certificates for it.
+type Revocation struct {
+	SerialNumber string    `json:"serialNumber,omitempty"`
+	Identity     string    `json:"identity,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	RevokedAt    time.Time `json:"revokedAt"`
+}
+
+// Validate checks that exactly one of SerialNumber and Identity is set.
+func (r Revocation) Validate() error {
+	switch {
+	case r.SerialNumber == "" && r.Identity == "":
+		return fmt.Errorf("revocation needs a serial number or an identity")
+	case r.SerialNumber != "" && r.Identity != "":
+		return fmt.Errorf("revocation sets both serial number %s and identity %s", r.SerialNumber, r.Identity)
+	case r.SerialNumber != "":
+		if _, err := ParseSerialNumber(r.SerialNumber); err != nil {
+			return err
+		}
+	default:
+		u, err := url.Parse(r.Identity)
+		if err != nil || u.Scheme != "spiffe" || u.Host == "" {
+			return fmt.Errorf("identity %q is not a SPIFFE ID", r.Identity)
+		}
+	}
+	if _, ok := ReasonCode(r.Reason); !ok {
+		return fmt.Errorf("unknown revocation reason %q", r.Reason)
+	}
+	return nil
+}
+
+// key identifies the revoked object, so a repeated revocation replaces the
+// previous entry.
+func (r Revocation) key() string {
+	if r.Identity != "" {
+		return r.Identity
+	}
+	serial, err := ParseSerialNumber(r.SerialNumber)
+	if err != nil {
+		return r.SerialNumber
+	}
+	return FormatSerialNumber(serial)
+}
+
+// ReasonCode returns the CRL reason code of reason. An empty reason is
+// unspecified.
+func ReasonCode(reason string) (int, bool) {
+	if reason == "" {
+		return 0, true
+	}
+	code, ok := revocationReasons[reason]
+	return code, ok
+}
+
+// ParseSerialNumber parses a hexadecimal serial number, as printed by openssl,
+// with or without colons and a 0x prefix.
+func ParseSerialNumber(s string) (*big.Int, error) {
+	hex := strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x"), ":", "")
+	serial, ok := new(big.Int).SetString(hex, 16)
+	if !ok || serial.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid serial number %q", s)
+	}
+	return serial, nil
+}
+
+// FormatSerialNumber prints serial the way ParseSerialNumber reads it back.
+func FormatSerialNumber(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+// ParseRevocations reads the revocations stored in RevocationsConfigMap.
+func ParseRevocations(data string) ([]Revocation, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+	var revocations []Revocation
+	if err := json.Unmarshal([]byte(data), &revocations); err != nil {
+		return nil, fmt.Errorf("invalid revocations: %v", err)
+	}
+	for _, r := range revocations {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return revocations, nil
+}
+
+// MarshalRevocations encodes revocations for RevocationsConfigMap. Later
+// entries for the same serial number or identity replace earlier ones.
+func MarshalRevocations(revocations []Revocation) (string, error) {
+	byKey := make(map[string]Revocation, len(revocations))
+	for _, r := range revocations {
+		if err := r.Validate(); err != nil {
+			return "", err
+		}
+		byKey[r.key()] = r
+	}
+	out := make([]Revocation, 0, len(byKey))
+	for _, r := range byKey {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].key() < out[j].key()
+	})
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// RevocationSet indexes revocations by serial number and identity.
+type RevocationSet struct {
+	serials    map[string]Revocation
+	identities map[string]Revocation
+}
+
+// NewRevocationSet indexes revocations, which must have been validated.
+func NewRevocationSet(revocations []Revocation) *RevocationSet {
+	set := &RevocationSet{
+		serials:    map[string]Revocation{},
+		identities: map[string]Revocation{},
+	}
+	for _, r := range revocations {
+		if r.Identity != "" {
+			set.identities[r.Identity] = r
+			continue
+		}
+		set.serials[r.key()] = r
+	}
+	return set
+}
+
+// IdentityRevoked reports whether identity may no longer be issued
+// certificates.
+func (s *RevocationSet) IdentityRevoked(identity string) bool {
+	if s == nil {
+		return false
+	}
+	_, found := s.identities[identity]
+	return found
+}
+
+// CertificateRevoked reports whether cert is revoked by its serial number or
+// by one of its URI SAN identities.
+func (s *RevocationSet) CertificateRevoked(cert *x509.Certificate) bool {
+	if s == nil || cert == nil {
+		return false
+	}
+	if _, found := s.serials[FormatSerialNumber(cert.SerialNumber)]; found {
+		return true
+	}
+	for _, uri := range cert.URIs {
+		if s.IdentityRevoked(uri.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Entries returns the CRL entries of the set. A CRL can only list serial
+// numbers, so identity revocations are expanded with issued, the serial
+// numbers of the certificates known to carry each identity.
+func (s *RevocationSet) Entries(issued map[string][]*big.Int) []x509.RevocationListEntry {
+	if s == nil {
+		return nil
+	}
+	bySerial := map[string]x509.RevocationListEntry{}
+	add := func(serial *big.Int, r Revocation) {
+		code, _ := ReasonCode(r.Reason)
+		bySerial[FormatSerialNumber(serial)] = x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: r.RevokedAt.UTC(),
+			ReasonCode:     code,
+		}
+	}
+	for _, r := range s.serials {
+		serial, err := ParseSerialNumber(r.SerialNumber)
+		if err != nil {
+			continue
+		}
+		add(serial, r)
+	}
+	for identity, r := range s.identities {
+		for _, serial := range issued[identity] {
+			add(serial, r)
+		}
+	}
+	entries := make([]x509.RevocationListEntry, 0, len(bySerial))
+	for _, entry := range bySerial {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].SerialNumber.Cmp(entries[j].SerialNumber) < 0
+	})
+	return entries
+}
+
+// issuedCertificates remembers the serial numbers the CA signed for each
+// identity until the certificates expire, so an identity revocation can list
+// them in a CRL. It only knows what this CA instance signed since it started.
+type issuedCertificates struct {
+	mu         sync.Mutex
+	byIdentity map[string]map[string]issuedCertificate
+	prunedAt   time.Time
+}
+
+type issuedCertificate struct {
+	serial   *big.Int
+	notAfter time.Time
+}
+
+// issuedPruneInterval bounds how long expired certificates of identities that
+// are no longer signed for stay in memory.
+const issuedPruneInterval = time.Hour
+
+func (i *issuedCertificates) record(identities []string, serial *big.Int, notAfter, now time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.byIdentity == nil {
+		i.byIdentity = map[string]map[string]issuedCertificate{}
+	}
+	if now.Sub(i.prunedAt) > issuedPruneInterval {
+		for identity := range i.byIdentity {
+			i.prune(identity, now)
+		}
+		i.prunedAt = now
+	}
+	for _, identity := range identities {
+		certs := i.byIdentity[identity]
+		if certs == nil {
+			certs = map[string]issuedCertificate{}
+			i.byIdentity[identity] = certs
+		}
+		certs[FormatSerialNumber(serial)] = issuedCertificate{serial: serial, notAfter: notAfter}
+	}
+}
+
+func (i *issuedCertificates) prune(identity string, now time.Time) {
+	certs := i.byIdentity[identity]
+	for key, cert := range certs {
+		if !now.Before(cert.notAfter) {
+			delete(certs, key)
+		}
+	}
+	if len(certs) == 0 {
+		delete(i.byIdentity, identity)
+	}
+}
+
+func (i *issuedCertificates) serials(identities []string, now time.Time) map[string][]*big.Int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := map[string][]*big.Int{}
+	for _, identity := range identities {
+		i.prune(identity, now)
+		for _, cert := range i.byIdentity[identity] {
+			out[identity] = append(out[identity], cert.serial)
+		}
+	}
+	return out
+}
+
+// IssuedSerialNumbers returns the serial numbers of the unexpired
+// certificates this CA signed for identities since it started. Certificates
+// signed by another dubbod replica, or before a restart, are not included.
+func (ca *DubboCA) IssuedSerialNumbers(identities []string, now time.Time) map[string][]*big.Int {
+	return ca.issued.serials(identities, now)
+}
+
+// IdentityRevocations returns the identities the set revokes.
+func (s *RevocationSet) IdentityRevocations() []string {
+	if s == nil {
+		return nil
+	}
+	out := make([]string, 0, len(s.identities))
+	for identity := range s.identities {
+		out = append(out, identity)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SetRevocations replaces the revocations the CA enforces while signing.
+func (ca *DubboCA) SetRevocations(set *RevocationSet) {
+	ca.revocations.Store(set)
+}
+
+// Revocations returns the revocations the CA enforces, nil when there are none.
+func (ca *DubboCA) Revocations() *RevocationSet {
+	return ca.revocations.Load()
+}
+
+func (ca *DubboCA) checkRevokedIdentities(subjectIDs []string) error {
+	set := ca.revocations.Load()
+	for _, id := range subjectIDs {
+		if set.IdentityRevoked(id) {
+			return caerror.NewError(caerror.CSRError, fmt.Errorf("identity %s is revoked", id))
+		}
+	}
+	return nil
+}
+
+// GenerateCRL returns a PEM encoded CRL listing entries, signed by the CA
+// signing certificate. The CRL number increases with now, so replicas signing
+// the same list agree on which CRL is newer.
+func (ca *DubboCA) GenerateCRL(entries []x509.RevocationListEntry, now time.Time) ([]byte, error) {
+	signingCert, signingKey, _, _ := ca.keyCertBundle.GetAll()
+	if signingCert == nil || signingKey == nil {
+		return nil, caerror.NewError(caerror.CANotReady, fmt.Errorf("Dubbo CA is not ready")) // nolint
+	}
+	if signingCert.KeyUsage != 0 && signingCert.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return nil, caerror.NewError(caerror.CAIllegalConfig,
+			fmt.Errorf("CA certificate %s may not sign CRLs, rotate it to a certificate with the cRLSign key usage",
+				signingCert.Subject))
+	}
+	signer, ok := (*signingKey).(crypto.Signer)
+	if !ok {
+		return nil, caerror.NewError(caerror.CAIllegalConfig, fmt.Errorf("CA private key cannot sign CRLs"))
+	}
+	now = now.UTC()
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		Number:                    big.NewInt(now.UnixNano()),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(CRLValidity),
+	}, signingCert, signer)
+	if err != nil {
+		return nil, caerror.NewError(caerror.CertGenError, err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
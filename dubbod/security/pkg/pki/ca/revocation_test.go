@@ -0,0 +1,195 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
+)
+
+func TestParseSerialNumber(t *testing.T) {
+	for _, in := range []string{"1a:2B:3c", "0x1a2b3c", " 1A2B3C "} {
+		serial, err := ParseSerialNumber(in)
+		if err != nil {
+			t.Fatalf("ParseSerialNumber(%q): %v", in, err)
+		}
+		if got := FormatSerialNumber(serial); got != "1a2b3c" {
+			t.Fatalf("ParseSerialNumber(%q) = %s, want 1a2b3c", in, got)
+		}
+	}
+	for _, in := range []string{"", "0", "xyz"} {
+		if _, err := ParseSerialNumber(in); err == nil {
+			t.Fatalf("ParseSerialNumber(%q) succeeded", in)
+		}
+	}
+}
+
+func TestMarshalRevocationsReplacesDuplicates(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := MarshalRevocations([]Revocation{
+		{SerialNumber: "0A:0B", Reason: "superseded", RevokedAt: at},
+		{Identity: "spiffe://cluster.local/ns/default/sa/api", RevokedAt: at},
+		{SerialNumber: "a0b", Reason: "keyCompromise", RevokedAt: at},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseRevocations(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d revocations, want 2: %s", len(got), data)
+	}
+	if got[0].Reason != "keyCompromise" || got[1].Identity == "" {
+		t.Fatalf("unexpected revocations %+v", got)
+	}
+
+	for _, bad := range []Revocation{
+		{},
+		{SerialNumber: "1", Identity: "spiffe://cluster.local/ns/a/sa/b"},
+		{Identity: "cluster.local/ns/a/sa/b"},
+		{SerialNumber: "1", Reason: "lost"},
+	} {
+		if _, err := MarshalRevocations([]Revocation{bad}); err == nil {
+			t.Fatalf("MarshalRevocations(%+v) succeeded", bad)
+		}
+	}
+}
+
+func TestRevocationSet(t *testing.T) {
+	revoked := "spiffe://cluster.local/ns/default/sa/api"
+	set := NewRevocationSet([]Revocation{
+		{SerialNumber: "ff"},
+		{Identity: revoked, Reason: "keyCompromise"},
+	})
+	uri, _ := url.Parse(revoked)
+	other, _ := url.Parse("spiffe://cluster.local/ns/default/sa/web")
+
+	if !set.CertificateRevoked(&x509.Certificate{SerialNumber: big.NewInt(255), URIs: []*url.URL{other}}) {
+		t.Fatal("certificate revoked by serial number is not revoked")
+	}
+	if !set.CertificateRevoked(&x509.Certificate{SerialNumber: big.NewInt(1), URIs: []*url.URL{uri}}) {
+		t.Fatal("certificate of a revoked identity is not revoked")
+	}
+	if set.CertificateRevoked(&x509.Certificate{SerialNumber: big.NewInt(2), URIs: []*url.URL{other}}) {
+		t.Fatal("unrelated certificate is revoked")
+	}
+	var empty *RevocationSet
+	if empty.IdentityRevoked(revoked) || empty.Entries(nil) != nil {
+		t.Fatal("nil set revokes")
+	}
+
+	entries := set.Entries(map[string][]*big.Int{revoked: {big.NewInt(16), big.NewInt(255)}})
+	if len(entries) != 2 || entries[0].SerialNumber.Int64() != 16 || entries[1].SerialNumber.Int64() != 255 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entries[0].ReasonCode != 1 {
+		t.Fatalf("reason code = %d, want 1", entries[0].ReasonCode)
+	}
+}
+
+func TestGenerateCRL(t *testing.T) {
+	rootCert, rootKey := genRoot(t)
+	bundle, err := util.NewVerifiedKeyCertBundleFromPem(rootCert, rootKey, nil, rootCert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &DubboCA{keyCertBundle: bundle, defaultCertTTL: time.Hour, maxCertTTL: time.Hour}
+
+	now := time.Now()
+	crlPEM, err := ca.GenerateCRL([]x509.RevocationListEntry{{SerialNumber: big.NewInt(42), RevocationTime: now}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(crlPEM)
+	if block == nil || block.Type != "X509 CRL" {
+		t.Fatalf("unexpected CRL PEM %q", crlPEM)
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingCert, _, _, _ := bundle.GetAll()
+	if err := crl.CheckSignatureFrom(signingCert); err != nil {
+		t.Fatalf("CRL is not signed by the CA: %v", err)
+	}
+	if len(crl.RevokedCertificateEntries) != 1 || crl.RevokedCertificateEntries[0].SerialNumber.Int64() != 42 {
+		t.Fatalf("unexpected CRL entries %+v", crl.RevokedCertificateEntries)
+	}
+	if err := util.Verify(rootCert, rootKey, nil, rootCert, crlPEM); err != nil {
+		t.Fatalf("bundle with generated CRL does not verify: %v", err)
+	}
+}
+
+func TestSignRefusesRevokedIdentity(t *testing.T) {
+	rootCert, rootKey := genRoot(t)
+	bundle, err := util.NewVerifiedKeyCertBundleFromPem(rootCert, rootKey, nil, rootCert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &DubboCA{keyCertBundle: bundle, defaultCertTTL: time.Hour, maxCertTTL: time.Hour}
+	identity := "spiffe://cluster.local/ns/default/sa/api"
+	csr, _, err := util.GenCSR(util.CertOptions{Host: identity, RSAKeySize: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ca.Sign(csr, CertOpts{SubjectIDs: []string{identity}, TTL: time.Minute}); err != nil {
+		t.Fatalf("Sign() before revocation: %v", err)
+	}
+	ca.SetRevocations(NewRevocationSet([]Revocation{{Identity: identity}}))
+	if _, err := ca.Sign(csr, CertOpts{SubjectIDs: []string{identity}, TTL: time.Minute}); err == nil {
+		t.Fatal("Sign() issued a certificate for a revoked identity")
+	}
+}
+
+func TestIssuedSerialNumbers(t *testing.T) {
+	rootCert, rootKey := genRoot(t)
+	bundle, err := util.NewVerifiedKeyCertBundleFromPem(rootCert, rootKey, nil, rootCert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &DubboCA{keyCertBundle: bundle, defaultCertTTL: time.Hour, maxCertTTL: time.Hour}
+	identity := "spiffe://cluster.local/ns/default/sa/api"
+	csr, _, err := util.GenCSR(util.CertOptions{Host: identity, RSAKeySize: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := ca.Sign(csr, CertOpts{SubjectIDs: []string{identity}, TTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := util.ParsePemEncodedCertificate(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issued := ca.IssuedSerialNumbers([]string{identity, "spiffe://cluster.local/ns/default/sa/other"}, time.Now())
+	if len(issued) != 1 || len(issued[identity]) != 1 || issued[identity][0].Cmp(cert.SerialNumber) != 0 {
+		t.Fatalf("issued = %v, want serial %s for %s", issued, FormatSerialNumber(cert.SerialNumber), identity)
+	}
+	if issued := ca.IssuedSerialNumbers([]string{identity}, cert.NotAfter); len(issued) != 0 {
+		t.Fatalf("issued after expiry = %v, want none", issued)
+	}
+}
@@ -0,0 +1,185 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
+)
+
+// RootRotationPhase is a stage of the staged self-signed root rotation. Each
+// phase only advances once every workload has converged on the previous one,
+// so no peer is ever asked to trust a root it has not received yet.
+type RootRotationPhase string
+
+const (
+	// RootRotationIdle means no rotation is in progress.
+	RootRotationIdle RootRotationPhase = "Idle"
+	// RootRotationDistributing trusts the old and the new root together while
+	// the old root still signs.
+	RootRotationDistributing RootRotationPhase = "DistributingBundle"
+	// RootRotationReissuing signs with the new root and re-issues every leaf
+	// certificate, while both roots stay trusted.
+	RootRotationReissuing RootRotationPhase = "ReissuingCertificates"
+	// RootRotationRetiring drops the old root from the trust bundle.
+	RootRotationRetiring RootRotationPhase = "RetiringOldRoot"
+)
+
+// RootRotationPhases lists the phases in the order a rotation walks them.
+var RootRotationPhases = []RootRotationPhase{
+	RootRotationIdle,
+	RootRotationDistributing,
+	RootRotationReissuing,
+	RootRotationRetiring,
+}
+
+const (
+	// Keys of the CA secret holding rotation state next to the signing key.
+	NextCACertFile       = "next-ca-cert.pem"
+	NextCAPrivateKeyFile = "next-ca-key.pem"
+	PreviousCACertFile   = "previous-ca-cert.pem"
+	RootRotationPhaseKey = "root-rotation-phase"
+
+	// RootRotationStatusConfigMap is the status resource describing the rotation.
+	RootRotationStatusConfigMap = "dubbo-ca-root-rotation"
+	RootRotationStatusKey       = "status"
+	// RootRotationRequestAnnotation on the status ConfigMap asks for a rotation
+	// ahead of the root's grace period. Its value identifies the request.
+	RootRotationRequestAnnotation = "dubbo.apache.org/root-rotation-requested"
+
+	rootRotationPendingLimit = 20
+)
+
+// RootRotationStatus is published on the status ConfigMap after every check.
+type RootRotationStatus struct {
+	Phase RootRotationPhase `json:"phase"`
+	// Reason is why the current rotation started: RootExpiring or Requested.
+	Reason             string     `json:"reason,omitempty"`
+	RotationStartedAt  *time.Time `json:"rotationStartedAt,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+	LastCompletedAt    *time.Time `json:"lastCompletedAt,omitempty"`
+	RootExpiresAt      *time.Time `json:"rootExpiresAt,omitempty"`
+	NextRootExpiresAt  *time.Time `json:"nextRootExpiresAt,omitempty"`
+	// PendingWorkloads counts workloads that have not converged on the current
+	// phase; Pending names the first of them.
+	PendingWorkloads int      `json:"pendingWorkloads"`
+	Pending          []string `json:"pending,omitempty"`
+	Message          string   `json:"message,omitempty"`
+	// HandledRequest is the last RootRotationRequestAnnotation value acted on.
+	HandledRequest string `json:"handledRequest,omitempty"`
+}
+
+// RootRotationProgress returns the workloads that do not hold trustBundle yet
+// or, when signingRoot is set, whose leaf certificate is not signed by it.
+type RootRotationProgress func(trustBundle, signingRoot []byte) ([]string, error)
+
+func currentRootRotationPhase(data map[string][]byte) RootRotationPhase {
+	phase := RootRotationPhase(data[RootRotationPhaseKey])
+	for _, known := range RootRotationPhases {
+		if phase == known {
+			return phase
+		}
+	}
+	return RootRotationIdle
+}
+
+// rootBundleFromSecret returns the trusted roots recorded in the CA secret.
+// Outside a rotation only the signing certificate is trusted.
+func rootBundleFromSecret(data map[string][]byte) []byte {
+	if currentRootRotationPhase(data) != RootRotationIdle && len(data[RootCertFile]) > 0 {
+		return data[RootCertFile]
+	}
+	return data[CACertFile]
+}
+
+func nextRootRotationPhase(phase RootRotationPhase) RootRotationPhase {
+	for i, known := range RootRotationPhases {
+		if known == phase && i+1 < len(RootRotationPhases) {
+			return RootRotationPhases[i+1]
+		}
+	}
+	return RootRotationIdle
+}
+
+// advanceRootRotation returns a copy of the CA secret data moved to phase next.
+// nextCert and nextKey are only used when a rotation starts.
+func advanceRootRotation(data map[string][]byte, next RootRotationPhase, nextCert, nextKey []byte) map[string][]byte {
+	out := make(map[string][]byte, len(data)+4)
+	for k, v := range data {
+		out[k] = v
+	}
+	switch next {
+	case RootRotationDistributing:
+		out[NextCACertFile] = nextCert
+		out[NextCAPrivateKeyFile] = nextKey
+		out[RootCertFile] = joinRoots(data[CACertFile], nextCert)
+	case RootRotationReissuing:
+		out[PreviousCACertFile] = data[CACertFile]
+		out[CACertFile] = data[NextCACertFile]
+		out[CAPrivateKeyFile] = data[NextCAPrivateKeyFile]
+		out[RootCertFile] = joinRoots(data[NextCACertFile], data[CACertFile])
+		delete(out, NextCACertFile)
+		delete(out, NextCAPrivateKeyFile)
+	case RootRotationRetiring:
+		out[RootCertFile] = data[CACertFile]
+		delete(out, PreviousCACertFile)
+	case RootRotationIdle:
+		out[RootCertFile] = data[CACertFile]
+		delete(out, RootRotationPhaseKey)
+		return out
+	}
+	out[RootRotationPhaseKey] = []byte(next)
+	return out
+}
+
+func fillRootExpiry(status *RootRotationStatus, data map[string][]byte) {
+	status.RootExpiresAt = certExpiry(data[CACertFile])
+	status.NextRootExpiresAt = certExpiry(data[NextCACertFile])
+}
+
+func certExpiry(certPEM []byte) *time.Time {
+	if len(certPEM) == 0 {
+		return nil
+	}
+	cert, err := util.ParsePemEncodedCertificate(certPEM)
+	if err != nil {
+		return nil
+	}
+	return &cert.NotAfter
+}
+
+func joinRoots(roots ...[]byte) []byte {
+	var out []byte
+	for _, root := range roots {
+		root = bytes.TrimSpace(root)
+		if len(root) == 0 || bytes.Contains(out, root) {
+			continue
+		}
+		out = append(out, root...)
+		out = append(out, '\n')
+	}
+	return out
+}
+
+func limitPending(pending []string) []string {
+	if len(pending) <= rootRotationPendingLimit {
+		return pending
+	}
+	return pending[:rootRotationPendingLimit]
+}
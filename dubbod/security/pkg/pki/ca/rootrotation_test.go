@@ -0,0 +1,131 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
+)
+
+func genRoot(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	cert, key, err := util.GenCertKeyFromOptions(util.CertOptions{
+		TTL:          time.Hour,
+		Org:          "cluster.local",
+		IsCA:         true,
+		IsSelfSigned: true,
+		RSAKeySize:   2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestAdvanceRootRotation(t *testing.T) {
+	oldCert, oldKey := genRoot(t)
+	newCert, newKey := genRoot(t)
+	data := map[string][]byte{
+		CACertFile:       oldCert,
+		CAPrivateKeyFile: oldKey,
+		RootCertFile:     oldCert,
+	}
+
+	phase := RootRotationIdle
+	var seen []RootRotationPhase
+	for {
+		next := nextRootRotationPhase(phase)
+		data = advanceRootRotation(data, next, newCert, newKey)
+		phase = currentRootRotationPhase(data)
+		if phase != next {
+			t.Fatalf("phase = %s, want %s", phase, next)
+		}
+		seen = append(seen, phase)
+
+		roots := rootBundleFromSecret(data)
+		signer := data[CACertFile]
+		switch phase {
+		case RootRotationDistributing:
+			if !bytes.Equal(signer, oldCert) {
+				t.Fatalf("%s: old root must still sign", phase)
+			}
+			if !bytes.Contains(roots, bytes.TrimSpace(oldCert)) || !bytes.Contains(roots, bytes.TrimSpace(newCert)) {
+				t.Fatalf("%s: bundle must trust both roots", phase)
+			}
+		case RootRotationReissuing:
+			if !bytes.Equal(signer, newCert) || !bytes.Equal(data[CAPrivateKeyFile], newKey) {
+				t.Fatalf("%s: new root must sign", phase)
+			}
+			if !bytes.Contains(roots, bytes.TrimSpace(oldCert)) {
+				t.Fatalf("%s: old root must stay trusted", phase)
+			}
+			if _, ok := data[NextCACertFile]; ok {
+				t.Fatalf("%s: next root should be promoted", phase)
+			}
+		case RootRotationRetiring, RootRotationIdle:
+			if !bytes.Equal(roots, newCert) {
+				t.Fatalf("%s: only the new root should be trusted", phase)
+			}
+			if _, ok := data[PreviousCACertFile]; ok {
+				t.Fatalf("%s: previous root should be dropped", phase)
+			}
+		}
+		if phase == RootRotationIdle {
+			break
+		}
+	}
+	want := []RootRotationPhase{RootRotationDistributing, RootRotationReissuing, RootRotationRetiring, RootRotationIdle}
+	if len(seen) != len(want) {
+		t.Fatalf("phases = %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("phases = %v, want %v", seen, want)
+		}
+	}
+	if _, ok := data[RootRotationPhaseKey]; ok {
+		t.Fatal("phase key should be removed once idle")
+	}
+}
+
+func TestRootBundleFromSecret(t *testing.T) {
+	data := map[string][]byte{
+		CACertFile:   []byte("signing"),
+		RootCertFile: []byte("bundle"),
+	}
+	if got := rootBundleFromSecret(data); string(got) != "signing" {
+		t.Fatalf("idle bundle = %q, want signing cert", got)
+	}
+	data[RootRotationPhaseKey] = []byte("Unknown")
+	if got := rootBundleFromSecret(data); string(got) != "signing" {
+		t.Fatalf("unknown phase bundle = %q, want signing cert", got)
+	}
+	data[RootRotationPhaseKey] = []byte(RootRotationReissuing)
+	if got := rootBundleFromSecret(data); string(got) != "bundle" {
+		t.Fatalf("rotating bundle = %q, want root-cert.pem", got)
+	}
+}
+
+func TestJoinRoots(t *testing.T) {
+	got := joinRoots([]byte("a\n"), nil, []byte("b"), []byte("a"))
+	if string(got) != "a\nb\n" {
+		t.Fatalf("joinRoots = %q", got)
+	}
+}
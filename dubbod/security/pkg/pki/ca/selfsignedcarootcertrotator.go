@@ -17,12 +17,20 @@
 package ca
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"math/rand"
 	"time"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/k8s/controller"
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
 	certutil "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/util"
 	"github.com/apache/dubbo-kubernetes/pkg/log"
+	v1 "k8s.io/api/core/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -37,8 +45,10 @@ type SelfSignedCARootCertRotatorConfig struct {
 	client             corev1.CoreV1Interface
 	CheckInterval      time.Duration
 	caCertTTL          time.Duration
+	workloadCertTTL    time.Duration
 	retryInterval      time.Duration
 	retryMax           time.Duration
+	caRSAKeySize       int
 	dualUse            bool
 	enableJitter       bool
 }
@@ -49,14 +59,18 @@ type SelfSignedCARootCertRotator struct {
 	backOffTime        time.Duration
 	ca                 *DubboCA
 	onRootCertUpdate   func() error
+	progress           RootRotationProgress
 }
 
-func NewSelfSignedCARootCertRotator(config *SelfSignedCARootCertRotatorConfig, ca *DubboCA, onRootCertUpdate func() error) *SelfSignedCARootCertRotator {
+func NewSelfSignedCARootCertRotator(config *SelfSignedCARootCertRotatorConfig, ca *DubboCA,
+	onRootCertUpdate func() error, progress RootRotationProgress,
+) *SelfSignedCARootCertRotator {
 	rotator := &SelfSignedCARootCertRotator{
 		caSecretController: controller.NewCaSecretController(config.client),
 		config:             config,
 		ca:                 ca,
 		onRootCertUpdate:   onRootCertUpdate,
+		progress:           progress,
 	}
 	if config.enableJitter {
 		// Select a back off time in seconds, which is in the range of [0, rotator.config.CheckInterval).
@@ -89,7 +103,7 @@ func (rotator *SelfSignedCARootCertRotator) Run(stopCh chan struct{}) {
 		select {
 		case <-ticker.C:
 			rootCertRotatorLog.Info("Check and rotate root cert.")
-			// TODO rotator.checkAndRotateRootCert()
+			rotator.checkAndRotateRootCert()
 		case _, ok := <-stopCh:
 			if !ok {
 				rootCertRotatorLog.Info("Received stop signal, so stop the root cert rotator.")
@@ -101,3 +115,205 @@ func (rotator *SelfSignedCARootCertRotator) Run(stopCh chan struct{}) {
 		}
 	}
 }
+
+// checkAndRotateRootCert moves the staged root rotation forward by at most one
+// phase. The CA secret is the source of truth, so every replica first adopts
+// whatever the secret holds and only the replica winning the secret update
+// advances the rotation.
+func (rotator *SelfSignedCARootCertRotator) checkAndRotateRootCert() {
+	secrets := rotator.config.client.Secrets(rotator.config.caStorageNamespace)
+	caSecret, err := secrets.Get(context.TODO(), rotator.config.secretName, metav1.GetOptions{})
+	if err != nil {
+		rootCertRotatorLog.Errorf("Failed to get CA secret %s/%s (%v)",
+			rotator.config.caStorageNamespace, rotator.config.secretName, err)
+		return
+	}
+	if err := rotator.syncKeyCertBundle(caSecret.Data); err != nil {
+		rootCertRotatorLog.Errorf("Failed to load CA secret %s/%s (%v)",
+			rotator.config.caStorageNamespace, rotator.config.secretName, err)
+		return
+	}
+
+	status, request := rotator.loadStatus()
+	now := time.Now()
+	current := caSecret.Data
+	data, err := rotator.nextRotationStep(caSecret.Data, &status, request, now)
+	if err != nil {
+		status.Message = err.Error()
+		rootCertRotatorLog.Errorf("Root cert rotation failed in phase %s (%v)", status.Phase, err)
+	}
+	if data != nil {
+		caSecret = caSecret.DeepCopy()
+		caSecret.Data = data
+		if _, err := secrets.Update(context.TODO(), caSecret, metav1.UpdateOptions{}); err != nil {
+			// A conflict means another replica advanced the rotation; pick it up on the next tick.
+			rootCertRotatorLog.Warnf("Failed to update CA secret %s/%s, retry on next check (%v)",
+				caSecret.Namespace, caSecret.Name, err)
+			return
+		}
+		rootCertRotatorLog.Infof("Root cert rotation moved to phase %s", status.Phase)
+		status.LastTransitionTime = &now
+		current = data
+		if err := rotator.syncKeyCertBundle(data); err != nil {
+			rootCertRotatorLog.Errorf("Failed to load rotated CA secret (%v)", err)
+		}
+	}
+	fillRootExpiry(&status, current)
+	rotator.writeStatus(status)
+}
+
+// nextRotationStep returns the CA secret data of the next phase, or nil when
+// the rotation has to stay in the current one. status is updated in place.
+func (rotator *SelfSignedCARootCertRotator) nextRotationStep(data map[string][]byte,
+	status *RootRotationStatus, request string, now time.Time,
+) (map[string][]byte, error) {
+	phase := currentRootRotationPhase(data)
+	status.Phase = phase
+	status.Message = ""
+	status.PendingWorkloads = 0
+	status.Pending = nil
+
+	if phase == RootRotationIdle {
+		reason := ""
+		if _, err := rotator.config.certInspector.GetWaitTime(data[CACertFile], now); err != nil {
+			reason = "RootExpiring"
+			rootCertRotatorLog.Infof("Root cert needs rotation: %v", err)
+		} else if request != "" && request != status.HandledRequest {
+			reason = "Requested"
+		}
+		if reason == "" {
+			return nil, nil
+		}
+		status.HandledRequest = request
+		nextCert, nextKey, err := util.GenCertKeyFromOptions(util.CertOptions{
+			TTL:          rotator.config.caCertTTL,
+			Org:          rotator.config.org,
+			IsCA:         true,
+			IsSelfSigned: true,
+			RSAKeySize:   rotator.config.caRSAKeySize,
+			IsDualUse:    rotator.config.dualUse,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate next root cert and key (%v)", err)
+		}
+		status.Phase = RootRotationDistributing
+		status.Reason = reason
+		status.RotationStartedAt = &now
+		return advanceRootRotation(data, RootRotationDistributing, nextCert, nextKey), nil
+	}
+
+	// Requests made while a rotation is running are satisfied by it.
+	status.HandledRequest = request
+	var signingRoot []byte
+	if phase != RootRotationDistributing {
+		signingRoot = data[CACertFile]
+	}
+	if phase == RootRotationReissuing {
+		// Workloads requesting certificates through the CA server are not
+		// tracked, so give them one full certificate lifetime to renew.
+		if status.LastTransitionTime == nil {
+			status.LastTransitionTime = &now
+		}
+		if wait := status.LastTransitionTime.Add(rotator.config.workloadCertTTL); now.Before(wait) {
+			status.Message = fmt.Sprintf("waiting until %s for workload certificates to renew", wait.Format(time.RFC3339))
+			return nil, nil
+		}
+	}
+	if rotator.progress != nil {
+		// Compare with the roots in use, which include any extra root cert file.
+		pending, err := rotator.progress(rotator.ca.GetCAKeyCertBundle().GetRootCertPem(), signingRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check workload progress (%v)", err)
+		}
+		if len(pending) > 0 {
+			status.PendingWorkloads = len(pending)
+			status.Pending = limitPending(pending)
+			status.Message = fmt.Sprintf("waiting for %d workloads before leaving phase %s", len(pending), phase)
+			return nil, nil
+		}
+	}
+
+	next := nextRootRotationPhase(phase)
+	status.Phase = next
+	if next == RootRotationIdle {
+		status.LastCompletedAt = &now
+		status.RotationStartedAt = nil
+	}
+	return advanceRootRotation(data, next, nil, nil), nil
+}
+
+// syncKeyCertBundle loads the signing key and trusted roots recorded in the CA
+// secret if they differ from the ones in use.
+func (rotator *SelfSignedCARootCertRotator) syncKeyCertBundle(data map[string][]byte) error {
+	rootCerts, err := util.AppendRootCerts(rootBundleFromSecret(data), rotator.config.rootCertFile)
+	if err != nil {
+		return fmt.Errorf("failed to append root certificates (%v)", err)
+	}
+	bundle := rotator.ca.GetCAKeyCertBundle()
+	cert, key, _, roots := bundle.GetAllPem()
+	if bytes.Equal(cert, data[CACertFile]) && bytes.Equal(key, data[CAPrivateKeyFile]) && bytes.Equal(roots, rootCerts) {
+		return nil
+	}
+	if err := bundle.VerifyAndSetAll(data[CACertFile], data[CAPrivateKeyFile], nil, rootCerts, nil); err != nil {
+		return err
+	}
+	rootCertRotatorLog.Infof("Loaded CA key and roots from secret: %s", certificateSummary(rootCerts))
+	if rotator.onRootCertUpdate != nil {
+		if err := rotator.onRootCertUpdate(); err != nil {
+			rootCertRotatorLog.Errorf("Failed to run root cert update callback (%v)", err)
+		}
+	}
+	return nil
+}
+
+// loadStatus returns the last published status and the pending manual request.
+func (rotator *SelfSignedCARootCertRotator) loadStatus() (RootRotationStatus, string) {
+	status := RootRotationStatus{Phase: RootRotationIdle}
+	cm, err := rotator.config.client.ConfigMaps(rotator.config.caStorageNamespace).
+		Get(context.TODO(), RootRotationStatusConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if !apierror.IsNotFound(err) {
+			rootCertRotatorLog.Warnf("Failed to get root rotation status (%v)", err)
+		}
+		return status, ""
+	}
+	if raw := cm.Data[RootRotationStatusKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &status); err != nil {
+			rootCertRotatorLog.Warnf("Ignoring malformed root rotation status (%v)", err)
+		}
+	}
+	return status, cm.Annotations[RootRotationRequestAnnotation]
+}
+
+func (rotator *SelfSignedCARootCertRotator) writeStatus(status RootRotationStatus) {
+	raw, err := json.Marshal(status)
+	if err != nil {
+		rootCertRotatorLog.Errorf("Failed to encode root rotation status (%v)", err)
+		return
+	}
+	configMaps := rotator.config.client.ConfigMaps(rotator.config.caStorageNamespace)
+	cm, err := configMaps.Get(context.TODO(), RootRotationStatusConfigMap, metav1.GetOptions{})
+	if apierror.IsNotFound(err) {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      RootRotationStatusConfigMap,
+				Namespace: rotator.config.caStorageNamespace,
+			},
+			Data: map[string]string{RootRotationStatusKey: string(raw)},
+		}
+		_, err = configMaps.Create(context.TODO(), cm, metav1.CreateOptions{})
+	} else if err == nil {
+		if cm.Data[RootRotationStatusKey] == string(raw) {
+			return
+		}
+		cm = cm.DeepCopy()
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[RootRotationStatusKey] = string(raw)
+		_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		rootCertRotatorLog.Warnf("Failed to publish root rotation status (%v)", err)
+	}
+}
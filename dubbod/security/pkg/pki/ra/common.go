@@ -44,6 +44,9 @@ type RegistrationAuthority interface {
 	caserver.CertificateAuthority
 	SetCACertificatesFromMeshConfig([]*meshv1alpha1.MeshConfig_CertificateData)
 	GetRootCertFromMeshConfig(signerName string) ([]byte, error)
+	// GetTrustBundle returns every root workloads signed through this RA may
+	// chain to, so peers using different signers still trust each other.
+	GetTrustBundle() []byte
 }
 
 type DubboRAOptions struct {
@@ -56,6 +59,9 @@ type DubboRAOptions struct {
 	K8sClient        clientset.Interface
 	TrustDomain      string
 	CertSignerDomain string
+	// ApproveCSR makes the RA approve the CSRs it submits. Disable it when an
+	// external approver, such as cert-manager approver-policy, owns approval.
+	ApproveCSR bool
 }
 
 // NewDubboRA is a factory method that returns an RA that implements the RegistrationAuthority functionality.
@@ -82,7 +88,10 @@ func (r *KubernetesRA) SignWithCertChain(csrPEM []byte, certOpts ca.CertOpts) ([
 	}
 	respCertChain := []string{string(cert)}
 	var possibleRootCert, rootCertFromMeshConfig, rootCertFromCertChain []byte
-	certSigner := r.certSignerDomain + "/" + certOpts.CertSigner
+	certSigner, err := r.signerName(certOpts.CertSigner)
+	if err != nil {
+		return nil, raerror.NewError(raerror.CertGenError, err)
+	}
 	if len(r.GetCAKeyCertBundle().GetRootCertPem()) == 0 {
 		rootCertFromCertChain, err = util.FindRootCertFromCertificateChainBytes(cert)
 		if err != nil {
@@ -114,6 +123,12 @@ func (r *KubernetesRA) GetCAKeyCertBundle() *util.KeyCertBundle {
 	return r.keyCertBundle
 }
 
+func (r *KubernetesRA) GetTrustBundle() []byte {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return mergeTrustBundle(r.GetCAKeyCertBundle().GetRootCertPem(), r.caCertificatesFromMeshConfig)
+}
+
 func (r *KubernetesRA) GetRootCertFromMeshConfig(signerName string) ([]byte, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
@@ -136,7 +151,9 @@ func (r *KubernetesRA) GetRootCertFromMeshConfig(signerName string) ([]byte, err
 }
 
 func (r *KubernetesRA) SetCACertificatesFromMeshConfig(caCertificates []*meshv1alpha1.MeshConfig_CertificateData) {
-	r.mutex.Lock()
+	// Rebuild rather than merge so roots removed from mesh config also leave
+	// the trust bundle.
+	certificates := make(map[string]string, len(caCertificates))
 	for _, pemCert := range caCertificates {
 		// TODO:  take care of spiffe bundle format as well
 		cert := pemCert.GetPem()
@@ -144,10 +161,12 @@ func (r *KubernetesRA) SetCACertificatesFromMeshConfig(caCertificates []*meshv1a
 		if len(certSigners) != 0 {
 			certSigner := strings.Join(certSigners, ",")
 			if cert != "" {
-				r.caCertificatesFromMeshConfig[certSigner] = cert
+				certificates[certSigner] = cert
 			}
 		}
 	}
+	r.mutex.Lock()
+	r.caCertificatesFromMeshConfig = certificates
 	r.mutex.Unlock()
 }
 
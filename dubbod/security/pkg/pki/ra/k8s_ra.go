@@ -70,14 +70,9 @@ func (r *KubernetesRA) Sign(csrPEM []byte, certOpts ca.CertOpts) ([]byte, error)
 }
 
 func (r *KubernetesRA) kubernetesSign(csrPEM []byte, caCertFile string, certSigner string, requestedLifetime time.Duration) ([]byte, error) {
-	certSignerDomain := r.certSignerDomain
-	if certSignerDomain == "" && certSigner != "" {
-		return nil, raerror.NewError(raerror.CertGenError, fmt.Errorf("certSignerDomain is required for signer %s", certSigner))
-	}
-	if certSignerDomain != "" && certSigner != "" {
-		certSigner = certSignerDomain + "/" + certSigner
-	} else {
-		certSigner = r.raOpts.CaSigner
+	signerName, err := r.signerName(certSigner)
+	if err != nil {
+		return nil, raerror.NewError(raerror.CertGenError, err)
 	}
 	usages := []cert.KeyUsage{
 		cert.UsageDigitalSignature,
@@ -85,9 +80,21 @@ func (r *KubernetesRA) kubernetesSign(csrPEM []byte, caCertFile string, certSign
 		cert.UsageServerAuth,
 		cert.UsageClientAuth,
 	}
-	certChain, _, err := chiron.SignCSRK8s(r.csrInterface, csrPEM, certSigner, usages, "", caCertFile, true, false, requestedLifetime)
+	certChain, _, err := chiron.SignCSRK8s(r.csrInterface, csrPEM, signerName, usages, "", caCertFile, r.raOpts.ApproveCSR, false, requestedLifetime)
 	if err != nil {
 		return nil, raerror.NewError(raerror.CertGenError, err)
 	}
 	return certChain, err
 }
+
+// signerName resolves the signer requested for a CSR, falling back to the
+// RA's configured signer.
+func (r *KubernetesRA) signerName(certSigner string) (string, error) {
+	if certSigner == "" {
+		certSigner = r.raOpts.CaSigner
+	}
+	if certSigner == "" {
+		return "", fmt.Errorf("no signer is configured for the Kubernetes RA")
+	}
+	return ResolveSignerName(certSigner, r.certSignerDomain)
+}
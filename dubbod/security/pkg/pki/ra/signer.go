@@ -0,0 +1,112 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ra
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+)
+
+const (
+	certManagerIssuerPrefix        = "issuer:"
+	certManagerClusterIssuerPrefix = "clusterissuer:"
+	certManagerIssuerSigner        = "issuers.cert-manager.io"
+	certManagerClusterIssuerSigner = "clusterissuers.cert-manager.io"
+)
+
+// ResolveSignerName turns a signer reference into a Kubernetes CSR signer name.
+// A reference is one of:
+//   - a full signer name such as "example.com/corp-pki", used as is;
+//   - "clusterissuer:<name>" or "issuer:<namespace>/<name>", naming a cert-manager
+//     issuer through its CertificateSigningRequest integration;
+//   - a bare name, qualified with certSignerDomain.
+//
+// References come from dubbod's own configuration, K8S_SIGNER or mesh config
+// caCertificates, never from the workload asking for a certificate.
+func ResolveSignerName(signer, certSignerDomain string) (string, error) {
+	signer = strings.TrimSpace(signer)
+	switch {
+	case signer == "":
+		return "", fmt.Errorf("signer name is empty")
+	case strings.HasPrefix(signer, certManagerClusterIssuerPrefix):
+		name := strings.TrimPrefix(signer, certManagerClusterIssuerPrefix)
+		if name == "" || strings.Contains(name, "/") {
+			return "", fmt.Errorf("invalid cert-manager cluster issuer reference %q", signer)
+		}
+		return certManagerClusterIssuerSigner + "/" + name, nil
+	case strings.HasPrefix(signer, certManagerIssuerPrefix):
+		ns, name, ok := strings.Cut(strings.TrimPrefix(signer, certManagerIssuerPrefix), "/")
+		if !ok || ns == "" || name == "" || strings.Contains(name, "/") {
+			return "", fmt.Errorf("invalid cert-manager issuer reference %q, want issuer:<namespace>/<name>", signer)
+		}
+		return certManagerIssuerSigner + "/" + ns + "." + name, nil
+	case strings.Contains(signer, "/"):
+		return signer, nil
+	case certSignerDomain == "":
+		return "", fmt.Errorf("certSignerDomain is required for signer %s", signer)
+	default:
+		return certSignerDomain + "/" + signer, nil
+	}
+}
+
+// SignerForNamespace returns the signer of the first mesh config caCertificates
+// entry listing namespace, or "" when the registration authority default
+// applies. Keeping the selection on the entry that also carries the signer's
+// root means a namespace is never moved to a signer its peers do not trust.
+func SignerForNamespace(caCertificates []*meshv1alpha1.MeshConfig_CertificateData, namespace string) string {
+	for _, entry := range caCertificates {
+		if len(entry.GetCertSigners()) == 0 {
+			continue
+		}
+		for _, ns := range entry.GetNamespaces() {
+			if ns == namespace {
+				return strings.TrimSpace(entry.GetCertSigners()[0])
+			}
+		}
+	}
+	return ""
+}
+
+// mergeTrustBundle concatenates the distinct PEM roots, keeping the first one
+// in front so it stays the preferred root for older clients.
+func mergeTrustBundle(first []byte, others map[string]string) []byte {
+	bundle := strings.TrimSpace(string(first))
+	seen := map[string]bool{bundle: true}
+	keys := make([]string, 0, len(others))
+	for k := range others {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		root := strings.TrimSpace(others[k])
+		if root == "" || seen[root] {
+			continue
+		}
+		seen[root] = true
+		if bundle != "" {
+			bundle += "\n"
+		}
+		bundle += root
+	}
+	if bundle == "" {
+		return nil
+	}
+	return []byte(bundle + "\n")
+}
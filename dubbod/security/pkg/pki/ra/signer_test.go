@@ -0,0 +1,90 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ra
+
+import (
+	"strings"
+	"testing"
+
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+)
+
+func TestResolveSignerName(t *testing.T) {
+	cases := []struct {
+		signer  string
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{signer: "example.com/corp-pki", want: "example.com/corp-pki"},
+		{signer: "corp-pki", domain: "example.com", want: "example.com/corp-pki"},
+		{signer: "corp-pki", wantErr: true},
+		{signer: "clusterissuer:corp-pki", want: "clusterissuers.cert-manager.io/corp-pki"},
+		{signer: "issuer:payments/pci", want: "issuers.cert-manager.io/payments.pci"},
+		{signer: "issuer:pci", wantErr: true},
+		{signer: "", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := ResolveSignerName(tc.signer, tc.domain)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("ResolveSignerName(%q, %q) = %q, want error", tc.signer, tc.domain, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("ResolveSignerName(%q, %q) = %q, %v, want %q", tc.signer, tc.domain, got, err, tc.want)
+		}
+	}
+}
+
+func TestSignerForNamespace(t *testing.T) {
+	caCertificates := []*meshv1alpha1.MeshConfig_CertificateData{
+		{CertSigners: []string{"clusterissuer:corp"}},
+		{CertSigners: []string{"issuer:payments/pci"}, Namespaces: []string{"payments"}},
+		{CertSigners: []string{"batch-signer"}, Namespaces: []string{"batch", "payments"}},
+		{Namespaces: []string{"orphan"}},
+	}
+	if got := SignerForNamespace(caCertificates, "payments"); got != "issuer:payments/pci" {
+		t.Fatalf("payments signer = %q", got)
+	}
+	if got := SignerForNamespace(caCertificates, "batch"); got != "batch-signer" {
+		t.Fatalf("batch signer = %q", got)
+	}
+	if got := SignerForNamespace(caCertificates, "default"); got != "" {
+		t.Fatalf("default signer = %q, want the registration authority default", got)
+	}
+	if got := SignerForNamespace(caCertificates, "orphan"); got != "" {
+		t.Fatalf("signer for an entry without signers = %q, want empty", got)
+	}
+	if got := SignerForNamespace(nil, "default"); got != "" {
+		t.Fatalf("signer without mesh config = %q, want empty", got)
+	}
+}
+
+func TestMergeTrustBundleDeduplicatesRoots(t *testing.T) {
+	got := string(mergeTrustBundle([]byte("root-a\n"), map[string]string{
+		"example.com/b": "root-b",
+		"example.com/a": "root-a",
+	}))
+	if got != "root-a\nroot-b\n" {
+		t.Fatalf("bundle = %q", got)
+	}
+	if strings.TrimSpace(string(mergeTrustBundle(nil, nil))) != "" {
+		t.Fatal("empty bundle is not empty")
+	}
+}
@@ -0,0 +1,124 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/k8s/tokenreview"
+	"github.com/apache/dubbo-kubernetes/pkg/config/mesh"
+	"github.com/apache/dubbo-kubernetes/pkg/security"
+	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	KubeJWTAuthenticatorType = "KubeJWTAuthenticator"
+)
+
+// KubeJWTAuthenticator authenticates callers by the projected service account
+// token they present, so workloads without a certificate yet can request one.
+type KubeJWTAuthenticator struct {
+	meshHolder mesh.Holder
+	client     kubernetes.Interface
+	audiences  []string
+}
+
+var _ security.Authenticator = &KubeJWTAuthenticator{}
+
+// NewKubeJWTAuthenticator creates an authenticator that reviews tokens against
+// client. Tokens must be issued for one of audiences or for the mesh trust domain.
+func NewKubeJWTAuthenticator(meshHolder mesh.Holder, client kubernetes.Interface, audiences []string) *KubeJWTAuthenticator {
+	return &KubeJWTAuthenticator{
+		meshHolder: meshHolder,
+		client:     client,
+		audiences:  audiences,
+	}
+}
+
+func (a *KubeJWTAuthenticator) AuthenticatorType() string {
+	return KubeJWTAuthenticatorType
+}
+
+// Authenticate validates the bearer token through the TokenReview API and maps
+// the service account it was issued to onto a SPIFFE identity in the mesh trust
+// domain.
+func (a *KubeJWTAuthenticator) Authenticate(authCtx security.AuthContext) (*security.Caller, error) {
+	var token string
+	var err error
+	ctx := context.Background()
+	switch {
+	case authCtx.GrpcContext != nil:
+		ctx = authCtx.GrpcContext
+		token, err = security.ExtractBearerToken(authCtx.GrpcContext)
+	case authCtx.Request != nil:
+		ctx = authCtx.Request.Context()
+		token, err = security.ExtractRequestToken(authCtx.Request)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("target JWT extraction error: %v", err)
+	}
+	return a.authenticate(ctx, token)
+}
+
+func (a *KubeJWTAuthenticator) authenticate(ctx context.Context, token string) (*security.Caller, error) {
+	// Match spiffe.MustGenSpiffeURI, which maps "@" in trust domains to ".".
+	trustDomain := strings.ReplaceAll(a.meshHolder.Mesh().GetTrustDomain(), "@", ".")
+	if err := validateTrustDomain(trustDomain); err != nil {
+		return nil, err
+	}
+	info, err := tokenreview.ValidateK8sJwt(ctx, a.client, token, a.tokenAudiences(trustDomain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate the JWT: %v", err)
+	}
+	id := spiffe.Identity{
+		TrustDomain:    trustDomain,
+		Namespace:      info.PodNamespace,
+		ServiceAccount: info.PodServiceAccount,
+	}
+	return &security.Caller{
+		AuthSource:     security.AuthSourceIDToken,
+		Identities:     []string{id.String()},
+		KubernetesInfo: info,
+	}, nil
+}
+
+func (a *KubeJWTAuthenticator) tokenAudiences(trustDomain string) []string {
+	audiences := make([]string, 0, len(a.audiences)+1)
+	for _, aud := range a.audiences {
+		if aud != "" && aud != trustDomain {
+			audiences = append(audiences, aud)
+		}
+	}
+	return append(audiences, trustDomain)
+}
+
+// validateTrustDomain rejects trust domains that would produce a malformed or
+// ambiguous SPIFFE URI.
+func validateTrustDomain(trustDomain string) error {
+	if trustDomain == "" {
+		return fmt.Errorf("mesh trust domain is not set")
+	}
+	if strings.ContainsAny(trustDomain, "/: ") {
+		return fmt.Errorf("invalid mesh trust domain %q", trustDomain)
+	}
+	return nil
+}
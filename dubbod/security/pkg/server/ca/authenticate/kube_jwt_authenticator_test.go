@@ -0,0 +1,87 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/dubbo-kubernetes/pkg/security"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+	"google.golang.org/grpc/metadata"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+type staticMesh struct {
+	trustDomain string
+}
+
+func (m staticMesh) Mesh() *meshv1alpha1.MeshConfig {
+	return &meshv1alpha1.MeshConfig{TrustDomain: m.trustDomain}
+}
+
+func TestKubeJWTAuthenticatorMapsTokenToSpiffeIdentity(t *testing.T) {
+	client := fake.NewClientset()
+	var audiences []string
+	client.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		audiences = review.Spec.Audiences
+		if review.Spec.Token != "vm-token" {
+			return true, &authenticationv1.TokenReview{}, nil
+		}
+		return true, &authenticationv1.TokenReview{Status: authenticationv1.TokenReviewStatus{
+			Authenticated: true,
+			Audiences:     []string{"example.org"},
+			User:          authenticationv1.UserInfo{Username: "system:serviceaccount:vm:billing"},
+		}}, nil
+	})
+	authenticator := NewKubeJWTAuthenticator(staticMesh{trustDomain: "example.org"}, client, []string{"dubbo-ca"})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer vm-token"))
+	caller, err := authenticator.Authenticate(security.AuthContext{GrpcContext: ctx})
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if caller.AuthSource != security.AuthSourceIDToken {
+		t.Fatalf("auth source = %v, want ID token", caller.AuthSource)
+	}
+	if len(caller.Identities) != 1 || caller.Identities[0] != "spiffe://example.org/ns/vm/sa/billing" {
+		t.Fatalf("identities = %v", caller.Identities)
+	}
+	if len(audiences) != 2 || audiences[0] != "dubbo-ca" || audiences[1] != "example.org" {
+		t.Fatalf("reviewed audiences = %v, want configured audience and trust domain", audiences)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer other"))
+	if _, err := authenticator.Authenticate(security.AuthContext{GrpcContext: ctx}); err == nil {
+		t.Fatal("Authenticate() with an unauthenticated token error = nil, want error")
+	}
+	if _, err := authenticator.Authenticate(security.AuthContext{GrpcContext: context.Background()}); err == nil {
+		t.Fatal("Authenticate() without a token error = nil, want error")
+	}
+}
+
+func TestKubeJWTAuthenticatorRejectsInvalidTrustDomain(t *testing.T) {
+	authenticator := NewKubeJWTAuthenticator(staticMesh{trustDomain: "example.org/evil"}, fake.NewClientset(), nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	if _, err := authenticator.Authenticate(security.AuthContext{GrpcContext: ctx}); err == nil {
+		t.Fatal("Authenticate() error = nil, want invalid trust domain error")
+	}
+}
@@ -18,13 +18,16 @@ package ca
 
 import (
 	"context"
+	"fmt"
 	"github.com/apache/dubbo-kubernetes/pkg/log"
+	"strings"
 	"time"
 
 	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/ca"
 	caerror "github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/error"
 	"github.com/apache/dubbo-kubernetes/dubbod/security/pkg/pki/util"
 	"github.com/apache/dubbo-kubernetes/pkg/security"
+	"github.com/apache/dubbo-kubernetes/pkg/spiffe"
 	pb "github.com/kdubbo/api/security/v1alpha3"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -38,6 +41,13 @@ type Server struct {
 	Authenticators []security.Authenticator
 	serverCertTTL  time.Duration
 	ca             CertificateAuthority
+	// CertSignerForNamespace picks the signer for a caller based on its
+	// namespace. An empty result keeps the default.
+	CertSignerForNamespace func(namespace string) string
+	// DefaultCertSigner is the signer used when CertSignerForNamespace picks
+	// none. Callers may name it explicitly; any other signer they name is
+	// refused.
+	DefaultCertSigner string
 }
 
 type CertificateAuthority interface {
@@ -73,7 +83,11 @@ func (s *Server) CreateCertificate(ctx context.Context, request *pb.DubboCertifi
 		sans = caller.Identities
 	}
 	crMetadata := request.Metadata.GetFields()
-	certSigner := crMetadata[security.CertSigner].GetStringValue()
+	certSigner, err := s.certSigner(caller, crMetadata[security.CertSigner].GetStringValue())
+	if err != nil {
+		serverCaLog.Warnf("rejecting CSR: %v", err)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
 	_, _, certChainBytes, rootCertBytes := s.ca.GetCAKeyCertBundle().GetAll()
 	certOpts := ca.CertOpts{
 		SubjectIDs: sans,
@@ -136,3 +150,42 @@ func (s *Server) authenticate(ctx context.Context) (*security.Caller, error) {
 	}
 	return nil, status.Error(codes.Unauthenticated, "authentication failure")
 }
+
+// certSigner returns the signer for a caller's CSR. The signer is always the
+// one mesh config assigns to the caller's namespace, or the default when it
+// assigns none; the signer a caller requests is only checked against it.
+// Otherwise any workload could have dubbod submit, and with auto-approval
+// approve, a CSR for a signer of its choosing.
+func (s *Server) certSigner(caller *security.Caller, requested string) (string, error) {
+	var signer string
+	if s.CertSignerForNamespace != nil {
+		if ns := callerNamespace(caller); ns != "" {
+			signer = s.CertSignerForNamespace(ns)
+		}
+	}
+	requested = strings.TrimSpace(requested)
+	switch {
+	case requested == "" || requested == signer:
+		return signer, nil
+	case signer == "" && requested == s.DefaultCertSigner:
+		return "", nil
+	default:
+		return "", fmt.Errorf("signer %q is not allowed for namespace %q", requested, callerNamespace(caller))
+	}
+}
+
+// callerNamespace returns the Kubernetes namespace an authenticated caller runs in.
+func callerNamespace(caller *security.Caller) string {
+	if caller == nil {
+		return ""
+	}
+	if caller.KubernetesInfo.PodNamespace != "" {
+		return caller.KubernetesInfo.PodNamespace
+	}
+	for _, id := range caller.Identities {
+		if identity, err := spiffe.ParseIdentity(id); err == nil {
+			return identity.Namespace
+		}
+	}
+	return ""
+}
@@ -44,6 +44,8 @@ type installArgs struct {
 	// skipConfirmation determines whether the user is prompted for confirmation.
 	// If set to true, the user is not prompted, and a "Yes" response is assumed in all cases.
 	skipConfirmation bool
+	// revision installs a revisioned control plane beside any existing one.
+	revision string
 }
 
 func (i *installArgs) String() string {
@@ -51,6 +53,7 @@ func (i *installArgs) String() string {
 	b.WriteString("filenames:    " + (fmt.Sprint(i.filenames) + "\n"))
 	b.WriteString("sets:    " + (fmt.Sprint(i.sets) + "\n"))
 	b.WriteString("waitTimeout: " + fmt.Sprint(i.waitTimeout) + "\n")
+	b.WriteString("revision:    " + i.revision + "\n")
 	return b.String()
 }
 
@@ -59,6 +62,7 @@ func addInstallFlags(cmd *cobra.Command, args *installArgs) {
 	cmd.PersistentFlags().StringArrayVarP(&args.sets, "set", "s", nil, `Override dubboOperator values, such as selecting profiles, etc.`)
 	cmd.PersistentFlags().BoolVarP(&args.skipConfirmation, "skip-confirmation", "y", false, `The skipConfirmation determines whether the user is prompted for confirmation.`)
 	cmd.PersistentFlags().DurationVar(&args.waitTimeout, "wait-timeout", 300*time.Second, "Maximum time to wait for Dubbo resources in each component to be ready.")
+	cmd.PersistentFlags().StringVarP(&args.revision, "revision", "r", "", revisionFlagHelpStr)
 }
 
 // InstallCmdWithArgs generates an Dubbo install manifest and applies it to a cluster.
@@ -72,6 +76,9 @@ func InstallCmdWithArgs(ctx cli.Context, rootArgs *RootArgs, iArgs *installArgs)
  
   # Apply a demo profile.
   dubboctl install --set profile=demo -y
+
+  # Install a canary control plane beside the default one.
+  dubboctl install --revision canary -y
 		`,
 		Aliases: []string{"apply"},
 		Args:    cobra.ExactArgs(0),
@@ -97,14 +104,15 @@ func InstallCmd(ctx cli.Context) *cobra.Command {
 }
 
 func Install(kubeClient kube.CLIClient, rootArgs *RootArgs, iArgs *installArgs, cl clog.Logger, stdOut io.Writer, p Printer) error {
-	setFlags := applyFlagAliases(iArgs.sets)
+	setFlags := applyFlagAliases(iArgs.sets, iArgs.revision)
 	manifests, vals, err := render.GenerateManifest(iArgs.filenames, setFlags, cl, kubeClient)
 	if err != nil {
 		return fmt.Errorf("generate config: %v", err)
 	}
 	profile := ptr.NonEmptyOrDefault(vals.GetPathString("spec.profile"), "default")
+	revision := ptr.NonEmptyOrDefault(vals.GetPathString("spec.values.revision"), "default")
 	if !rootArgs.DryRun && !iArgs.skipConfirmation {
-		prompt := fmt.Sprintf("The %q profile will be installed into the cluster. \nDo you want to proceed? (y/N)", profile)
+		prompt := fmt.Sprintf("The %q profile will be installed into the cluster as revision %q. \nDo you want to proceed? (y/N)", profile, revision)
 		if !OptionDeterminate(prompt, stdOut) {
 			p.Println("Canceled Completed.")
 			os.Exit(1)
@@ -125,8 +133,13 @@ func Install(kubeClient kube.CLIClient, rootArgs *RootArgs, iArgs *installArgs,
 	return nil
 }
 
-// --bar is an alias for --set bar=
-// --foo is an alias for --set foo=
-func applyFlagAliases(flags []string) []string {
+const revisionFlagHelpStr = `Target control plane revision. Resources are suffixed with the revision and
+only namespaces labelled dubbo.apache.org/rev=<revision>, or with a tag pointing at it, are injected by it.`
+
+// --revision is an alias for --set values.revision=
+func applyFlagAliases(flags []string, revision string) []string {
+	if revision != "" {
+		flags = append(flags, fmt.Sprintf("values.revision=%s", revision))
+	}
 	return flags
 }
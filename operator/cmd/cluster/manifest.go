@@ -35,18 +35,22 @@ type manifestGenerateArgs struct {
 	filenames []string
 	// sets is a string with the format "path=value".
 	sets []string
+	// revision renders a revisioned control plane.
+	revision string
 }
 
 func (a *manifestGenerateArgs) String() string {
 	var b strings.Builder
 	b.WriteString("filenames:   " + fmt.Sprint(a.filenames) + "\n")
 	b.WriteString("sets:           " + fmt.Sprint(a.sets) + "\n")
+	b.WriteString("revision:       " + a.revision + "\n")
 	return b.String()
 }
 
 func addManifestGenerateFlags(cmd *cobra.Command, args *manifestGenerateArgs) {
 	cmd.PersistentFlags().StringSliceVarP(&args.filenames, "filename", "f", nil, `Path to the file containing the DubboOperator custom resource.`)
 	cmd.PersistentFlags().StringArrayVarP(&args.sets, "set", "s", nil, `Override dubboOperator values, such as selecting profiles, etc.`)
+	cmd.PersistentFlags().StringVarP(&args.revision, "revision", "r", "", revisionFlagHelpStr)
 }
 
 func ManifestCmd(ctx cli.Context) *cobra.Command {
@@ -100,7 +104,7 @@ const (
 )
 
 func manifestGenerate(kc kube.CLIClient, mgArgs *manifestGenerateArgs, cl clog.Logger) error {
-	setFlags := applyFlagAliases(mgArgs.sets)
+	setFlags := applyFlagAliases(mgArgs.sets, mgArgs.revision)
 	manifests, _, err := render.GenerateManifest(mgArgs.filenames, setFlags, cl, kc)
 	if err != nil {
 		return err
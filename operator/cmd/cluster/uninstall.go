@@ -26,6 +26,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/operator/pkg/util/clog"
 	"github.com/apache/dubbo-kubernetes/operator/pkg/util/progress"
 	"github.com/apache/dubbo-kubernetes/pkg/kube"
+	"github.com/apache/dubbo-kubernetes/pkg/util/ptr"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +38,9 @@ type uninstallArgs struct {
 	sets []string
 	// purge results in deletion of all Dubbo resources.
 	purge bool
+	// revision is the control plane revision to remove. Cluster scoped objects
+	// are suffixed per revision, so removing one leaves the others working.
+	revision string
 	// skipConfirmation determines whether the user is prompted for confirmation.
 	// If set to true, the user is not prompted, and a "Yes" response is assumed in all cases.
 	skipConfirmation bool
@@ -46,6 +50,7 @@ func addUninstallFlags(cmd *cobra.Command, args *uninstallArgs) {
 	// cmd.PersistentFlags().StringVarP(&args.filenames, "filenames", "f", "", "The filename of the DubboOperator CR.")
 	cmd.PersistentFlags().StringArrayVarP(&args.sets, "set", "s", nil, `Override dubboOperator values, such as selecting profiles, etc.`)
 	cmd.PersistentFlags().BoolVar(&args.purge, "purge", false, `Remove all dubbo related source code.`)
+	cmd.PersistentFlags().StringVarP(&args.revision, "revision", "r", "", `Target control plane revision to remove, "default" for the unrevisioned one.`)
 	cmd.PersistentFlags().BoolVarP(&args.skipConfirmation, "skip-confirmation", "y", false, `The skipConfirmation determines whether the user is prompted for confirmation.`)
 }
 
@@ -58,10 +63,13 @@ func UninstallCmd(ctx cli.Context) *cobra.Command {
 		Short: "Uninstall Dubbo related resources",
 		Long:  "The uninstall command will uninstall the dubbo cluster",
 		Example: ` # Uninstall all control planes and shared resources
-  dubboctl uninstall --purge`,
+  dubboctl uninstall --purge
+
+  # Uninstall the canary control plane only
+  dubboctl uninstall --revision canary`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if !uiArgs.purge {
-				return fmt.Errorf("at least one of the --purge flags must be set")
+			if !uiArgs.purge && uiArgs.revision == "" {
+				return fmt.Errorf("at least one of the --purge or --revision flags must be set")
 			}
 			if len(args) > 0 {
 				return fmt.Errorf("dubboctl uninstall does not take arguments")
@@ -81,7 +89,7 @@ func UninstallCmd(ctx cli.Context) *cobra.Command {
 func Uninstall(cmd *cobra.Command, ctx cli.Context, rootArgs *RootArgs, uiArgs *uninstallArgs) error {
 	cl := clog.NewConsoleLogger(cmd.OutOrStdout(), cmd.ErrOrStderr())
 	var kubeClient kube.CLIClient
-	kubeClient, err := ctx.CLIClientWithRevision("")
+	kubeClient, err := ctx.CLIClientWithRevision(uiArgs.revision)
 	if err != nil {
 		return err
 	}
@@ -91,7 +99,7 @@ func Uninstall(cmd *cobra.Command, ctx cli.Context, rootArgs *RootArgs, uiArgs *
 		cl.LogAndPrint("Purge uninstall will purge all Dubbo resources, ignoring the specified revision or dubbooperator file")
 	}
 
-	setFlags := applyFlagAliases(uiArgs.sets)
+	setFlags := applyFlagAliases(uiArgs.sets, uiArgs.revision)
 
 	files := []string{}
 
@@ -104,6 +112,7 @@ func Uninstall(cmd *cobra.Command, ctx cli.Context, rootArgs *RootArgs, uiArgs *
 		kubeClient,
 		vals.GetPathString("metadata.name"),
 		vals.GetPathString("metadata.namespace"),
+		ptr.NonEmptyOrDefault(vals.GetPathString("spec.values.revision"), "default"),
 		uiArgs.purge,
 	)
 	if err != nil {
@@ -127,6 +136,9 @@ func preCheck(cmd *cobra.Command, uiArgs *uninstallArgs, _ *clog.ConsoleLogger,
 	if uiArgs.purge {
 		needConfirmation = true
 		message += "All Dubbo resources will be pruned from the cluster.\n"
+	} else if uiArgs.revision != "" {
+		needConfirmation = true
+		message += fmt.Sprintf("The %q control plane revision will be removed from the cluster.\n", uiArgs.revision)
 	}
 	if dryRun || uiArgs.skipConfirmation {
 		return
@@ -19,8 +19,6 @@ package cluster
 import (
 	"github.com/apache/dubbo-kubernetes/dubboctl/pkg/cli"
 	"github.com/apache/dubbo-kubernetes/operator/pkg/util/clog"
-
-	// "github.com/apache/dubbo-kubernetes/operator/pkg/util/clog"
 	"github.com/spf13/cobra"
 )
 
@@ -44,11 +42,13 @@ func UpgradeCmd(ctx cli.Context) *cobra.Command {
   # Apply a default profile.
   dubboctl upgrade --profile=demo
 
+  # Upgrade the canary control plane in place, leaving other revisions alone.
+  dubboctl upgrade --revision canary -y
 		`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cl := clog.NewConsoleLogger(cmd.OutOrStdout(), cmd.ErrOrStderr())
 			p := NewPrinterForWriter(cmd.OutOrStderr())
-			client, err := ctx.CLIClient()
+			client, err := ctx.CLIClientWithRevision(upArgs.revision)
 			if err != nil {
 				return err
 			}
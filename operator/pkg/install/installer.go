@@ -32,6 +32,7 @@ import (
 	"github.com/apache/dubbo-kubernetes/operator/pkg/values"
 	"github.com/apache/dubbo-kubernetes/pkg/kube"
 	"github.com/apache/dubbo-kubernetes/pkg/slices"
+	"github.com/apache/dubbo-kubernetes/pkg/util/ptr"
 	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
 	"github.com/hashicorp/go-multierror"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -278,6 +279,9 @@ func getOwnerLabels(dop values.Map, c string) map[string]string {
 	if n := dop.GetPathString("metadata.namespace"); n != "" {
 		labels[manifest.OwningResourceNamespace] = n
 	}
+	// Revisions share the owner name, so the revision label is what keeps a
+	// canary install from pruning the control plane it is meant to sit beside.
+	labels[manifest.DubboRevisionLabel] = ptr.NonEmptyOrDefault(dop.GetPathString("spec.values.revision"), "default")
 
 	if c != "" {
 		labels[manifest.DubboComponentLabel] = c
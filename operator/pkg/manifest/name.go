@@ -23,6 +23,8 @@ const (
 	OwningResourceNamespace = "install.dubbooperator.dubbo.apache.org/owning-resource-namespace"
 	// DubboComponentLabel indicates which Dubbo component a resource belongs to.
 	DubboComponentLabel = "dubbooperator.dubbo.apache.org/component"
+	// DubboRevisionLabel indicates which control plane revision a resource belongs to.
+	DubboRevisionLabel = "dubbo.apache.org/rev"
 	// OwningResourceNotPruned indicates that the resource should not be pruned during reconciliation cycles,
 	// note this will not prevent the resource from being deleted if the owning resource is deleted.
 	OwningResourceNotPruned = "install.dubbooperator.dubbo.apache.org/owning-resource-not-pruned"
@@ -16,6 +16,7 @@
 package render
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -208,6 +209,34 @@ func TestGenerateManifestWiresActivationScalerEndToEnd(t *testing.T) {
 	}
 }
 
+// Replicas find each other for demand handoff through the headless Service,
+// which only resolves to them on the activation port.
+func TestGenerateManifestWiresActivationHandoffToTheReplicasService(t *testing.T) {
+	argsOf := func(set ...string) []string {
+		t.Helper()
+		manifests, _, err := GenerateManifest(nil, set, nil, nil)
+		if err != nil {
+			t.Fatalf("GenerateManifest() error = %v", err)
+		}
+		deployment := findManifest(t, manifests, "Deployment", "dubbod")
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		args, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+		return args
+	}
+
+	args := argsOf("values.global.activation.port=26031")
+	if !hasArgValue(args, "--activationPeerAddr", "dubbod-activation-replicas.dubbo-system.svc:26031") {
+		t.Fatalf("args = %v, want --activationPeerAddr on the replicas Service", args)
+	}
+	if !hasArgValue(args, "--activationDemandAddr", "dubbod-activation-replicas.dubbo-system.svc:26012") {
+		t.Fatalf("args = %v, want --activationDemandAddr on the replicas Service's secure xDS port", args)
+	}
+	args = argsOf("values.global.activation.handoff=false")
+	if slices.Contains(args, "--activationPeerAddr") {
+		t.Fatalf("args = %v, want no --activationPeerAddr with handoff disabled", args)
+	}
+}
+
 // Port zero is the documented off switch. It has to remove the listener, the
 // port and the Service together; leaving a Service behind would publish an
 // endpoint that refuses every connection.
@@ -417,7 +446,7 @@ func TestGenerateManifestSetOverridesHelmValues(t *testing.T) {
 		t.Fatalf("GenerateManifest() error = %v", err)
 	}
 
-	managementService := findManifest(t, manifests, "Service", "dubbod-management")
+	managementService := findManifest(t, manifests, "Service", "dubbod-management-canary")
 	managementPort := findPort(t, managementService, "management")
 	port, _, _ := unstructured.NestedInt64(managementPort, "port")
 	targetPort, _, _ := unstructured.NestedInt64(managementPort, "targetPort")
@@ -425,7 +454,7 @@ func TestGenerateManifestSetOverridesHelmValues(t *testing.T) {
 		t.Fatalf("dubbod-management port=%d targetPort=%d, want 26081/26081", port, targetPort)
 	}
 
-	deployment := findManifest(t, manifests, "Deployment", "dubbod")
+	deployment := findManifest(t, manifests, "Deployment", "dubbod-canary")
 	containers, ok, err := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
 	if err != nil || !ok || len(containers) == 0 {
 		t.Fatalf("deployment containers missing: ok=%v err=%v", ok, err)
@@ -439,7 +468,7 @@ func TestGenerateManifestSetOverridesHelmValues(t *testing.T) {
 		t.Fatal("dubbod deployment missing management containerPort 26081")
 	}
 
-	configMap := findManifest(t, manifests, "ConfigMap", "dubbo")
+	configMap := findManifest(t, manifests, "ConfigMap", "dubbo-canary")
 	if rev := configMap.GetLabels()["dubbo.apache.org/rev"]; rev != "canary" {
 		t.Fatalf("dubbo configmap revision label = %q, want canary", rev)
 	}
@@ -449,11 +478,57 @@ func TestGenerateManifestSetOverridesHelmValues(t *testing.T) {
 			t.Fatalf("mesh config missing %q:\n%s", want, mesh)
 		}
 	}
-	if hasManifest(manifests, "ValidatingWebhookConfiguration", "dubbo-validator-dubbo-system") {
+	if hasManifest(manifests, "ValidatingWebhookConfiguration", "dubbo-validator-canary-dubbo-system") {
 		t.Fatal("validating webhook rendered even though values.global.configValidation=false")
 	}
 }
 
+// A revision has to be installable beside the default control plane, so every
+// name it renders is suffixed and it must not claim the revision-less opt-ins.
+func TestGenerateManifestRevisionRendersSideBySideControlPlane(t *testing.T) {
+	manifests, _, err := GenerateManifest(nil, []string{"values.revision=canary"}, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateManifest() error = %v", err)
+	}
+
+	for _, name := range []string{"dubbod", "dubbod-management"} {
+		service := findManifest(t, manifests, "Service", name+"-canary")
+		if rev, _, _ := unstructured.NestedString(service.Object, "spec", "selector", "dubbo.apache.org/rev"); rev != "canary" {
+			t.Fatalf("%s-canary selector revision = %q, want canary", name, rev)
+		}
+	}
+	deployment := findManifest(t, manifests, "Deployment", "dubbod-canary")
+	if got := deploymentEnvValue(t, deployment, "REVISION"); got != "canary" {
+		t.Fatalf("REVISION = %q, want canary", got)
+	}
+	if rev, _, _ := unstructured.NestedString(deployment.Object, "spec", "selector", "matchLabels", "dubbo.apache.org/rev"); rev != "canary" {
+		t.Fatalf("deployment selector revision = %q, want canary", rev)
+	}
+	findManifest(t, manifests, "ConfigMap", "dubbo-inherent-injector-canary")
+	mesh, _, _ := unstructured.NestedString(findManifest(t, manifests, "ConfigMap", "dubbo-canary").Object, "data", "mesh")
+	if !strings.Contains(mesh, "discoveryAddress: dubbod-canary.dubbo-system.svc:26012") {
+		t.Fatalf("mesh config does not point at the canary dubbod:\n%s", mesh)
+	}
+	for _, kind := range []string{"Deployment", "Service"} {
+		if hasManifest(manifests, kind, "dubbod") {
+			t.Fatalf("revision rendered the default %s dubbod", kind)
+		}
+	}
+
+	webhook := findManifest(t, manifests, "MutatingWebhookConfiguration", "dubbo-inherent-injector-canary")
+	webhooks, _, _ := unstructured.NestedSlice(webhook.Object, "webhooks")
+	if len(webhooks) != 2 {
+		t.Fatalf("webhooks = %d, want only the two revision-selected webhooks", len(webhooks))
+	}
+	for _, raw := range webhooks {
+		entry := raw.(map[string]interface{})
+		service, _, _ := unstructured.NestedString(entry, "clientConfig", "service", "name")
+		if service != "dubbod-canary" {
+			t.Fatalf("webhook service = %q, want dubbod-canary", service)
+		}
+	}
+}
+
 func TestGenerateManifestRejectsRemovedInstallSurface(t *testing.T) {
 	tests := []struct {
 		name string
@@ -58,11 +58,11 @@ var (
 )
 
 // GetRemovedResources get the list of resources to be removed
-// 1. if includeClusterResources is false, we list the namespaced resources by matching revision and component labels.
+// 1. if includeClusterResources is false, we list the namespaced and revision suffixed cluster resources by matching revision and component labels.
 // 2. if includeClusterResources is true, we list the namespaced and cluster resources by component labels only.
 // If componentName is not empty, only resources associated with specific components would be returned
 // UnstructuredList of objects and corresponding list of name kind hash of k8sObjects would be returned
-func GetRemovedResources(kc kube.CLIClient, dopName, dopNamespace string, includeClusterResources bool) ([]*unstructured.UnstructuredList, error) {
+func GetRemovedResources(kc kube.CLIClient, dopName, dopNamespace, revision string, includeClusterResources bool) ([]*unstructured.UnstructuredList, error) {
 	var usList []*unstructured.UnstructuredList
 	labels := make(map[string]string)
 	if dopName != "" {
@@ -71,6 +71,9 @@ func GetRemovedResources(kc kube.CLIClient, dopName, dopNamespace string, includ
 	if dopNamespace != "" {
 		labels[manifest.OwningResourceNamespace] = dopNamespace
 	}
+	if revision != "" {
+		labels[manifest.DubboRevisionLabel] = revision
+	}
 	selector := klabels.Set(labels).AsSelectorPreValidated()
 	resources := NamespacedResources()
 	gvkList := append(resources, ClusterControlPlaneResources...)
@@ -35,6 +35,12 @@ const (
 
 	CACertNamespaceConfigMapDataName = "root-cert.pem"
 	CACRLNamespaceConfigMapDataName  = "ca-crl.pem"
+	// FederatedBundleMapNamespaceConfigMapDataName holds the SPIFFE bundle map
+	// of the federated trust domains next to the mesh root cert.
+	FederatedBundleMapNamespaceConfigMapDataName = "federated-bundle-map.json"
+	// SPIFFEBundleMapFilename holds the SPIFFE bundle map of the mesh and the
+	// federated trust domains in inherent gRPC workload Secrets.
+	SPIFFEBundleMapFilename = "spiffe-bundle-map.json"
 
 	PodInfoAnnotationsPath = "./etc/dubbo/pod/annotations"
 
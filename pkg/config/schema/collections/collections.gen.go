@@ -197,6 +197,24 @@ var (
 		ValidateProto: validation.ValidateFaultInjectionPolicy,
 	}.MustBuild()
 
+	GRPCRoute = resource.Builder{
+		Identifier: "GRPCRoute",
+		Group:      "gateway.networking.k8s.io",
+		Kind:       "GRPCRoute",
+		Plural:     "grpcroutes",
+		Version:    "v1",
+		VersionAliases: []string{
+			"v1",
+		},
+		Proto: "k8s.io.gateway_api.api.v1alpha1.GRPCRouteSpec", StatusProto: "k8s.io.gateway_api.api.v1alpha1.GRPCRouteStatus",
+		ReflectType: reflect.TypeOf(&sigsk8siogatewayapiapisv1.GRPCRouteSpec{}).Elem(), StatusType: reflect.TypeOf(&sigsk8siogatewayapiapisv1.GRPCRouteStatus{}).Elem(),
+		ProtoPackage: "sigs.k8s.io/gateway-api/apis/v1", StatusPackage: "sigs.k8s.io/gateway-api/apis/v1",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       false,
+		ValidateProto: validation.ValidateGRPCRoute,
+	}.MustBuild()
+
 	GatewayClass = resource.Builder{
 		Identifier: "GatewayClass",
 		Group:      "gateway.networking.k8s.io",
@@ -386,6 +404,21 @@ var (
 		ValidateProto: validation.EmptyValidate,
 	}.MustBuild()
 
+	RateLimitPolicy = resource.Builder{
+		Identifier: "RateLimitPolicy",
+		Group:      "networking.dubbo.apache.org",
+		Kind:       "RateLimitPolicy",
+		Plural:     "ratelimitpolicies",
+		Version:    "v1alpha3",
+		Proto:      "dubbo.networking.v1alpha3.RateLimitPolicy", StatusProto: "dubbo.meta.v1alpha1.DubboStatus",
+		ReflectType: reflect.TypeOf(&githubcomkdubboapinetworkingv1alpha3.RateLimitPolicy{}).Elem(), StatusType: reflect.TypeOf(&githubcomkdubboapimetav1alpha1.DubboStatus{}).Elem(),
+		ProtoPackage: "github.com/kdubbo/api/networking/v1alpha3", StatusPackage: "github.com/kdubbo/api/meta/v1alpha1",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       false,
+		ValidateProto: validation.ValidateRateLimitPolicy,
+	}.MustBuild()
+
 	ReferenceGrant = resource.Builder{
 		Identifier: "ReferenceGrant",
 		Group:      "gateway.networking.k8s.io",
@@ -567,6 +600,7 @@ var (
 		MustAdd(EndpointSlice).
 		MustAdd(Endpoints).
 		MustAdd(FaultInjectionPolicy).
+		MustAdd(GRPCRoute).
 		MustAdd(GatewayClass).
 		MustAdd(HTTPRoute).
 		MustAdd(HorizontalPodAutoscaler).
@@ -579,6 +613,7 @@ var (
 		MustAdd(PeerAuthentication).
 		MustAdd(Pod).
 		MustAdd(PodDisruptionBudget).
+		MustAdd(RateLimitPolicy).
 		MustAdd(ReferenceGrant).
 		MustAdd(RequestAuthentication).
 		MustAdd(Secret).
@@ -601,6 +636,7 @@ var (
 		MustAdd(Deployment).
 		MustAdd(EndpointSlice).
 		MustAdd(Endpoints).
+		MustAdd(GRPCRoute).
 		MustAdd(GatewayClass).
 		MustAdd(HTTPRoute).
 		MustAdd(HorizontalPodAutoscaler).
@@ -626,6 +662,7 @@ var (
 		MustAdd(DxgateService).
 		MustAdd(FaultInjectionPolicy).
 		MustAdd(PeerAuthentication).
+		MustAdd(RateLimitPolicy).
 		MustAdd(RequestAuthentication).
 		MustAdd(ServiceActivationPolicy).
 		MustAdd(ServiceEntry).
@@ -640,10 +677,12 @@ var (
 			MustAdd(CircuitBreakerPolicy).
 			MustAdd(DxgateService).
 			MustAdd(FaultInjectionPolicy).
+			MustAdd(GRPCRoute).
 			MustAdd(GatewayClass).
 			MustAdd(HTTPRoute).
 			MustAdd(KubernetesGateway).
 			MustAdd(PeerAuthentication).
+			MustAdd(RateLimitPolicy).
 			MustAdd(ReferenceGrant).
 			MustAdd(RequestAuthentication).
 			MustAdd(ServiceActivationPolicy).
@@ -659,10 +698,12 @@ var (
 				MustAdd(CircuitBreakerPolicy).
 				MustAdd(DxgateService).
 				MustAdd(FaultInjectionPolicy).
+				MustAdd(GRPCRoute).
 				MustAdd(GatewayClass).
 				MustAdd(HTTPRoute).
 				MustAdd(KubernetesGateway).
 				MustAdd(PeerAuthentication).
+				MustAdd(RateLimitPolicy).
 				MustAdd(ReferenceGrant).
 				MustAdd(RequestAuthentication).
 				MustAdd(ServiceActivationPolicy).
@@ -22,6 +22,8 @@ var (
 	EndpointSlice                  = config.GroupVersionKind{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice"}
 	Endpoints                      = config.GroupVersionKind{Group: "", Version: "v1", Kind: "Endpoints"}
 	FaultInjectionPolicy           = config.GroupVersionKind{Group: "networking.dubbo.apache.org", Version: "v1alpha3", Kind: "FaultInjectionPolicy"}
+	GRPCRoute                      = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GRPCRoute"}
+	GRPCRoute_v1                   = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GRPCRoute"}
 	GatewayClass                   = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GatewayClass"}
 	GatewayClass_v1                = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GatewayClass"}
 	HTTPRoute                      = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
@@ -37,6 +39,7 @@ var (
 	PeerAuthentication             = config.GroupVersionKind{Group: "security.dubbo.apache.org", Version: "v1alpha3", Kind: "PeerAuthentication"}
 	Pod                            = config.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
 	PodDisruptionBudget            = config.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}
+	RateLimitPolicy                = config.GroupVersionKind{Group: "networking.dubbo.apache.org", Version: "v1alpha3", Kind: "RateLimitPolicy"}
 	ReferenceGrant                 = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "ReferenceGrant"}
 	ReferenceGrant_v1beta1         = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "ReferenceGrant"}
 	RequestAuthentication          = config.GroupVersionKind{Group: "security.dubbo.apache.org", Version: "v1alpha3", Kind: "RequestAuthentication"}
@@ -78,6 +81,10 @@ func ToGVR(g config.GroupVersionKind) (schema.GroupVersionResource, bool) {
 		return gvr.Endpoints, true
 	case FaultInjectionPolicy:
 		return gvr.FaultInjectionPolicy, true
+	case GRPCRoute:
+		return gvr.GRPCRoute, true
+	case GRPCRoute_v1:
+		return gvr.GRPCRoute_v1, true
 	case GatewayClass:
 		return gvr.GatewayClass, true
 	case GatewayClass_v1:
@@ -108,6 +115,8 @@ func ToGVR(g config.GroupVersionKind) (schema.GroupVersionResource, bool) {
 		return gvr.Pod, true
 	case PodDisruptionBudget:
 		return gvr.PodDisruptionBudget, true
+	case RateLimitPolicy:
+		return gvr.RateLimitPolicy, true
 	case ReferenceGrant:
 		return gvr.ReferenceGrant, true
 	case ReferenceGrant_v1beta1:
@@ -161,6 +170,8 @@ func MustToKind(g config.GroupVersionKind) kind.Kind {
 		return kind.Endpoints
 	case FaultInjectionPolicy:
 		return kind.FaultInjectionPolicy
+	case GRPCRoute:
+		return kind.GRPCRoute
 	case GatewayClass:
 		return kind.GatewayClass
 	case HTTPRoute:
@@ -185,6 +196,8 @@ func MustToKind(g config.GroupVersionKind) kind.Kind {
 		return kind.Pod
 	case PodDisruptionBudget:
 		return kind.PodDisruptionBudget
+	case RateLimitPolicy:
+		return kind.RateLimitPolicy
 	case ReferenceGrant:
 		return kind.ReferenceGrant
 	case RequestAuthentication:
@@ -247,6 +260,8 @@ func FromGVR(g schema.GroupVersionResource) (config.GroupVersionKind, bool) {
 		return Endpoints, true
 	case gvr.FaultInjectionPolicy:
 		return FaultInjectionPolicy, true
+	case gvr.GRPCRoute:
+		return GRPCRoute, true
 	case gvr.GatewayClass:
 		return GatewayClass, true
 	case gvr.HTTPRoute:
@@ -271,6 +286,8 @@ func FromGVR(g schema.GroupVersionResource) (config.GroupVersionKind, bool) {
 		return Pod, true
 	case gvr.PodDisruptionBudget:
 		return PodDisruptionBudget, true
+	case gvr.RateLimitPolicy:
+		return RateLimitPolicy, true
 	case gvr.ReferenceGrant:
 		return ReferenceGrant, true
 	case gvr.RequestAuthentication:
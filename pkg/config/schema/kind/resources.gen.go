@@ -17,6 +17,7 @@ const (
 	EndpointSlice
 	Endpoints
 	FaultInjectionPolicy
+	GRPCRoute
 	GatewayClass
 	HTTPRoute
 	HorizontalPodAutoscaler
@@ -29,6 +30,7 @@ const (
 	PeerAuthentication
 	Pod
 	PodDisruptionBudget
+	RateLimitPolicy
 	ReferenceGrant
 	RequestAuthentication
 	Secret
@@ -70,6 +72,8 @@ func (k Kind) String() string {
 		return "Endpoints"
 	case FaultInjectionPolicy:
 		return "FaultInjectionPolicy"
+	case GRPCRoute:
+		return "GRPCRoute"
 	case GatewayClass:
 		return "GatewayClass"
 	case HTTPRoute:
@@ -94,6 +98,8 @@ func (k Kind) String() string {
 		return "Pod"
 	case PodDisruptionBudget:
 		return "PodDisruptionBudget"
+	case RateLimitPolicy:
+		return "RateLimitPolicy"
 	case ReferenceGrant:
 		return "ReferenceGrant"
 	case RequestAuthentication:
@@ -149,6 +155,8 @@ func FromString(s string) Kind {
 		return Endpoints
 	case "FaultInjectionPolicy":
 		return FaultInjectionPolicy
+	case "GRPCRoute":
+		return GRPCRoute
 	case "GatewayClass":
 		return GatewayClass
 	case "HTTPRoute":
@@ -173,6 +181,8 @@ func FromString(s string) Kind {
 		return Pod
 	case "PodDisruptionBudget":
 		return PodDisruptionBudget
+	case "RateLimitPolicy":
+		return RateLimitPolicy
 	case "ReferenceGrant":
 		return ReferenceGrant
 	case "RequestAuthentication":
@@ -56,6 +56,8 @@ func GetWriteClient[T runtime.Object](c ClientGetter, namespace string) ktypes.W
 		return c.Kube().CoreV1().Endpoints(namespace).(ktypes.WriteAPI[T])
 	case *apigithubcomapachedubbokubernetesapinetworkingv1alpha3.FaultInjectionPolicy:
 		return c.Dubbo().NetworkingV1alpha3().FaultInjectionPolicies(namespace).(ktypes.WriteAPI[T])
+	case *sigsk8siogatewayapiapisv1.GRPCRoute:
+		return c.GatewayAPI().GatewayV1().GRPCRoutes(namespace).(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1.GatewayClass:
 		return c.GatewayAPI().GatewayV1().GatewayClasses().(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1.HTTPRoute:
@@ -78,6 +80,8 @@ func GetWriteClient[T runtime.Object](c ClientGetter, namespace string) ktypes.W
 		return c.Kube().CoreV1().Pods(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapipolicyv1.PodDisruptionBudget:
 		return c.Kube().PolicyV1().PodDisruptionBudgets(namespace).(ktypes.WriteAPI[T])
+	case *apigithubcomapachedubbokubernetesapinetworkingv1alpha3.RateLimitPolicy:
+		return c.Dubbo().NetworkingV1alpha3().RateLimitPolicies(namespace).(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1beta1.ReferenceGrant:
 		return c.GatewayAPI().GatewayV1beta1().ReferenceGrants(namespace).(ktypes.WriteAPI[T])
 	case *apigithubcomapachedubbokubernetesapisecurityv1alpha3.RequestAuthentication:
@@ -129,6 +133,8 @@ func GetClient[T, TL runtime.Object](c ClientGetter, namespace string) ktypes.Re
 		return c.Kube().CoreV1().Endpoints(namespace).(ktypes.ReadWriteAPI[T, TL])
 	case *apigithubcomapachedubbokubernetesapinetworkingv1alpha3.FaultInjectionPolicy:
 		return c.Dubbo().NetworkingV1alpha3().FaultInjectionPolicies(namespace).(ktypes.ReadWriteAPI[T, TL])
+	case *sigsk8siogatewayapiapisv1.GRPCRoute:
+		return c.GatewayAPI().GatewayV1().GRPCRoutes(namespace).(ktypes.ReadWriteAPI[T, TL])
 	case *sigsk8siogatewayapiapisv1.GatewayClass:
 		return c.GatewayAPI().GatewayV1().GatewayClasses().(ktypes.ReadWriteAPI[T, TL])
 	case *sigsk8siogatewayapiapisv1.HTTPRoute:
@@ -151,6 +157,8 @@ func GetClient[T, TL runtime.Object](c ClientGetter, namespace string) ktypes.Re
 		return c.Kube().CoreV1().Pods(namespace).(ktypes.ReadWriteAPI[T, TL])
 	case *k8sioapipolicyv1.PodDisruptionBudget:
 		return c.Kube().PolicyV1().PodDisruptionBudgets(namespace).(ktypes.ReadWriteAPI[T, TL])
+	case *apigithubcomapachedubbokubernetesapinetworkingv1alpha3.RateLimitPolicy:
+		return c.Dubbo().NetworkingV1alpha3().RateLimitPolicies(namespace).(ktypes.ReadWriteAPI[T, TL])
 	case *sigsk8siogatewayapiapisv1beta1.ReferenceGrant:
 		return c.GatewayAPI().GatewayV1beta1().ReferenceGrants(namespace).(ktypes.ReadWriteAPI[T, TL])
 	case *apigithubcomapachedubbokubernetesapisecurityv1alpha3.RequestAuthentication:
@@ -202,6 +210,8 @@ func gvrToObject(g schema.GroupVersionResource) runtime.Object {
 		return &k8sioapicorev1.Endpoints{}
 	case gvr.FaultInjectionPolicy:
 		return &apigithubcomapachedubbokubernetesapinetworkingv1alpha3.FaultInjectionPolicy{}
+	case gvr.GRPCRoute:
+		return &sigsk8siogatewayapiapisv1.GRPCRoute{}
 	case gvr.GatewayClass:
 		return &sigsk8siogatewayapiapisv1.GatewayClass{}
 	case gvr.HTTPRoute:
@@ -224,6 +234,8 @@ func gvrToObject(g schema.GroupVersionResource) runtime.Object {
 		return &k8sioapicorev1.Pod{}
 	case gvr.PodDisruptionBudget:
 		return &k8sioapipolicyv1.PodDisruptionBudget{}
+	case gvr.RateLimitPolicy:
+		return &apigithubcomapachedubbokubernetesapinetworkingv1alpha3.RateLimitPolicy{}
 	case gvr.ReferenceGrant:
 		return &sigsk8siogatewayapiapisv1beta1.ReferenceGrant{}
 	case gvr.RequestAuthentication:
@@ -333,6 +345,13 @@ func getInformerFiltered(c ClientGetter, opts ktypes.InformerOptions, g schema.G
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.Dubbo().NetworkingV1alpha3().FaultInjectionPolicies(opts.Namespace).Watch(context.Background(), options)
 		}
+	case gvr.GRPCRoute:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.GatewayAPI().GatewayV1().GRPCRoutes(opts.Namespace).List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.GatewayAPI().GatewayV1().GRPCRoutes(opts.Namespace).Watch(context.Background(), options)
+		}
 	case gvr.GatewayClass:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.GatewayAPI().GatewayV1().GatewayClasses().List(context.Background(), options)
@@ -410,6 +429,13 @@ func getInformerFiltered(c ClientGetter, opts ktypes.InformerOptions, g schema.G
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.Kube().PolicyV1().PodDisruptionBudgets(opts.Namespace).Watch(context.Background(), options)
 		}
+	case gvr.RateLimitPolicy:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.Dubbo().NetworkingV1alpha3().RateLimitPolicies(opts.Namespace).List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.Dubbo().NetworkingV1alpha3().RateLimitPolicies(opts.Namespace).Watch(context.Background(), options)
+		}
 	case gvr.ReferenceGrant:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.GatewayAPI().GatewayV1beta1().ReferenceGrants(opts.Namespace).List(context.Background(), options)
@@ -56,6 +56,8 @@ func getGvk(obj any) (config.GroupVersionKind, bool) {
 		return gvk.FaultInjectionPolicy, true
 	case *apigithubcomapachedubbokubernetesapinetworkingv1alpha3.FaultInjectionPolicy:
 		return gvk.FaultInjectionPolicy, true
+	case *sigsk8siogatewayapiapisv1.GRPCRoute:
+		return gvk.GRPCRoute, true
 	case *sigsk8siogatewayapiapisv1.GatewayClass:
 		return gvk.GatewayClass, true
 	case *sigsk8siogatewayapiapisv1.HTTPRoute:
@@ -82,6 +84,10 @@ func getGvk(obj any) (config.GroupVersionKind, bool) {
 		return gvk.Pod, true
 	case *k8sioapipolicyv1.PodDisruptionBudget:
 		return gvk.PodDisruptionBudget, true
+	case *githubcomkdubboapinetworkingv1alpha3.RateLimitPolicy:
+		return gvk.RateLimitPolicy, true
+	case *apigithubcomapachedubbokubernetesapinetworkingv1alpha3.RateLimitPolicy:
+		return gvk.RateLimitPolicy, true
 	case *sigsk8siogatewayapiapisv1beta1.ReferenceGrant:
 		return gvk.ReferenceGrant, true
 	case *githubcomkdubboapisecurityv1alpha3.RequestAuthentication:
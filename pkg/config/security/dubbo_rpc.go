@@ -0,0 +1,151 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Authorization rule condition keys matching the Dubbo RPC a request calls.
+// They are set in AuthorizationPolicy rules like any other "when" key:
+//
+//	when:
+//	- key: dubbo.interface
+//	  values: ["org.apache.dubbo.samples.Greeter"]
+//	- key: dubbo.method
+//	  notValues: ["delete"]
+const (
+	DubboInterfaceKey = "dubbo.interface"
+	DubboMethodKey    = "dubbo.method"
+	DubboGroupKey     = "dubbo.group"
+	DubboVersionKey   = "dubbo.version"
+)
+
+// Keys the Dubbo conditions compile to for Triple requests, whose path is
+// "/<interface>/<method>" and which carry the group and version in headers.
+const (
+	URLPathConditionKey = "request.url_path"
+	tripleGroupHeader   = "tri-service-group"
+	tripleVersionHeader = "tri-service-version"
+)
+
+// DubboRPCKeys lists the Dubbo condition keys.
+var DubboRPCKeys = []string{DubboInterfaceKey, DubboMethodKey, DubboGroupKey, DubboVersionKey}
+
+// IsDubboRPCKey reports whether key matches a Dubbo RPC attribute.
+func IsDubboRPCKey(key string) bool {
+	switch key {
+	case DubboInterfaceKey, DubboMethodKey, DubboGroupKey, DubboVersionKey:
+		return true
+	}
+	return false
+}
+
+// HeaderConditionKey returns the condition key matching a request header.
+func HeaderConditionKey(header string) string {
+	return "request.headers[" + header + "]"
+}
+
+// ValidateDubboRPCCondition checks the values of a Dubbo condition. A value is
+// exact, "*", or a prefix ending in "*". Groups and versions may also be a
+// suffix starting with "*". Methods are exact or "*", since Triple matches them
+// as the suffix of the path.
+func ValidateDubboRPCCondition(key string, values []string) error {
+	for _, value := range values {
+		if value == "" {
+			return fmt.Errorf("%s values must not be empty", key)
+		}
+		if value == "*" {
+			continue
+		}
+		if strings.Contains(strings.Trim(value, "*"), "*") ||
+			(strings.HasPrefix(value, "*") && strings.HasSuffix(value, "*")) {
+			return fmt.Errorf("%s value %q may only use * as a whole value, prefix or suffix", key, value)
+		}
+		switch key {
+		case DubboInterfaceKey:
+			if strings.HasPrefix(value, "*") {
+				return fmt.Errorf("%s value %q must not start with *", key, value)
+			}
+			if strings.Contains(value, "/") {
+				return fmt.Errorf("%s value %q must not contain /", key, value)
+			}
+		case DubboMethodKey:
+			if strings.Contains(value, "*") {
+				return fmt.Errorf("%s value %q must be a method name or *", key, value)
+			}
+			if strings.Contains(value, "/") {
+				return fmt.Errorf("%s value %q must not contain /", key, value)
+			}
+		case DubboGroupKey, DubboVersionKey:
+		default:
+			return fmt.Errorf("unknown Dubbo condition key %s", key)
+		}
+	}
+	return nil
+}
+
+// TripleCondition compiles the values of a Dubbo condition into the path or
+// header condition enforcing it on Triple requests. Other conditions are
+// returned unchanged.
+func TripleCondition(key string, values []string) (string, []string) {
+	switch key {
+	case DubboInterfaceKey:
+		out := make([]string, 0, len(values))
+		for _, value := range values {
+			switch {
+			case value == "*":
+				out = append(out, "*")
+			case strings.HasSuffix(value, "*"):
+				out = append(out, "/"+value)
+			default:
+				out = append(out, "/"+value+"/*")
+			}
+		}
+		return URLPathConditionKey, out
+	case DubboMethodKey:
+		out := make([]string, 0, len(values))
+		for _, value := range values {
+			if value == "*" {
+				out = append(out, "*")
+				continue
+			}
+			out = append(out, "*/"+value)
+		}
+		return URLPathConditionKey, out
+	case DubboGroupKey:
+		return HeaderConditionKey(tripleGroupHeader), values
+	case DubboVersionKey:
+		return HeaderConditionKey(tripleVersionHeader), values
+	}
+	return key, values
+}
+
+// DubboRPCValueMatches reports whether value matches a condition value of a
+// Dubbo condition.
+func DubboRPCValueMatches(pattern, value string) bool {
+	switch {
+	case pattern == "*":
+		return true
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(value, strings.TrimPrefix(pattern, "*"))
+	}
+	return pattern == value
+}
@@ -0,0 +1,89 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateDubboRPCCondition(t *testing.T) {
+	valid := map[string][]string{
+		DubboInterfaceKey: {"org.apache.dubbo.Greeter", "org.apache.*", "*"},
+		DubboMethodKey:    {"sayHello", "*"},
+		DubboGroupKey:     {"gray", "canary-*", "*-eu"},
+		DubboVersionKey:   {"1.0.0", "2.*"},
+	}
+	for key, values := range valid {
+		if err := ValidateDubboRPCCondition(key, values); err != nil {
+			t.Fatalf("ValidateDubboRPCCondition(%s, %v) = %v", key, values, err)
+		}
+	}
+	invalid := map[string][]string{
+		DubboInterfaceKey: {"*Greeter"},
+		DubboMethodKey:    {"say*"},
+		DubboGroupKey:     {"*gray*"},
+		DubboVersionKey:   {""},
+		"dubbo.protocol":  {"tri"},
+	}
+	for key, values := range invalid {
+		if err := ValidateDubboRPCCondition(key, values); err == nil {
+			t.Fatalf("ValidateDubboRPCCondition(%s, %v) = nil, want error", key, values)
+		}
+	}
+	if err := ValidateDubboRPCCondition(DubboInterfaceKey, []string{"org/Greeter"}); err == nil {
+		t.Fatal("interface with a slash accepted")
+	}
+}
+
+func TestTripleCondition(t *testing.T) {
+	for _, tt := range []struct {
+		key        string
+		values     []string
+		wantKey    string
+		wantValues []string
+	}{
+		{DubboInterfaceKey, []string{"org.Greeter", "org.apache.*", "*"}, URLPathConditionKey, []string{"/org.Greeter/*", "/org.apache.*", "*"}},
+		{DubboMethodKey, []string{"sayHello", "*"}, URLPathConditionKey, []string{"*/sayHello", "*"}},
+		{DubboGroupKey, []string{"gray"}, "request.headers[tri-service-group]", []string{"gray"}},
+		{DubboVersionKey, []string{"1.0.0"}, "request.headers[tri-service-version]", []string{"1.0.0"}},
+		{"request.auth.claims[iss]", []string{"issuer"}, "request.auth.claims[iss]", []string{"issuer"}},
+	} {
+		key, values := TripleCondition(tt.key, tt.values)
+		if key != tt.wantKey || !reflect.DeepEqual(values, tt.wantValues) {
+			t.Fatalf("TripleCondition(%s, %v) = %s %v, want %s %v", tt.key, tt.values, key, values, tt.wantKey, tt.wantValues)
+		}
+	}
+}
+
+func TestDubboRPCValueMatches(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"*", "anything", true},
+		{"org.apache.*", "org.apache.Greeter", true},
+		{"org.apache.*", "com.Greeter", false},
+		{"*-eu", "gray-eu", true},
+		{"sayHello", "sayHello", true},
+		{"sayHello", "sayBye", false},
+	} {
+		if got := DubboRPCValueMatches(tt.pattern, tt.value); got != tt.want {
+			t.Fatalf("DubboRPCValueMatches(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
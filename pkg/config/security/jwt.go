@@ -0,0 +1,126 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package security holds security settings and reports shared by dubbod, its
+// data planes and dubboctl.
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JWTClaimToHeadersAnnotation copies claims of a verified token into
+	// request headers, as "header=claim,...". Nested claims use dots, e.g.
+	// "x-plan=plan.tier".
+	JWTClaimToHeadersAnnotation = "security.dubbo.apache.org/jwt-claim-to-headers"
+	// JWTForwardOriginalTokenAnnotation keeps the token in the request after
+	// verification when "true".
+	JWTForwardOriginalTokenAnnotation = "security.dubbo.apache.org/jwt-forward-original-token"
+	// JWTPayloadHeaderAnnotation names a header that receives the base64url
+	// encoded payload of a verified token.
+	JWTPayloadHeaderAnnotation = "security.dubbo.apache.org/jwt-payload-header"
+
+	// JWTClaimHeaderPrefix marks route header matches that match a claim of
+	// the verified token instead of a header sent by the caller. Only routes
+	// attached to a Gateway honour them: dxgate verifies the token, drops
+	// incoming headers with this prefix and copies the claims in before it
+	// routes. Routes attached to a Service are matched by the proxyless
+	// caller, where nothing is verified yet, so such matches are left out
+	// there and reported on the route status.
+	JWTClaimHeaderPrefix = "x-jwt-claim-"
+)
+
+var headerNameRegexp = regexp.MustCompile("^[a-z0-9!#$%&'*+\\-.^_`|~]+$")
+
+// ClaimToHeader copies one claim into one request header.
+type ClaimToHeader struct {
+	Header string
+	Claim  string
+}
+
+// JWTOutput is what a RequestAuthentication hands on from verified tokens.
+type JWTOutput struct {
+	ClaimToHeaders       []ClaimToHeader
+	ForwardOriginalToken bool
+	PayloadHeader        string
+}
+
+// JWTOutputFromAnnotations parses the JWT output annotations of a
+// RequestAuthentication. They apply to every JWT rule of the resource.
+func JWTOutputFromAnnotations(annotations map[string]string) (JWTOutput, error) {
+	out := JWTOutput{}
+	if raw := strings.TrimSpace(annotations[JWTClaimToHeadersAnnotation]); raw != "" {
+		seen := map[string]struct{}{}
+		for _, entry := range strings.Split(raw, ",") {
+			header, claim, found := strings.Cut(strings.TrimSpace(entry), "=")
+			header = strings.ToLower(strings.TrimSpace(header))
+			claim = strings.TrimSpace(claim)
+			if !found || claim == "" {
+				return JWTOutput{}, fmt.Errorf("%s: entry %q must be header=claim", JWTClaimToHeadersAnnotation, entry)
+			}
+			if err := validateOutputHeader(header); err != nil {
+				return JWTOutput{}, fmt.Errorf("%s: %v", JWTClaimToHeadersAnnotation, err)
+			}
+			if _, dup := seen[header]; dup {
+				return JWTOutput{}, fmt.Errorf("%s: header %s is set twice", JWTClaimToHeadersAnnotation, header)
+			}
+			seen[header] = struct{}{}
+			out.ClaimToHeaders = append(out.ClaimToHeaders, ClaimToHeader{Header: header, Claim: claim})
+		}
+	}
+	if raw := strings.TrimSpace(annotations[JWTForwardOriginalTokenAnnotation]); raw != "" {
+		forward, err := strconv.ParseBool(raw)
+		if err != nil {
+			return JWTOutput{}, fmt.Errorf("%s: %v", JWTForwardOriginalTokenAnnotation, err)
+		}
+		out.ForwardOriginalToken = forward
+	}
+	if raw := strings.ToLower(strings.TrimSpace(annotations[JWTPayloadHeaderAnnotation])); raw != "" {
+		if err := validateOutputHeader(raw); err != nil {
+			return JWTOutput{}, fmt.Errorf("%s: %v", JWTPayloadHeaderAnnotation, err)
+		}
+		out.PayloadHeader = raw
+	}
+	return out, nil
+}
+
+func validateOutputHeader(header string) error {
+	if !headerNameRegexp.MatchString(header) {
+		return fmt.Errorf("invalid header name %q", header)
+	}
+	if strings.HasPrefix(header, JWTClaimHeaderPrefix) {
+		return fmt.Errorf("header %s uses the prefix %s reserved for claim routing", header, JWTClaimHeaderPrefix)
+	}
+	return nil
+}
+
+// ClaimFromRouteHeader returns the claim a route header match refers to.
+// The claim keeps the case it was written with in the route.
+func ClaimFromRouteHeader(name string) (string, bool) {
+	if len(name) <= len(JWTClaimHeaderPrefix) || !strings.EqualFold(name[:len(JWTClaimHeaderPrefix)], JWTClaimHeaderPrefix) {
+		return "", false
+	}
+	return name[len(JWTClaimHeaderPrefix):], true
+}
+
+// ClaimRouteHeader is the header a verified claim is copied to for routing.
+func ClaimRouteHeader(claim string) string {
+	return JWTClaimHeaderPrefix + strings.ToLower(claim)
+}
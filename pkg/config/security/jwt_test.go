@@ -0,0 +1,83 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJWTOutputFromAnnotations(t *testing.T) {
+	out, err := JWTOutputFromAnnotations(map[string]string{
+		JWTClaimToHeadersAnnotation:       "X-Tenant=tenant, x-plan = plan.tier",
+		JWTForwardOriginalTokenAnnotation: "true",
+		JWTPayloadHeaderAnnotation:        "X-Jwt-Payload",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := JWTOutput{
+		ClaimToHeaders: []ClaimToHeader{
+			{Header: "x-tenant", Claim: "tenant"},
+			{Header: "x-plan", Claim: "plan.tier"},
+		},
+		ForwardOriginalToken: true,
+		PayloadHeader:        "x-jwt-payload",
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %+v, want %+v", out, want)
+	}
+
+	if out, err := JWTOutputFromAnnotations(nil); err != nil || !reflect.DeepEqual(out, JWTOutput{}) {
+		t.Fatalf("expected empty output, got %+v, %v", out, err)
+	}
+}
+
+func TestJWTOutputFromAnnotationsRejectsInvalid(t *testing.T) {
+	cases := map[string]map[string]string{
+		"missing claim":    {JWTClaimToHeadersAnnotation: "x-tenant"},
+		"bad header":       {JWTClaimToHeadersAnnotation: "x tenant=tenant"},
+		"pseudo header":    {JWTClaimToHeadersAnnotation: ":authority=aud"},
+		"duplicate header": {JWTClaimToHeadersAnnotation: "x-a=sub,X-A=iss"},
+		"reserved prefix":  {JWTClaimToHeadersAnnotation: "x-jwt-claim-tenant=tenant"},
+		"bad forward":      {JWTForwardOriginalTokenAnnotation: "sometimes"},
+		"bad payload":      {JWTPayloadHeaderAnnotation: "x payload"},
+	}
+	for name, annotations := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := JWTOutputFromAnnotations(annotations); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestClaimFromRouteHeader(t *testing.T) {
+	claim, ok := ClaimFromRouteHeader("X-JWT-Claim-tenantId")
+	if !ok || claim != "tenantId" {
+		t.Fatalf("got %q, %v", claim, ok)
+	}
+	if _, ok := ClaimFromRouteHeader("x-jwt-claim-"); ok {
+		t.Fatal("empty claim should not match")
+	}
+	if _, ok := ClaimFromRouteHeader("x-tenant"); ok {
+		t.Fatal("plain header should not match")
+	}
+	if got := ClaimRouteHeader("tenantId"); got != "x-jwt-claim-tenantid" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,163 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+)
+
+// Proxyless workloads serving a PERMISSIVE port count the connections they
+// accept on it in InboundConnectionsMetric, exposed on their metrics port.
+// dubbod collects the counters to tell which callers still use plaintext
+// before the port is moved to STRICT.
+//
+// Callers are labelled by workload rather than address so the series stay
+// bounded as pods come and go: the workload looks the peer address up in the
+// endpoints of its runtime config and reports "<namespace>/<workload>", or
+// UnknownSourceWorkload for addresses outside the mesh.
+const (
+	InboundConnectionsMetric = "dubbo_inbound_connections_total"
+
+	InboundConnectionServiceLabel         = "service"
+	InboundConnectionPortLabel            = "port"
+	InboundConnectionTransportLabel       = "transport"
+	InboundConnectionSourceWorkloadLabel  = "source_workload"
+	InboundConnectionSourcePrincipalLabel = "source_principal"
+
+	TransportPlaintext = "plaintext"
+	TransportMTLS      = "mtls"
+
+	UnknownSourceWorkload = "unknown"
+)
+
+// InboundConnectionLabels lists the labels of InboundConnectionsMetric.
+var InboundConnectionLabels = []string{
+	InboundConnectionServiceLabel,
+	InboundConnectionPortLabel,
+	InboundConnectionTransportLabel,
+	InboundConnectionSourceWorkloadLabel,
+	InboundConnectionSourcePrincipalLabel,
+}
+
+// InboundConnection is one series of InboundConnectionsMetric.
+type InboundConnection struct {
+	Service         string
+	Port            int
+	Transport       string
+	SourceWorkload  string
+	SourcePrincipal string
+	Count           float64
+}
+
+// ParseInboundConnections reads the InboundConnectionsMetric series out of a
+// Prometheus text exposition. Other metrics are ignored.
+func ParseInboundConnections(in io.Reader) ([]InboundConnection, error) {
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, err
+	}
+	family, ok := families[InboundConnectionsMetric]
+	if !ok {
+		return nil, nil
+	}
+	out := make([]InboundConnection, 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		conn := InboundConnection{}
+		for _, label := range metric.GetLabel() {
+			switch label.GetName() {
+			case InboundConnectionServiceLabel:
+				conn.Service = label.GetValue()
+			case InboundConnectionPortLabel:
+				port, err := strconv.Atoi(label.GetValue())
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid port %q", InboundConnectionsMetric, label.GetValue())
+				}
+				conn.Port = port
+			case InboundConnectionTransportLabel:
+				conn.Transport = label.GetValue()
+			case InboundConnectionSourceWorkloadLabel:
+				conn.SourceWorkload = label.GetValue()
+			case InboundConnectionSourcePrincipalLabel:
+				conn.SourcePrincipal = label.GetValue()
+			}
+		}
+		switch {
+		case metric.GetCounter() != nil:
+			conn.Count = metric.GetCounter().GetValue()
+		case metric.GetUntyped() != nil:
+			conn.Count = metric.GetUntyped().GetValue()
+		}
+		out = append(out, conn)
+	}
+	return out, nil
+}
+
+// MTLSReadinessReport is what dubbod serves on its mTLS readiness endpoint.
+type MTLSReadinessReport struct {
+	GeneratedAt time.Time              `json:"generatedAt"`
+	Services    []ServiceMTLSReadiness `json:"services"`
+}
+
+// ServiceMTLSReadiness lists who called one PERMISSIVE service port, split
+// by transport, since its workloads started.
+type ServiceMTLSReadiness struct {
+	Host             string                `json:"host"`
+	Namespace        string                `json:"namespace"`
+	Name             string                `json:"name"`
+	Port             int                   `json:"port"`
+	Mode             string                `json:"mode"`
+	ReportingPods    int                   `json:"reportingPods"`
+	UnreachablePods  []string              `json:"unreachablePods,omitempty"`
+	PlaintextCallers []MTLSReadinessCaller `json:"plaintextCallers,omitempty"`
+	MTLSCallers      []MTLSReadinessCaller `json:"mtlsCallers,omitempty"`
+}
+
+// MTLSReadinessCaller is one client of a service port. Workload is the
+// namespace/name of the calling workload, or UnknownSourceWorkload for
+// clients outside the mesh.
+type MTLSReadinessCaller struct {
+	Workload    string  `json:"workload,omitempty"`
+	Principal   string  `json:"principal,omitempty"`
+	Connections float64 `json:"connections"`
+}
+
+// ReadyForStrict reports whether every workload behind the port reported
+// and none of them accepted a plaintext connection.
+func (s ServiceMTLSReadiness) ReadyForStrict() bool {
+	return s.ReportingPods > 0 && len(s.UnreachablePods) == 0 && len(s.PlaintextCallers) == 0
+}
+
+// SortCallers orders callers by descending connection count.
+func SortCallers(callers []MTLSReadinessCaller) {
+	sort.Slice(callers, func(i, j int) bool {
+		if callers[i].Connections != callers[j].Connections {
+			return callers[i].Connections > callers[j].Connections
+		}
+		if callers[i].Workload != callers[j].Workload {
+			return callers[i].Workload < callers[j].Workload
+		}
+		return callers[i].Principal < callers[j].Principal
+	})
+}
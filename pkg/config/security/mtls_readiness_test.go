@@ -0,0 +1,73 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInboundConnections(t *testing.T) {
+	exposition := `# HELP grpc_server_started_total Total number of RPCs started on the server.
+# TYPE grpc_server_started_total counter
+grpc_server_started_total{grpc_method="Get"} 12
+# HELP dubbo_inbound_connections_total Inbound connections accepted by transport.
+# TYPE dubbo_inbound_connections_total counter
+dubbo_inbound_connections_total{service="reviews.foo.svc.cluster.local",port="9080",transport="plaintext",source_workload="unknown",source_principal=""} 3
+dubbo_inbound_connections_total{service="reviews.foo.svc.cluster.local",port="9080",transport="mtls",source_workload="foo/productpage",source_principal="spiffe://cluster.local/ns/foo/sa/productpage"} 5
+`
+	got, err := ParseInboundConnections(strings.NewReader(exposition))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]InboundConnection{
+		TransportPlaintext: {Service: "reviews.foo.svc.cluster.local", Port: 9080, Transport: TransportPlaintext, SourceWorkload: UnknownSourceWorkload, Count: 3},
+		TransportMTLS: {
+			Service: "reviews.foo.svc.cluster.local", Port: 9080, Transport: TransportMTLS, SourceWorkload: "foo/productpage",
+			SourcePrincipal: "spiffe://cluster.local/ns/foo/sa/productpage", Count: 5,
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d connections, want %d", len(got), len(want))
+	}
+	for _, conn := range got {
+		if !reflect.DeepEqual(conn, want[conn.Transport]) {
+			t.Fatalf("got %+v, want %+v", conn, want[conn.Transport])
+		}
+	}
+
+	if got, err := ParseInboundConnections(strings.NewReader("# TYPE up gauge\nup 1\n")); err != nil || got != nil {
+		t.Fatalf("expected no connections, got %v, %v", got, err)
+	}
+}
+
+func TestServiceMTLSReadinessReadyForStrict(t *testing.T) {
+	ready := ServiceMTLSReadiness{ReportingPods: 2, MTLSCallers: []MTLSReadinessCaller{{Workload: "foo/productpage", Connections: 1}}}
+	if !ready.ReadyForStrict() {
+		t.Fatal("expected ready")
+	}
+	for name, s := range map[string]ServiceMTLSReadiness{
+		"plaintext caller": {ReportingPods: 1, PlaintextCallers: []MTLSReadinessCaller{{Workload: UnknownSourceWorkload}}},
+		"no reports":       {},
+		"unreachable pod":  {ReportingPods: 1, UnreachablePods: []string{"foo/reviews-1"}},
+	} {
+		if s.ReadyForStrict() {
+			t.Fatalf("%s: expected not ready", name)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"math"
 	"net"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -28,12 +29,15 @@ import (
 	"github.com/apache/dubbo-kubernetes/pkg/config/constants"
 	"github.com/apache/dubbo-kubernetes/pkg/config/labels"
 	"github.com/apache/dubbo-kubernetes/pkg/config/protocol"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	telemetryconfig "github.com/apache/dubbo-kubernetes/pkg/config/telemetry"
 	"github.com/apache/dubbo-kubernetes/pkg/config/visibility"
 	networking "github.com/kdubbo/api/networking/v1alpha3"
 	security "github.com/kdubbo/api/security/v1alpha3"
 	telemetry "github.com/kdubbo/api/telemetry/v1alpha3"
+	"golang.org/x/net/http/httpguts"
 	kvalidation "k8s.io/apimachinery/pkg/util/validation"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // ValidateAuthorizationPolicy checks that an AuthorizationPolicy is well-formed.
@@ -46,12 +50,23 @@ var ValidateAuthorizationPolicy = validateFunc(
 		v := Validation{}
 		v = appendValidation(v, validateWorkloadSelector(spec.GetSelector()))
 		if spec.GetAction() != security.AuthorizationPolicy_ALLOW &&
-			spec.GetAction() != security.AuthorizationPolicy_DENY {
+			spec.GetAction() != security.AuthorizationPolicy_DENY &&
+			spec.GetAction() != security.AuthorizationPolicy_CUSTOM {
 			v = appendValidation(v, fmt.Errorf("unsupported action %q", spec.GetAction()))
 		}
 		if spec.GetAction() == security.AuthorizationPolicy_DENY && len(spec.GetRules()) == 0 {
 			v = appendValidation(v, fmt.Errorf("a DENY policy must have at least one rule; an empty DENY policy matches nothing"))
 		}
+		if spec.GetAction() == security.AuthorizationPolicy_CUSTOM {
+			if strings.TrimSpace(spec.GetProvider().GetName()) == "" {
+				v = appendValidation(v, fmt.Errorf("a CUSTOM policy must name an external authorization provider"))
+			}
+			if len(spec.GetRules()) == 0 {
+				v = appendValidation(v, fmt.Errorf("a CUSTOM policy must have at least one rule selecting the requests to check"))
+			}
+		} else if spec.GetProvider() != nil {
+			v = appendValidation(v, fmt.Errorf("provider is only valid with the CUSTOM action"))
+		}
 		for i, rule := range spec.GetRules() {
 			if rule == nil {
 				v = appendValidation(v, fmt.Errorf("rule[%d] must not be null", i))
@@ -88,7 +103,15 @@ var ValidateAuthorizationPolicy = validateFunc(
 					}
 				}
 			}
-			if hasWorkloadPrincipal && (hasRequestPrincipal || len(rule.GetWhen()) > 0) {
+			// Dubbo RPC conditions describe the call, not the caller, so they
+			// combine with workload principals.
+			hasJWTCondition := false
+			for _, when := range rule.GetWhen() {
+				if when != nil && !securityconfig.IsDubboRPCKey(when.GetKey()) {
+					hasJWTCondition = true
+				}
+			}
+			if hasWorkloadPrincipal && (hasRequestPrincipal || hasJWTCondition) {
 				v = appendValidation(v, fmt.Errorf("rule[%d] must not mix workload principal and JWT constraints", i))
 			}
 			for j, when := range rule.GetWhen() {
@@ -102,6 +125,15 @@ var ValidateAuthorizationPolicy = validateFunc(
 				if len(when.GetValues()) == 0 && len(when.GetNotValues()) == 0 {
 					v = appendValidation(v, fmt.Errorf("rule[%d].when[%d] must specify values or notValues", i, j))
 				}
+				if strings.HasPrefix(when.GetKey(), "dubbo.") {
+					if !securityconfig.IsDubboRPCKey(when.GetKey()) {
+						v = appendValidation(v, fmt.Errorf("rule[%d].when[%d].key %s is not one of %s",
+							i, j, when.GetKey(), strings.Join(securityconfig.DubboRPCKeys, ", ")))
+					} else if err := securityconfig.ValidateDubboRPCCondition(when.GetKey(),
+						append(append([]string(nil), when.GetValues()...), when.GetNotValues()...)); err != nil {
+						v = appendValidation(v, fmt.Errorf("rule[%d].when[%d]: %v", i, j, err))
+					}
+				}
 			}
 		}
 		return v.Unwrap()
@@ -181,6 +213,9 @@ var ValidateRequestAuthentication = validateFunc(
 				}
 			}
 		}
+		if _, err := securityconfig.JWTOutputFromAnnotations(cfg.Annotations); err != nil {
+			v = appendValidation(v, err)
+		}
 		return v.Unwrap()
 	})
 
@@ -281,6 +316,64 @@ var ValidateFaultInjectionPolicy = RegisterValidateFunc("ValidateFaultInjectionP
 		return v.Unwrap()
 	})
 
+// ValidateRateLimitPolicy checks that a RateLimitPolicy describes a token
+// bucket workloads can enforce.
+var ValidateRateLimitPolicy = RegisterValidateFunc("ValidateRateLimitPolicy",
+	func(cfg config.Config) (Warning, error) {
+		spec, ok := cfg.Spec.(*networking.RateLimitPolicy)
+		if !ok {
+			return nil, fmt.Errorf("cannot cast to RateLimitPolicy")
+		}
+		v := Validation{}
+		if len(spec.GetTargetRefs()) == 0 {
+			v = appendValidation(v, fmt.Errorf("targetRefs must not be empty"))
+		}
+		for i, ref := range spec.GetTargetRefs() {
+			if ref == nil {
+				v = appendValidation(v, fmt.Errorf("targetRefs[%d] must not be null", i))
+				continue
+			}
+			if ref.GetKind() != "Service" {
+				v = appendValidation(v, fmt.Errorf("targetRefs[%d].kind %q is not supported; only Service targets are applied", i, ref.GetKind()))
+			}
+			if group := strings.TrimSpace(ref.GetGroup()); group != "" && group != "core" {
+				v = appendValidation(v, fmt.Errorf("targetRefs[%d].group %q is not supported; use the core API group", i, group))
+			}
+			if ref.GetName() == "" {
+				v = appendValidation(v, fmt.Errorf("targetRefs[%d].name must not be empty", i))
+			}
+		}
+		if bucket := spec.GetTokenBucket(); bucket == nil {
+			v = appendValidation(v, fmt.Errorf("tokenBucket must be set"))
+		} else {
+			if bucket.GetMaxTokens() == 0 {
+				v = appendValidation(v, fmt.Errorf("tokenBucket.maxTokens must be greater than 0"))
+			}
+			if bucket.GetTokensPerFill() > bucket.GetMaxTokens() {
+				v = appendValidation(v, fmt.Errorf("tokenBucket.tokensPerFill %d must not exceed maxTokens %d", bucket.GetTokensPerFill(), bucket.GetMaxTokens()))
+			}
+			v = appendValidation(v, validatePositiveDuration("tokenBucket.fillInterval", bucket.GetFillInterval()))
+			if bucket.GetFillInterval() == nil {
+				v = appendValidation(v, fmt.Errorf("tokenBucket.fillInterval must be set"))
+			} else if bucket.GetFillInterval().CheckValid() == nil && bucket.GetFillInterval().AsDuration() < 50*time.Millisecond {
+				v = appendValidation(v, fmt.Errorf("tokenBucket.fillInterval must be at least 50ms"))
+			}
+		}
+		switch spec.GetKey() {
+		case networking.RateLimitPolicy_HEADER:
+			if spec.GetHeader() == "" {
+				v = appendValidation(v, fmt.Errorf("header must be set when key is HEADER"))
+			} else if !httpguts.ValidHeaderFieldName(spec.GetHeader()) {
+				v = appendValidation(v, fmt.Errorf("header %q is not a valid header name", spec.GetHeader()))
+			}
+		default:
+			if spec.GetHeader() != "" {
+				v = appendValidation(v, fmt.Errorf("header is only used when key is HEADER"))
+			}
+		}
+		return v.Unwrap()
+	})
+
 // ValidateDxgateService checks that a mesh-native LLM, MCP, or A2A backend can
 // be compiled into one unambiguous data-plane configuration.
 var ValidateDxgateService = RegisterValidateFunc("ValidateDxgateService",
@@ -722,3 +815,94 @@ var ValidateTelemetry = RegisterValidateFunc("ValidateTelemetry",
 		}
 		return v.Unwrap()
 	})
+
+var (
+	grpcServiceNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z_0-9]*(\.[A-Za-z_][A-Za-z_0-9]*)*$`)
+	grpcMethodNameRegexp  = regexp.MustCompile(`^[A-Za-z_][A-Za-z_0-9]*$`)
+)
+
+// ValidateGRPCRoute checks that a GRPCRoute can be compiled into proxyless RDS.
+var ValidateGRPCRoute = RegisterValidateFunc("ValidateGRPCRoute",
+	func(cfg config.Config) (Warning, error) {
+		spec, ok := cfg.Spec.(*gatewayv1.GRPCRouteSpec)
+		if !ok {
+			return nil, fmt.Errorf("cannot cast to GRPCRouteSpec")
+		}
+		v := Validation{}
+		for i, rule := range spec.Rules {
+			for j, match := range rule.Matches {
+				field := fmt.Sprintf("rules[%d].matches[%d]", i, j)
+				v = appendValidation(v, validateGRPCMethodMatch(field+".method", match.Method))
+				for k, header := range match.Headers {
+					if strings.TrimSpace(string(header.Name)) == "" {
+						v = appendValidation(v, fmt.Errorf("%s.headers[%d].name must be set", field, k))
+					}
+					if header.Type != nil && *header.Type == gatewayv1.GRPCHeaderMatchRegularExpression {
+						if _, err := regexp.Compile(header.Value); err != nil {
+							v = appendValidation(v, fmt.Errorf("%s.headers[%d].value is not a valid regular expression: %v", field, k, err))
+						}
+					}
+				}
+			}
+			v = appendValidation(v, validateGRPCRouteFilters(fmt.Sprintf("rules[%d].filters", i), rule.Filters))
+			for j, backend := range rule.BackendRefs {
+				field := fmt.Sprintf("rules[%d].backendRefs[%d]", i, j)
+				if backend.Kind != nil && *backend.Kind != "Service" {
+					v = appendValidation(v, fmt.Errorf("%s.kind %q is not supported; only Service backends are routed", field, *backend.Kind))
+				}
+				v = appendValidation(v, validateGRPCRouteFilters(field+".filters", backend.Filters))
+			}
+		}
+		return v.Unwrap()
+	})
+
+func validateGRPCMethodMatch(field string, match *gatewayv1.GRPCMethodMatch) error {
+	if match == nil {
+		return nil
+	}
+	service, method := "", ""
+	if match.Service != nil {
+		service = *match.Service
+	}
+	if match.Method != nil {
+		method = *match.Method
+	}
+	if service == "" && method == "" {
+		return fmt.Errorf("%s must set service or method", field)
+	}
+	if match.Type != nil && *match.Type == gatewayv1.GRPCMethodMatchRegularExpression {
+		var errs error
+		if _, err := regexp.Compile(service); err != nil {
+			errs = AppendErrors(errs, fmt.Errorf("%s.service is not a valid regular expression: %v", field, err))
+		}
+		if _, err := regexp.Compile(method); err != nil {
+			errs = AppendErrors(errs, fmt.Errorf("%s.method is not a valid regular expression: %v", field, err))
+		}
+		return errs
+	}
+	if service != "" && !grpcServiceNameRegexp.MatchString(service) {
+		return fmt.Errorf("%s.service %q is not a valid gRPC service name", field, service)
+	}
+	if method != "" && !grpcMethodNameRegexp.MatchString(method) {
+		return fmt.Errorf("%s.method %q is not a valid gRPC method name", field, method)
+	}
+	return nil
+}
+
+func validateGRPCRouteFilters(field string, filters []gatewayv1.GRPCRouteFilter) error {
+	var errs error
+	seen := map[gatewayv1.GRPCRouteFilterType]bool{}
+	for i, filter := range filters {
+		switch filter.Type {
+		case gatewayv1.GRPCRouteFilterRequestHeaderModifier, gatewayv1.GRPCRouteFilterResponseHeaderModifier:
+			if seen[filter.Type] {
+				errs = AppendErrors(errs, fmt.Errorf("%s[%d]: %s may only be set once", field, i, filter.Type))
+			}
+			seen[filter.Type] = true
+		case gatewayv1.GRPCRouteFilterRequestMirror:
+		default:
+			errs = AppendErrors(errs, fmt.Errorf("%s[%d].type %q is not supported by proxyless gRPC", field, i, filter.Type))
+		}
+	}
+	return errs
+}
@@ -25,10 +25,12 @@ import (
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/apache/dubbo-kubernetes/pkg/config"
+	securityconfig "github.com/apache/dubbo-kubernetes/pkg/config/security"
 	networking "github.com/kdubbo/api/networking/v1alpha3"
 	security "github.com/kdubbo/api/security/v1alpha3"
 	telemetry "github.com/kdubbo/api/telemetry/v1alpha3"
 	typev1alpha3 "github.com/kdubbo/api/type/v1alpha3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func makeConfig(spec config.Spec) config.Config {
@@ -73,6 +75,41 @@ func TestValidateAuthorizationPolicy(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "workload principal with Dubbo RPC conditions",
+			spec: &security.AuthorizationPolicy{
+				Action: security.AuthorizationPolicy_ALLOW,
+				Rules: []*security.Rule{{
+					From: []*security.From{{
+						Source: &security.Source{Principals: []string{"cluster.local/ns/default/sa/client"}},
+					}},
+					When: []*security.Condition{
+						{Key: "dubbo.interface", Values: []string{"org.apache.dubbo.Greeter"}},
+						{Key: "dubbo.method", NotValues: []string{"delete"}},
+						{Key: "dubbo.version", Values: []string{"1.*"}},
+					},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown Dubbo RPC condition key",
+			spec: &security.AuthorizationPolicy{
+				Rules: []*security.Rule{{
+					When: []*security.Condition{{Key: "dubbo.protocol", Values: []string{"tri"}}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Dubbo method pattern",
+			spec: &security.AuthorizationPolicy{
+				Rules: []*security.Rule{{
+					When: []*security.Condition{{Key: "dubbo.method", Values: []string{"get*"}}},
+				}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty deny policy",
 			spec: &security.AuthorizationPolicy{
@@ -156,6 +193,65 @@ func TestValidateAuthorizationPolicy(t *testing.T) {
 	}
 }
 
+func TestValidateCustomAuthorizationPolicy(t *testing.T) {
+	rule := &security.Rule{From: []*security.From{{
+		Source: &security.Source{Principals: []string{"cluster.local/ns/default/sa/client"}},
+	}}}
+	provider := func(name string) *security.AuthorizationPolicy_Provider {
+		return &security.AuthorizationPolicy_Provider{Provider: &security.AuthorizationPolicy_ExtensionProvider{Name: name}}
+	}
+	cases := []struct {
+		name    string
+		spec    *security.AuthorizationPolicy
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			spec: &security.AuthorizationPolicy{
+				Action:       security.AuthorizationPolicy_CUSTOM,
+				ActionDetail: provider("opa"),
+				Rules:        []*security.Rule{rule},
+			},
+		},
+		{
+			name: "empty provider",
+			spec: &security.AuthorizationPolicy{
+				Action:       security.AuthorizationPolicy_CUSTOM,
+				ActionDetail: provider(" "),
+				Rules:        []*security.Rule{rule},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no provider",
+			spec:    &security.AuthorizationPolicy{Action: security.AuthorizationPolicy_CUSTOM, Rules: []*security.Rule{rule}},
+			wantErr: true,
+		},
+		{
+			name: "provider on deny action",
+			spec: &security.AuthorizationPolicy{
+				Action:       security.AuthorizationPolicy_DENY,
+				ActionDetail: provider("opa"),
+				Rules:        []*security.Rule{rule},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no rules",
+			spec:    &security.AuthorizationPolicy{Action: security.AuthorizationPolicy_CUSTOM, ActionDetail: provider("opa")},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValidateAuthorizationPolicy(makeConfig(tc.spec))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err=%v, wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidatePeerAuthentication(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -206,9 +302,10 @@ func TestValidatePeerAuthentication(t *testing.T) {
 
 func TestValidateRequestAuthentication(t *testing.T) {
 	cases := []struct {
-		name    string
-		spec    *security.RequestAuthentication
-		wantErr bool
+		name        string
+		spec        *security.RequestAuthentication
+		annotations map[string]string
+		wantErr     bool
 	}{
 		{
 			name:    "empty",
@@ -253,10 +350,31 @@ func TestValidateRequestAuthentication(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "claim to headers",
+			spec: &security.RequestAuthentication{
+				JwtRules: []*security.JWTRule{{Issuer: "issuer"}},
+			},
+			annotations: map[string]string{
+				securityconfig.JWTClaimToHeadersAnnotation:       "x-tenant=tenant",
+				securityconfig.JWTForwardOriginalTokenAnnotation: "true",
+			},
+			wantErr: false,
+		},
+		{
+			name: "claim to reserved header",
+			spec: &security.RequestAuthentication{
+				JwtRules: []*security.JWTRule{{Issuer: "issuer"}},
+			},
+			annotations: map[string]string{securityconfig.JWTClaimToHeadersAnnotation: "x-jwt-claim-tenant=tenant"},
+			wantErr:     true,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := ValidateRequestAuthentication(makeConfig(tc.spec))
+			cfg := makeConfig(tc.spec)
+			cfg.Annotations = tc.annotations
+			_, err := ValidateRequestAuthentication(cfg)
 			if (err != nil) != tc.wantErr {
 				t.Fatalf("got err=%v, wantErr=%v", err, tc.wantErr)
 			}
@@ -423,6 +541,95 @@ func TestValidateFaultInjectionPolicy(t *testing.T) {
 	}
 }
 
+func TestValidateRateLimitPolicy(t *testing.T) {
+	validRef := []*networking.PolicyTargetReference{{Kind: "Service", Name: "backend"}}
+	bucket := func(maxTokens, tokensPerFill uint32, fillInterval time.Duration) *networking.TokenBucket {
+		return &networking.TokenBucket{MaxTokens: maxTokens, TokensPerFill: tokensPerFill, FillInterval: durationpb.New(fillInterval)}
+	}
+	cases := []struct {
+		name    string
+		spec    *networking.RateLimitPolicy
+		wantErr bool
+	}{
+		{
+			name: "per caller",
+			spec: &networking.RateLimitPolicy{
+				TargetRefs:  validRef,
+				TokenBucket: bucket(100, 10, time.Second),
+				Key:         networking.RateLimitPolicy_CALLER,
+			},
+		},
+		{
+			name: "per header",
+			spec: &networking.RateLimitPolicy{
+				TargetRefs:  validRef,
+				TokenBucket: bucket(100, 0, time.Second),
+				Key:         networking.RateLimitPolicy_HEADER,
+				Header:      "x-tenant",
+			},
+		},
+		{
+			name:    "no target refs",
+			spec:    &networking.RateLimitPolicy{TokenBucket: bucket(1, 0, time.Second)},
+			wantErr: true,
+		},
+		{
+			name: "unsupported target",
+			spec: &networking.RateLimitPolicy{
+				TargetRefs:  []*networking.PolicyTargetReference{{Kind: "Gateway", Name: "edge"}},
+				TokenBucket: bucket(1, 0, time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no bucket",
+			spec:    &networking.RateLimitPolicy{TargetRefs: validRef},
+			wantErr: true,
+		},
+		{
+			name:    "no tokens",
+			spec:    &networking.RateLimitPolicy{TargetRefs: validRef, TokenBucket: bucket(0, 0, time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "fill above max",
+			spec:    &networking.RateLimitPolicy{TargetRefs: validRef, TokenBucket: bucket(1, 2, time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "fill interval below CRD minimum",
+			spec:    &networking.RateLimitPolicy{TargetRefs: validRef, TokenBucket: bucket(1, 0, time.Millisecond)},
+			wantErr: true,
+		},
+		{
+			name: "header key without header",
+			spec: &networking.RateLimitPolicy{
+				TargetRefs:  validRef,
+				TokenBucket: bucket(1, 0, time.Second),
+				Key:         networking.RateLimitPolicy_HEADER,
+			},
+			wantErr: true,
+		},
+		{
+			name: "header without header key",
+			spec: &networking.RateLimitPolicy{
+				TargetRefs:  validRef,
+				TokenBucket: bucket(1, 0, time.Second),
+				Header:      "x-tenant",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValidateRateLimitPolicy(makeConfig(tc.spec))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err=%v, wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateDxgateService(t *testing.T) {
 	openAI := func() *networking.DxgateService {
 		return &networking.DxgateService{
@@ -745,3 +952,65 @@ func TestValidateTelemetry(t *testing.T) {
 		t.Fatal("ValidateTelemetry() accepted selector on meshlevel Telemetry")
 	}
 }
+
+func TestValidateGRPCRoute(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	regex := gatewayv1.GRPCMethodMatchRegularExpression
+	methodRule := func(match *gatewayv1.GRPCMethodMatch, filters ...gatewayv1.GRPCRouteFilter) *gatewayv1.GRPCRouteSpec {
+		return &gatewayv1.GRPCRouteSpec{Rules: []gatewayv1.GRPCRouteRule{{
+			Matches: []gatewayv1.GRPCRouteMatch{{Method: match}},
+			Filters: filters,
+		}}}
+	}
+	cases := []struct {
+		name    string
+		spec    *gatewayv1.GRPCRouteSpec
+		wantErr bool
+	}{
+		{
+			name: "service and method",
+			spec: methodRule(&gatewayv1.GRPCMethodMatch{Service: ptr("org.apache.dubbo.GreetService"), Method: ptr("Greet")},
+				gatewayv1.GRPCRouteFilter{Type: gatewayv1.GRPCRouteFilterRequestHeaderModifier}),
+		},
+		{
+			name: "regular expression",
+			spec: methodRule(&gatewayv1.GRPCMethodMatch{Type: &regex, Service: ptr("org\\.apache\\..*")}),
+		},
+		{
+			name:    "empty method match",
+			spec:    methodRule(&gatewayv1.GRPCMethodMatch{}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid service name",
+			spec:    methodRule(&gatewayv1.GRPCMethodMatch{Service: ptr("org/apache")}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid regular expression",
+			spec:    methodRule(&gatewayv1.GRPCMethodMatch{Type: &regex, Method: ptr("Greet(")}),
+			wantErr: true,
+		},
+		{
+			name: "extension ref filter",
+			spec: methodRule(&gatewayv1.GRPCMethodMatch{Method: ptr("Greet")},
+				gatewayv1.GRPCRouteFilter{Type: gatewayv1.GRPCRouteFilterExtensionRef}),
+			wantErr: true,
+		},
+		{
+			name: "duplicate header modifier",
+			spec: methodRule(&gatewayv1.GRPCMethodMatch{Method: ptr("Greet")},
+				gatewayv1.GRPCRouteFilter{Type: gatewayv1.GRPCRouteFilterRequestHeaderModifier},
+				gatewayv1.GRPCRouteFilter{Type: gatewayv1.GRPCRouteFilterRequestHeaderModifier}),
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValidateGRPCRoute(makeConfig(tc.spec))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err=%v, wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -54,6 +54,10 @@ type GenerateBootstrapOptions struct {
 	XdsUdsPath       string
 	DiscoveryAddress string
 	CertDir          string
+	// SPIFFEBundleMap points the certificate provider at the SPIFFE bundle map
+	// in CertDir, so peers are verified against the roots of their own trust
+	// domain.
+	SPIFFEBundleMap bool
 }
 
 type CertificateProvider struct {
@@ -62,10 +66,13 @@ type CertificateProvider struct {
 }
 
 type FileWatcherCertProviderConfig struct {
-	CertificateFile   string          `json:"certificate_file,omitempty"`
-	PrivateKeyFile    string          `json:"private_key_file,omitempty"`
-	CACertificateFile string          `json:"ca_certificate_file,omitempty"`
-	RefreshDuration   json.RawMessage `json:"refresh_interval,omitempty"`
+	CertificateFile   string `json:"certificate_file,omitempty"`
+	PrivateKeyFile    string `json:"private_key_file,omitempty"`
+	CACertificateFile string `json:"ca_certificate_file,omitempty"`
+	// SPIFFEBundleMapFile takes precedence over CACertificateFile for peer
+	// verification in gRPC releases that support it.
+	SPIFFEBundleMapFile string          `json:"spiffe_trust_bundle_map_file,omitempty"`
+	RefreshDuration     json.RawMessage `json:"refresh_interval,omitempty"`
 }
 
 type ChannelCreds struct {
@@ -152,15 +159,19 @@ func GenerateBootstrap(opts GenerateBootstrapOptions) (*Bootstrap, error) {
 			return nil, err
 		}
 
+		providerConfig := FileWatcherCertProviderConfig{
+			PrivateKeyFile:    path.Join(opts.CertDir, "key.pem"),
+			CertificateFile:   path.Join(opts.CertDir, "cert-chain.pem"),
+			CACertificateFile: path.Join(opts.CertDir, "root-cert.pem"),
+			RefreshDuration:   refresh,
+		}
+		if opts.SPIFFEBundleMap {
+			providerConfig.SPIFFEBundleMapFile = path.Join(opts.CertDir, "spiffe-bundle-map.json")
+		}
 		bootstrap.CertProviders = map[string]CertificateProvider{
 			"default": {
 				PluginName: "file_watcher",
-				Config: FileWatcherCertProviderConfig{
-					PrivateKeyFile:    path.Join(opts.CertDir, "key.pem"),
-					CertificateFile:   path.Join(opts.CertDir, "cert-chain.pem"),
-					CACertificateFile: path.Join(opts.CertDir, "root-cert.pem"),
-					RefreshDuration:   refresh,
-				},
+				Config:     providerConfig,
 			},
 		}
 	}
@@ -200,7 +211,11 @@ func (b *Bootstrap) FileWatcherProvider() *FileWatcherCertProviderConfig {
 }
 
 func (c *FileWatcherCertProviderConfig) FilePaths() []string {
-	return []string{c.CertificateFile, c.PrivateKeyFile, c.CACertificateFile}
+	paths := []string{c.CertificateFile, c.PrivateKeyFile, c.CACertificateFile}
+	if c.SPIFFEBundleMapFile != "" {
+		paths = append(paths, c.SPIFFEBundleMapFile)
+	}
+	return paths
 }
 
 func (cp *CertificateProvider) UnmarshalJSON(data []byte) error {
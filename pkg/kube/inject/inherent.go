@@ -46,6 +46,9 @@ const (
 	InherentGRPCKeepaliveTimeout                = "10s"
 	InherentGRPCConfigFileName                  = "dubbo-grpc-xds.json"
 	InherentGRPCConfigPath                      = InherentXDSMountPath + "/" + InherentGRPCConfigFileName
+	// InherentGRPCSecretLabel marks the Secrets dubbod writes for inherent gRPC
+	// workloads, so they can be watched without caching every Secret.
+	InherentGRPCSecretLabel = "dubbo.apache.org/inherent-grpc"
 	// InherentGatewayInboundPort is the managed dxgate listener. Application
 	// workloads keep their own declared ports in proxyless mode.
 	InherentGatewayInboundPort = 15080
@@ -18,12 +18,14 @@ package security
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 
 	dubbolog "github.com/apache/dubbo-kubernetes/pkg/log"
@@ -57,6 +59,13 @@ const (
 	CertSigner = "CertSigner"
 )
 
+const (
+	// BearerTokenPrefix is the prefix of the authorization header carrying a JWT.
+	BearerTokenPrefix = "Bearer "
+	// AuthorizationMeta is the gRPC metadata and HTTP header carrying the bearer token.
+	AuthorizationMeta = "authorization"
+)
+
 type AuthContext struct {
 	GrpcContext context.Context
 	Request     *http.Request
@@ -74,6 +83,9 @@ type SecretItem struct {
 	ResourceName     string
 	CreatedTime      time.Time
 	ExpireTime       time.Time
+	// CRL lists the certificates revoked by the mesh CA. It is only set on
+	// root certificate resources.
+	CRL []byte
 }
 
 type SecretManager interface {
@@ -90,6 +102,7 @@ type AuthSource int
 
 const (
 	AuthSourceClientCertificate AuthSource = iota
+	AuthSourceIDToken
 )
 
 type KubernetesInfo struct {
@@ -135,6 +148,12 @@ type Options struct {
 	FileDebounceDuration time.Duration
 	WorkloadNamespace    string
 	ServiceAccount       string
+	// FederatedTrustBundleFilePath is a SPIFFE bundle map with the roots of
+	// federated trust domains, merged into ROOTCA and watched for changes.
+	FederatedTrustBundleFilePath string
+	// CRLFilePath is a PEM file with the CA revocation lists, served next to
+	// ROOTCA and watched for changes.
+	CRLFilePath string
 }
 
 type CredFetcher interface {
@@ -236,3 +255,30 @@ func GetConnectionAddress(ctx context.Context) string {
 	}
 	return peerAddr
 }
+
+// ExtractBearerToken returns the JWT from the authorization metadata of an
+// incoming gRPC call.
+func ExtractBearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no metadata is attached")
+	}
+	for _, value := range md.Get(AuthorizationMeta) {
+		if strings.HasPrefix(value, BearerTokenPrefix) {
+			return strings.TrimPrefix(value, BearerTokenPrefix), nil
+		}
+	}
+	return "", fmt.Errorf("no bearer token exists in HTTP authorization header")
+}
+
+// ExtractRequestToken returns the JWT from the authorization header of an HTTP request.
+func ExtractRequestToken(req *http.Request) (string, error) {
+	value := req.Header.Get(AuthorizationMeta)
+	if value == "" {
+		return "", fmt.Errorf("no HTTP authorization header exists")
+	}
+	if strings.HasPrefix(value, BearerTokenPrefix) {
+		return strings.TrimPrefix(value, BearerTokenPrefix), nil
+	}
+	return "", fmt.Errorf("no bearer token exists in HTTP authorization header")
+}
@@ -0,0 +1,240 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffe
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
+	jose "github.com/go-jose/go-jose/v4"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+)
+
+// FederatedTrustDomain is a foreign trust domain whose workloads are trusted,
+// together with where its root certificates come from.
+type FederatedTrustDomain struct {
+	TrustDomain string
+	// BundleURL is a SPIFFE bundle endpoint.
+	BundleURL string
+	// PEM holds the roots inline, as PEM or as a SPIFFE bundle document.
+	PEM string
+}
+
+// FederatedTrustDomains returns the foreign trust domains listed in the
+// trustDomains of mesh config caCertificates entries. An entry names its roots
+// either inline in pem or through spiffeBundleUrl. The mesh trust domain and
+// its aliases are never federated, since a bundle listing them would let a
+// foreign CA issue local identities. Entries are sorted by trust domain and
+// the first entry naming a trust domain wins.
+func FederatedTrustDomains(caCertificates []*meshv1alpha1.MeshConfig_CertificateData,
+	trustDomain string, trustDomainAliases []string,
+) []FederatedTrustDomain {
+	local := sets.New(trustDomainAliases...).Insert(sanitizeTrustDomain(trustDomain))
+	seen := sets.New[string]()
+	var out []FederatedTrustDomain
+	for _, entry := range caCertificates {
+		if entry.GetSpiffeBundleUrl() == "" && entry.GetPem() == "" {
+			continue
+		}
+		for _, td := range entry.GetTrustDomains() {
+			td = strings.TrimSpace(td)
+			if err := ValidateTrustDomain(td); err != nil {
+				log.Warnf("Ignoring federated trust domain: %v", err)
+				continue
+			}
+			if local.Contains(td) {
+				log.Warnf("Federated trust domain %s is the mesh trust domain or one of its aliases, skipping", td)
+				continue
+			}
+			if seen.InsertContains(td) {
+				continue
+			}
+			out = append(out, FederatedTrustDomain{
+				TrustDomain: td,
+				BundleURL:   entry.GetSpiffeBundleUrl(),
+				PEM:         entry.GetPem(),
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TrustDomain < out[j].TrustDomain })
+	return out
+}
+
+// ValidateTrustDomain rejects trust domains that cannot appear in a SPIFFE ID.
+func ValidateTrustDomain(td string) error {
+	if td == "" {
+		return fmt.Errorf("trust domain must not be empty")
+	}
+	if strings.ContainsAny(td, "/: \t") {
+		return fmt.Errorf("trust domain %q must not contain '/', ':' or whitespace", td)
+	}
+	return nil
+}
+
+// DecodeTrustBundle returns the root certificates in data, which is either PEM
+// or a SPIFFE bundle document.
+func DecodeTrustBundle(data []byte) ([]*x509.Certificate, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("trust bundle is empty")
+	}
+	if data[0] == '{' {
+		doc := new(bundleDoc)
+		if err := json.Unmarshal(data, doc); err != nil {
+			return nil, fmt.Errorf("failed to decode SPIFFE bundle: %v", err)
+		}
+		return doc.x509Authorities()
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("trust bundle holds no PEM certificate")
+	}
+	return certs, nil
+}
+
+// bundleMap is the SPIFFE bundle map read by the gRPC file watcher certificate
+// provider: one SPIFFE bundle document per trust domain.
+type bundleMap struct {
+	TrustDomains map[string]*bundleDoc `json:"trust_domains"`
+}
+
+// EncodeBundleMap encodes PEM roots by trust domain as a SPIFFE bundle map, so
+// a peer verifies each identity against the roots of its own trust domain only.
+func EncodeBundleMap(bundles map[string][]byte) ([]byte, error) {
+	out := bundleMap{TrustDomains: make(map[string]*bundleDoc, len(bundles))}
+	for td, bundle := range bundles {
+		roots, err := DecodeTrustBundle(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("trust domain %s: %v", td, err)
+		}
+		doc := new(bundleDoc)
+		for _, root := range roots {
+			doc.Keys = append(doc.Keys, jose.JSONWebKey{
+				Key:          root.PublicKey,
+				Certificates: []*x509.Certificate{root},
+				Use:          "x509-svid",
+			})
+		}
+		out.TrustDomains[td] = doc
+	}
+	return json.Marshal(out)
+}
+
+// DecodeBundleMap returns the root certificates of each trust domain in a
+// SPIFFE bundle map.
+func DecodeBundleMap(data []byte) (map[string][]*x509.Certificate, error) {
+	m := new(bundleMap)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to decode SPIFFE bundle map: %v", err)
+	}
+	out := make(map[string][]*x509.Certificate, len(m.TrustDomains))
+	for td, doc := range m.TrustDomains {
+		if doc == nil {
+			continue
+		}
+		roots, err := doc.x509Authorities()
+		if err != nil {
+			return nil, fmt.Errorf("trust domain %s: %v", td, err)
+		}
+		out[td] = roots
+	}
+	return out, nil
+}
+
+// CertsToPEM encodes certs as a PEM bundle.
+func CertsToPEM(certs []*x509.Certificate) []byte {
+	var out []byte
+	for _, cert := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return out
+}
+
+// ExpandWithTrustDomainAliases is ExpandWithTrustDomains for identities of the
+// local trust domain only: an identity in trustDomain or one of its aliases is
+// expanded to all of them, while identities of federated trust domains are kept
+// as they are so a foreign workload is never mistaken for a local one.
+func ExpandWithTrustDomainAliases(spiffeIdentities sets.String, trustDomain string, trustDomainAliases []string) sets.String {
+	if len(trustDomainAliases) == 0 {
+		return spiffeIdentities
+	}
+	local := sets.New(trustDomainAliases...).Insert(sanitizeTrustDomain(trustDomain))
+	out := sets.New[string]()
+	for id := range spiffeIdentities {
+		out.Insert(id)
+		if !strings.HasPrefix(id, URIPrefix) {
+			continue
+		}
+		m, err := ParseIdentity(id)
+		if err != nil {
+			log.Errorf("Failed to extract SPIFFE trust domain from %v: %v", id, err)
+			continue
+		}
+		if !local.Contains(m.TrustDomain) {
+			continue
+		}
+		for td := range local {
+			m.TrustDomain = td
+			out.Insert(m.String())
+		}
+	}
+	return out
+}
+
+// ExpandPrincipals expands authorization principals written as
+// <trust-domain>/ns/<namespace>/sa/<service-account> in the same way, so a
+// principal naming the local trust domain also matches its aliases. Principals
+// of federated trust domains and patterns are returned unchanged.
+func ExpandPrincipals(principals []string, trustDomain string, trustDomainAliases []string) []string {
+	if len(trustDomainAliases) == 0 || len(principals) == 0 {
+		return principals
+	}
+	out := make([]string, 0, len(principals))
+	seen := sets.New[string]()
+	for _, principal := range principals {
+		expanded := []string{principal}
+		if !strings.Contains(principal, "*") {
+			ids := ExpandWithTrustDomainAliases(sets.New(URIPrefix+principal), trustDomain, trustDomainAliases)
+			expanded = expanded[:0]
+			for _, id := range sets.SortedList(ids) {
+				expanded = append(expanded, strings.TrimPrefix(id, URIPrefix))
+			}
+		}
+		for _, p := range expanded {
+			if !seen.InsertContains(p) {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
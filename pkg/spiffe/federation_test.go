@@ -0,0 +1,158 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/dubbo-kubernetes/pkg/util/sets"
+	meshv1alpha1 "github.com/kdubbo/api/mesh/v1alpha1"
+)
+
+func TestFederatedTrustDomains(t *testing.T) {
+	got := FederatedTrustDomains([]*meshv1alpha1.MeshConfig_CertificateData{
+		{CertificateData: &meshv1alpha1.MeshConfig_CertificateData_SpiffeBundleUrl{
+			SpiffeBundleUrl: "https://bundle.partner.example/bundle",
+		}, TrustDomains: []string{" partner.example "}},
+		{CertificateData: &meshv1alpha1.MeshConfig_CertificateData_Pem{Pem: "roots"},
+			TrustDomains: []string{"other.org", "partner.example", "old.local", "bad/td"}},
+		{CertificateData: &meshv1alpha1.MeshConfig_CertificateData_Pem{Pem: "signer roots"},
+			CertSigners: []string{"example.com/corp"}},
+		{TrustDomains: []string{"empty.org"}},
+	}, "cluster.local", []string{"old.local"})
+	want := []FederatedTrustDomain{
+		{TrustDomain: "other.org", PEM: "roots"},
+		{TrustDomain: "partner.example", BundleURL: "https://bundle.partner.example/bundle"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FederatedTrustDomains() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeTrustBundle(t *testing.T) {
+	cert := testRootCert(t)
+	fromPEM, err := DecodeTrustBundle(CertsToPEM([]*x509.Certificate{cert}))
+	if err != nil || len(fromPEM) != 1 || !fromPEM[0].Equal(cert) {
+		t.Fatalf("DecodeTrustBundle(PEM) = %v, %v", fromPEM, err)
+	}
+
+	doc := fmt.Sprintf(`{"keys":[{"use":"x509-svid","kty":"EC","crv":"P-256","x":%q,"y":%q,"x5c":[%q]}]}`,
+		base64.RawURLEncoding.EncodeToString(cert.PublicKey.(*ecdsa.PublicKey).X.Bytes()),
+		base64.RawURLEncoding.EncodeToString(cert.PublicKey.(*ecdsa.PublicKey).Y.Bytes()),
+		base64.StdEncoding.EncodeToString(cert.Raw))
+	fromJSON, err := DecodeTrustBundle([]byte(doc))
+	if err != nil || len(fromJSON) != 1 || !fromJSON[0].Equal(cert) {
+		t.Fatalf("DecodeTrustBundle(JSON) = %v, %v", fromJSON, err)
+	}
+
+	if _, err := DecodeTrustBundle([]byte("not a bundle")); err == nil {
+		t.Fatal("DecodeTrustBundle(garbage) = nil error, want error")
+	}
+}
+
+func TestBundleMapRoundTrip(t *testing.T) {
+	partner, other := testRootCert(t), testRootCert(t)
+	data, err := EncodeBundleMap(map[string][]byte{
+		"partner.example": CertsToPEM([]*x509.Certificate{partner}),
+		"other.org":       CertsToPEM([]*x509.Certificate{other}),
+	})
+	if err != nil {
+		t.Fatalf("EncodeBundleMap() error = %v", err)
+	}
+	got, err := DecodeBundleMap(data)
+	if err != nil {
+		t.Fatalf("DecodeBundleMap() error = %v", err)
+	}
+	if len(got) != 2 || len(got["partner.example"]) != 1 || !got["partner.example"][0].Equal(partner) ||
+		len(got["other.org"]) != 1 || !got["other.org"][0].Equal(other) {
+		t.Fatalf("DecodeBundleMap() = %v, want each root under its own trust domain", got)
+	}
+
+	if _, err := EncodeBundleMap(map[string][]byte{"bad.org": []byte("not a bundle")}); err == nil {
+		t.Fatal("EncodeBundleMap(garbage) = nil error, want error")
+	}
+}
+
+func TestExpandWithTrustDomainAliasesKeepsFederatedIdentities(t *testing.T) {
+	got := ExpandWithTrustDomainAliases(sets.New(
+		"spiffe://cluster.local/ns/a/sa/b",
+		"spiffe://partner.example/ns/c/sa/d",
+		"dns.name",
+	), "cluster.local", []string{"old.local"})
+	want := []string{
+		"dns.name",
+		"spiffe://cluster.local/ns/a/sa/b",
+		"spiffe://old.local/ns/a/sa/b",
+		"spiffe://partner.example/ns/c/sa/d",
+	}
+	if list := sets.SortedList(got); !reflect.DeepEqual(list, want) {
+		t.Fatalf("ExpandWithTrustDomainAliases() = %v, want %v", list, want)
+	}
+}
+
+func TestExpandPrincipals(t *testing.T) {
+	got := ExpandPrincipals([]string{
+		"old.local/ns/a/sa/b",
+		"partner.example/ns/c/sa/d",
+		"*/ns/e/sa/*",
+	}, "cluster.local", []string{"old.local"})
+	want := []string{
+		"cluster.local/ns/a/sa/b",
+		"old.local/ns/a/sa/b",
+		"partner.example/ns/c/sa/d",
+		"*/ns/e/sa/*",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExpandPrincipals() = %v, want %v", got, want)
+	}
+}
+
+func testRootCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"partner.example"}},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
@@ -193,18 +193,9 @@ func RetrieveSpiffeBundleRootCerts(config map[string]string, caCertPool *x509.Ce
 			return nil, fmt.Errorf("trust domain [%s] at URL [%s] failed to decode bundle: %v", trustDomain, endpoint, err)
 		}
 
-		var certs []*x509.Certificate
-		for i, key := range doc.Keys {
-			if key.Use == "x509-svid" {
-				if len(key.Certificates) != 1 {
-					return nil, fmt.Errorf("trust domain [%s] at URL [%s] expected 1 certificate in x509-svid entry %d; got %d",
-						trustDomain, endpoint, i, len(key.Certificates))
-				}
-				certs = append(certs, key.Certificates[0])
-			}
-		}
-		if len(certs) == 0 {
-			return nil, fmt.Errorf("trust domain [%s] at URL [%s] does not provide a X509 SVID", trustDomain, endpoint)
+		certs, err := doc.x509Authorities()
+		if err != nil {
+			return nil, fmt.Errorf("trust domain [%s] at URL [%s] %v", trustDomain, endpoint, err)
 		}
 		ret[trustDomain] = certs
 	}
@@ -214,6 +205,23 @@ func RetrieveSpiffeBundleRootCerts(config map[string]string, caCertPool *x509.Ce
 	return ret, nil
 }
 
+// x509Authorities returns the root certificates of the x509-svid entries.
+func (doc *bundleDoc) x509Authorities() ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for i, key := range doc.Keys {
+		if key.Use == "x509-svid" {
+			if len(key.Certificates) != 1 {
+				return nil, fmt.Errorf("expected 1 certificate in x509-svid entry %d; got %d", i, len(key.Certificates))
+			}
+			certs = append(certs, key.Certificates[0])
+		}
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("does not provide a X509 SVID")
+	}
+	return certs, nil
+}
+
 func (i Identity) String() string {
 	return URIPrefix + i.TrustDomain + "/ns/" + i.Namespace + "/sa/" + i.ServiceAccount
 }
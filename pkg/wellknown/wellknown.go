@@ -26,6 +26,12 @@ const (
 const (
 	// JWTAuthentication validates JWT tokens when they are present.
 	JWTAuthentication = "filters.http.jwt_authn"
+	// HTTPExternalAuthorization delegates requests matched by CUSTOM
+	// authorization policies to an external decision service.
+	HTTPExternalAuthorization = "filters.http.ext_authz"
+	// HTTPLocalRateLimit rejects inbound requests once the token bucket of
+	// their rate limit descriptor is empty.
+	HTTPLocalRateLimit = "filters.http.local_ratelimit"
 	// HTTPRoleBasedAccessControl enforces request authorization policies.
 	HTTPRoleBasedAccessControl = "filters.http.rbac"
 	// HTTPRouter forwards the request after earlier HTTP filters have accepted it.
@@ -0,0 +1,260 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Regenerate with:
+//
+//   protoc -I . --go_out=. --go_opt=paths=source_relative ext_authz.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.0
+// source: ext_authz.proto
+
+package filterpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ExtAuthz is the config of the filters.http.ext_authz HTTP filter. It sends
+// the requests matched by CUSTOM AuthorizationPolicies to an HTTP decision
+// service and admits them only on a 2xx answer. The service receives the
+// original method, the path behind path_prefix and the allowed headers.
+//
+// The filter is never optional: a data plane that cannot run it must reject
+// the listener rather than admit the requests the policies mean to guard.
+type ExtAuthz struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the mesh config extension provider the policies delegate to.
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Outbound cluster of the decision service.
+	Cluster string `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Host the check requests are sent to.
+	Authority string `protobuf:"bytes,3,opt,name=authority,proto3" json:"authority,omitempty"`
+	// Prepended to the original path of a check request.
+	PathPrefix string `protobuf:"bytes,4,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	// Bounds a check request.
+	Timeout *durationpb.Duration `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Admits requests when the service cannot be reached or does not answer in
+	// time. Otherwise they are rejected with status_on_error.
+	FailureModeAllow bool `protobuf:"varint,6,opt,name=failure_mode_allow,json=failureModeAllow,proto3" json:"failure_mode_allow,omitempty"`
+	// HTTP status of a request rejected because the check failed.
+	StatusOnError uint32 `protobuf:"varint,7,opt,name=status_on_error,json=statusOnError,proto3" json:"status_on_error,omitempty"`
+	// Request headers forwarded to the service, in lowercase. Authorization,
+	// Host, Method and Path always are.
+	AllowedHeaders []string `protobuf:"bytes,8,rep,name=allowed_headers,json=allowedHeaders,proto3" json:"allowed_headers,omitempty"`
+	// Response headers of an allowing check that are added to the request.
+	HeadersToUpstreamOnAllow []string `protobuf:"bytes,9,rep,name=headers_to_upstream_on_allow,json=headersToUpstreamOnAllow,proto3" json:"headers_to_upstream_on_allow,omitempty"`
+	// Response headers of a denying check that are returned to the caller.
+	HeadersToDownstreamOnDeny []string `protobuf:"bytes,10,rep,name=headers_to_downstream_on_deny,json=headersToDownstreamOnDeny,proto3" json:"headers_to_downstream_on_deny,omitempty"`
+	// RBAC filter config whose rules select the requests to check. Requests
+	// matching none of them skip the check.
+	Matcher       *anypb.Any `protobuf:"bytes,11,opt,name=matcher,proto3" json:"matcher,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtAuthz) Reset() {
+	*x = ExtAuthz{}
+	mi := &file_ext_authz_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtAuthz) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtAuthz) ProtoMessage() {}
+
+func (x *ExtAuthz) ProtoReflect() protoreflect.Message {
+	mi := &file_ext_authz_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtAuthz.ProtoReflect.Descriptor instead.
+func (*ExtAuthz) Descriptor() ([]byte, []int) {
+	return file_ext_authz_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExtAuthz) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ExtAuthz) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *ExtAuthz) GetAuthority() string {
+	if x != nil {
+		return x.Authority
+	}
+	return ""
+}
+
+func (x *ExtAuthz) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+func (x *ExtAuthz) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *ExtAuthz) GetFailureModeAllow() bool {
+	if x != nil {
+		return x.FailureModeAllow
+	}
+	return false
+}
+
+func (x *ExtAuthz) GetStatusOnError() uint32 {
+	if x != nil {
+		return x.StatusOnError
+	}
+	return 0
+}
+
+func (x *ExtAuthz) GetAllowedHeaders() []string {
+	if x != nil {
+		return x.AllowedHeaders
+	}
+	return nil
+}
+
+func (x *ExtAuthz) GetHeadersToUpstreamOnAllow() []string {
+	if x != nil {
+		return x.HeadersToUpstreamOnAllow
+	}
+	return nil
+}
+
+func (x *ExtAuthz) GetHeadersToDownstreamOnDeny() []string {
+	if x != nil {
+		return x.HeadersToDownstreamOnDeny
+	}
+	return nil
+}
+
+func (x *ExtAuthz) GetMatcher() *anypb.Any {
+	if x != nil {
+		return x.Matcher
+	}
+	return nil
+}
+
+var File_ext_authz_proto protoreflect.FileDescriptor
+
+const file_ext_authz_proto_rawDesc = "" +
+	"\n" +
+	"\x0fext_authz.proto\x12\x15dubbo.filters.http.v1\x1a\x19google/protobuf/any.proto\x1a\x1egoogle/protobuf/duration.proto\"\xe5\x03\n" +
+	"\bExtAuthz\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x18\n" +
+	"\acluster\x18\x02 \x01(\tR\acluster\x12\x1c\n" +
+	"\tauthority\x18\x03 \x01(\tR\tauthority\x12\x1f\n" +
+	"\vpath_prefix\x18\x04 \x01(\tR\n" +
+	"pathPrefix\x123\n" +
+	"\atimeout\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12,\n" +
+	"\x12failure_mode_allow\x18\x06 \x01(\bR\x10failureModeAllow\x12&\n" +
+	"\x0fstatus_on_error\x18\a \x01(\rR\rstatusOnError\x12'\n" +
+	"\x0fallowed_headers\x18\b \x03(\tR\x0eallowedHeaders\x12>\n" +
+	"\x1cheaders_to_upstream_on_allow\x18\t \x03(\tR\x18headersToUpstreamOnAllow\x12@\n" +
+	"\x1dheaders_to_downstream_on_deny\x18\n" +
+	" \x03(\tR\x19headersToDownstreamOnDeny\x12.\n" +
+	"\amatcher\x18\v \x01(\v2\x14.google.protobuf.AnyR\amatcherB5Z3github.com/apache/dubbo-kubernetes/pkg/xds/filterpbb\x06proto3"
+
+var (
+	file_ext_authz_proto_rawDescOnce sync.Once
+	file_ext_authz_proto_rawDescData []byte
+)
+
+func file_ext_authz_proto_rawDescGZIP() []byte {
+	file_ext_authz_proto_rawDescOnce.Do(func() {
+		file_ext_authz_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ext_authz_proto_rawDesc), len(file_ext_authz_proto_rawDesc)))
+	})
+	return file_ext_authz_proto_rawDescData
+}
+
+var file_ext_authz_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_ext_authz_proto_goTypes = []any{
+	(*ExtAuthz)(nil),            // 0: dubbo.filters.http.v1.ExtAuthz
+	(*durationpb.Duration)(nil), // 1: google.protobuf.Duration
+	(*anypb.Any)(nil),           // 2: google.protobuf.Any
+}
+var file_ext_authz_proto_depIdxs = []int32{
+	1, // 0: dubbo.filters.http.v1.ExtAuthz.timeout:type_name -> google.protobuf.Duration
+	2, // 1: dubbo.filters.http.v1.ExtAuthz.matcher:type_name -> google.protobuf.Any
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_ext_authz_proto_init() }
+func file_ext_authz_proto_init() {
+	if File_ext_authz_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ext_authz_proto_rawDesc), len(file_ext_authz_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ext_authz_proto_goTypes,
+		DependencyIndexes: file_ext_authz_proto_depIdxs,
+		MessageInfos:      file_ext_authz_proto_msgTypes,
+	}.Build()
+	File_ext_authz_proto = out.File
+	file_ext_authz_proto_goTypes = nil
+	file_ext_authz_proto_depIdxs = nil
+}